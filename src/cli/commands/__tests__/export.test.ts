@@ -0,0 +1,46 @@
+import { mkdir, mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { setActiveProfile } from '../../../utils/config';
+import { buildUsageExport } from '../export';
+
+describe('export command', () => {
+  let root: string;
+
+  const writeLog = async (logDir: string, project: string) => {
+    await mkdir(join(logDir, `-${project}`), { recursive: true });
+    const event = {
+      timestamp: new Date().toISOString(),
+      sessionId: 'abc',
+      cwd: `/nonexistent/ccstat-profile/${project}`,
+    };
+    await writeFile(join(logDir, `-${project}`, 'session.jsonl'), JSON.stringify(event) + '\n');
+  };
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-export-'));
+    await writeLog(join(root, 'personal'), 'blog');
+    await writeLog(join(root, 'work'), 'billing');
+
+    const config = {
+      logDirs: [join(root, 'personal')],
+      profiles: { work: { logDirs: [join(root, 'work')] } },
+    };
+    process.env.CCSTAT_CONFIG = join(root, 'config.json');
+    await writeFile(process.env.CCSTAT_CONFIG, JSON.stringify(config));
+  });
+
+  afterEach(async () => {
+    setActiveProfile(undefined);
+    delete process.env.CCSTAT_CONFIG;
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should read the log directories of the profile given before the subcommand', async () => {
+    const options = { days: '1', allTime: true };
+    expect((await buildUsageExport(options)).projects.map(p => p.name)).toEqual(['blog']);
+
+    setActiveProfile('work');
+    expect((await buildUsageExport(options)).projects.map(p => p.name)).toEqual(['billing']);
+  });
+});
@@ -0,0 +1,36 @@
+import { hostname } from 'os';
+import { getDurationModel } from '../../core/duration';
+import {
+  createActivityWatchEvents,
+  getActivityWatchBucketId,
+  pushActivityWatchEvents,
+} from '../../core/export/activitywatch';
+import { loadTimelines } from '../../core/parser';
+import { calculateStartTime } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface ActivityWatchCommandOptions {
+  url: string;
+  days: string;
+  hours?: string;
+}
+
+export async function activityWatchCommand(options: ActivityWatchCommandOptions): Promise<void> {
+  const endTime = new Date();
+  const startTime = calculateStartTime(
+    endTime,
+    options.hours ? undefined : parseInt(options.days),
+    options.hours ? parseInt(options.hours) : undefined
+  );
+
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(startTime, endTime, undefined, getLoadOptions(settings));
+  const events = createActivityWatchEvents(timelines, getDurationModel().idleMinutes);
+
+  const host = hostname();
+  const sent = await pushActivityWatchEvents(options.url, events, host);
+  console.log(
+    `Sent ${sent} ${sent === 1 ? 'event' : 'events'} to bucket ${getActivityWatchBucketId(host)}`
+  );
+}
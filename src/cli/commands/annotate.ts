@@ -0,0 +1,43 @@
+import { format } from 'date-fns';
+import {
+  appendAnnotation,
+  getAnnotationsPath,
+  loadAnnotations,
+  parseAnnotationTime,
+} from '../../core/annotations';
+import { loadSettings } from '../../utils/config';
+
+export interface AnnotateCommandOptions {
+  at?: string;
+}
+
+export async function annotateCommand(
+  label: string | undefined,
+  options: AnnotateCommandOptions
+): Promise<void> {
+  const path = getAnnotationsPath(await loadSettings());
+
+  if (label) {
+    const time = options.at ? parseAnnotationTime(options.at) : new Date();
+    if (!time) {
+      throw new Error(
+        `Invalid time '${options.at}'. Use a time (14:05, 2pm), a duration ago (30m) ` +
+          'or "YYYY-MM-DD HH:MM"'
+      );
+    }
+    await appendAnnotation(path, { timestamp: time.toISOString(), label });
+    console.log(`Annotated ${format(time, 'yyyy-MM-dd HH:mm')}: ${label}`);
+    return;
+  }
+
+  const annotations = await loadAnnotations(path);
+  if (annotations.length === 0) {
+    console.log('No annotations');
+  } else {
+    console.log(`Annotations (${path}):`);
+    for (const annotation of annotations) {
+      const time = format(new Date(annotation.timestamp), 'yyyy-MM-dd HH:mm');
+      console.log(` - ${time}  ${annotation.label}`);
+    }
+  }
+}
@@ -0,0 +1,45 @@
+import { getBackfillStorePath } from '../../core/store';
+import { loadSettings } from '../../utils/config';
+import {
+  backfillArchive,
+  DEFAULT_BACKFILL_BATCH_SIZE,
+  getBackfillCheckpointPath,
+} from '../../core/store/backfill';
+
+export interface BackfillCommandOptions {
+  batchSize?: string;
+  restart?: boolean;
+}
+
+export async function backfillCommand(
+  archiveDirs: string[],
+  options: BackfillCommandOptions
+): Promise<void> {
+  const batchSize = options.batchSize ? parseInt(options.batchSize) : DEFAULT_BACKFILL_BATCH_SIZE;
+  if (!(batchSize > 0)) {
+    throw new Error(`Invalid batch size '${options.batchSize}'. Must be a positive number`);
+  }
+
+  const settings = await loadSettings();
+  const storePath = getBackfillStorePath();
+  const result = await backfillArchive({
+    storePath,
+    checkpointPath: getBackfillCheckpointPath(),
+    logDirs: archiveDirs,
+    batchSize,
+    restart: options.restart,
+    gitFallback: settings.gitFallback,
+    nestedRepositories: settings.nestedRepositories,
+    onProgress: (processed, total) => {
+      const percent = Math.round((processed / total) * 100);
+      process.stderr.write(`\rBackfilled ${processed}/${total} files (${percent}%)`);
+    },
+  });
+  if (result.files > 0) process.stderr.write('\n');
+
+  console.log(
+    `Added ${result.aggregates} daily totals from ${result.events} events in ${result.files} ` +
+      `files to ${storePath}` +
+      (result.skipped > 0 ? ` (${result.skipped} files already backfilled)` : '')
+  );
+}
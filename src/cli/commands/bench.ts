@@ -0,0 +1,32 @@
+import { runBenchmark, formatBenchmarkResult } from '../../core/bench';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { calculateStartTime } from '../../utils/timeRange';
+
+export interface BenchCommandOptions {
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  cpuProfile?: string;
+}
+
+export async function benchCommand(options: BenchCommandOptions): Promise<void> {
+  const endTime = options.allTime ? undefined : new Date();
+  const startTime = endTime
+    ? calculateStartTime(
+        endTime,
+        options.hours ? undefined : parseInt(options.days),
+        options.hours ? parseInt(options.hours) : undefined
+      )
+    : undefined;
+
+  const result = await runBenchmark({
+    startTime,
+    endTime,
+    width: process.stdout.columns || 80,
+    profileDir: options.cpuProfile,
+    loadOptions: getLoadOptions(await loadSettings()),
+  });
+
+  console.log(formatBenchmarkResult(result));
+}
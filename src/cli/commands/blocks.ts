@@ -0,0 +1,44 @@
+import { subDays } from 'date-fns';
+import { loadTimelines } from '../../core/parser';
+import {
+  formatBlockBurnDown,
+  getActiveBlock,
+  identifyBlocks,
+  inferBlockCapacity,
+} from '../../core/blocks';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface BlocksCommandOptions {
+  days: string;
+  barWidth: string;
+}
+
+export async function blocksCommand(options: BlocksCommandOptions): Promise<void> {
+  const days = parseInt(options.days);
+  if (!(days > 0)) {
+    throw new Error(`Invalid days '${options.days}'. Must be a positive number`);
+  }
+  const barWidth = parseInt(options.barWidth);
+  if (!(barWidth > 0)) {
+    throw new Error(`Invalid bar width '${options.barWidth}'. Must be a positive number`);
+  }
+
+  const now = new Date();
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(
+    subDays(now, days),
+    now,
+    undefined,
+    getLoadOptions(settings)
+  );
+
+  const blocks = identifyBlocks(timelines);
+  const active = getActiveBlock(blocks, now);
+  if (!active) {
+    console.log('No active block. A new 5-hour block starts with your next message.');
+    return;
+  }
+
+  console.log(formatBlockBurnDown(active, inferBlockCapacity(blocks, active), now, barWidth));
+}
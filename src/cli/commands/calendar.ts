@@ -0,0 +1,57 @@
+import {
+  createCalendar,
+  DEFAULT_CALENDAR_DAYS,
+  formatCalendar,
+  formatCalendarHeader,
+  getCalendarStart,
+  renderCalendarImage,
+} from '../../core/calendar';
+import { loadTimelines } from '../../core/parser';
+import { ColorTheme, getColorScheme, THEME_HEX_COLORS } from '../../ui/colorThemes';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+import {
+  detectGraphicsProtocol,
+  formatInlineImage,
+  GraphicsProtocol,
+} from '../../utils/terminalGraphics';
+
+export interface CalendarCommandOptions {
+  days: string;
+  color: ColorTheme;
+  project?: string[];
+  // A protocol, or true to detect one from the terminal
+  image?: GraphicsProtocol | boolean;
+}
+
+export async function calendarCommand(options: CalendarCommandOptions): Promise<void> {
+  const days = parseInt(options.days);
+  if (!(days > 0)) {
+    throw new Error(`Invalid day count '${options.days}'. Must be a positive number`);
+  }
+
+  const now = new Date();
+  const settings = await loadSettings();
+  const start = getCalendarStart(days, now);
+  let timelines = await loadTimelines(start, now, undefined, getLoadOptions(settings));
+  if (options.project && options.project.length > 0) {
+    timelines = timelines.filter(t => matchesProjectName(options.project!, t.projectName));
+  }
+  const calendar = createCalendar(timelines, days, now);
+
+  const protocol =
+    options.image === true ? detectGraphicsProtocol() : options.image ? options.image : null;
+  if (options.image === true && !protocol) {
+    console.error('No inline image support detected in this terminal; drawing with unicode');
+  }
+
+  console.log(formatCalendarHeader(calendar));
+  // Images only make sense on a terminal; piped output gets the unicode calendar
+  if (protocol && process.stdout.isTTY) {
+    const png = renderCalendarImage(calendar, THEME_HEX_COLORS[options.color]);
+    console.log(formatInlineImage(png, protocol));
+  } else {
+    console.log(formatCalendar(calendar, getColorScheme(options.color)));
+  }
+}
@@ -0,0 +1,32 @@
+import { readFile } from 'fs/promises';
+import {
+  calibrateIdleThreshold,
+  formatCalibration,
+  getTrackedRange,
+  parseTrackedTime,
+} from '../../core/calibrate';
+import { getDurationModel } from '../../core/duration';
+import { loadTimelines } from '../../core/parser';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export async function calibrateCommand(file: string): Promise<void> {
+  const entries = parseTrackedTime(await readFile(file, 'utf-8'), file);
+  const range = getTrackedRange(entries);
+  if (!range) {
+    console.log(`${file} contains no tracked time to compare`);
+    return;
+  }
+
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(
+    range.start,
+    range.end,
+    undefined,
+    getLoadOptions(settings)
+  );
+
+  const model = getDurationModel();
+  const rows = calibrateIdleThreshold(timelines, entries, model);
+  console.log(formatCalibration(rows, model.idleMinutes));
+}
@@ -0,0 +1,60 @@
+import { getCommitTime } from '../../core/git';
+import { loadTimelines, resolveDirectoryRepository } from '../../core/parser';
+import {
+  createCommitContext,
+  DEFAULT_PROMPT_LIMIT,
+  formatCommitContext,
+} from '../../core/commitContext';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { parseSince } from '../../utils/timeRange';
+
+export interface CommitContextCommandOptions {
+  since?: string;
+  prompts?: string;
+  json?: boolean;
+}
+
+export async function commitContextCommand(options: CommitContextCommandOptions): Promise<void> {
+  const promptLimit = options.prompts ? parseInt(options.prompts) : DEFAULT_PROMPT_LIMIT;
+  if (!(promptLimit >= 0)) {
+    throw new Error(`Invalid prompt count '${options.prompts}'. Must be 0 or more`);
+  }
+
+  const now = new Date();
+  const directory = process.cwd();
+  let since: Date | null;
+  if (options.since) {
+    since = parseSince(options.since, now);
+    if (!since) {
+      throw new Error(
+        `Invalid time '${options.since}'. Use a time (9am, 14:30), a duration (2h, 3d) or a date`
+      );
+    }
+  } else {
+    since = await getCommitTime(directory);
+  }
+
+  const settings = await loadSettings();
+  const loadOptions = getLoadOptions(settings);
+  const repository = await resolveDirectoryRepository(directory, loadOptions);
+  const timelines = await loadTimelines(
+    since ?? undefined,
+    since ? now : undefined,
+    undefined,
+    loadOptions
+  );
+  const timeline = timelines.find(t => t.projectId === repository.id);
+  const context = createCommitContext(timeline?.projectName ?? repository.name, timeline, since);
+
+  if (options.json) {
+    console.log(JSON.stringify(context, null, 2));
+    return;
+  }
+  // Nothing on stdout, so a commit message hook appends nothing
+  if (context.minutes === 0 && context.prompts.length === 0) {
+    console.error(`No Claude activity in ${context.projectName} since the last commit`);
+    return;
+  }
+  console.log(formatCommitContext(context, promptLimit));
+}
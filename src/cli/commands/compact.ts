@@ -0,0 +1,29 @@
+import { startOfDay } from 'date-fns';
+import { loadTimelines } from '../../core/parser';
+import { generateDemoTimelines, DemoOptions } from '../../core/demo';
+import { createCompactSummary } from '../../core/compact';
+import { Settings } from '../../utils/config';
+import { evaluateExitCode, ExitConditions } from '../../utils/exitCodes';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+// Print today's summary as a single JSON line, skipping the interactive UI. Exit conditions
+// apply to today's activity, the range the summary covers.
+export async function compactCommand(
+  demo: DemoOptions | undefined,
+  settings: Settings,
+  exitConditions: ExitConditions = {}
+): Promise<void> {
+  const now = new Date();
+  const startTime = startOfDay(now);
+
+  const timelines = demo
+    ? generateDemoTimelines(startTime, now, demo)
+    : await loadTimelines(startTime, now, undefined, getLoadOptions(settings));
+
+  console.log(JSON.stringify(createCompactSummary(timelines, now)));
+
+  const exitCode = evaluateExitCode(timelines, exitConditions);
+  if (exitCode !== 0) {
+    process.exitCode = exitCode;
+  }
+}
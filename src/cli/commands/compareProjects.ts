@@ -0,0 +1,53 @@
+import { compareProjects, formatComparison } from '../../core/compare';
+import { loadTimelines } from '../../core/parser';
+import { SparklineRenderMode } from '../../ui/renderModes';
+import { calculateStartTime } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+
+export interface CompareProjectsCommandOptions {
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  width: string;
+  render?: SparklineRenderMode;
+}
+
+export async function compareProjectsCommand(
+  projects: string[],
+  options: CompareProjectsCommandOptions
+): Promise<void> {
+  if (projects.length < 2) {
+    throw new Error('Name at least two projects to compare');
+  }
+  const width = parseInt(options.width);
+  if (!(width > 0)) {
+    throw new Error(`Invalid width '${options.width}'. Must be a positive number`);
+  }
+
+  const now = new Date();
+  const rangeEnd = options.allTime ? undefined : now;
+  const rangeStart = rangeEnd
+    ? calculateStartTime(
+        rangeEnd,
+        options.hours ? undefined : parseInt(options.days),
+        options.hours ? parseInt(options.hours) : undefined
+      )
+    : undefined;
+
+  const settings = await loadSettings();
+  const timelines = (
+    await loadTimelines(rangeStart, rangeEnd, undefined, getLoadOptions(settings))
+  ).filter(t => matchesProjectName(projects, t.projectName));
+
+  // All time spans from the first to the last event of the compared projects
+  const startTime =
+    rangeStart ?? new Date(Math.min(...timelines.map(t => t.startTime.getTime()), now.getTime()));
+  const endTime = rangeEnd ?? now;
+
+  const braille = options.render === 'braille';
+  console.log(
+    formatComparison(compareProjects(timelines, projects, startTime, endTime, width, braille))
+  );
+}
@@ -0,0 +1,91 @@
+import { spawnSync } from 'child_process';
+import { existsSync } from 'fs';
+import { render } from 'ink';
+import React from 'react';
+import { createDefaultConfig } from '../../core/onboarding';
+import { ConfigForm } from '../../ui/ConfigForm';
+import { Config, getConfigPath, loadConfig, saveConfig } from '../../utils/config';
+import { getEditor, getEditorCommand } from '../../utils/projectActions';
+import {
+  ConfigOperation,
+  formatConfigValue,
+  getConfigValue,
+  listConfigValues,
+  parseConfigValue,
+  resolveConfigKey,
+  setConfigValue,
+  validateConfig,
+} from '../../utils/configKeys';
+
+async function saveValidConfig(config: Config, configPath: string): Promise<void> {
+  const problem = validateConfig(config);
+  if (problem) throw new Error(`${configPath}: ${problem}`);
+  await saveConfig(config, configPath);
+}
+
+// Open the file in $VISUAL or $EDITOR, then check what was saved
+async function editConfigFile(configPath: string): Promise<void> {
+  if (!existsSync(configPath)) await saveConfig(createDefaultConfig(), configPath);
+
+  const editor = getEditor();
+  const result = spawnSync(getEditorCommand(configPath, editor), { stdio: 'inherit', shell: true });
+  if (result.status !== 0) throw new Error(`${editor} exited with status ${result.status}`);
+
+  // loadConfig reports invalid JSON and types, validateConfig the values of known settings
+  const problem = validateConfig(await loadConfig(configPath));
+  if (problem) throw new Error(`${configPath}: ${problem}`);
+}
+
+// `ccstat config [operation] [key] [value]`; without an operation, a form for the settings
+export async function configCommand(
+  operation: ConfigOperation | undefined,
+  args: string[]
+): Promise<void> {
+  const configPath = getConfigPath();
+
+  // A file that doesn't load is exactly what the editor is for, so it isn't loaded first
+  if (operation === 'edit') {
+    await editConfigFile(configPath);
+    return;
+  }
+
+  const config = await loadConfig(configPath);
+
+  switch (operation) {
+    case undefined: {
+      const app = render(
+        React.createElement(ConfigForm, {
+          config,
+          configPath,
+          onSave: updated => saveValidConfig(updated, configPath),
+        })
+      );
+      await app.waitUntilExit();
+      return;
+    }
+    case 'list':
+      for (const line of listConfigValues(config)) console.log(line);
+      return;
+    case 'get': {
+      if (!args[0]) throw new Error('Missing setting, e.g. ccstat config get color');
+      const value = getConfigValue(config, resolveConfigKey(args[0]).path);
+      if (value === undefined) throw new Error(`${args[0]} is not set`);
+      console.log(formatConfigValue(value));
+      return;
+    }
+    case 'set': {
+      if (args.length < 2) throw new Error('Missing value, e.g. ccstat config set color ocean');
+      const { configKey, path } = resolveConfigKey(args[0]);
+      const value = parseConfigValue(configKey, args.slice(1).join(' '));
+      await saveValidConfig(setConfigValue(config, path, value), configPath);
+      console.log(`${args[0]} = ${formatConfigValue(value)}`);
+      return;
+    }
+    case 'unset': {
+      if (!args[0]) throw new Error('Missing setting, e.g. ccstat config unset color');
+      const { path } = resolveConfigKey(args[0]);
+      await saveValidConfig(setConfigValue(config, path, undefined), configPath);
+      return;
+    }
+  }
+}
@@ -0,0 +1,42 @@
+import { loadTimelines } from '../../core/parser';
+import {
+  analyzeContextPressure,
+  DEFAULT_CONTEXT_WINDOW,
+  formatContextPressure,
+} from '../../core/context';
+import { calculateStartTime } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+
+export interface ContextCommandOptions {
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  project?: string[];
+  window?: string;
+}
+
+export async function contextCommand(options: ContextCommandOptions): Promise<void> {
+  const contextWindow = options.window ? parseInt(options.window) : DEFAULT_CONTEXT_WINDOW;
+  if (!(contextWindow > 0)) {
+    throw new Error(`Invalid context window '${options.window}'. Must be a positive integer`);
+  }
+
+  const endTime = options.allTime ? undefined : new Date();
+  const startTime = endTime
+    ? calculateStartTime(
+        endTime,
+        options.hours ? undefined : parseInt(options.days),
+        options.hours ? parseInt(options.hours) : undefined
+      )
+    : undefined;
+
+  const settings = await loadSettings();
+  let timelines = await loadTimelines(startTime, endTime, undefined, getLoadOptions(settings));
+  if (options.project && options.project.length > 0) {
+    timelines = timelines.filter(t => matchesProjectName(options.project!, t.projectName));
+  }
+
+  console.log(formatContextPressure(analyzeContextPressure(timelines, contextWindow)));
+}
@@ -0,0 +1,49 @@
+import { isSameDay } from 'date-fns';
+import { loadTimelines } from '../../core/parser';
+import { formatTailEntry, listTimelineEntries, EventRole } from '../../core/tail';
+import { calculateStartTime, parseSince } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+
+export interface EventsCommandOptions {
+  days: string;
+  hours?: string;
+  since?: string;
+  allTime?: boolean;
+  project?: string[];
+  role?: EventRole[];
+}
+
+export async function eventsCommand(options: EventsCommandOptions): Promise<void> {
+  const now = new Date();
+  let startTime: Date | undefined;
+  if (options.since) {
+    const since = parseSince(options.since, now);
+    if (!since) {
+      throw new Error(
+        `Invalid time '${options.since}'. Use a time (9am, 14:30), a duration (2h, 3d) or a date`
+      );
+    }
+    startTime = since;
+  } else if (!options.allTime) {
+    startTime = calculateStartTime(
+      now,
+      options.hours ? undefined : parseInt(options.days),
+      options.hours ? parseInt(options.hours) : undefined
+    );
+  }
+  const endTime = startTime ? now : undefined;
+
+  const settings = await loadSettings();
+  let timelines = await loadTimelines(startTime, endTime, undefined, getLoadOptions(settings));
+  if (options.project && options.project.length > 0) {
+    timelines = timelines.filter(t => matchesProjectName(options.project!, t.projectName));
+  }
+
+  // Lists reaching back before today need the date on every line
+  const timeFormat = startTime && isSameDay(startTime, now) ? 'HH:mm:ss' : 'MM/dd HH:mm:ss';
+  for (const entry of listTimelineEntries(timelines, options.role)) {
+    console.log(formatTailEntry(entry, timeFormat));
+  }
+}
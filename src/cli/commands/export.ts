@@ -0,0 +1,99 @@
+import { hostname } from 'os';
+import { loadTimelines } from '../../core/parser';
+import { createUsageExport, ExportRange, UsageExport } from '../../core/export';
+import { ExportFormat } from '../../core/export/formats';
+import { formatEventLines } from '../../core/export/events';
+import {
+  createActivityWatchEvents,
+  createActivityWatchImport,
+} from '../../core/export/activitywatch';
+import {
+  CARD_DAYS,
+  createActivityCard,
+  formatReadmeMarkdown,
+  formatReadmeSvg,
+} from '../../core/export/githubReadme';
+import { formatInfluxLines } from '../../core/export/influx';
+import { USAGE_EXPORT_JSON_SCHEMA } from '../../core/export/schema';
+import { getDurationModel } from '../../core/duration';
+import { Timeline } from '../../models/models';
+import { calculateStartTime } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface ExportCommandOptions {
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  user?: string;
+  format?: ExportFormat;
+  directories?: boolean;
+  schema?: boolean;
+}
+
+async function loadExportTimelines(
+  options: ExportCommandOptions
+): Promise<{ timelines: Timeline[]; range: ExportRange | null }> {
+  const endTime = options.allTime ? undefined : new Date();
+  const startTime = endTime
+    ? calculateStartTime(
+        endTime,
+        options.hours ? undefined : parseInt(options.days),
+        options.hours ? parseInt(options.hours) : undefined
+      )
+    : undefined;
+
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(startTime, endTime, undefined, getLoadOptions(settings));
+  const range = startTime && endTime ? { start: startTime, end: endTime } : null;
+
+  return { timelines, range };
+}
+
+// Load the requested range and aggregate it into an export; shared with push and serve
+export async function buildUsageExport(options: ExportCommandOptions): Promise<UsageExport> {
+  const { timelines, range } = await loadExportTimelines(options);
+  return createUsageExport(timelines, range, options.user, options.directories);
+}
+
+export async function exportCommand(options: ExportCommandOptions): Promise<void> {
+  if (options.schema) {
+    console.log(JSON.stringify(USAGE_EXPORT_JSON_SCHEMA, null, 2));
+    return;
+  }
+
+  // Profile cards always cover the last week, so a scheduled job needs no range options
+  if (options.format === 'github-readme' || options.format === 'github-svg') {
+    const now = new Date();
+    const { timelines } = await loadExportTimelines({
+      ...options,
+      days: `${CARD_DAYS}`,
+      hours: undefined,
+      allTime: false,
+    });
+    const card = createActivityCard(timelines, now);
+    console.log(
+      options.format === 'github-readme' ? formatReadmeMarkdown(card) : formatReadmeSvg(card)
+    );
+    return;
+  }
+
+  if (options.format === 'activitywatch') {
+    const { timelines } = await loadExportTimelines(options);
+    const events = createActivityWatchEvents(timelines, getDurationModel().idleMinutes);
+    console.log(JSON.stringify(createActivityWatchImport(events, hostname(), new Date()), null, 2));
+    return;
+  }
+
+  if (options.format === 'influx' || options.format === 'events') {
+    const { timelines } = await loadExportTimelines(options);
+    const lines =
+      options.format === 'influx' ? formatInfluxLines(timelines) : formatEventLines(timelines);
+    if (lines.length > 0) {
+      console.log(lines.join('\n'));
+    }
+    return;
+  }
+
+  console.log(JSON.stringify(await buildUsageExport(options), null, 2));
+}
@@ -0,0 +1,47 @@
+import {
+  createForecast,
+  FORECAST_METHOD_VALUES,
+  formatForecast,
+  getForecastPeriods,
+  isValidForecastMethod,
+} from '../../core/forecast';
+import { loadTimelines } from '../../core/parser';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface ForecastCommandOptions {
+  weeks: string;
+  method: string;
+  pricePerMtok?: string;
+  currency: string;
+}
+
+export async function forecastCommand(options: ForecastCommandOptions): Promise<void> {
+  const weeks = parseInt(options.weeks);
+  if (!(weeks > 0)) {
+    throw new Error(`Invalid weeks '${options.weeks}'. Must be a positive number`);
+  }
+  if (!isValidForecastMethod(options.method)) {
+    throw new Error(
+      `Invalid method '${options.method}'. Valid options: ${FORECAST_METHOD_VALUES.join(', ')}`
+    );
+  }
+
+  const price = options.pricePerMtok !== undefined ? parseFloat(options.pricePerMtok) : undefined;
+  if (price !== undefined && !(price >= 0)) {
+    throw new Error(`Invalid price '${options.pricePerMtok}'. Must be a non-negative number`);
+  }
+
+  const now = new Date();
+  const periods = getForecastPeriods(weeks, now);
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(
+    periods[0].start,
+    periods[weeks - 1].end,
+    undefined,
+    getLoadOptions(settings)
+  );
+
+  const forecast = createForecast(timelines, weeks, options.method, now, price, settings.vacations);
+  console.log(formatForecast(forecast, options.currency));
+}
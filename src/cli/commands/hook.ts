@@ -0,0 +1,20 @@
+import {
+  appendHookEvent,
+  createHookEvent,
+  getHookEventsPath,
+  parseHookInput,
+} from '../../core/hooks';
+
+async function readStdin(): Promise<string> {
+  const chunks: Buffer[] = [];
+  for await (const chunk of process.stdin) {
+    chunks.push(chunk);
+  }
+  return Buffer.concat(chunks).toString('utf-8');
+}
+
+// Runs on every hook invocation, so it only appends one line and exits
+export async function hookCommand(): Promise<void> {
+  const input = parseHookInput(await readStdin());
+  await appendHookEvent(getHookEventsPath(), createHookEvent(input, new Date()));
+}
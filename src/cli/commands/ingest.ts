@@ -0,0 +1,64 @@
+import { getStorePath } from '../../core/store';
+import { compactStore, DEFAULT_RETENTION_DAYS } from '../../core/store/compact';
+import { ingestLogFiles } from '../../core/store/ingest';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface IngestCommandOptions {
+  watch?: boolean | string;
+  compact?: boolean;
+  keepDays?: string;
+}
+
+const DEFAULT_WATCH_SECONDS = 30;
+
+export async function ingestCommand(options: IngestCommandOptions): Promise<void> {
+  const settings = await loadSettings();
+  const storePath = getStorePath();
+
+  const retentionDays = settings.retentionDays ?? DEFAULT_RETENTION_DAYS;
+  const keepDays = options.keepDays ? parseInt(options.keepDays) : retentionDays;
+  if (!(keepDays > 0)) {
+    throw new Error(`Invalid keep days '${options.keepDays}'. Must be a positive number`);
+  }
+
+  const ingest = async () => {
+    const result = await ingestLogFiles(storePath, settings.logDirs);
+    if (result.files > 0 || !options.watch) {
+      console.log(`Ingested ${result.events} events from ${result.files} files into ${storePath}`);
+    }
+  };
+
+  await ingest();
+  if (options.compact) {
+    const result = await compactStore(storePath, keepDays, new Date(), getLoadOptions(settings));
+    console.log(
+      `Compacted ${result.compactedEvents} events older than ${keepDays} days into daily totals ` +
+        `(${result.keptDays} days of events and ${result.aggregates} daily totals kept)`
+    );
+  }
+  if (!options.watch) {
+    if (!settings.store) {
+      console.log('Set "store": true in the config file to read reports from the store');
+    }
+    return;
+  }
+
+  const seconds =
+    typeof options.watch === 'string' ? parseInt(options.watch) : DEFAULT_WATCH_SECONDS;
+  if (!(seconds > 0)) {
+    throw new Error(`Invalid watch interval '${options.watch}'. Must be a positive number`);
+  }
+
+  // Runs are sequential so two ingests never append the same lines
+  const loop = () => {
+    setTimeout(() => {
+      ingest()
+        .catch(error => {
+          console.error('Error:', error instanceof Error ? error.message : error);
+        })
+        .finally(loop);
+    }, seconds * 1000);
+  };
+  loop();
+}
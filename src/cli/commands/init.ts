@@ -0,0 +1,53 @@
+import { existsSync } from 'fs';
+import { createInterface } from 'readline/promises';
+import { inspectLogDir } from '../../core/environment';
+import {
+  createDefaultConfig,
+  formatLogDirsFound,
+  formatMainFlags,
+  getOnboardingMarkerPath,
+  markOnboarded,
+} from '../../core/onboarding';
+import { getProjectsDirs } from '../../core/parser';
+import { getConfigPath, loadSettings, saveConfig } from '../../utils/config';
+
+export interface InitCommandOptions {
+  // Create the config file without asking
+  yes?: boolean;
+}
+
+async function confirm(question: string): Promise<boolean> {
+  const rl = createInterface({ input: process.stdin, output: process.stdout });
+  try {
+    const answer = (await rl.question(`${question} [Y/n] `)).trim().toLowerCase();
+    return answer === '' || answer === 'y' || answer === 'yes';
+  } finally {
+    rl.close();
+  }
+}
+
+// First-run flow, also run by `ccstat init`: show which Claude directories were found, offer a
+// config file with the defaults and explain the main options
+export async function initCommand(options: InitCommandOptions): Promise<void> {
+  const configPath = getConfigPath();
+  const settings = await loadSettings();
+  const logDirs = await Promise.all(getProjectsDirs(settings.logDirs).map(inspectLogDir));
+
+  console.log('Welcome to ccstat!');
+  console.log('');
+  console.log(formatLogDirsFound(logDirs));
+  console.log('');
+
+  if (existsSync(configPath)) {
+    console.log(`Config file: ${configPath} (already exists, left as is)`);
+  } else if (options.yes || (await confirm(`Create a config file at ${configPath}?`))) {
+    await saveConfig(createDefaultConfig(), configPath);
+    console.log(`Created ${configPath}; edit it to pin or ignore projects, or to save views.`);
+  } else {
+    console.log('No config file created; run `ccstat init` to create one later.');
+  }
+
+  console.log('');
+  console.log(formatMainFlags());
+  await markOnboarded(getOnboardingMarkerPath(), new Date());
+}
@@ -0,0 +1,38 @@
+import { format } from 'date-fns';
+import { loadTimelines } from '../../core/parser';
+import { createInvoice, formatInvoice, parseMonth } from '../../core/invoice';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { filterTimelinesByTags } from '../../utils/tags';
+
+export interface InvoiceCommandOptions {
+  tag: string[];
+  month?: string;
+  rate?: string;
+  currency: string;
+}
+
+export async function invoiceCommand(options: InvoiceCommandOptions): Promise<void> {
+  const month = options.month ?? format(new Date(), 'yyyy-MM');
+  const range = parseMonth(month);
+  if (!range) {
+    throw new Error(`Invalid month '${month}'. Use YYYY-MM, e.g. 2025-06`);
+  }
+
+  const rate = options.rate !== undefined ? parseFloat(options.rate) : undefined;
+  if (rate !== undefined && !(rate >= 0)) {
+    throw new Error(`Invalid rate '${options.rate}'. Must be a non-negative number`);
+  }
+
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(
+    range.start,
+    range.end,
+    undefined,
+    getLoadOptions(settings)
+  );
+  const tagged = filterTimelinesByTags(timelines, options.tag, settings.tags ?? {});
+
+  const invoice = createInvoice(tagged, month, rate);
+  console.log(formatInvoice(invoice, options.tag.join(', '), options.currency));
+}
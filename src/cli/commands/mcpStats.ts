@@ -0,0 +1,32 @@
+import { loadTimelines } from '../../core/parser';
+import { collectMcpStats, formatMcpStats } from '../../core/mcp';
+import { calculateStartTime } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+
+export interface McpStatsCommandOptions {
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  project?: string[];
+}
+
+export async function mcpStatsCommand(options: McpStatsCommandOptions): Promise<void> {
+  const endTime = options.allTime ? undefined : new Date();
+  const startTime = endTime
+    ? calculateStartTime(
+        endTime,
+        options.hours ? undefined : parseInt(options.days),
+        options.hours ? parseInt(options.hours) : undefined
+      )
+    : undefined;
+
+  const settings = await loadSettings();
+  let timelines = await loadTimelines(startTime, endTime, undefined, getLoadOptions(settings));
+  if (options.project && options.project.length > 0) {
+    timelines = timelines.filter(t => matchesProjectName(options.project!, t.projectName));
+  }
+
+  console.log(formatMcpStats(collectMcpStats(timelines)));
+}
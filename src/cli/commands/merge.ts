@@ -0,0 +1,21 @@
+import { readFile } from 'fs/promises';
+import { basename, extname } from 'path';
+import { parseUsageExport } from '../../core/export';
+import { mergeUsageExports, formatTeamReport, NamedExport } from '../../core/merge';
+
+export interface MergeCommandOptions {
+  anonymous?: boolean;
+}
+
+export async function mergeCommand(files: string[], options: MergeCommandOptions): Promise<void> {
+  const exports: NamedExport[] = [];
+
+  for (const file of files) {
+    const data = parseUsageExport(await readFile(file, 'utf-8'), file);
+    // Fall back to the file name for exports created without --user
+    exports.push({ name: data.user ?? basename(file, extname(file)), data });
+  }
+
+  const report = mergeUsageExports(exports, { anonymous: options.anonymous });
+  console.log(formatTeamReport(report));
+}
@@ -0,0 +1,27 @@
+import { getConfigPath, loadConfig, saveConfig } from '../../utils/config';
+
+export interface PinCommandOptions {
+  remove?: boolean;
+}
+
+export async function pinCommand(projects: string[], options: PinCommandOptions): Promise<void> {
+  const config = await loadConfig();
+  const pinned = config.pinned ?? [];
+
+  if (projects.length > 0) {
+    config.pinned = options.remove
+      ? pinned.filter(name => !projects.includes(name))
+      : [...pinned, ...projects.filter(name => !pinned.includes(name))];
+    await saveConfig(config);
+  }
+
+  const current = config.pinned ?? [];
+  if (current.length === 0) {
+    console.log('No pinned projects');
+  } else {
+    console.log(`Pinned projects (${getConfigPath()}):`);
+    for (const name of current) {
+      console.log(` - ${name}`);
+    }
+  }
+}
@@ -0,0 +1,55 @@
+import { getCommitTime, getMergeBase } from '../../core/git';
+import { loadTimelines, resolveDirectoryRepository } from '../../core/parser';
+import {
+  createPrSummary,
+  DEFAULT_PR_PROMPT_LIMIT,
+  formatPrSummaryMarkdown,
+} from '../../core/prSummary';
+import { resolveSessionTitles } from '../../core/sessions';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface PrSummaryCommandOptions {
+  since: string;
+  prompts?: string;
+  json?: boolean;
+}
+
+export async function prSummaryCommand(options: PrSummaryCommandOptions): Promise<void> {
+  const promptLimit = options.prompts ? parseInt(options.prompts) : DEFAULT_PR_PROMPT_LIMIT;
+  if (!(promptLimit >= 0)) {
+    throw new Error(`Invalid prompt count '${options.prompts}'. Must be 0 or more`);
+  }
+
+  const directory = process.cwd();
+  // Refs starting with a dash would be read as git options
+  const mergeBase = options.since.startsWith('-')
+    ? null
+    : await getMergeBase(directory, options.since);
+  const since = mergeBase && (await getCommitTime(directory, mergeBase));
+  if (!since) {
+    throw new Error(`Unknown git ref '${options.since}', or not inside a git repository`);
+  }
+
+  const now = new Date();
+  const settings = await loadSettings();
+  const repository = await resolveDirectoryRepository(directory, getLoadOptions(settings));
+  const summaries = new Map<string, string>();
+  const timelines = await loadTimelines(since, now, undefined, {
+    ...getLoadOptions(settings),
+    summaries,
+  });
+  const timeline = timelines.find(t => t.projectId === repository.id);
+  const summary = createPrSummary(
+    timeline?.projectName ?? repository.name,
+    timeline,
+    resolveSessionTitles(timeline ? [timeline] : [], summaries),
+    options.since,
+    since,
+    now
+  );
+
+  console.log(
+    options.json ? JSON.stringify(summary, null, 2) : formatPrSummaryMarkdown(summary, promptLimit)
+  );
+}
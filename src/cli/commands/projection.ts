@@ -0,0 +1,82 @@
+import { loadTimelines } from '../../core/parser';
+import {
+  formatProjection,
+  getLimitPeriod,
+  isValidLimitPeriod,
+  PlanLimit,
+  projectUsage,
+} from '../../core/projection';
+import { loadSettings, Settings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface ProjectionCommandOptions {
+  tokens?: string;
+  hours?: string;
+  period?: string;
+  watch?: boolean | string;
+}
+
+const DEFAULT_WATCH_SECONDS = 60;
+
+function parseLimitValue(value: string | undefined, name: string): number | undefined {
+  if (value === undefined) return undefined;
+
+  const parsed = parseFloat(value);
+  if (!(parsed > 0)) {
+    throw new Error(`Invalid ${name} limit '${value}'. Must be a positive number`);
+  }
+  return parsed;
+}
+
+// Command-line values override the limit from the config file
+export function resolvePlanLimit(options: ProjectionCommandOptions, settings: Settings): PlanLimit {
+  const period = options.period ?? settings.limit?.period ?? 'weekly';
+  if (!isValidLimitPeriod(period)) {
+    throw new Error(`Invalid period '${period}'. Valid options: daily, weekly`);
+  }
+
+  const tokens = parseLimitValue(options.tokens, 'token') ?? settings.limit?.tokens;
+  const hours = parseLimitValue(options.hours, 'hours') ?? settings.limit?.hours;
+  if (tokens === undefined && hours === undefined) {
+    throw new Error('No limit set. Pass --tokens or --hours, or add "limit" to the config file');
+  }
+
+  return { period, tokens, hours };
+}
+
+async function renderProjection(limit: PlanLimit, settings: Settings): Promise<string> {
+  const now = new Date();
+  const { start } = getLimitPeriod(limit.period, now);
+  const timelines = await loadTimelines(start, now, undefined, getLoadOptions(settings));
+  return formatProjection(projectUsage(timelines, limit, now), now);
+}
+
+export async function projectionCommand(options: ProjectionCommandOptions): Promise<void> {
+  const settings = await loadSettings();
+  const limit = resolvePlanLimit(options, settings);
+
+  if (!options.watch) {
+    console.log(await renderProjection(limit, settings));
+    return;
+  }
+
+  const seconds =
+    typeof options.watch === 'string' ? parseInt(options.watch) : DEFAULT_WATCH_SECONDS;
+  if (!(seconds > 0)) {
+    throw new Error(`Invalid watch interval '${options.watch}'. Must be a positive number`);
+  }
+
+  const refresh = async () => {
+    const output = await renderProjection(limit, settings);
+    console.clear();
+    console.log(output);
+    console.log(`\nRefreshing every ${seconds}s · Ctrl+C to quit`);
+  };
+
+  await refresh();
+  setInterval(() => {
+    refresh().catch(error => {
+      console.error('Error:', error instanceof Error ? error.message : error);
+    });
+  }, seconds * 1000);
+}
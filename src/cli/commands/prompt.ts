@@ -0,0 +1,34 @@
+import { mkdir } from 'fs/promises';
+import {
+  createPromptScript,
+  DEFAULT_PROMPT_INTERVAL_SECONDS,
+  formatPromptSegment,
+  getPromptCacheDir,
+  PromptShell,
+} from '../../core/prompt';
+import { loadProjectDayStats } from './status';
+
+export interface PromptCommandOptions {
+  // Print the script for this shell instead of a segment
+  shell?: PromptShell;
+  interval?: string;
+  directory?: string;
+}
+
+export async function promptCommand(options: PromptCommandOptions): Promise<void> {
+  if (options.shell) {
+    const interval = options.interval
+      ? parseInt(options.interval)
+      : DEFAULT_PROMPT_INTERVAL_SECONDS;
+    if (!(interval > 0)) {
+      throw new Error(`Invalid interval '${options.interval}'. Must be a positive number`);
+    }
+    // Private to the user, since the scripts write to it on every prompt
+    await mkdir(getPromptCacheDir(), { recursive: true, mode: 0o700 });
+    process.stdout.write(createPromptScript(options.shell, interval));
+    return;
+  }
+
+  const stats = await loadProjectDayStats(options.directory ?? process.cwd(), new Date());
+  console.log(formatPromptSegment(stats));
+}
@@ -0,0 +1,57 @@
+import { createHash } from 'crypto';
+import { hostname, userInfo } from 'os';
+import { COLLECTOR_TOKEN_ENV, getCollectorUrl } from '../../core/collector';
+import { UsageExport } from '../../core/export';
+import { buildUsageExport, ExportCommandOptions } from './export';
+
+export interface PushCommandOptions extends ExportCommandOptions {
+  endpoint: string;
+  // Shared secret of the collector; defaults to $CCSTAT_COLLECTOR_TOKEN
+  token?: string;
+  yes?: boolean;
+}
+
+// Stable per-machine identifier that doesn't reveal the user or host name
+export function anonymousUserId(): string {
+  const digest = createHash('sha256').update(`${userInfo().username}@${hostname()}`).digest('hex');
+  return `anon-${digest.slice(0, 12)}`;
+}
+
+export async function pushCommand(options: PushCommandOptions): Promise<void> {
+  const data = await buildUsageExport({ ...options, user: options.user ?? anonymousUserId() });
+
+  // Always show exactly what leaves the machine: the request body itself
+  console.log(`The following summary will be sent to ${options.endpoint}:`);
+  console.log(serializeUsageExport(data));
+
+  if (!options.yes) {
+    console.log('Re-run with --yes to send it.');
+    return;
+  }
+
+  await sendUsageExport(options.endpoint, data, options.token);
+  console.log('Sent.');
+}
+
+// Request body of a push, also printed before asking to send it
+export function serializeUsageExport(data: UsageExport): string {
+  return JSON.stringify(data, null, 2);
+}
+
+export async function sendUsageExport(
+  endpoint: string,
+  data: UsageExport,
+  token = process.env[COLLECTOR_TOKEN_ENV]
+): Promise<void> {
+  const headers: Record<string, string> = { 'Content-Type': 'application/json' };
+  if (token) headers.Authorization = `Bearer ${token}`;
+
+  const response = await fetch(getCollectorUrl(endpoint, 'api/v1/usage'), {
+    method: 'POST',
+    headers,
+    body: serializeUsageExport(data),
+  });
+  if (!response.ok) {
+    throw new Error(`Collector responded with ${response.status} ${response.statusText}`);
+  }
+}
@@ -0,0 +1,43 @@
+import { readFile } from 'fs/promises';
+import { loadTimelines } from '../../core/parser';
+import {
+  calculateDailyTokens,
+  formatReconciliation,
+  getCcusageRange,
+  parseCcusageExport,
+  reconcileDaily,
+} from '../../core/reconcile';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface ReconcileCommandOptions {
+  tolerance: string;
+}
+
+export async function reconcileCommand(
+  file: string,
+  options: ReconcileCommandOptions
+): Promise<void> {
+  const tolerance = parseFloat(options.tolerance);
+  if (!(tolerance >= 0)) {
+    throw new Error(`Invalid tolerance '${options.tolerance}'. Must be a non-negative percentage`);
+  }
+
+  const ccusage = parseCcusageExport(await readFile(file, 'utf-8'), file);
+  const range = getCcusageRange(ccusage);
+  if (!range) {
+    console.log(`${file} contains no days to compare`);
+    return;
+  }
+
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(
+    range.start,
+    range.end,
+    undefined,
+    getLoadOptions(settings)
+  );
+
+  const rows = reconcileDaily(calculateDailyTokens(timelines), ccusage, tolerance / 100);
+  console.log(formatReconciliation(rows));
+}
@@ -0,0 +1,20 @@
+import { formatRepairResult, repairLogFile } from '../../core/repair';
+import { formatValidationReport, validateLogFile } from '../../core/validate';
+
+export interface RepairCommandOptions {
+  file: string[];
+}
+
+export async function repairCommand(options: RepairCommandOptions): Promise<void> {
+  const now = new Date();
+  for (const path of options.file) {
+    const { output, ...result } = await repairLogFile(path);
+    console.log(formatRepairResult(path, result, output));
+
+    // Whatever is left, e.g. implausible timestamps, is reported but not changed
+    const report = await validateLogFile(output, now);
+    if (report.issues.length > 0) {
+      console.log(formatValidationReport([report]));
+    }
+  }
+}
@@ -0,0 +1,239 @@
+import { startOfDay } from 'date-fns';
+import { mkdir, writeFile } from 'fs/promises';
+import { dirname, join } from 'path';
+import { createUsageServer } from '../../core/serve';
+import { clearRepositoryCache, loadTimelines } from '../../core/parser';
+import { createControlServer, listenOnSocket } from '../../core/control';
+import { COLLECTOR_TOKEN_ENV, loadSubmissions } from '../../core/collector';
+import { formatTeamReport, mergeUsageExports } from '../../core/merge';
+import {
+  parseDailyTime,
+  parseScheduleEntry,
+  ScheduledTask,
+  ScheduleEntry,
+  startScheduler,
+} from '../../core/scheduler';
+import { getActivityStatus, getStatusRange, getStatusSnapshotPath } from '../../core/status';
+import { formatDailySummary } from '../../core/summary';
+import { Timeline } from '../../models/models';
+import { getDataDir, loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { sendDesktopNotification } from '../../utils/notify';
+import { calculateStartTime } from '../../utils/timeRange';
+import { buildUsageExport } from './export';
+import { anonymousUserId, sendUsageExport } from './push';
+import { refreshStatusSnapshot } from './status';
+
+export interface ServeCommandOptions {
+  port: string;
+  host: string;
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  collector?: boolean;
+  dataDir: string;
+  // Shared secret required from clients, also sent with scheduled pushes
+  token?: string;
+  schedule: ScheduleEntry[];
+  snapshotDir: string;
+  endpoint?: string;
+  webhook?: string;
+  user?: string;
+  summaryAt?: string;
+  notify?: boolean;
+  // Control socket path, or true for the default one in the data directory
+  socket?: string | boolean;
+}
+
+// Timelines loaded like every other report, with the config read on each call so a long-running
+// server follows changes to it
+async function loadConfiguredTimelines(startTime: Date, endTime: Date): Promise<Timeline[]> {
+  return loadTimelines(startTime, endTime, undefined, getLoadOptions(await loadSettings()));
+}
+
+// Slack-compatible incoming webhook payload
+function parseConfigSchedule(entries: string[] = []): ScheduleEntry[] {
+  return entries.map(text => {
+    const entry = parseScheduleEntry(text);
+    if (!entry) throw new Error(`Invalid schedule '${text}' in the config file`);
+    return entry;
+  });
+}
+
+async function postToWebhook(webhook: string, text: string): Promise<void> {
+  const response = await fetch(webhook, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ text: '```\n' + text + '\n```' }),
+  });
+  if (!response.ok) {
+    throw new Error(`Webhook responded with ${response.status} ${response.statusText}`);
+  }
+}
+
+async function runScheduledTask(task: ScheduledTask, options: ServeCommandOptions): Promise<void> {
+  switch (task) {
+    case 'snapshot': {
+      const data = await buildUsageExport(options);
+      await mkdir(options.snapshotDir, { recursive: true });
+      const fileName = `ccstat-${data.generatedAt.replace(/[:.]/g, '-')}.json`;
+      await writeFile(join(options.snapshotDir, fileName), JSON.stringify(data, null, 2));
+      break;
+    }
+
+    case 'push': {
+      if (!options.endpoint) throw new Error('push requires --endpoint');
+      const data = await buildUsageExport({
+        ...options,
+        user: options.user ?? anonymousUserId(),
+      });
+      await sendUsageExport(options.endpoint, data, options.token);
+      break;
+    }
+
+    case 'report': {
+      if (!options.webhook) throw new Error('report requires --webhook');
+      // Collectors report on the whole team, otherwise on this machine only
+      const exports = options.collector
+        ? await loadSubmissions(options.dataDir)
+        : [{ name: options.user ?? 'me', data: await buildUsageExport(options) }];
+      await postToWebhook(options.webhook, formatTeamReport(mergeUsageExports(exports)));
+      break;
+    }
+
+    case 'summary': {
+      const now = new Date();
+      const text = formatDailySummary(await loadConfiguredTimelines(startOfDay(now), now), now);
+
+      // Always printed; notification and webhook are opt-in
+      console.log(text);
+      if (options.notify) {
+        const [title, ...details] = text.split('\n');
+        await sendDesktopNotification(title, details.join('\n'));
+      }
+      if (options.webhook) await postToWebhook(options.webhook, text);
+      break;
+    }
+  }
+}
+
+// How often the editor status snapshot is rewritten while the control socket is up; well within
+// SNAPSHOT_MAX_AGE_SECONDS, so `ccstat status --project` never has to parse logs itself
+const STATUS_SNAPSHOT_INTERVAL_MS = 30 * 1000;
+
+function keepStatusSnapshotFresh(): void {
+  const refresh = () =>
+    refreshStatusSnapshot(new Date()).catch(error => {
+      console.error('Status snapshot failed:', error instanceof Error ? error.message : error);
+    });
+  refresh();
+  setInterval(refresh, STATUS_SNAPSHOT_INTERVAL_MS);
+}
+
+// Answer `status`, `reload` and `report <range>` over a unix socket, for editors and scripts that
+// would otherwise start a fresh ccstat each time
+async function startControlSocket(options: ServeCommandOptions): Promise<string> {
+  const path =
+    typeof options.socket === 'string' ? options.socket : join(getDataDir(), 'ccstat.sock');
+  await mkdir(dirname(path), { recursive: true });
+
+  const startedAt = new Date();
+  let reloadedAt: Date | null = null;
+  const server = createControlServer({
+    status: async () => {
+      const now = new Date();
+      const { startTime, endTime } = getStatusRange(now);
+      return {
+        pid: process.pid,
+        startedAt: startedAt.toISOString(),
+        uptimeSeconds: Math.round((now.getTime() - startedAt.getTime()) / 1000),
+        reloadedAt: reloadedAt?.toISOString() ?? null,
+        activity: getActivityStatus(await loadConfiguredTimelines(startTime, endTime), now),
+      };
+    },
+    reload: async () => {
+      clearRepositoryCache();
+      reloadedAt = new Date();
+      return { ok: true };
+    },
+    report: range =>
+      buildUsageExport({
+        ...options,
+        days: `${range.days ?? 1}`,
+        hours: range.hours !== undefined ? `${range.hours}` : undefined,
+        allTime: range.allTime,
+      }),
+  });
+
+  await listenOnSocket(server, path);
+  keepStatusSnapshotFresh();
+  return path;
+}
+
+export async function serveCommand(options: ServeCommandOptions): Promise<void> {
+  const token = options.token ?? process.env[COLLECTOR_TOKEN_ENV];
+  // Anyone who can reach a collector could otherwise read the team report and overwrite
+  // submissions
+  if (options.collector && !token) {
+    throw new Error(
+      `--collector requires a shared token: pass --token or set ${COLLECTOR_TOKEN_ENV}`
+    );
+  }
+
+  const server = createUsageServer({
+    loadExport: () => buildUsageExport(options),
+    loadTimelines: loadConfiguredTimelines,
+    searchRange: () => {
+      const endTime = new Date();
+      return { startTime: calculateStartTime(endTime, 30), endTime };
+    },
+    collector: options.collector,
+    dataDir: options.dataDir,
+    token,
+  });
+
+  const port = parseInt(options.port);
+  await new Promise<void>(resolve => server.listen(port, options.host, resolve));
+
+  console.log(`ccstat serving on http://${options.host}:${port}`);
+  if (options.collector) {
+    console.log(' - POST /api/v1/usage   accept pushed summaries');
+    console.log(' - GET  /api/v1/team    merged team report');
+    console.log(`Submissions are stored in ${options.dataDir}`);
+  } else {
+    console.log(' - GET  /api/v1/usage   local usage export');
+    console.log(' - GET  /api/v1/status  sessions active in the last few minutes');
+    console.log(' - POST /grafana/*      Grafana SimpleJSON/Infinity datasource');
+  }
+  if (token) console.log('Requests must send "Authorization: Bearer <token>"');
+  if (options.socket) {
+    const socketPath = await startControlSocket(options);
+    console.log(`Control socket at ${socketPath} (status, reload, report <7d|12h|all>)`);
+    console.log(`Status snapshot kept fresh at ${getStatusSnapshotPath()}`);
+  }
+
+  const settings = await loadSettings();
+  const summaryAt = options.summaryAt ?? settings.summaryAt;
+  // Entries given with --schedule replace those in the config file
+  const schedule =
+    options.schedule.length > 0 ? [...options.schedule] : parseConfigSchedule(settings.schedule);
+  if (summaryAt) {
+    const entry = parseDailyTime(summaryAt, 'summary');
+    if (!entry) throw new Error(`Invalid summary time '${summaryAt}'. Use HH:MM, e.g. 18:30`);
+    schedule.push(entry);
+  }
+
+  if (schedule.length > 0) {
+    startScheduler(
+      schedule,
+      task => runScheduledTask(task, options),
+      (entry, error) => {
+        const message = error instanceof Error ? error.message : error;
+        console.error(`Scheduled ${entry.task} (${entry.expression}) failed:`, message);
+      }
+    );
+    for (const entry of schedule) {
+      console.log(`Scheduled ${entry.task} at "${entry.expression}"`);
+    }
+  }
+}
@@ -0,0 +1,39 @@
+import { loadTimelines } from '../../core/parser';
+import { formatSessionList, groupSessions, resolveSessionTitles } from '../../core/sessions';
+import { calculateStartTime } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+
+export interface SessionsCommandOptions {
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  project?: string[];
+  mergeContinued?: boolean;
+}
+
+export async function sessionsCommand(options: SessionsCommandOptions): Promise<void> {
+  const endTime = options.allTime ? undefined : new Date();
+  const startTime = endTime
+    ? calculateStartTime(
+        endTime,
+        options.hours ? undefined : parseInt(options.days),
+        options.hours ? parseInt(options.hours) : undefined
+      )
+    : undefined;
+
+  const settings = await loadSettings();
+  const summaries = new Map<string, string>();
+  let timelines = await loadTimelines(startTime, endTime, undefined, {
+    ...getLoadOptions(settings),
+    summaries,
+  });
+  if (options.project && options.project.length > 0) {
+    timelines = timelines.filter(t => matchesProjectName(options.project!, t.projectName));
+  }
+
+  const titles = resolveSessionTitles(timelines, summaries);
+  const sessions = groupSessions(timelines, titles, options.mergeContinued);
+  console.log(formatSessionList(sessions));
+}
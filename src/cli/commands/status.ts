@@ -0,0 +1,85 @@
+import { startOfDay } from 'date-fns';
+import { getHookEventsPath, loadHookTimelines, mergeTimelines } from '../../core/hooks';
+import { getQualifiedName } from '../../core/git';
+import { loadTimelines, resolveDirectoryRepository } from '../../core/parser';
+import {
+  createStatusSnapshot,
+  formatActivityStatus,
+  formatProjectStatus,
+  getActivityStatus,
+  getProjectDayStats,
+  getStatusRange,
+  getStatusSnapshotPath,
+  ProjectDayStats,
+  readStatusSnapshot,
+  StatusSnapshot,
+  writeStatusSnapshot,
+} from '../../core/status';
+import { Timeline } from '../../models/models';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface StatusCommandOptions {
+  idle: string;
+  json?: boolean;
+  // Directory inside the project to report today's totals for
+  project?: string;
+}
+
+async function loadStatusTimelines(startTime: Date, endTime: Date): Promise<Timeline[]> {
+  const settings = await loadSettings();
+  const timelines = await loadTimelines(startTime, endTime, undefined, getLoadOptions(settings));
+  // Hook events arrive as they happen, before the session log is flushed
+  const hookTimelines = await loadHookTimelines(
+    getHookEventsPath(),
+    startTime,
+    endTime,
+    settings.ignore
+  );
+  return mergeTimelines(timelines, hookTimelines);
+}
+
+// Today's snapshot, recomputed and saved when the cached one is missing or stale
+export async function refreshStatusSnapshot(
+  now: Date,
+  idleMinutes?: number
+): Promise<StatusSnapshot> {
+  const timelines = await loadStatusTimelines(startOfDay(now), now);
+  const snapshot = createStatusSnapshot(timelines, now, idleMinutes);
+  await writeStatusSnapshot(getStatusSnapshotPath(), snapshot);
+  return snapshot;
+}
+
+// Today's totals for the project containing a directory, from the snapshot when it is fresh
+export async function loadProjectDayStats(
+  directory: string,
+  now: Date,
+  idleMinutes?: number
+): Promise<ProjectDayStats> {
+  const settings = await loadSettings();
+  const repository = await resolveDirectoryRepository(directory, getLoadOptions(settings));
+  const snapshot =
+    (await readStatusSnapshot(getStatusSnapshotPath(), now)) ??
+    (await refreshStatusSnapshot(now, idleMinutes));
+  return getProjectDayStats(snapshot, repository.name, getQualifiedName(repository));
+}
+
+export async function statusCommand(options: StatusCommandOptions): Promise<void> {
+  const idleMinutes = parseInt(options.idle);
+  if (!(idleMinutes > 0)) {
+    throw new Error(`Invalid idle minutes '${options.idle}'. Must be a positive number`);
+  }
+
+  const now = new Date();
+
+  if (options.project) {
+    const stats = await loadProjectDayStats(options.project, now, idleMinutes);
+    console.log(options.json ? JSON.stringify(stats) : formatProjectStatus(stats));
+    return;
+  }
+
+  const { startTime, endTime } = getStatusRange(now);
+  const timelines = await loadStatusTimelines(startTime, endTime);
+  const status = getActivityStatus(timelines, now, idleMinutes);
+  console.log(options.json ? JSON.stringify(status) : formatActivityStatus(status));
+}
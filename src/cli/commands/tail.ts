@@ -0,0 +1,40 @@
+import { createTailEntries, formatTailEntry, readAppendedEvents } from '../../core/tail';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+
+export interface TailCommandOptions {
+  project?: string[];
+  interval: string;
+}
+
+export async function tailCommand(options: TailCommandOptions): Promise<void> {
+  const seconds = parseFloat(options.interval);
+  if (!(seconds > 0)) {
+    throw new Error(`Invalid interval '${options.interval}'. Must be a positive number`);
+  }
+
+  const settings = await loadSettings();
+  const loadOptions = getLoadOptions(settings);
+  const offsets = new Map<string, number>();
+  await readAppendedEvents(offsets, loadOptions, true);
+
+  const poll = async () => {
+    const directoryEventMap = await readAppendedEvents(offsets, loadOptions);
+    const entries = await createTailEntries(directoryEventMap, options.project, loadOptions);
+    for (const entry of entries) {
+      console.log(formatTailEntry(entry));
+    }
+  };
+
+  // Polls are sequential so a slow read never prints the same lines twice
+  const loop = () => {
+    setTimeout(() => {
+      poll()
+        .catch(error => {
+          console.error('Error:', error instanceof Error ? error.message : error);
+        })
+        .finally(loop);
+    }, seconds * 1000);
+  };
+  loop();
+}
@@ -0,0 +1,86 @@
+import { loadTimelines } from '../../core/parser';
+import { readDailyAggregates } from '../../core/store';
+import {
+  DEFAULT_ANOMALY_THRESHOLD,
+  findProjectAnomalies,
+  formatAnomalies,
+  markAnomalousRows,
+} from '../../core/trend/anomalies';
+import {
+  createTrendRows,
+  formatTrend,
+  getHistoryStart,
+  getTrendPeriods,
+  parseSmoothingWindow,
+} from '../../core/trend';
+import { SparklineRenderMode } from '../../ui/renderModes';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+
+export interface TrendCommandOptions {
+  weeks: string;
+  days?: string;
+  project?: string[];
+  smooth?: string;
+  anomalyThreshold?: string;
+  render?: SparklineRenderMode;
+}
+
+export async function trendCommand(options: TrendCommandOptions): Promise<void> {
+  const unit = options.days ? 'day' : 'week';
+  const value = options.days ?? options.weeks;
+  const count = parseInt(value);
+  if (!(count > 0)) {
+    throw new Error(`Invalid ${unit} count '${value}'. Must be a positive number`);
+  }
+
+  const smoothDays = options.smooth ? parseSmoothingWindow(options.smooth) : undefined;
+  if (smoothDays === null) {
+    throw new Error(`Invalid smoothing window '${options.smooth}'. Use days or weeks, e.g. 7d`);
+  }
+
+  const threshold = options.anomalyThreshold
+    ? parseFloat(options.anomalyThreshold)
+    : DEFAULT_ANOMALY_THRESHOLD;
+  if (!(threshold > 0)) {
+    throw new Error(
+      `Invalid anomaly threshold '${options.anomalyThreshold}'. Must be a positive number`
+    );
+  }
+
+  const now = new Date();
+  const periods = getTrendPeriods(unit, count, now);
+  const settings = await loadSettings();
+  const loadOptions = getLoadOptions(settings);
+  const start = getHistoryStart(periods, smoothDays);
+  const end = periods[periods.length - 1].end;
+  // Compacted days are added back from their daily totals below
+  const loaded = await loadTimelines(start, end, undefined, {
+    ...loadOptions,
+    onCompactedRange: undefined,
+  });
+
+  // Days the store compacted and archives backfilled only exist as daily totals
+  const inProjects = (projectName: string) =>
+    !options.project?.length || matchesProjectName(options.project, projectName);
+  const timelines = loaded.filter(t => inProjects(t.projectName));
+  const aggregates = (await readDailyAggregates(loaded, loadOptions.store)).filter(a =>
+    inProjects(a.projectName)
+  );
+
+  const vacations = settings.vacations;
+  const rows = createTrendRows(timelines, periods, unit, {
+    smoothDays,
+    now,
+    aggregates,
+    vacations,
+  });
+  console.log(formatTrend(markAnomalousRows(rows, threshold), options.render === 'braille'));
+
+  const anomalies = findProjectAnomalies(timelines, periods, unit, threshold, vacations);
+  if (anomalies.length > 0) {
+    console.log('');
+    console.log(formatAnomalies(anomalies));
+  }
+}
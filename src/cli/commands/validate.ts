@@ -0,0 +1,37 @@
+import { resolve } from 'path';
+import { discoverLogFiles } from '../../core/parser';
+import { formatValidationReport, validateLogFile } from '../../core/validate';
+import { loadSettings } from '../../utils/config';
+import { EXIT_INVALID_LOGS } from '../../utils/exitCodes';
+
+export interface ValidateCommandOptions {
+  json?: boolean;
+  verbose?: boolean;
+}
+
+// Check the given JSONL files, or every Claude log file; exits with EXIT_INVALID_LOGS when any
+// has problems so archiving scripts can stop
+export async function validateCommand(
+  files: string[],
+  options: ValidateCommandOptions
+): Promise<void> {
+  const paths =
+    files.length > 0
+      ? files.map(file => resolve(file))
+      : Array.from((await discoverLogFiles((await loadSettings()).logDirs)).keys()).sort();
+
+  const now = new Date();
+  const reports = [];
+  for (const path of paths) {
+    reports.push(await validateLogFile(path, now));
+  }
+
+  console.log(
+    options.json
+      ? JSON.stringify(reports, null, 2)
+      : formatValidationReport(reports, options.verbose)
+  );
+  if (reports.some(report => report.issues.length > 0)) {
+    process.exitCode = EXIT_INVALID_LOGS;
+  }
+}
@@ -0,0 +1,24 @@
+import { collectEnvironmentReport, formatEnvironmentReport } from '../../core/environment';
+import { getConfigPath, getDataDir, loadSettings, Settings } from '../../utils/config';
+
+// `ccstat --version --verbose`: the version plus an environment report for bug filing
+export async function versionCommand(version: string, profile?: string): Promise<void> {
+  let settings: Settings = {};
+  let configError: string | undefined;
+  try {
+    settings = await loadSettings(profile);
+  } catch (error) {
+    // A broken config is exactly what the report should show, not a reason to fail
+    configError = error instanceof Error ? error.message : String(error);
+  }
+
+  const report = await collectEnvironmentReport({
+    version,
+    configPath: getConfigPath(),
+    configError,
+    dataDir: getDataDir(),
+    profile: profile ?? process.env.CCSTAT_PROFILE,
+    logDirs: settings.logDirs,
+  });
+  console.log(formatEnvironmentReport(report));
+}
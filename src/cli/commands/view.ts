@@ -0,0 +1,17 @@
+import { getConfigPath, loadConfig } from '../../utils/config';
+
+// Running a view is handled before parsing, see expandViewArgs in the CLI entry point
+export async function viewCommand(): Promise<void> {
+  const config = await loadConfig();
+  const views = Object.entries(config.views ?? {});
+
+  if (views.length === 0) {
+    console.log(`No views defined in ${getConfigPath()}`);
+    return;
+  }
+
+  console.log(`Views (${getConfigPath()}):`);
+  for (const [name, args] of views) {
+    console.log(` - ${name}: ${args.join(' ')}`);
+  }
+}
@@ -0,0 +1,48 @@
+import { loadTimelines } from '../../core/parser';
+import {
+  formatWorkingHoursReport,
+  parseWorkingHours,
+  splitByWorkingHours,
+} from '../../core/workingHours';
+import { calculateStartTime } from '../../utils/timeRange';
+import { loadSettings } from '../../utils/config';
+import { getLoadOptions } from '../../utils/loadOptions';
+import { matchesProjectName } from '../../utils/projectNames';
+
+export interface WorkingHoursCommandOptions {
+  days: string;
+  hours?: string;
+  allTime?: boolean;
+  project?: string[];
+  window?: string;
+}
+
+const DEFAULT_START = '09:00';
+const DEFAULT_END = '18:00';
+
+export async function workingHoursCommand(options: WorkingHoursCommandOptions): Promise<void> {
+  const settings = await loadSettings();
+  const configured = settings.workingHours;
+  const window =
+    options.window ?? `${configured?.start ?? DEFAULT_START}-${configured?.end ?? DEFAULT_END}`;
+  const workingHours = parseWorkingHours(window, configured?.days);
+  if (!workingHours) {
+    throw new Error(`Invalid working hours '${window}'. Use HH:MM-HH:MM, e.g. 09:00-18:00`);
+  }
+
+  const endTime = options.allTime ? undefined : new Date();
+  const startTime = endTime
+    ? calculateStartTime(
+        endTime,
+        options.hours ? undefined : parseInt(options.days),
+        options.hours ? parseInt(options.hours) : undefined
+      )
+    : undefined;
+
+  let timelines = await loadTimelines(startTime, endTime, undefined, getLoadOptions(settings));
+  if (options.project && options.project.length > 0) {
+    timelines = timelines.filter(t => matchesProjectName(options.project!, t.projectName));
+  }
+
+  console.log(formatWorkingHoursReport(splitByWorkingHours(timelines, workingHours), workingHours));
+}
@@ -1,9 +1,95 @@
 #!/usr/bin/env node
+import { spawnSync } from 'child_process';
 import { Command } from 'commander';
 import { render } from 'ink';
 import React from 'react';
 import { App } from '../ui/App';
 import { isValidColorTheme, COLOR_THEME_VALUES } from '../ui/colorThemes';
+import {
+  isValidRenderMode,
+  isValidSparklineRenderMode,
+  RENDER_MODE_VALUES,
+  SPARKLINE_RENDER_MODE_VALUES,
+} from '../ui/renderModes';
+import { parseHourRange } from '../ui/utils/tableUtils';
+import { isValidTimestampPolicy, TIMESTAMP_POLICY_VALUES } from '../utils/timestampSanity';
+import {
+  getConfigPath,
+  loadConfig,
+  loadSettings,
+  resolveView,
+  setActiveProfile,
+} from '../utils/config';
+import { isValidConfigOperation, CONFIG_OPERATION_VALUES } from '../utils/configKeys';
+import { getLoadOptions } from '../utils/loadOptions';
+import { isValidGroupBy, GROUP_BY_VALUES } from '../utils/grouping';
+import { isValidSessionMode, SESSION_MODE_VALUES } from '../utils/sessionModes';
+import { getEditorCommand } from '../utils/projectActions';
+import { isValidExportFormat, EXPORT_FORMAT_VALUES } from '../core/export/formats';
+import {
+  DURATION_ALGORITHM_VALUES,
+  isValidDurationAlgorithm,
+  setDurationModel,
+} from '../core/duration';
+import { isValidNestedRepositoryMode, NESTED_REPOSITORY_VALUES } from '../core/git';
+import { getAnnotationsPath } from '../core/annotations';
+import { ACTIVITYWATCH_DEFAULT_URL } from '../core/export/activitywatch';
+import { DEFAULT_CALENDAR_DAYS } from '../core/calendar';
+import { DEFAULT_PROMPT_LIMIT } from '../core/commitContext';
+import { getOnboardingMarkerPath, shouldOnboard } from '../core/onboarding';
+import { DEFAULT_PR_PROMPT_LIMIT } from '../core/prSummary';
+import {
+  DEFAULT_PROMPT_INTERVAL_SECONDS,
+  isValidPromptShell,
+  PROMPT_SHELL_VALUES,
+} from '../core/prompt';
+import { parseScheduleEntry, ScheduleEntry, SCHEDULED_TASK_VALUES } from '../core/scheduler';
+import { isValidEventRole, EVENT_ROLE_VALUES } from '../core/tail';
+import { isValidGraphicsProtocol, GRAPHICS_PROTOCOL_VALUES } from '../utils/terminalGraphics';
+import { isValidOutputFormat, OUTPUT_FORMAT_VALUES } from '../utils/outputFormats';
+import { activityWatchCommand } from './commands/activitywatch';
+import { annotateCommand } from './commands/annotate';
+import { backfillCommand } from './commands/backfill';
+import { benchCommand } from './commands/bench';
+import { blocksCommand } from './commands/blocks';
+import { calendarCommand } from './commands/calendar';
+import { calibrateCommand } from './commands/calibrate';
+import { commitContextCommand } from './commands/commitContext';
+import { compactCommand } from './commands/compact';
+import { configCommand } from './commands/config';
+import { compareProjectsCommand } from './commands/compareProjects';
+import { contextCommand } from './commands/context';
+import { eventsCommand } from './commands/events';
+import { invoiceCommand } from './commands/invoice';
+import { latencyCommand } from './commands/latency';
+import { pinCommand } from './commands/pin';
+import { prSummaryCommand } from './commands/prSummary';
+import { promptCommand } from './commands/prompt';
+import { exportCommand } from './commands/export';
+import { forecastCommand } from './commands/forecast';
+import { hookCommand } from './commands/hook';
+import { initCommand } from './commands/init';
+import { ingestCommand } from './commands/ingest';
+import { mcpStatsCommand } from './commands/mcpStats';
+import { mergeCommand } from './commands/merge';
+import { projectionCommand } from './commands/projection';
+import { pushCommand } from './commands/push';
+import { reconcileCommand } from './commands/reconcile';
+import { repairCommand } from './commands/repair';
+import { serveCommand } from './commands/serve';
+import { sessionsCommand } from './commands/sessions';
+import { statusCommand } from './commands/status';
+import { tailCommand } from './commands/tail';
+import { trendCommand } from './commands/trend';
+import { validateCommand } from './commands/validate';
+import { versionCommand } from './commands/version';
+import { viewCommand } from './commands/view';
+import { workingHoursCommand } from './commands/workingHours';
+
+const VERSION = '2.0.5';
+
+const ENTER_ALTERNATE_SCREEN = '\u001B[?1049h\u001B[H';
+const LEAVE_ALTERNATE_SCREEN = '\u001B[?1049l';
 
 const program = new Command();
 
@@ -13,16 +99,531 @@ program
   .option('-d, --days <number>', 'display activity for the last N days', '1')
   .option('-H, --hours <number>', 'display activity for the last N hours')
   .option('-c, --color <theme>', 'color theme: forest, ocean, sunset, violet', 'forest')
-  .option('-s --sort <field>', 'sort by field: project, timeline, events, duration', 'timeline')
+  .option('--color-by-project', 'give each project its own hue (intensity still shows density)')
+  .option('--accessible', 'describe activity periods and peaks in text instead of the timeline')
+  .option('--heat-names', 'tint project names by recency: bright if recently active, dim if not')
+  .option(
+    '-s --sort <field>',
+    'sort by field: project, timeline, events, duration, hot (recent events weigh more)',
+    'timeline'
+  )
   .option('-r, --reverse', 'reverse sort order (default: ascending)')
   .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('-t, --tag <tags...>', 'filter by project tags from the config file')
+  .option(
+    '--group-by <field>',
+    'rows per: project, worktree, directory, session, mode, tag, none',
+    'project'
+  )
+  .option('-w, --worktree', 'one row per worktree, same as --group-by worktree')
+  .option('--session-mode <mode>', 'only count sessions that are: interactive, agentic')
   .option('-a, --all-time', 'display all session history across all time periods')
-  .version('2.0.5')
-  .parse(process.argv);
+  .option('--ignore <patterns...>', 'skip sessions in these directories or globs, e.g. "/tmp/**"')
+  .option(
+    '--render <mode>',
+    'timeline rendering: block, half-block (2x resolution), braille (2x, dot height shows density)',
+    'block'
+  )
+  .option('--emoji', 'emoji timeline that keeps its colors when pasted, same as --render emoji')
+  .option('--overview', 'add a top row aggregating activity across all projects')
+  .option('--last-prompt', 'show a redacted preview of the latest prompt per project')
+  .option('--no-trend', 'skip comparing each project with the preceding window of the same length')
+  .option('-i, --interactive', 'select projects with the keyboard to open or copy their paths')
+  .option('--gridlines', 'draw dimmed markers in the timeline under each axis tick')
+  .option('--shade-weekends', 'shade weekend columns in multi-day ranges')
+  .option('--off-hours <range>', 'shade off-hours columns, e.g. 19-8 (local hours)')
+  .option('--stack-weeks', 'render ranges over a week as one row per week')
+  .option('--profile <name>', 'use a named profile from the config file')
+  .option('--width <number>', 'force rendering width instead of detecting terminal size')
+  .option('--format <format>', 'output: table, compact-json (today, for scripts)', 'table')
+  .option('--strict', 'fail on malformed log lines instead of skipping them')
+  .option('--fail-if-no-activity', 'exit with code 2 when the range has no activity')
+  .option('--fail-over-budget <minutes>', 'exit with code 3 when active minutes exceed the budget')
+  .option('--bad-timestamps <mode>', 'out-of-range timestamps: warn, clamp, exclude', 'warn')
+  .option('--nested-repos <mode>', 'group repositories inside another by: innermost, outermost')
+  .option('--user-events-only', 'count only your own prompts, not assistant or tool events')
+  .option('--duration-model <algorithm>', 'estimate active minutes by: interval, buckets, events')
+  .option('--demo', 'render synthesized demo data instead of reading Claude logs')
+  .option('--demo-projects <number>', 'number of projects to synthesize in demo mode', '6')
+  .option('--demo-seed <number>', 'random seed for reproducible demo data')
+  .option('--demo-events-per-day <number>', 'average events per project per day in demo mode')
+  .option('--demo-tokens <number>', 'average output tokens per assistant event in demo mode')
+  .option('--demo-worktrees <number>', 'number of worktrees per project in demo mode', '0')
+  .option('--verbose', 'with --version, also report config, data and log directories')
+  .version(VERSION)
+  .enablePositionalOptions()
+  .hook('preAction', configureRun)
+  .action(main);
+
+program
+  .command('bench')
+  .description('measure time and memory spent in each processing phase')
+  .option('-d, --days <number>', 'analyze activity for the last N days', '1')
+  .option('-H, --hours <number>', 'analyze activity for the last N hours')
+  .option('-a, --all-time', 'analyze all session history across all time periods')
+  .option('--cpu-profile <dir>', 'write a CPU profile and heap snapshot to the directory')
+  .action(options => runCommand(() => benchCommand(options)));
+
+program
+  .command('pin')
+  .description('pin projects to the top of the table, or list pinned projects')
+  .argument('[projects...]', 'project names to pin')
+  .option('-r, --remove', 'unpin the given projects instead')
+  .action((projects, options) => runCommand(() => pinCommand(projects, options)));
+
+program
+  .command('annotate')
+  .description('mark a moment such as a deployment on the timeline, or list the annotations')
+  .argument('[label]', 'text shown below the table, e.g. "deployed v2"')
+  .option('--at <time>', 'when it happened: 14:05, 2pm, 30m (ago) or "YYYY-MM-DD HH:MM"')
+  .action((label, options) => runCommand(() => annotateCommand(label, options)));
+
+program
+  .command('init')
+  .description('find Claude Code logs, create a config file with defaults and show the basics')
+  .option('-y, --yes', 'create the config file without asking')
+  .action(options => runCommand(() => initCommand(options)));
+
+program
+  .command('config')
+  .description('edit the config file in a form, or list, get, set, unset or edit its settings')
+  .argument('[operation]', `one of: ${CONFIG_OPERATION_VALUES.join(', ')}`)
+  .argument('[args...]', 'setting, e.g. duration.idleMinutes or profiles.work.color, and value')
+  .action(async (operation, args) => {
+    if (operation && !isValidConfigOperation(operation)) {
+      console.error(`Error: Invalid config operation '${operation}'.`);
+      console.error(`Available operations: ${CONFIG_OPERATION_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+
+    await runCommand(() => configCommand(operation, args));
+  });
+
+program
+  .command('view')
+  .description('run a named list of arguments from the config file, or list the views')
+  .argument('[name]', 'view to run; arguments after it are appended to the saved ones')
+  .action(() => runCommand(() => viewCommand()));
+
+program
+  .command('backfill')
+  .description('add daily totals from an archive of old session logs to the ccstat store')
+  .argument('<dirs...>', 'archived projects directories, laid out like ~/.claude/projects')
+  .option('--batch-size <number>', 'log files processed per checkpointed batch (default: 200)')
+  .option('--restart', 'drop the totals of earlier backfills and process every file again')
+  .action((dirs, options) => runCommand(() => backfillCommand(dirs, options)));
+
+program
+  .command('blocks')
+  .description('show how much of the active 5-hour block is used compared with earlier blocks')
+  .option('-d, --days <number>', 'days of history used to infer block capacity', '7')
+  .option('--bar-width <number>', 'width of the burn-down bars', '30')
+  .action(options => runCommand(() => blocksCommand(options)));
+
+program
+  .command('hook')
+  .description('record a Claude Code hook event (reads the hook JSON from stdin)')
+  .action(() => runCommand(() => hookCommand()));
+
+program
+  .command('ingest')
+  .description('copy new session log events into the ccstat store read by reports')
+  .option('-w, --watch [seconds]', 'keep ingesting every N seconds (default: 30)')
+  .option('--compact', 'replace old raw events with daily totals per project')
+  .option('--keep-days <number>', 'days of raw events kept by --compact (default: 90)')
+  .action(options => runCommand(() => ingestCommand(options)));
+
+program
+  .command('invoice')
+  .description('itemize daily duration and cost for tagged projects in a month')
+  .requiredOption('-t, --tag <tags...>', 'project tags to bill, e.g. client:acme')
+  .option('--month <month>', 'month to itemize as YYYY-MM (default: current month)')
+  .option('--rate <amount>', 'hourly rate used to compute amounts')
+  .option('--currency <code>', 'currency label for amounts', 'USD')
+  .action(options => runCommand(() => invoiceCommand(options)));
+
+program
+  .command('projection')
+  .description('project burn rate against a plan limit and when it will be reached')
+  .option('--tokens <number>', 'token limit per period (default: limit.tokens in config)')
+  .option('--hours <number>', 'active hours limit per period (default: limit.hours in config)')
+  .option('--period <period>', 'limit period: daily, weekly (default: limit.period or weekly)')
+  .option('-w, --watch [seconds]', 'refresh the projection every N seconds (default: 60)')
+  .action(options => runCommand(() => projectionCommand(options)));
+
+program
+  .command('reconcile')
+  .description('compare daily token counts with a ccusage export')
+  .argument('<file>', 'output of `ccusage daily --json`')
+  .option('--tolerance <percent>', 'allowed difference per day before flagging it', '1')
+  .action((file, options) => runCommand(() => reconcileCommand(file, options)));
+
+program
+  .command('calibrate')
+  .description('compare estimated durations with tracked time and suggest an idle threshold')
+  .argument('<file>', 'CSV with date, hours or minutes, and optionally project columns')
+  .action(file => runCommand(() => calibrateCommand(file)));
+
+program
+  .command('context')
+  .description('count sessions per project that neared the context limit or were compacted')
+  .option('-d, --days <number>', 'check sessions from the last N days', '7')
+  .option('-H, --hours <number>', 'check sessions from the last N hours')
+  .option('-a, --all-time', 'check sessions across all time periods')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('--window <tokens>', 'context window size in tokens (default: 200000)')
+  .action(options => runCommand(() => contextCommand(options)));
+
+program
+  .command('latency')
+  .description('report median and 95th percentile reply latency per project and model')
+  .option('-d, --days <number>', 'measure replies from the last N days', '7')
+  .option('-H, --hours <number>', 'measure replies from the last N hours')
+  .option('-a, --all-time', 'measure replies across all time periods')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .action(options => runCommand(() => latencyCommand(options)));
+
+program
+  .command('mcp-stats')
+  .description('count MCP tool calls per server and tool, and the projects making them')
+  .option('-d, --days <number>', 'count calls from the last N days', '7')
+  .option('-H, --hours <number>', 'count calls from the last N hours')
+  .option('-a, --all-time', 'count calls across all time periods')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .action(options => runCommand(() => mcpStatsCommand(options)));
+
+program
+  .command('events')
+  .description('list events in time order with a preview, e.g. what you asked this morning')
+  .option('-d, --days <number>', 'list events from the last N days', '1')
+  .option('-H, --hours <number>', 'list events from the last N hours')
+  .option('--since <time>', 'list events since a time (9am, 14:30), duration ago (2h) or date')
+  .option('-a, --all-time', 'list events across all time periods')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('--role <roles...>', 'only list events of these roles (space-separated)')
+  .action(async options => {
+    const invalid = (options.role ?? []).find((role: string) => !isValidEventRole(role));
+    if (invalid) {
+      console.error(`Error: Invalid role '${invalid}'.`);
+      console.error(`Available roles: ${EVENT_ROLE_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+
+    await runCommand(() => eventsCommand(options));
+  });
+
+program
+  .command('commit-context')
+  .description('summarize Claude activity in this repository since the last commit')
+  .option('--since <time>', 'start at a time (9am, 14:30), duration ago (2h) or date instead')
+  .option('--prompts <number>', `prompts to list (default: ${DEFAULT_PROMPT_LIMIT})`)
+  .option('--json', 'print the duration, sessions and every prompt as JSON')
+  .action(options => runCommand(() => commitContextCommand(options)));
+
+program
+  .command('pr-summary')
+  .description('summarize Claude sessions, prompts and tools on this branch for a PR description')
+  .requiredOption('--since <ref>', 'base branch or commit the branch started from, e.g. main')
+  .option('--prompts <number>', `prompts to list (default: ${DEFAULT_PR_PROMPT_LIMIT})`)
+  .option('--json', 'print the full structured summary as JSON')
+  .action(options => runCommand(() => prSummaryCommand(options)));
+
+program
+  .command('tail')
+  .description('print new events of the matching projects as they are logged')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('--interval <seconds>', 'seconds between checks for new events', '1')
+  .action(options => runCommand(() => tailCommand(options)));
+
+program
+  .command('compare-projects')
+  .description('compare the metrics and timelines of projects side by side')
+  .argument('<projects...>', 'two or more project names')
+  .option('-d, --days <number>', 'compare activity from the last N days', '7')
+  .option('-H, --hours <number>', 'compare activity from the last N hours')
+  .option('-a, --all-time', 'compare activity across all time periods')
+  .option('-w, --width <number>', 'width of the timelines in characters', '60')
+  .option('--render <mode>', 'sparkline rendering: block, braille (2 cells per character)')
+  .action(async (projects, options) => {
+    if (options.render && !isValidSparklineRenderMode(options.render)) {
+      console.error(`Error: Invalid render mode '${options.render}'.`);
+      console.error(`Available render modes: ${SPARKLINE_RENDER_MODE_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+
+    await runCommand(() => compareProjectsCommand(projects, options));
+  });
+
+program
+  .command('working-hours')
+  .description('split active time per project into working hours and after hours')
+  .option('-d, --days <number>', 'analyze activity from the last N days', '7')
+  .option('-H, --hours <number>', 'analyze activity from the last N hours')
+  .option('-a, --all-time', 'analyze activity across all time periods')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('--window <range>', 'working hours as HH:MM-HH:MM (default: config or 09:00-18:00)')
+  .action(options => runCommand(() => workingHoursCommand(options)));
+
+program
+  .command('sessions')
+  .description('list sessions with their conversation titles')
+  .option('-d, --days <number>', 'list sessions from the last N days', '1')
+  .option('-H, --hours <number>', 'list sessions from the last N hours')
+  .option('-a, --all-time', 'list sessions across all time periods')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('--merge-continued', 'list resumed sessions as one conversation, counting resumes')
+  .action(options => runCommand(() => sessionsCommand(options)));
+
+program
+  .command('validate')
+  .description('check Claude log files for invalid lines, missing or out-of-order timestamps')
+  .argument('[files...]', 'JSONL files to check (default: every Claude log file)')
+  .option('--json', 'print the report per file as JSON')
+  .option('--verbose', 'also list files without problems')
+  .action((files, options) => runCommand(() => validateCommand(files, options)));
+
+program
+  .command('repair')
+  .description('write a .repaired copy of log files without unreadable lines, in time order')
+  .requiredOption('--file <paths...>', 'JSONL log files to repair')
+  .action(options => runCommand(() => repairCommand(options)));
+
+program
+  .command('status')
+  .description('report whether a Claude session is working right now, and on which project')
+  .option('--idle <minutes>', 'minutes without events before a session counts as idle', '5')
+  .option('--json', 'print the status as JSON for scripts and status bars')
+  .option('--project <dir>', "today's totals for the project containing a directory, from a cache")
+  .action(options => runCommand(() => statusCommand(options)));
+
+program
+  .command('prompt')
+  .description("print today's active time in this repository for a shell prompt")
+  .option('--shell <shell>', 'print a prompt segment script to eval instead: zsh, bash, fish')
+  .option(
+    '--interval <seconds>',
+    `seconds between refreshes in the script (default: ${DEFAULT_PROMPT_INTERVAL_SECONDS})`
+  )
+  .option('--directory <dir>', 'directory inside the project (default: current directory)')
+  .action(async options => {
+    if (options.shell && !isValidPromptShell(options.shell)) {
+      console.error(`Error: Invalid shell '${options.shell}'.`);
+      console.error(`Available shells: ${PROMPT_SHELL_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+
+    await runCommand(() => promptCommand(options));
+  });
+
+program
+  .command('trend')
+  .description('show weekly or daily totals with sparklines over a long horizon')
+  .option('-w, --weeks <number>', 'one line per week for the last N weeks', '12')
+  .option('-d, --days <number>', 'one line per day for the last N days instead')
+  .option('--smooth <window>', 'add a rolling average of daily minutes, e.g. 7d or 2w')
+  .option('--anomaly-threshold <sigma>', 'std devs from the rest to flag a period (default: 3)')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('--render <mode>', 'sparkline rendering: block, braille (2 buckets per character)')
+  .action(async options => {
+    if (options.render && !isValidSparklineRenderMode(options.render)) {
+      console.error(`Error: Invalid render mode '${options.render}'.`);
+      console.error(`Available render modes: ${SPARKLINE_RENDER_MODE_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+
+    await runCommand(() => trendCommand(options));
+  });
+
+program
+  .command('calendar')
+  .description('show daily activity as a contribution calendar, one column per week')
+  .option('-d, --days <number>', 'show the last N days', `${DEFAULT_CALENDAR_DAYS}`)
+  .option('-c, --color <theme>', 'color theme: forest, ocean, sunset, violet', 'forest')
+  .option('-p, --project <names...>', 'filter by project names (space-separated)')
+  .option('--image [protocol]', 'draw an inline image: kitty, iterm (default: detect terminal)')
+  .action(async options => {
+    if (!isValidColorTheme(options.color)) {
+      console.error(`Error: Invalid color theme '${options.color}'.`);
+      console.error(`Available color themes: ${COLOR_THEME_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+    if (typeof options.image === 'string' && !isValidGraphicsProtocol(options.image)) {
+      console.error(`Error: Invalid image protocol '${options.image}'.`);
+      console.error(`Available image protocols: ${GRAPHICS_PROTOCOL_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+
+    await runCommand(() => calendarCommand(options));
+  });
+
+program
+  .command('forecast')
+  .description("estimate next week's duration, tokens and cost per project")
+  .option('-w, --weeks <number>', 'complete weeks of history to base the forecast on', '8')
+  .option('--method <method>', 'forecast method: average, linear', 'average')
+  .option('--price-per-mtok <amount>', 'price per million tokens used to estimate cost')
+  .option('--currency <code>', 'currency label for costs', 'USD')
+  .action(options => runCommand(() => forecastCommand(options)));
+
+program
+  .command('export')
+  .description('print aggregated project activity as JSON for sharing with ccstat merge')
+  .option('-d, --days <number>', 'export activity for the last N days', '1')
+  .option('-H, --hours <number>', 'export activity for the last N hours')
+  .option('-a, --all-time', 'export all session history across all time periods')
+  .option('--user <name>', 'name to record in the export (default: file name when merged)')
+  .option(
+    '--format <format>',
+    'output format: json, influx (line protocol, hourly), events (JSON per event), ' +
+      'github-readme or github-svg (weekly card for a profile README), ' +
+      'activitywatch (bucket import)',
+    'json'
+  )
+  .option('--directories', 'include event counts per working directory in JSON output')
+  .option('--schema', 'print the JSON Schema of the JSON output instead of exporting')
+  .action(async options => {
+    if (!isValidExportFormat(options.format)) {
+      console.error(`Error: Invalid export format '${options.format}'.`);
+      console.error(`Available formats: ${EXPORT_FORMAT_VALUES.join(', ')}`);
+      process.exit(1);
+    }
+
+    await runCommand(() => exportCommand(options));
+  });
+
+program
+  .command('merge')
+  .description('combine exports from several teammates into a team report')
+  .argument('<files...>', 'JSON files produced by ccstat export')
+  .option('--anonymous', 'replace member names with anonymous labels')
+  .action((files, options) => runCommand(() => mergeCommand(files, options)));
+
+program
+  .command('activitywatch')
+  .description('add per-project spans of Claude activity to a local ActivityWatch server')
+  .option('--url <url>', 'ActivityWatch server URL', ACTIVITYWATCH_DEFAULT_URL)
+  .option('-d, --days <number>', 'send activity from the last N days', '1')
+  .option('-H, --hours <number>', 'send activity from the last N hours')
+  .action(options => runCommand(() => activityWatchCommand(options)));
+
+program
+  .command('push')
+  .description('send an anonymized usage summary to a ccstat collector (opt-in)')
+  .requiredOption('--endpoint <url>', 'collector base URL, e.g. https://ccstat.example.com')
+  .option('-d, --days <number>', 'summarize activity for the last N days', '1')
+  .option('-H, --hours <number>', 'summarize activity for the last N hours')
+  .option('-a, --all-time', 'summarize all session history across all time periods')
+  .option('--user <name>', 'name to report instead of an anonymous machine id')
+  .option('--token <token>', 'shared secret of the collector (default: $CCSTAT_COLLECTOR_TOKEN)')
+  .option('--yes', 'send the listed summary without stopping to review it')
+  .action(options => runCommand(() => pushCommand(options)));
+
+program
+  .command('serve')
+  .description('serve usage data over HTTP, optionally collecting summaries from teammates')
+  .option('--port <number>', 'port to listen on', '7878')
+  .option('--host <address>', 'address to bind', '127.0.0.1')
+  .option('-d, --days <number>', 'serve activity for the last N days', '1')
+  .option('-H, --hours <number>', 'serve activity for the last N hours')
+  .option('-a, --all-time', 'serve all session history across all time periods')
+  .option('--collector', 'accept summaries sent with ccstat push')
+  .option('--data-dir <dir>', 'where the collector stores submissions', './ccstat-collector')
+  .option(
+    '--token <token>',
+    'shared secret clients must send, required with --collector (default: $CCSTAT_COLLECTOR_TOKEN)'
+  )
+  .option(
+    '--schedule <entry>',
+    `run a task on a cron schedule, e.g. "0 18 * * 5 report" (${SCHEDULED_TASK_VALUES.join(', ')})`,
+    collectScheduleEntry,
+    []
+  )
+  .option('--snapshot-dir <dir>', 'where scheduled snapshots are written', './ccstat-snapshots')
+  .option('--endpoint <url>', 'collector base URL for scheduled pushes')
+  .option('--webhook <url>', 'Slack-compatible webhook URL for scheduled reports')
+  .option('--summary-at <time>', 'print an end-of-day summary daily at a local HH:MM time')
+  .option('--notify', 'also show the end-of-day summary as a desktop notification')
+  .option('--user <name>', 'name to report instead of an anonymous machine id')
+  .option('--socket [path]', 'also answer status, reload and report commands on a unix socket')
+  .action(options => runCommand(() => serveCommand(options)));
+
+function collectScheduleEntry(value: string, previous: ScheduleEntry[]): ScheduleEntry[] {
+  const entry = parseScheduleEntry(value);
+  if (!entry) {
+    console.error(`Error: Invalid schedule '${value}'.`);
+    console.error(`Use five cron fields followed by a task: ${SCHEDULED_TASK_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+  return [...previous, entry];
+}
+
+// Profiles and durations apply to every command, so both are set up front: subcommands load
+// settings for the profile given before them, and share one duration model
+async function configureRun() {
+  const { durationModel, profile } = program.opts();
+  setActiveProfile(profile);
+
+  if (durationModel && !isValidDurationAlgorithm(durationModel)) {
+    console.error(`Error: Invalid duration model '${durationModel}'.`);
+    console.error(`Available models: ${DURATION_ALGORITHM_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+
+  // Invalid settings are reported by the command itself
+  const settings = await loadSettings().catch(() => undefined);
+  setDurationModel({
+    ...settings?.duration,
+    ...(durationModel && { algorithm: durationModel }),
+  });
+}
+
+// Run a command, reporting a failure as a one-line error and exit code 1
+async function runCommand(command: () => Promise<unknown>): Promise<void> {
+  try {
+    await command();
+  } catch (error) {
+    console.error('Error:', error instanceof Error ? error.message : error);
+    process.exit(1);
+  }
+}
+
+function parseOptionalInt(value?: string): number | undefined {
+  return value !== undefined ? parseInt(value) : undefined;
+}
 
 async function main() {
   const options = program.opts();
 
+  // First run in a terminal: show what was found and offer a config file, then the table
+  if (
+    !options.demo &&
+    process.stdin.isTTY &&
+    process.stdout.isTTY &&
+    shouldOnboard(getConfigPath(), getOnboardingMarkerPath())
+  ) {
+    await runCommand(async () => {
+      await initCommand({});
+      console.log('');
+    });
+  }
+
+  // Load user configuration, applying the selected profile
+  const settings = await loadSettings().catch(error => {
+    console.error('Error:', error instanceof Error ? error.message : error);
+    return process.exit(1);
+  });
+
+  // Options left at their defaults fall back to the config file
+  if (program.getOptionValueSource('color') === 'default' && settings.color) {
+    options.color = settings.color;
+  }
+  if (program.getOptionValueSource('format') === 'default' && settings.format) {
+    options.format = settings.format;
+  }
+  if (options.userEventsOnly) {
+    settings.userEventsOnly = true;
+  }
+
   // Validate color theme
   if (!isValidColorTheme(options.color)) {
     console.error(`Error: Invalid color theme '${options.color}'.`);
@@ -30,6 +631,142 @@ async function main() {
     process.exit(1);
   }
 
+  // Validate render mode
+  if (options.emoji) {
+    options.render = 'emoji';
+  }
+  if (!isValidRenderMode(options.render)) {
+    console.error(`Error: Invalid render mode '${options.render}'.`);
+    console.error(`Available render modes: ${RENDER_MODE_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+
+  // Validate timestamp policy
+  if (!isValidTimestampPolicy(options.badTimestamps)) {
+    console.error(`Error: Invalid timestamp mode '${options.badTimestamps}'.`);
+    console.error(`Available modes: ${TIMESTAMP_POLICY_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+
+  // Validate nested repository grouping
+  if (options.nestedRepos && !isValidNestedRepositoryMode(options.nestedRepos)) {
+    console.error(`Error: Invalid nested repository mode '${options.nestedRepos}'.`);
+    console.error(`Available modes: ${NESTED_REPOSITORY_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+
+  // Validate off-hours range
+  const offHours = options.offHours ? parseHourRange(options.offHours) : undefined;
+  if (offHours === null) {
+    console.error(`Error: Invalid off-hours range '${options.offHours}'.`);
+    console.error('Use START-END in local hours, e.g. 19-8');
+    process.exit(1);
+  }
+
+  // Validate rendering width
+  const width = parseOptionalInt(options.width);
+  if (width !== undefined && (isNaN(width) || width <= 0)) {
+    console.error(`Error: Invalid width '${options.width}'. Width must be a positive integer.`);
+    process.exit(1);
+  }
+
+  // Validate budget
+  const budgetMinutes = parseOptionalInt(options.failOverBudget);
+  if (budgetMinutes !== undefined && (isNaN(budgetMinutes) || budgetMinutes < 0)) {
+    console.error(
+      `Error: Invalid budget '${options.failOverBudget}'. Must be a non-negative number of minutes.`
+    );
+    process.exit(1);
+  }
+
+  // Validate grouping
+  if (options.worktree) {
+    options.groupBy = 'worktree';
+  }
+  if (!isValidGroupBy(options.groupBy)) {
+    console.error(`Error: Invalid grouping '${options.groupBy}'.`);
+    console.error(`Available groupings: ${GROUP_BY_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+
+  // Validate session mode
+  if (options.sessionMode && !isValidSessionMode(options.sessionMode)) {
+    console.error(`Error: Invalid session mode '${options.sessionMode}'.`);
+    console.error(`Available modes: ${SESSION_MODE_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+
+  // Validate output format
+  if (!isValidOutputFormat(options.format)) {
+    console.error(`Error: Invalid output format '${options.format}'.`);
+    console.error(`Available formats: ${OUTPUT_FORMAT_VALUES.join(', ')}`);
+    process.exit(1);
+  }
+
+  // Validate demo options
+  if (options.demo && !(parseInt(options.demoProjects) > 0)) {
+    console.error(
+      `Error: Invalid demo project count '${options.demoProjects}'. Must be a positive integer.`
+    );
+    process.exit(1);
+  }
+  const demoSeed = parseOptionalInt(options.demoSeed);
+  if (options.demo && demoSeed !== undefined && isNaN(demoSeed)) {
+    console.error(`Error: Invalid demo seed '${options.demoSeed}'. Must be an integer.`);
+    process.exit(1);
+  }
+  const demoEventsPerDay = parseOptionalInt(options.demoEventsPerDay);
+  if (options.demo && demoEventsPerDay !== undefined && !(demoEventsPerDay > 0)) {
+    console.error(
+      `Error: Invalid demo event rate '${options.demoEventsPerDay}'. Must be a positive integer.`
+    );
+    process.exit(1);
+  }
+  const demoTokens = parseOptionalInt(options.demoTokens);
+  if (options.demo && demoTokens !== undefined && !(demoTokens >= 0)) {
+    console.error(
+      `Error: Invalid demo token count '${options.demoTokens}'. Must be a non-negative integer.`
+    );
+    process.exit(1);
+  }
+  if (options.demo && !(parseInt(options.demoWorktrees) >= 0)) {
+    console.error(
+      `Error: Invalid demo worktrees '${options.demoWorktrees}'. Must be a non-negative integer.`
+    );
+    process.exit(1);
+  }
+
+  const demo = options.demo
+    ? {
+        projects: parseInt(options.demoProjects),
+        seed: demoSeed,
+        eventsPerDay: demoEventsPerDay,
+        averageTokens: demoTokens,
+        worktrees: parseInt(options.demoWorktrees),
+      }
+    : undefined;
+
+  const exitConditions = {
+    failIfNoActivity: options.failIfNoActivity || false,
+    budgetMinutes,
+  };
+
+  if (options.format === 'compact-json') {
+    await runCommand(() => compactCommand(demo, settings, exitConditions));
+    return;
+  }
+
+  // Editors need the terminal, so they are launched only after the UI has exited
+  let editorPath: string | undefined;
+
+  // Interactive mode takes over the screen so mouse clicks map to fixed rows
+  const restoreScreen = () => {
+    if (options.interactive) process.stdout.write(LEAVE_ALTERNATE_SCREEN);
+  };
+  if (options.interactive) {
+    process.stdout.write(ENTER_ALTERNATE_SCREEN);
+  }
+
   const app = render(
     React.createElement(App, {
       days: options.hours ? undefined : parseInt(options.days),
@@ -39,15 +776,73 @@ async function main() {
       reverse: options.reverse || false,
       allTime: options.allTime || false,
       project: options.project || [],
+      width,
+      strict: options.strict || false,
+      timestampPolicy: options.badTimestamps,
+      renderMode: options.render,
+      stackWeeks: options.stackWeeks || false,
+      gridlines: options.gridlines || false,
+      colorByProject: options.colorByProject || false,
+      heatNames: options.heatNames || false,
+      accessible: options.accessible || false,
+      overview: options.overview || false,
+      lastPrompt: options.lastPrompt || false,
+      trend: options.trend,
+      interactive: options.interactive || false,
+      pinned: settings.pinned ?? [],
+      ignore: [...(settings.ignore ?? []), ...(options.ignore ?? [])],
+      logDirs: settings.logDirs,
+      store: getLoadOptions(settings).store,
+      gitFallback: settings.gitFallback,
+      nestedRepositories: options.nestedRepos ?? settings.nestedRepositories,
+      userEventsOnly: settings.userEventsOnly,
+      tags: options.tag || [],
+      sessionMode: options.sessionMode,
+      groupBy: options.groupBy,
+      projectTags: settings.tags ?? {},
+      annotationsPath: getAnnotationsPath(settings),
+      exitConditions,
+      onOpenInEditor: (path: string) => {
+        editorPath = path;
+      },
+      shading: { weekends: options.shadeWeekends || false, offHours },
+      demo,
     })
   );
 
   try {
     await app.waitUntilExit();
   } catch (error) {
+    restoreScreen();
     console.error('Error:', error);
     process.exit(1);
   }
+  restoreScreen();
+
+  if (editorPath) {
+    spawnSync(getEditorCommand(editorPath), { stdio: 'inherit', shell: true });
+  }
+}
+
+// `ccstat view <name> [args...]` runs as if the saved arguments had been typed in its place
+async function expandViewArgs(args: string[]): Promise<string[]> {
+  if (args[0] !== 'view' || !args[1]) return args;
+  return [...resolveView(await loadConfig(), args[1]), ...args.slice(2)];
 }
 
-main();
+// Commander exits on --version before other options are parsed, so look for --verbose first
+const args = process.argv.slice(2);
+if (args.includes('--verbose') && (args.includes('--version') || args.includes('-V'))) {
+  const profileIndex = args.indexOf('--profile');
+  versionCommand(VERSION, profileIndex >= 0 ? args[profileIndex + 1] : undefined).catch(error => {
+    console.error('Error:', error instanceof Error ? error.message : error);
+    process.exit(1);
+  });
+} else {
+  expandViewArgs(args)
+    .then(expanded => program.parseAsync(expanded, { from: 'user' }))
+    .catch(error => {
+      console.error('Error:', error instanceof Error ? error.message : error);
+      process.exit(1);
+    });
+}
@@ -0,0 +1,52 @@
+import { mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import {
+  appendAnnotation,
+  filterAnnotations,
+  loadAnnotations,
+  parseAnnotationTime,
+} from '../index';
+
+describe('annotations', () => {
+  let directory: string;
+
+  beforeEach(async () => {
+    directory = await mkdtemp(join(tmpdir(), 'ccstat-annotations-'));
+  });
+
+  afterEach(async () => {
+    await rm(directory, { recursive: true, force: true });
+  });
+
+  it('should read times as for --since, or a local date and time', () => {
+    const now = new Date(2025, 0, 10, 15, 0);
+    expect(parseAnnotationTime('14:05', now)).toEqual(new Date(2025, 0, 10, 14, 5));
+    expect(parseAnnotationTime('30m', now)).toEqual(new Date(2025, 0, 10, 14, 30));
+    expect(parseAnnotationTime('2025-01-08 9:30', now)).toEqual(new Date(2025, 0, 8, 9, 30));
+    expect(parseAnnotationTime('after lunch', now)).toBeNull();
+  });
+
+  it('should load appended annotations in time order, skipping bad lines', async () => {
+    const path = join(directory, 'data', 'annotations.jsonl');
+    await appendAnnotation(path, { timestamp: '2025-01-10T14:05:00.000Z', label: 'deployed v2' });
+    await appendAnnotation(path, { timestamp: '2025-01-10T09:00:00.000Z', label: 'standup' });
+    await writeFile(path, '{"label":"no time"}\nnot json\n', { flag: 'a' });
+
+    expect((await loadAnnotations(path)).map(a => a.label)).toEqual(['standup', 'deployed v2']);
+    expect(await loadAnnotations(join(directory, 'missing.jsonl'))).toEqual([]);
+  });
+
+  it('should keep annotations within the displayed range', () => {
+    const annotations = [
+      { timestamp: '2025-01-09T12:00:00.000Z', label: 'before' },
+      { timestamp: '2025-01-10T12:00:00.000Z', label: 'inside' },
+    ];
+    const filtered = filterAnnotations(
+      annotations,
+      new Date('2025-01-10T00:00:00Z'),
+      new Date('2025-01-11T00:00:00Z')
+    );
+    expect(filtered.map(a => a.label)).toEqual(['inside']);
+  });
+});
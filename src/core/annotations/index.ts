@@ -0,0 +1,70 @@
+import { appendFile, mkdir, readFile } from 'fs/promises';
+import { dirname, join } from 'path';
+import { z } from 'zod';
+import { getDataDir, Settings } from '../../utils/config';
+import { parseSince } from '../../utils/timeRange';
+
+// A point in time worth lining activity up against, such as a deployment or a meeting
+export const AnnotationSchema = z.object({
+  timestamp: z.string(),
+  label: z.string(),
+});
+
+export type Annotation = z.infer<typeof AnnotationSchema>;
+
+export function getAnnotationsPath(settings: Settings = {}): string {
+  return settings.annotationsFile ?? join(getDataDir(), 'annotations.jsonl');
+}
+
+// Parse --at: a clock time or duration ago as for --since, or a local "YYYY-MM-DD HH:MM"
+export function parseAnnotationTime(value: string, now = new Date()): Date | null {
+  const match = /^(\d{4})-(\d{2})-(\d{2})[T ](\d{1,2}):(\d{2})$/.exec(value.trim());
+  if (match) {
+    const [year, month, day, hour, minute] = match.slice(1).map(part => parseInt(part));
+    const date = new Date(year, month - 1, day, hour, minute);
+    return isNaN(date.getTime()) ? null : date;
+  }
+  return parseSince(value, now);
+}
+
+export async function appendAnnotation(path: string, annotation: Annotation): Promise<void> {
+  await mkdir(dirname(path), { recursive: true });
+  await appendFile(path, JSON.stringify(annotation) + '\n');
+}
+
+// Annotations in time order; a missing file has none and unreadable lines are skipped
+export async function loadAnnotations(path: string): Promise<Annotation[]> {
+  let content: string;
+  try {
+    content = await readFile(path, 'utf-8');
+  } catch {
+    return [];
+  }
+
+  const annotations: Annotation[] = [];
+  for (const line of content.split('\n')) {
+    if (!line.trim()) continue;
+    try {
+      const result = AnnotationSchema.safeParse(JSON.parse(line));
+      if (result.success && !isNaN(new Date(result.data.timestamp).getTime())) {
+        annotations.push(result.data);
+      }
+    } catch {
+      continue;
+    }
+  }
+  return annotations.sort(
+    (a, b) => new Date(a.timestamp).getTime() - new Date(b.timestamp).getTime()
+  );
+}
+
+export function filterAnnotations(
+  annotations: Annotation[],
+  startTime: Date,
+  endTime: Date
+): Annotation[] {
+  return annotations.filter(annotation => {
+    const time = new Date(annotation.timestamp);
+    return time >= startTime && time <= endTime;
+  });
+}
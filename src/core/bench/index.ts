@@ -0,0 +1,158 @@
+import { Session } from 'inspector';
+import { writeHeapSnapshot } from 'v8';
+import { mkdir, writeFile } from 'fs/promises';
+import { join } from 'path';
+import {
+  discoverLogFiles,
+  parseLogFiles,
+  mapDirectoriesToRepositories,
+  groupEventsByRepository,
+  LoadOptions,
+} from '../parser';
+import { calculateActivityLevels, createTimeAxis } from '../../ui/utils/tableUtils';
+
+export interface BenchmarkOptions {
+  startTime?: Date;
+  endTime?: Date;
+  width: number;
+  profileDir?: string;
+  // Log directories and repository settings, as reports use them
+  loadOptions?: LoadOptions;
+}
+
+export interface PhaseTiming {
+  phase: string;
+  durationMs: number;
+  heapUsedBytes: number;
+}
+
+export interface BenchmarkResult {
+  phases: PhaseTiming[];
+  totalMs: number;
+  fileCount: number;
+  eventCount: number;
+  projectCount: number;
+  peakRssBytes: number;
+  profileFiles: string[];
+}
+
+// Promisified wrapper around the callback-based inspector API
+function post<T>(session: Session, method: string, params?: object): Promise<T> {
+  return new Promise((resolve, reject) => {
+    session.post(method, params, (error, result) => {
+      if (error) {
+        reject(error);
+      } else {
+        resolve(result as T);
+      }
+    });
+  });
+}
+
+// Run the full loading pipeline phase by phase, measuring time and memory
+export async function runBenchmark(options: BenchmarkOptions): Promise<BenchmarkResult> {
+  const { startTime, endTime, width, profileDir, loadOptions = {} } = options;
+  const phases: PhaseTiming[] = [];
+  const profileFiles: string[] = [];
+
+  const measure = async <T>(phase: string, fn: () => T | Promise<T>): Promise<T> => {
+    const started = performance.now();
+    const result = await fn();
+    phases.push({
+      phase,
+      durationMs: performance.now() - started,
+      heapUsedBytes: process.memoryUsage().heapUsed,
+    });
+    return result;
+  };
+
+  let session: Session | undefined;
+  if (profileDir) {
+    session = new Session();
+    session.connect();
+    await post(session, 'Profiler.enable');
+    await post(session, 'Profiler.start');
+  }
+
+  const fileToDirectoryMap = await measure('discovery', () =>
+    discoverLogFiles(loadOptions.logDirs)
+  );
+  const directoryEventMap = await measure('parse', () =>
+    parseLogFiles(fileToDirectoryMap, startTime, endTime, undefined, loadOptions)
+  );
+  const repoDirectoryMap = await measure('git', () =>
+    mapDirectoriesToRepositories(directoryEventMap, loadOptions)
+  );
+  const grouped = await measure('group', () =>
+    groupEventsByRepository(directoryEventMap, repoDirectoryMap)
+  );
+  const timelines = Array.from(grouped.values());
+
+  await measure('render', () => {
+    // Lay out the time axis and every timeline row as the table would
+    const rangeStart =
+      startTime ?? new Date(Math.min(...timelines.map(t => t.startTime.getTime()), Date.now()));
+    const rangeEnd =
+      endTime ?? new Date(Math.max(...timelines.map(t => t.endTime.getTime()), Date.now()));
+
+    createTimeAxis(rangeStart, rangeEnd, width);
+    for (const timeline of timelines) {
+      calculateActivityLevels(timeline.events, rangeStart, rangeEnd, width);
+    }
+  });
+
+  if (session && profileDir) {
+    const { profile } = await post<{ profile: object }>(session, 'Profiler.stop');
+    session.disconnect();
+
+    await mkdir(profileDir, { recursive: true });
+    const cpuProfilePath = join(profileDir, 'ccstat.cpuprofile');
+    await writeFile(cpuProfilePath, JSON.stringify(profile));
+    profileFiles.push(cpuProfilePath);
+    profileFiles.push(writeHeapSnapshot(join(profileDir, 'ccstat.heapsnapshot')));
+  }
+
+  return {
+    phases,
+    totalMs: phases.reduce((sum, p) => sum + p.durationMs, 0),
+    fileCount: fileToDirectoryMap.size,
+    eventCount: timelines.reduce((sum, t) => sum + t.eventCount, 0),
+    projectCount: timelines.length,
+    // maxRSS is reported in kilobytes
+    peakRssBytes: process.resourceUsage().maxRSS * 1024,
+    profileFiles,
+  };
+}
+
+function formatMilliseconds(ms: number): string {
+  return `${ms.toFixed(1)} ms`;
+}
+
+function formatMegabytes(bytes: number): string {
+  return `${(bytes / (1024 * 1024)).toFixed(1)} MB`;
+}
+
+export function formatBenchmarkResult(result: BenchmarkResult): string {
+  const lines: string[] = ['Benchmark Results:'];
+
+  for (const { phase, durationMs, heapUsedBytes } of result.phases) {
+    const duration = formatMilliseconds(durationMs).padStart(10);
+    lines.push(` - ${phase.padEnd(10)} ${duration}   heap ${formatMegabytes(heapUsedBytes)}`);
+  }
+
+  lines.push(` - ${'total'.padEnd(10)} ${formatMilliseconds(result.totalMs).padStart(10)}`);
+  lines.push('');
+  lines.push(
+    `Files: ${result.fileCount}, Events: ${result.eventCount}, Projects: ${result.projectCount}`
+  );
+  lines.push(`Peak memory (RSS): ${formatMegabytes(result.peakRssBytes)}`);
+
+  if (result.profileFiles.length > 0) {
+    lines.push('Profiles written:');
+    for (const file of result.profileFiles) {
+      lines.push(` - ${file}`);
+    }
+  }
+
+  return lines.join('\n');
+}
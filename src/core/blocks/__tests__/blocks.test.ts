@@ -0,0 +1,58 @@
+import { Timeline } from '../../../models/models';
+import {
+  formatBlockBurnDown,
+  getActiveBlock,
+  identifyBlocks,
+  inferBlockCapacity,
+} from '../index';
+
+const at = (hour: number, minute = 0) => new Date(2025, 5, 12, hour, minute);
+
+function createTestTimeline(name: string, times: Date[], tokens: number): Timeline {
+  return {
+    projectName: name,
+    events: times.map(time => ({
+      timestamp: time.toISOString(),
+      usage: { outputTokens: tokens },
+    })),
+    eventCount: times.length,
+    activeDuration: 0,
+    startTime: times[0],
+    endTime: times[times.length - 1],
+  };
+}
+
+describe('blocks', () => {
+  const timelines = [
+    createTestTimeline('web', [at(1, 30), at(3), at(5, 59)], 100),
+    createTestTimeline('api', [at(6, 10), at(8, 20)], 50),
+  ];
+
+  it('should open a new block at the hour of the first event after the previous one ends', () => {
+    const blocks = identifyBlocks(timelines);
+
+    expect(blocks.map(block => [block.start, block.end, block.messages, block.tokens])).toEqual([
+      [at(1), at(6), 3, 300],
+      [at(6), at(11), 2, 100],
+    ]);
+  });
+
+  it('should infer capacity from finished blocks only', () => {
+    const blocks = identifyBlocks(timelines);
+    const active = getActiveBlock(blocks, at(9));
+
+    expect(active).toBe(blocks[1]);
+    expect(getActiveBlock(blocks, at(11))).toBeUndefined();
+    expect(inferBlockCapacity(blocks, active)).toEqual({ messages: 3, tokens: 300 });
+    expect(inferBlockCapacity([blocks[1]], blocks[1])).toBeNull();
+  });
+
+  it('should render bars against capacity', () => {
+    const blocks = identifyBlocks(timelines);
+    const output = formatBlockBurnDown(blocks[1], { messages: 4, tokens: 200 }, at(8, 30), 10);
+
+    expect(output).toContain('[█████░░░░░] 50% of 200');
+    expect(output).toContain('2h 30m left');
+    expect(output).toContain('Pace: 1.0x');
+  });
+});
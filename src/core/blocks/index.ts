@@ -0,0 +1,124 @@
+import { addHours, startOfHour } from 'date-fns';
+import { Event, Timeline } from '../../models/models';
+import { createTokenCounter } from '../../utils/tokens';
+import { formatHours } from '../../utils/durations';
+
+// Claude plans meter usage in rolling windows that open with the first message
+export const BLOCK_HOURS = 5;
+
+export interface UsageBlock {
+  start: Date;
+  end: Date;
+  messages: number;
+  tokens: number;
+  lastActivity: Date;
+}
+
+export interface BlockCapacity {
+  messages: number;
+  tokens: number;
+}
+
+// Split all events into 5-hour blocks starting at the hour of the first event
+export function identifyBlocks(timelines: Timeline[]): UsageBlock[] {
+  const events: Event[] = timelines.flatMap(timeline => timeline.events);
+  events.sort((a, b) => new Date(a.timestamp).getTime() - new Date(b.timestamp).getTime());
+
+  const blocks: UsageBlock[] = [];
+  let current: UsageBlock | undefined;
+  const countTokens = createTokenCounter();
+
+  for (const event of events) {
+    const time = new Date(event.timestamp);
+    if (!current || time >= current.end) {
+      const start = startOfHour(time);
+      current = {
+        start,
+        end: addHours(start, BLOCK_HOURS),
+        messages: 0,
+        tokens: 0,
+        lastActivity: time,
+      };
+      blocks.push(current);
+    }
+
+    current.messages++;
+    current.tokens += countTokens(event);
+    current.lastActivity = time;
+  }
+
+  return blocks;
+}
+
+export function getActiveBlock(blocks: UsageBlock[], now: Date): UsageBlock | undefined {
+  const last = blocks[blocks.length - 1];
+  return last && now < last.end ? last : undefined;
+}
+
+// The busiest finished block is the best available estimate of what a block can hold
+export function inferBlockCapacity(
+  blocks: UsageBlock[],
+  active?: UsageBlock
+): BlockCapacity | null {
+  const finished = blocks.filter(block => block !== active);
+  if (finished.length === 0) return null;
+
+  return {
+    messages: Math.max(...finished.map(block => block.messages)),
+    tokens: Math.max(...finished.map(block => block.tokens)),
+  };
+}
+
+function formatBar(fraction: number, width: number): string {
+  const filled = Math.round(Math.min(Math.max(fraction, 0), 1) * width);
+  return '█'.repeat(filled) + '░'.repeat(width - filled);
+}
+
+function formatRemaining(ms: number): string {
+  return formatHours(Math.max(Math.round(ms / 60000), 0));
+}
+
+export function formatBlockBurnDown(
+  block: UsageBlock,
+  capacity: BlockCapacity | null,
+  now: Date,
+  barWidth: number
+): string {
+  const elapsed = (now.getTime() - block.start.getTime()) / (BLOCK_HOURS * 60 * 60 * 1000);
+  const lines: string[] = [
+    `Active block: ${block.start.toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' })}` +
+      ` - ${block.end.toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' })}`,
+    '',
+    `${'Time'.padEnd(10)}[${formatBar(elapsed, barWidth)}] ` +
+      `${formatRemaining(block.end.getTime() - now.getTime())} left`,
+  ];
+
+  const rows: [string, number, number | undefined][] = [
+    ['Messages', block.messages, capacity?.messages],
+    ['Tokens', block.tokens, capacity?.tokens],
+  ];
+  for (const [label, used, total] of rows) {
+    if (!total) {
+      lines.push(`${label.padEnd(10)}${used} (no earlier blocks to compare)`);
+      continue;
+    }
+    const fraction = used / total;
+    lines.push(
+      `${label.padEnd(10)}[${formatBar(fraction, barWidth)}] ` +
+        `${Math.round(fraction * 100)}% of ${total} (busiest earlier block)`
+    );
+  }
+
+  // Using capacity faster than time means the block runs out before it resets
+  if (capacity && capacity.tokens > 0 && elapsed > 0) {
+    const pace = block.tokens / capacity.tokens / elapsed;
+    lines.push('');
+    lines.push(
+      pace > 1
+        ? `Pace: ${pace.toFixed(1)}x — ahead of your busiest block, consider slowing down`
+        : `Pace: ${pace.toFixed(1)}x — on track`
+    );
+  }
+
+  return lines.join('\n');
+}
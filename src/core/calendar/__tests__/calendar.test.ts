@@ -0,0 +1,87 @@
+import { inflateSync } from 'zlib';
+import { Timeline } from '../../../models/models';
+import { createCalendar, formatCalendar, renderCalendarImage } from '../index';
+
+const at = (day: number, hour: number) => new Date(2025, 5, day, hour);
+
+const timeline = (projectName: string, times: Date[]): Timeline => ({
+  projectName,
+  events: times.map(time => ({ timestamp: time.toISOString(), sessionId: 's', type: 'user' })),
+  eventCount: times.length,
+  activeDuration: 0,
+  startTime: times[0],
+  endTime: times[times.length - 1],
+});
+
+// Wednesday; ten days back starts on Monday 2025-06-02
+const now = at(11, 18);
+
+const timelines = [
+  timeline('webapp', [at(2, 9), at(2, 10), at(2, 11), at(10, 9)]),
+  timeline('api', [at(2, 15)]),
+];
+
+describe('calendar', () => {
+  it('should lay out days as weeks with density levels', () => {
+    const calendar = createCalendar(timelines, 10, now);
+
+    expect(calendar.start).toEqual(at(2, 0));
+    expect(calendar.events).toBe(5);
+    expect(calendar.weeks).toHaveLength(2);
+    expect(calendar.weeks[0].map(day => day?.level)).toEqual([4, 0, 0, 0, 0, 0, 0]);
+    expect(calendar.weeks[1].map(day => day?.level)).toEqual([
+      0,
+      2,
+      0,
+      undefined,
+      undefined,
+      undefined,
+      undefined,
+    ]);
+  });
+
+  it('should leave out events outside the range', () => {
+    const calendar = createCalendar(timelines, 2, now);
+
+    expect(calendar.events).toBe(1);
+    expect(calendar.weeks[0].map(day => day?.events)).toEqual([
+      undefined,
+      1,
+      0,
+      undefined,
+      undefined,
+      undefined,
+      undefined,
+    ]);
+  });
+
+  it('should draw one row per weekday below the month names', () => {
+    const colorScheme = [0, 1, 2, 3, 4].map(level => (text: string) => `<${level}${text}>`);
+    const lines = formatCalendar(createCalendar(timelines, 10, now), colorScheme).split('\n');
+
+    expect(lines).toHaveLength(8);
+    expect(lines[0]).toBe('    Jun');
+    expect(lines[1]).toMatch(/^Mon <4■>/);
+    expect(lines[2]).toContain('<2■>');
+    expect(lines[7]).not.toContain('<');
+  });
+
+  it('should render a PNG with one cell per day', () => {
+    const png = renderCalendarImage(createCalendar(timelines, 10, now), [
+      '#000000',
+      '#111111',
+      '#222222',
+      '#333333',
+      '#ff0000',
+    ]);
+
+    // 2 weeks × 15px + 3px gap by 7 days × 15px + 3px gap
+    expect(png.readUInt32BE(16)).toBe(33);
+    expect(png.readUInt32BE(20)).toBe(108);
+
+    // First pixel of Monday 06-02 at (3, 3), after the filter byte of its row
+    const raw = inflateSync(png.subarray(41, 41 + png.readUInt32BE(33)));
+    const offset = 3 * (33 * 4 + 1) + 1 + 3 * 4;
+    expect(Array.from(raw.subarray(offset, offset + 4))).toEqual([0xff, 0, 0, 0xff]);
+  });
+});
@@ -0,0 +1,160 @@
+import chalk from 'chalk';
+import { addDays, format, startOfDay, startOfWeek, subDays } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { ColorScheme } from '../../ui/colorThemes';
+import { encodePng } from '../../utils/png';
+
+export const DEFAULT_CALENDAR_DAYS = 182;
+
+export interface CalendarDay {
+  date: Date;
+  events: number;
+  // Density level 0-4 as in the timeline (0 = no activity)
+  level: number;
+}
+
+export interface Calendar {
+  start: Date;
+  end: Date;
+  events: number;
+  // One column per week, Monday first; days outside the range are null
+  weeks: Array<Array<CalendarDay | null>>;
+}
+
+// Weekday rows Monday to Sunday; like GitHub, only every other row is labelled
+const WEEKDAY_LABELS = ['Mon', '', 'Wed', '', 'Fri', '', ''];
+
+const LABEL_WIDTH = 4;
+
+const CELL_CHAR = '■';
+
+// Start of the first of the last `days` local days, today included
+export function getCalendarStart(days: number, now: Date): Date {
+  return startOfDay(subDays(now, days - 1));
+}
+
+// Events per local day of the last `days` days, laid out as a contribution calendar
+export function createCalendar(timelines: Timeline[], days: number, now: Date): Calendar {
+  const start = getCalendarStart(days, now);
+  const end = addDays(startOfDay(now), 1);
+
+  const counts = new Map<number, number>();
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      const day = startOfDay(new Date(event.timestamp)).getTime();
+      if (day >= start.getTime() && day < end.getTime()) {
+        counts.set(day, (counts.get(day) ?? 0) + 1);
+      }
+    }
+  }
+  const maxEvents = Math.max(...counts.values(), 1);
+
+  const weeks: Calendar['weeks'] = [];
+  for (
+    let weekStart = startOfWeek(start, { weekStartsOn: 1 });
+    weekStart < end;
+    weekStart = addDays(weekStart, 7)
+  ) {
+    weeks.push(
+      Array.from({ length: 7 }, (_, i) => {
+        const date = addDays(weekStart, i);
+        if (date < start || date >= end) return null;
+
+        const events = counts.get(date.getTime()) ?? 0;
+        const level = events === 0 ? 0 : Math.min(4, Math.floor((events / maxEvents) * 4) + 1);
+        return { date, events, level };
+      })
+    );
+  }
+
+  return {
+    start,
+    end,
+    events: Array.from(counts.values()).reduce((sum, count) => sum + count, 0),
+    weeks,
+  };
+}
+
+// Month names above the first week of each month, two columns per week; a name that would run
+// into the previous one is left out
+function formatMonthLabels(calendar: Calendar): string {
+  let line = '';
+  let lastMonth = -1;
+  calendar.weeks.forEach((week, i) => {
+    const firstDay = week.find(day => day !== null)!;
+    const month = firstDay.date.getMonth();
+    if (month === lastMonth) return;
+
+    const column = LABEL_WIDTH + i * 2;
+    if (line === '' || column > line.length) {
+      line = line.padEnd(column) + format(firstDay.date, 'MMM');
+    }
+    lastMonth = month;
+  });
+  return line;
+}
+
+// Unicode calendar colored like the timeline, for terminals without inline images
+export function formatCalendar(calendar: Calendar, colorScheme: ColorScheme): string {
+  const lines = [formatMonthLabels(calendar)];
+
+  for (let weekday = 0; weekday < 7; weekday++) {
+    const cells = calendar.weeks.map(week => {
+      const day = week[weekday];
+      if (!day) return ' ';
+      if (day.level === 0) return chalk.dim(CELL_CHAR);
+
+      const color = colorScheme[day.level];
+      return typeof color === 'function' ? color(CELL_CHAR) : chalk.hex(color)(CELL_CHAR);
+    });
+    lines.push(WEEKDAY_LABELS[weekday].padEnd(LABEL_WIDTH) + cells.join(' ').trimEnd());
+  }
+  return lines.join('\n');
+}
+
+// One line above the calendar, e.g. "2026-04-16 – 2026-10-15: 1234 events"
+export function formatCalendarHeader(calendar: Calendar): string {
+  return (
+    `${format(calendar.start, 'yyyy-MM-dd')} – ${format(subDays(calendar.end, 1), 'yyyy-MM-dd')}` +
+    `: ${calendar.events} events`
+  );
+}
+
+// Pixel size of a day in the inline image, and of the gap around it
+const IMAGE_CELL_SIZE = 12;
+const IMAGE_GAP = 3;
+
+// Days without activity, visible on dark and light terminal backgrounds alike
+const IMAGE_EMPTY_COLOR = '#3a3a3a';
+
+function parseHexColor(hex: string): [number, number, number] {
+  const value = parseInt(hex.slice(1), 16);
+  return [(value >> 16) & 0xff, (value >> 8) & 0xff, value & 0xff];
+}
+
+// The calendar as a PNG with a transparent background, colored with a theme's hex colors
+export function renderCalendarImage(calendar: Calendar, hexColors: string[]): Buffer {
+  const step = IMAGE_CELL_SIZE + IMAGE_GAP;
+  const width = calendar.weeks.length * step + IMAGE_GAP;
+  const height = 7 * step + IMAGE_GAP;
+  const pixels = Buffer.alloc(width * height * 4);
+
+  calendar.weeks.forEach((week, column) => {
+    week.forEach((day, row) => {
+      if (!day) return;
+
+      const [r, g, b] = parseHexColor(day.level === 0 ? IMAGE_EMPTY_COLOR : hexColors[day.level]);
+      for (let y = 0; y < IMAGE_CELL_SIZE; y++) {
+        for (let x = 0; x < IMAGE_CELL_SIZE; x++) {
+          const offset =
+            ((IMAGE_GAP + row * step + y) * width + IMAGE_GAP + column * step + x) * 4;
+          pixels[offset] = r;
+          pixels[offset + 1] = g;
+          pixels[offset + 2] = b;
+          pixels[offset + 3] = 0xff;
+        }
+      }
+    });
+  });
+  return encodePng(width, height, pixels);
+}
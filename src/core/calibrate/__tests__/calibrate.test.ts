@@ -0,0 +1,57 @@
+import { Timeline } from '../../../models/models';
+import { DEFAULT_DURATION_MODEL } from '../../duration';
+import {
+  calibrateIdleThreshold,
+  findBestThreshold,
+  formatCalibration,
+  getTrackedRange,
+  parseTrackedTime,
+} from '../index';
+
+const at = (day: number, hour: number, minute: number) =>
+  new Date(2025, 5, day, hour, minute).toISOString();
+
+// Events every 8 minutes for an hour: only thresholds of 8 minutes or more count the gaps
+const timeline: Timeline = {
+  projectName: 'web',
+  events: Array.from({ length: 8 }, (_, i) => ({ timestamp: at(2, 9, i * 8) })),
+  eventCount: 8,
+  activeDuration: 56,
+  startTime: new Date(at(2, 9, 0)),
+  endTime: new Date(at(2, 9, 56)),
+};
+
+describe('calibrate', () => {
+  it('should parse tracked hours or minutes', () => {
+    const entries = parseTrackedTime('Date,Project,Hours\n2025-06-02,web,1.5\n', 'a.csv');
+    expect(entries).toEqual([{ date: '2025-06-02', project: 'web', minutes: 90 }]);
+
+    expect(parseTrackedTime('date,minutes\n"2025-06-03",45', 'b.csv')).toEqual([
+      { date: '2025-06-03', project: undefined, minutes: 45 },
+    ]);
+    expect(getTrackedRange(entries)).toEqual({
+      start: new Date(2025, 5, 2),
+      end: new Date(2025, 5, 3),
+    });
+  });
+
+  it('should name the file and line of invalid rows', () => {
+    expect(() => parseTrackedTime('project,hours\nweb,1', 'a.csv')).toThrow('a.csv: expected');
+    expect(() => parseTrackedTime('date,hours\n2025-06-02,x', 'a.csv')).toThrow('a.csv:2:');
+  });
+
+  it('should suggest the threshold closest to the tracked time', () => {
+    const entries = [{ date: '2025-06-02', project: 'web', minutes: 56 }];
+    const rows = calibrateIdleThreshold([timeline], entries, DEFAULT_DURATION_MODEL, [5, 10]);
+
+    expect(rows.map(row => row.estimatedMinutes)).toEqual([0, 56]);
+    expect(findBestThreshold(rows)?.idleMinutes).toBe(10);
+    expect(formatCalibration(rows, 5)).toContain('"idleMinutes": 10');
+  });
+
+  it('should compare entries without a project with all projects that day', () => {
+    const entries = [{ date: '2025-06-02', minutes: 60 }];
+    const [row] = calibrateIdleThreshold([timeline], entries, DEFAULT_DURATION_MODEL, [10]);
+    expect(row.meanError).toBe(4);
+  });
+});
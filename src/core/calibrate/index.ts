@@ -0,0 +1,157 @@
+import { addDays, format, parse } from 'date-fns';
+import { Event, Timeline } from '../../models/models';
+import { calculateActiveDuration, DurationModel } from '../duration';
+
+// Idle thresholds tried when calibrating, in minutes
+export const CALIBRATION_THRESHOLDS = [1, 2, 3, 5, 7, 10, 15, 20, 30, 45, 60];
+
+// Time tracked outside ccstat for a day, for one project or, without one, for all of them
+export interface TrackedEntry {
+  date: string;
+  project?: string;
+  minutes: number;
+}
+
+export interface CalibrationRow {
+  idleMinutes: number;
+  estimatedMinutes: number;
+  trackedMinutes: number;
+  // Mean absolute difference per tracked entry, in minutes
+  meanError: number;
+}
+
+const splitCsvLine = (line: string) =>
+  line.split(',').map(field => field.trim().replace(/^"(.*)"$/, '$1'));
+
+// CSV with a header row: date (YYYY-MM-DD), hours or minutes, and optionally project
+export function parseTrackedTime(content: string, source: string): TrackedEntry[] {
+  const lines = content.split(/\r?\n/).filter(line => line.trim() !== '');
+  if (lines.length === 0) return [];
+
+  const header = splitCsvLine(lines[0]).map(name => name.toLowerCase());
+  const dateColumn = header.indexOf('date');
+  const projectColumn = header.indexOf('project');
+  const hoursColumn = header.indexOf('hours');
+  const minutesColumn = header.indexOf('minutes');
+  if (dateColumn < 0 || (hoursColumn < 0 && minutesColumn < 0)) {
+    throw new Error(`${source}: expected a header with date and hours or minutes columns`);
+  }
+
+  return lines.slice(1).map((line, index) => {
+    const fields = splitCsvLine(line);
+    const date = fields[dateColumn];
+    const value = parseFloat(fields[minutesColumn >= 0 ? minutesColumn : hoursColumn]);
+    if (!/^\d{4}-\d{2}-\d{2}$/.test(date ?? '') || !(value >= 0)) {
+      throw new Error(`${source}:${index + 2}: expected a YYYY-MM-DD date and a duration`);
+    }
+
+    return {
+      date,
+      project: (projectColumn >= 0 && fields[projectColumn]) || undefined,
+      minutes: minutesColumn >= 0 ? value : value * 60,
+    };
+  });
+}
+
+// Local date range covered by the entries, for loading matching events
+export function getTrackedRange(entries: TrackedEntry[]): { start: Date; end: Date } | null {
+  if (entries.length === 0) return null;
+
+  const dates = entries.map(entry => entry.date).sort();
+  const start = parse(dates[0], 'yyyy-MM-dd', new Date());
+  const end = addDays(parse(dates[dates.length - 1], 'yyyy-MM-dd', new Date()), 1);
+  return { start, end };
+}
+
+// Events per project per local date, measured separately like ccstat's daily reports
+function groupEventsByDay(timelines: Timeline[]): Map<string, Map<string, Event[]>> {
+  const days = new Map<string, Map<string, Event[]>>();
+
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      const date = format(new Date(event.timestamp), 'yyyy-MM-dd');
+      const projects = days.get(date) ?? new Map<string, Event[]>();
+      const events = projects.get(timeline.projectName) ?? [];
+      events.push(event);
+      projects.set(timeline.projectName, events);
+      days.set(date, projects);
+    }
+  }
+
+  return days;
+}
+
+// Estimate every tracked entry with each threshold; other settings of the model are kept
+export function calibrateIdleThreshold(
+  timelines: Timeline[],
+  entries: TrackedEntry[],
+  model: DurationModel,
+  thresholds = CALIBRATION_THRESHOLDS
+): CalibrationRow[] {
+  const days = groupEventsByDay(timelines);
+  const trackedMinutes = entries.reduce((sum, entry) => sum + entry.minutes, 0);
+
+  return thresholds.map(idleMinutes => {
+    const thresholdModel = { ...model, algorithm: 'interval' as const, idleMinutes };
+    const estimate = (events: Event[]) => calculateActiveDuration(events, thresholdModel);
+
+    let estimatedMinutes = 0;
+    let totalError = 0;
+    for (const entry of entries) {
+      const projects = days.get(entry.date) ?? new Map<string, Event[]>();
+      const estimated = entry.project
+        ? estimate(projects.get(entry.project) ?? [])
+        : Array.from(projects.values()).reduce((sum, events) => sum + estimate(events), 0);
+
+      estimatedMinutes += estimated;
+      totalError += Math.abs(estimated - entry.minutes);
+    }
+
+    return {
+      idleMinutes,
+      estimatedMinutes,
+      trackedMinutes,
+      meanError: entries.length > 0 ? totalError / entries.length : 0,
+    };
+  });
+}
+
+export function findBestThreshold(rows: CalibrationRow[]): CalibrationRow | null {
+  return rows.reduce<CalibrationRow | null>(
+    (best, row) => (!best || row.meanError < best.meanError ? row : best),
+    null
+  );
+}
+
+export function formatCalibration(rows: CalibrationRow[], currentIdleMinutes: number): string {
+  const best = findBestThreshold(rows);
+  const lines: string[] = [
+    `${'Idle min'.padEnd(10)}${'Estimated'.padStart(12)}${'Tracked'.padStart(12)}` +
+      `${'Mean error'.padStart(12)}`,
+  ];
+
+  for (const row of rows) {
+    const marks = [
+      row === best ? 'best' : '',
+      row.idleMinutes === currentIdleMinutes ? 'current' : '',
+    ].filter(Boolean);
+    lines.push(
+      `${`${row.idleMinutes}`.padEnd(10)}${`${Math.round(row.estimatedMinutes)}`.padStart(12)}` +
+        `${`${Math.round(row.trackedMinutes)}`.padStart(12)}` +
+        `${row.meanError.toFixed(1).padStart(12)}` +
+        (marks.length > 0 ? `  ${marks.join(', ')}` : '')
+    );
+  }
+
+  if (best) {
+    lines.push('');
+    lines.push(
+      best.idleMinutes === currentIdleMinutes
+        ? `The current idle threshold of ${currentIdleMinutes} minutes fits best`
+        : `Suggested setting: "duration": { "idleMinutes": ${best.idleMinutes} } ` +
+            `(mean error ${best.meanError.toFixed(1)} min per entry)`
+    );
+  }
+
+  return lines.join('\n');
+}
@@ -0,0 +1,80 @@
+import { mkdtemp, rm } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { UsageExport } from '../../export';
+import { getCollectorUrl, loadSubmissions, saveSubmission, submissionFileName } from '../index';
+
+const createExport = (user: string | undefined, events: number): UsageExport => ({
+  user,
+  generatedAt: '2025-01-01T00:00:00.000Z',
+  range: null,
+  projects: [
+    {
+      name: 'api',
+      events,
+      duration: 10,
+      firstActivity: '2025-01-01T09:00:00.000Z',
+      lastActivity: '2025-01-01T10:00:00.000Z',
+    },
+  ],
+});
+
+describe('collector store', () => {
+  let dataDir: string;
+
+  beforeEach(async () => {
+    dataDir = await mkdtemp(join(tmpdir(), 'ccstat-collector-'));
+  });
+
+  afterEach(async () => {
+    await rm(dataDir, { recursive: true, force: true });
+  });
+
+  it('should sanitize user names for file names', () => {
+    expect(submissionFileName('anon-1a2b')).toMatch(/^anon-1a2b-[0-9a-f]{16}\.json$/);
+    expect(submissionFileName('../etc/passwd')).toMatch(/^___etc_passwd-[0-9a-f]{16}\.json$/);
+  });
+
+  it('should give names that sanitize alike their own files', async () => {
+    expect(submissionFileName('a.b')).not.toBe(submissionFileName('a_b'));
+
+    await saveSubmission(dataDir, createExport('a.b', 1));
+    await saveSubmission(dataDir, createExport('a_b', 2));
+    const submissions = await loadSubmissions(dataDir);
+    expect(submissions.map(s => s.name).sort()).toEqual(['a.b', 'a_b']);
+  });
+
+  it('should keep the path of the endpoint', () => {
+    expect(getCollectorUrl('http://host:7878', '/api/v1/usage').href).toBe(
+      'http://host:7878/api/v1/usage'
+    );
+    expect(getCollectorUrl('https://example.com/ccstat', '/api/v1/usage').href).toBe(
+      'https://example.com/ccstat/api/v1/usage'
+    );
+    expect(getCollectorUrl('https://example.com/ccstat/', 'api/v1/team').href).toBe(
+      'https://example.com/ccstat/api/v1/team'
+    );
+  });
+
+  it('should keep only the latest submission per user', async () => {
+    await saveSubmission(dataDir, createExport('alice', 1));
+    await saveSubmission(dataDir, createExport('alice', 5));
+    await saveSubmission(dataDir, createExport('bob', 2));
+
+    const submissions = await loadSubmissions(dataDir);
+    expect(submissions.map(s => [s.name, s.data.projects[0].events])).toEqual([
+      ['alice', 5],
+      ['bob', 2],
+    ]);
+  });
+
+  it('should reject submissions without a user', async () => {
+    await expect(saveSubmission(dataDir, createExport(undefined, 1))).rejects.toThrow(
+      'submission has no user'
+    );
+  });
+
+  it('should return no submissions for a missing directory', async () => {
+    expect(await loadSubmissions(join(dataDir, 'missing'))).toEqual([]);
+  });
+});
@@ -0,0 +1,51 @@
+import { createHash } from 'crypto';
+import { mkdir, readdir, readFile, writeFile } from 'fs/promises';
+import { join } from 'path';
+import { parseUsageExport, UsageExport } from '../export';
+import { NamedExport } from '../merge';
+
+// Environment variable holding the shared secret of a collector and the machines pushing to it
+export const COLLECTOR_TOKEN_ENV = 'CCSTAT_COLLECTOR_TOKEN';
+
+// A file name per submitter: the readable part keeps only safe characters, and the hash of the
+// full name keeps users whose names sanitize alike (e.g. "a.b" and "a_b") apart
+export function submissionFileName(user: string): string {
+  const digest = createHash('sha256').update(user).digest('hex').slice(0, 16);
+  return `${user.replace(/[^a-zA-Z0-9_-]/g, '_').slice(0, 64)}-${digest}.json`;
+}
+
+// URL of a collector API path below the endpoint, so endpoints behind a path prefix
+// (e.g. https://example.com/ccstat) keep it
+export function getCollectorUrl(endpoint: string, path: string): URL {
+  const base = endpoint.endsWith('/') ? endpoint : `${endpoint}/`;
+  return new URL(path.replace(/^\/+/, ''), base);
+}
+
+// Store the latest submission per user, replacing earlier ones
+export async function saveSubmission(dataDir: string, data: UsageExport): Promise<string> {
+  if (!data.user) {
+    throw new Error('submission has no user');
+  }
+
+  await mkdir(dataDir, { recursive: true });
+  const filePath = join(dataDir, submissionFileName(data.user));
+  await writeFile(filePath, JSON.stringify(data));
+  return filePath;
+}
+
+export async function loadSubmissions(dataDir: string): Promise<NamedExport[]> {
+  let files: string[];
+  try {
+    files = await readdir(dataDir);
+  } catch {
+    return [];
+  }
+
+  const submissions: NamedExport[] = [];
+  for (const file of files.filter(f => f.endsWith('.json')).sort()) {
+    const filePath = join(dataDir, file);
+    const data = parseUsageExport(await readFile(filePath, 'utf-8'), filePath);
+    submissions.push({ name: data.user ?? file, data });
+  }
+  return submissions;
+}
@@ -0,0 +1,67 @@
+import { Timeline } from '../../../models/models';
+import { createCommitContext, formatCommitContext } from '../index';
+
+const at = (hour: number, minute: number) => new Date(2025, 5, 12, hour, minute);
+
+const prompt = (time: Date, sessionId: string, content: unknown) => ({
+  timestamp: time.toISOString(),
+  sessionId,
+  type: 'user',
+  message: { role: 'user', content },
+});
+
+const timeline: Timeline = {
+  projectName: 'webapp',
+  events: [
+    prompt(at(9, 0), 'a', 'add retries to the upload client'),
+    { timestamp: at(9, 1).toISOString(), sessionId: 'a', type: 'assistant' },
+    prompt(at(9, 30), 'a', [{ type: 'text', text: 'use token=abc123 for the test' }]),
+    prompt(at(10, 0), 'b', 'add retries to the upload client'),
+    // Tool results aren't prompts
+    prompt(at(10, 1), 'b', [{ type: 'tool_result', content: 'ok' }]),
+  ],
+  eventCount: 5,
+  activeDuration: 65,
+  startTime: at(9, 0),
+  endTime: at(10, 1),
+};
+
+describe('commit context', () => {
+  it('should collect distinct redacted prompts since the last commit', () => {
+    expect(createCommitContext('webapp', timeline, at(8, 45))).toEqual({
+      projectName: 'webapp',
+      since: at(8, 45),
+      minutes: 65,
+      sessions: 2,
+      prompts: ['add retries to the upload client', 'use [redacted] for the test'],
+    });
+  });
+
+  it('should describe a project without activity', () => {
+    expect(createCommitContext('api', undefined, null)).toEqual({
+      projectName: 'api',
+      since: null,
+      minutes: 0,
+      sessions: 0,
+      prompts: [],
+    });
+  });
+
+  it('should format a block for a commit message', () => {
+    const context = createCommitContext('webapp', timeline, at(8, 45));
+
+    expect(formatCommitContext(context)).toBe(
+      [
+        'AI assistance (Claude Code): 1h 05m over 2 session(s) in webapp since 2025-06-12 08:45',
+        '',
+        'Prompts:',
+        '- add retries to the upload client',
+        '- use [redacted] for the test',
+      ].join('\n')
+    );
+    expect(formatCommitContext(context, 1).split('\n').slice(-2)).toEqual([
+      '- add retries to the upload client',
+      '- … and 1 more',
+    ]);
+  });
+});
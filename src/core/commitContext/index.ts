@@ -0,0 +1,73 @@
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { countSessions } from '../../ui/utils/columns';
+import { getPromptText, redactPrompt, truncateText } from '../../utils/prompts';
+import { formatHours } from '../../utils/durations';
+
+export const DEFAULT_PROMPT_LIMIT = 10;
+
+// Prompts are summarized to one line each, short enough for a commit message body
+const PROMPT_WIDTH = 72;
+
+export interface CommitContext {
+  projectName: string;
+  // Last commit; null before the first one
+  since: Date | null;
+  minutes: number;
+  sessions: number;
+  // Distinct prompts, oldest first
+  prompts: string[];
+}
+
+// Distinct prompts of a timeline, redacted and cut to one line each
+export function collectPrompts(timeline: Timeline | undefined): string[] {
+  const prompts: string[] = [];
+  for (const event of timeline?.events ?? []) {
+    const text = getPromptText(event);
+    if (!text || text.trim().length === 0) continue;
+
+    const prompt = truncateText(redactPrompt(text), PROMPT_WIDTH);
+    if (!prompts.includes(prompt)) prompts.push(prompt);
+  }
+  return prompts;
+}
+
+// Claude activity on the project since the last commit; the timeline is expected to cover that
+// range only
+export function createCommitContext(
+  projectName: string,
+  timeline: Timeline | undefined,
+  since: Date | null
+): CommitContext {
+  return {
+    projectName,
+    since,
+    minutes: timeline?.activeDuration ?? 0,
+    sessions: timeline ? countSessions([timeline]) : 0,
+    prompts: collectPrompts(timeline),
+  };
+}
+
+// Plain text for a commit message or PR description; no line starts with "#", which git would
+// drop as a comment
+export function formatCommitContext(
+  context: CommitContext,
+  promptLimit = DEFAULT_PROMPT_LIMIT
+): string {
+  const since = context.since ? `since ${format(context.since, 'yyyy-MM-dd HH:mm')}` : 'so far';
+  const lines = [
+    `AI assistance (Claude Code): ${formatHours(context.minutes)} over ` +
+      `${context.sessions} session(s) in ${context.projectName} ${since}`,
+  ];
+
+  if (context.prompts.length > 0) {
+    lines.push('', 'Prompts:');
+    for (const prompt of context.prompts.slice(0, promptLimit)) {
+      lines.push(`- ${prompt}`);
+    }
+    if (context.prompts.length > promptLimit) {
+      lines.push(`- … and ${context.prompts.length - promptLimit} more`);
+    }
+  }
+  return lines.join('\n');
+}
@@ -0,0 +1,44 @@
+import { Timeline } from '../../../models/models';
+import { createCompactSummary, COMPACT_SCHEMA_VERSION } from '../index';
+
+const createTimeline = (projectName: string, activeDuration: number, tokens: number): Timeline => ({
+  projectName,
+  events: [{ timestamp: '2025-01-01T10:00:00Z', usage: { outputTokens: tokens } }],
+  eventCount: 1,
+  activeDuration,
+  startTime: new Date('2025-01-01T10:00:00Z'),
+  endTime: new Date('2025-01-01T10:00:00Z'),
+});
+
+describe('createCompactSummary', () => {
+  const day = new Date(2025, 0, 1, 12, 0);
+
+  it('should summarize the day with the busiest project on top', () => {
+    const summary = createCompactSummary(
+      [createTimeline('web', 15, 100), createTimeline('api', 45, 20)],
+      day
+    );
+
+    expect(summary).toEqual({
+      v: COMPACT_SCHEMA_VERSION,
+      date: '2025-01-01',
+      topProject: 'api',
+      minutes: 60,
+      events: 2,
+      tokens: 120,
+      cost: null,
+    });
+  });
+
+  it('should keep the same shape without activity', () => {
+    expect(createCompactSummary([], day)).toEqual({
+      v: COMPACT_SCHEMA_VERSION,
+      date: '2025-01-01',
+      topProject: null,
+      minutes: 0,
+      events: 0,
+      tokens: 0,
+      cost: null,
+    });
+  });
+});
@@ -0,0 +1,35 @@
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { sumEventTokens } from '../../utils/tokens';
+
+// Bump only on breaking changes so launcher scripts can rely on the shape
+export const COMPACT_SCHEMA_VERSION = 1;
+
+export interface CompactSummary {
+  v: number;
+  date: string;
+  topProject: string | null;
+  minutes: number;
+  events: number;
+  tokens: number;
+  // Reserved until pricing data is available; always null for now
+  cost: number | null;
+}
+
+// Summarize one day of activity for widgets and menu-bar scripts
+export function createCompactSummary(timelines: Timeline[], day: Date): CompactSummary {
+  const top = timelines.reduce<Timeline | null>(
+    (best, t) => (best === null || t.activeDuration > best.activeDuration ? t : best),
+    null
+  );
+
+  return {
+    v: COMPACT_SCHEMA_VERSION,
+    date: format(day, 'yyyy-MM-dd'),
+    topProject: top?.projectName ?? null,
+    minutes: timelines.reduce((sum, t) => sum + t.activeDuration, 0),
+    events: timelines.reduce((sum, t) => sum + t.eventCount, 0),
+    tokens: sumEventTokens(timelines.flatMap(t => t.events)),
+    cost: null,
+  };
+}
@@ -0,0 +1,51 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import { compareProjects, describeComparison, formatComparison } from '../index';
+
+const event = (hour: number, minute: number, sessionId = 's1'): Event => ({
+  timestamp: new Date(2025, 5, 9, hour, minute).toISOString(),
+  sessionId,
+  usage: { outputTokens: 1000 },
+});
+
+describe('compareProjects', () => {
+  const startTime = new Date(2025, 5, 9, 0);
+  const endTime = new Date(2025, 5, 10, 0);
+  const timelines = [
+    createTimeline('web', [event(9, 0), event(9, 4), event(10, 0, 's2'), event(10, 2, 's2')]),
+    createTimeline('api', [event(15, 0), event(15, 3)]),
+  ];
+
+  it('should collect metrics in the given order, with zeros for idle projects', () => {
+    const comparison = compareProjects(timelines, ['api', 'web', 'docs'], startTime, endTime, 24);
+
+    const rows = comparison.projects.map(p => [p.projectName, p.events, p.sessions, p.duration]);
+    expect(rows).toEqual([
+      ['api', 2, 1, 3],
+      ['web', 4, 2, 6],
+      ['docs', 0, 0, 0],
+    ]);
+    expect(comparison.projects[1].share).toBeCloseTo(2 / 3);
+    expect(comparison.projects[1].tokens).toBe(4000);
+    expect(comparison.projects[0].activity[15]).toBe(2);
+  });
+
+  it('should say which project took more time', () => {
+    const { projects } = compareProjects(timelines, ['api', 'web'], startTime, endTime, 24);
+
+    expect(describeComparison(projects)).toBe('web took 2.0× the Claude time of api');
+    expect(describeComparison([projects[0], { ...projects[1], duration: 0 }])).toBe(
+      'Only api had activity in this range'
+    );
+  });
+
+  it('should print metrics side by side and timelines on one scale', () => {
+    const output = formatComparison(
+      compareProjects(timelines, ['web', 'api'], startTime, endTime, 24)
+    );
+
+    expect(output).toContain('Duration             6m        3m');
+    expect(output).toContain('Share               67%       33%');
+    expect(output.split('\n').find(line => line.startsWith('api'))).toMatch(/^api\s+█\s*$/);
+  });
+});
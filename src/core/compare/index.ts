@@ -0,0 +1,136 @@
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { brailleSparkline } from '../../utils/braille';
+import { matchesProjectName } from '../../utils/projectNames';
+import { formatTokens, sumEventTokens } from '../../utils/tokens';
+import { countActiveDays, countSessions } from '../../ui/utils/columns';
+import { createTimeAxis, getBucketIndex } from '../../ui/utils/tableUtils';
+import { sparkline } from '../trend';
+
+export interface ProjectMetrics {
+  projectName: string;
+  events: number;
+  sessions: number;
+  activeDays: number;
+  duration: number;
+  tokens: number;
+  // Part of the compared projects' combined duration
+  share: number;
+  // Events per timeline cell, on the scale shared by all compared projects
+  activity: number[];
+}
+
+export interface ProjectComparison {
+  startTime: Date;
+  endTime: Date;
+  width: number;
+  // Sparklines drawn in braille, with two activity cells per character
+  braille?: boolean;
+  projects: ProjectMetrics[];
+}
+
+function countActivity(timeline: Timeline, startTime: Date, endTime: Date, width: number) {
+  const counts: number[] = new Array(width).fill(0);
+  for (const event of timeline.events) {
+    const index = getBucketIndex(
+      new Date(event.timestamp).getTime(),
+      startTime.getTime(),
+      endTime.getTime(),
+      width
+    );
+    if (index >= 0) counts[index]++;
+  }
+  return counts;
+}
+
+// Metrics of the named projects in the given order; projects without activity get zeros
+export function compareProjects(
+  timelines: Timeline[],
+  names: string[],
+  startTime: Date,
+  endTime: Date,
+  width: number,
+  braille = false
+): ProjectComparison {
+  const cells = braille ? width * 2 : width;
+  const selected = names.map(name =>
+    timelines.find(t => matchesProjectName([name], t.projectName))
+  );
+  const totalDuration = selected.reduce((sum, t) => sum + (t?.activeDuration ?? 0), 0);
+
+  const projects = names.map((projectName, i) => {
+    const timeline = selected[i];
+    if (!timeline) {
+      return {
+        projectName,
+        events: 0,
+        sessions: 0,
+        activeDays: 0,
+        duration: 0,
+        tokens: 0,
+        share: 0,
+        activity: new Array(cells).fill(0),
+      };
+    }
+    return {
+      projectName,
+      events: timeline.eventCount,
+      sessions: countSessions([timeline]),
+      activeDays: countActiveDays(timeline),
+      duration: timeline.activeDuration,
+      tokens: sumEventTokens(timeline.events),
+      share: totalDuration > 0 ? timeline.activeDuration / totalDuration : 0,
+      activity: countActivity(timeline, startTime, endTime, cells),
+    };
+  });
+
+  return { startTime, endTime, width, braille, projects };
+}
+
+// Which project takes more time, and by how much
+export function describeComparison(projects: ProjectMetrics[]): string {
+  const ranked = [...projects].sort((a, b) => b.duration - a.duration);
+  const [first, second] = ranked;
+  if (!first || first.duration === 0) return 'None of the projects had activity in this range';
+  if (!second || second.duration === 0) {
+    return `Only ${first.projectName} had activity in this range`;
+  }
+  if (first.duration === second.duration) return 'The projects took the same time';
+
+  const ratio = first.duration / second.duration;
+  return `${first.projectName} took ${ratio.toFixed(1)}× the Claude time of ${second.projectName}`;
+}
+
+export function formatComparison(comparison: ProjectComparison): string {
+  const { projects, startTime, endTime, width, braille } = comparison;
+  const labelWidth = Math.max(11, ...projects.map(p => p.projectName.length)) + 2;
+  const columnWidth = Math.max(10, ...projects.map(p => p.projectName.length + 2));
+
+  const row = (label: string, values: string[]) =>
+    label.padEnd(labelWidth) + values.map(value => value.padStart(columnWidth)).join('');
+
+  const lines = [
+    `${format(startTime, 'yyyy-MM-dd HH:mm')} - ${format(endTime, 'yyyy-MM-dd HH:mm')}`,
+    '',
+    row('', projects.map(p => p.projectName)),
+    row('Events', projects.map(p => `${p.events}`)),
+    row('Sessions', projects.map(p => `${p.sessions}`)),
+    row('Active days', projects.map(p => `${p.activeDays}`)),
+    row('Duration', projects.map(p => `${p.duration}m`)),
+    row('Tokens', projects.map(p => formatTokens(p.tokens))),
+    row('Share', projects.map(p => `${Math.round(p.share * 100)}%`)),
+    '',
+    `${''.padEnd(labelWidth)}${createTimeAxis(startTime, endTime, width)}`,
+  ];
+
+  // One scale for every project so the rows can be compared cell by cell
+  const max = Math.max(...projects.flatMap(p => p.activity), 0);
+  const draw = braille ? brailleSparkline : sparkline;
+  for (const project of projects) {
+    lines.push(`${project.projectName.padEnd(labelWidth)}${draw(project.activity, max)}`);
+  }
+
+  lines.push('');
+  lines.push(describeComparison(projects));
+  return lines.join('\n');
+}
@@ -0,0 +1,60 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import {
+  analyzeContextPressure,
+  formatContextPressure,
+  getContextTokens,
+  isCompactionEvent,
+} from '../index';
+
+const reply = (minute: number, sessionId: string, contextTokens: number): Event => ({
+  timestamp: new Date(Date.UTC(2025, 0, 1, 10, minute)).toISOString(),
+  sessionId,
+  type: 'assistant',
+  message: { usage: { input_tokens: 10, cache_read_input_tokens: contextTokens - 10 } },
+});
+
+describe('context pressure', () => {
+  it('should count cached tokens as part of the context', () => {
+    expect(getContextTokens(reply(0, 's', 50_000))).toBe(50_000);
+    expect(
+      getContextTokens({
+        timestamp: '2025-01-01T10:00:00Z',
+        usage: { inputTokens: 5, cacheReadTokens: 100, cacheWriteTokens: 20, outputTokens: 9 },
+      })
+    ).toBe(125);
+  });
+
+  it('should recognize compaction records', () => {
+    expect(isCompactionEvent({ timestamp: '', type: 'system', subtype: 'compact_boundary' })).toBe(
+      true
+    );
+    expect(isCompactionEvent({ timestamp: '', type: 'user', isCompactSummary: true })).toBe(true);
+    expect(isCompactionEvent(reply(0, 's', 10))).toBe(false);
+  });
+
+  it('should count sessions near the limit and compacted per project', () => {
+    const compaction: Event = {
+      timestamp: new Date(Date.UTC(2025, 0, 1, 11)).toISOString(),
+      sessionId: 'b',
+      type: 'system',
+      subtype: 'compact_boundary',
+    };
+    const rows = analyzeContextPressure([
+      createTimeline('api', [reply(0, 'x', 20_000)]),
+      createTimeline('web', [
+        reply(0, 'a', 40_000),
+        reply(1, 'a', 170_000),
+        reply(2, 'b', 150_000),
+        compaction,
+        reply(3, 'c', 30_000),
+      ]),
+    ]);
+
+    expect(rows).toEqual([
+      { projectName: 'web', sessions: 3, nearLimit: 1, compacted: 1, peakTokens: 170_000 },
+      { projectName: 'api', sessions: 1, nearLimit: 0, compacted: 0, peakTokens: 20_000 },
+    ]);
+    expect(formatContextPressure(rows)).toMatch(/web\s+3\s+1\s+1\s+170\.0k/);
+  });
+});
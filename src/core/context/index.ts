@@ -0,0 +1,89 @@
+import { Event, Timeline } from '../../models/models';
+import { formatTokens } from '../../utils/tokens';
+
+// Claude's context window, and the share of it past which Claude Code compacts soon
+export const DEFAULT_CONTEXT_WINDOW = 200_000;
+const NEAR_LIMIT_FRACTION = 0.8;
+
+export interface ContextPressure {
+  projectName: string;
+  sessions: number;
+  // Sessions whose context grew past NEAR_LIMIT_FRACTION of the window
+  nearLimit: number;
+  // Sessions containing a compaction
+  compacted: number;
+  // Largest context seen in any session of the project, in tokens
+  peakTokens: number;
+}
+
+// Tokens in the prompt sent with an assistant message, including cached ones
+export function getContextTokens(event: Event): number {
+  if (event.usage) {
+    const { inputTokens = 0, cacheWriteTokens = 0, cacheReadTokens = 0 } = event.usage;
+    return inputTokens + cacheWriteTokens + cacheReadTokens;
+  }
+
+  const usage = event.message?.usage;
+  if (!usage || typeof usage !== 'object') return 0;
+  const count = (value: unknown) => (typeof value === 'number' ? value : 0);
+  return (
+    count(usage.input_tokens) +
+    count(usage.cache_creation_input_tokens) +
+    count(usage.cache_read_input_tokens)
+  );
+}
+
+// Compaction boundaries and the summaries Claude Code continues from afterwards
+export function isCompactionEvent(event: Event): boolean {
+  return event.subtype === 'compact_boundary' || event.isCompactSummary === true;
+}
+
+export function analyzeContextPressure(
+  timelines: Timeline[],
+  contextWindow = DEFAULT_CONTEXT_WINDOW
+): ContextPressure[] {
+  const threshold = contextWindow * NEAR_LIMIT_FRACTION;
+
+  return timelines
+    .map(timeline => {
+      const sessions = new Map<string, { peak: number; compacted: boolean }>();
+      for (const event of timeline.events) {
+        if (!event.sessionId) continue;
+        const session = sessions.get(event.sessionId) ?? { peak: 0, compacted: false };
+        session.peak = Math.max(session.peak, getContextTokens(event));
+        session.compacted ||= isCompactionEvent(event);
+        sessions.set(event.sessionId, session);
+      }
+
+      const values = Array.from(sessions.values());
+      return {
+        projectName: timeline.projectName,
+        sessions: values.length,
+        nearLimit: values.filter(session => session.peak >= threshold).length,
+        compacted: values.filter(session => session.compacted).length,
+        peakTokens: Math.max(0, ...values.map(session => session.peak)),
+      };
+    })
+    .filter(pressure => pressure.sessions > 0)
+    .sort((a, b) => b.nearLimit + b.compacted - (a.nearLimit + a.compacted));
+}
+
+export function formatContextPressure(rows: ContextPressure[]): string {
+  if (rows.length === 0) {
+    return 'No sessions found in the specified time range';
+  }
+
+  const nameWidth = Math.max(7, ...rows.map(row => row.projectName.length)) + 2;
+  const lines = [
+    `${'Project'.padEnd(nameWidth)}${'Sessions'.padStart(10)}${'Near limit'.padStart(12)}` +
+      `${'Compacted'.padStart(11)}${'Peak'.padStart(8)}`,
+  ];
+  for (const row of rows) {
+    lines.push(
+      `${row.projectName.padEnd(nameWidth)}${`${row.sessions}`.padStart(10)}` +
+        `${`${row.nearLimit}`.padStart(12)}${`${row.compacted}`.padStart(11)}` +
+        `${formatTokens(row.peakTokens).padStart(8)}`
+    );
+  }
+  return lines.join('\n');
+}
@@ -0,0 +1,123 @@
+import { spawnSync } from 'child_process';
+import { mkdtemp, readFile, rm, writeFile } from 'fs/promises';
+import { createConnection, Server } from 'net';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import {
+  ControlHandlers,
+  createControlServer,
+  handleControlLine,
+  listenOnSocket,
+  parseReportRange,
+} from '../index';
+
+const handlers: ControlHandlers = {
+  status: async () => ({ pid: 1 }),
+  reload: async () => ({ ok: true }),
+  report: async range => ({ range }),
+};
+
+// Send lines and collect one response line per request
+function query(path: string, lines: string[]): Promise<unknown[]> {
+  return new Promise((resolve, reject) => {
+    let received = '';
+    const socket = createConnection(path, () => socket.write(lines.join('\n') + '\n'));
+    socket.on('data', chunk => {
+      received += chunk.toString();
+      const responses = received.split('\n').filter(Boolean);
+      if (responses.length === lines.length) {
+        socket.end();
+        resolve(responses.map(response => JSON.parse(response)));
+      }
+    });
+    socket.on('error', reject);
+  });
+}
+
+// Leave a socket file behind the way a killed process does
+function leaveStaleSocket(path: string): void {
+  const script =
+    `require('net').createServer().listen(${JSON.stringify(path)}, ` +
+    `() => process.kill(process.pid, 'SIGKILL'))`;
+  spawnSync(process.execPath, ['-e', script]);
+}
+
+describe('control socket', () => {
+  it('should parse report ranges', () => {
+    expect(parseReportRange()).toEqual({ days: 1 });
+    expect(parseReportRange('7d')).toEqual({ days: 7 });
+    expect(parseReportRange('12h')).toEqual({ hours: 12 });
+    expect(parseReportRange('all')).toEqual({ allTime: true });
+    expect(parseReportRange('0d')).toBeNull();
+    expect(parseReportRange('week')).toBeNull();
+  });
+
+  it('should answer errors instead of failing', async () => {
+    expect(await handleControlLine('stop', handlers)).toEqual({
+      error: "unknown command 'stop'. Available commands: status, reload, report",
+    });
+    expect(await handleControlLine('report 7w', handlers)).toEqual({
+      error: "invalid range '7w'. Use e.g. 7d, 12h or all",
+    });
+    expect(
+      await handleControlLine('status', {
+        ...handlers,
+        status: () => Promise.reject(new Error('no logs')),
+      })
+    ).toEqual({ error: 'no logs' });
+  });
+
+  describe('server', () => {
+    let directory: string;
+    let server: Server;
+
+    beforeEach(async () => {
+      directory = await mkdtemp(join(tmpdir(), 'ccstat-control-'));
+      server = createControlServer(handlers);
+    });
+
+    afterEach(async () => {
+      await new Promise(resolve => server.close(resolve));
+      await rm(directory, { recursive: true, force: true });
+    });
+
+    it('should answer each command with a line of JSON, in order', async () => {
+      const path = join(directory, 'ccstat.sock');
+      await listenOnSocket(server, path);
+
+      expect(await query(path, ['status', 'report 7d', 'reload'])).toEqual([
+        { pid: 1 },
+        { range: { days: 7 } },
+        { ok: true },
+      ]);
+    });
+
+    it('should replace a stale socket file', async () => {
+      const path = join(directory, 'ccstat.sock');
+      leaveStaleSocket(path);
+      await listenOnSocket(server, path);
+
+      expect(await query(path, ['status'])).toEqual([{ pid: 1 }]);
+    });
+
+    it('should leave a file that is not a socket alone', async () => {
+      const path = join(directory, 'notes.txt');
+      await writeFile(path, 'keep me');
+
+      await expect(listenOnSocket(server, path)).rejects.toThrow(
+        `${path} exists and is not a socket`
+      );
+      expect(await readFile(path, 'utf-8')).toBe('keep me');
+    });
+
+    it('should refuse a socket another process is listening on', async () => {
+      const path = join(directory, 'ccstat.sock');
+      await listenOnSocket(server, path);
+      const other = createControlServer(handlers);
+
+      await expect(listenOnSocket(other, path)).rejects.toThrow(
+        `${path} is in use by another ccstat process`
+      );
+    });
+  });
+});
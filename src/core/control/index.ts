@@ -0,0 +1,107 @@
+import { existsSync } from 'fs';
+import { lstat, unlink } from 'fs/promises';
+import { createConnection, createServer, Server, Socket } from 'net';
+import { createInterface } from 'readline';
+import { TimeRange } from '../../utils/timeRange';
+
+export const CONTROL_COMMAND_VALUES = ['status', 'reload', 'report'] as const;
+
+export type ControlCommand = (typeof CONTROL_COMMAND_VALUES)[number];
+
+export function isValidControlCommand(value: string): value is ControlCommand {
+  return CONTROL_COMMAND_VALUES.includes(value as ControlCommand);
+}
+
+export interface ControlHandlers {
+  // State of the running process
+  status: () => Promise<unknown>;
+  // Drop cached lookups, such as repository names
+  reload: () => Promise<unknown>;
+  // Usage export for a range
+  report: (range: TimeRange) => Promise<unknown>;
+}
+
+// Range argument of `report`: "7d", "12h" or "all"; a day when left out
+export function parseReportRange(value?: string): TimeRange | null {
+  if (!value) return { days: 1 };
+  if (value === 'all') return { allTime: true };
+
+  const match = /^(\d+)([dh])$/.exec(value);
+  if (!match || parseInt(match[1]) === 0) return null;
+  return match[2] === 'd' ? { days: parseInt(match[1]) } : { hours: parseInt(match[1]) };
+}
+
+// Run one command line, e.g. "report 7d"; failures are answered with { error } as over HTTP
+export async function handleControlLine(line: string, handlers: ControlHandlers): Promise<unknown> {
+  const [command, ...args] = line.trim().split(/\s+/);
+
+  try {
+    if (!isValidControlCommand(command)) {
+      throw new Error(
+        `unknown command '${command}'. Available commands: ${CONTROL_COMMAND_VALUES.join(', ')}`
+      );
+    }
+
+    switch (command) {
+      case 'status':
+        return await handlers.status();
+      case 'reload':
+        return await handlers.reload();
+      case 'report': {
+        const range = parseReportRange(args[0]);
+        if (!range) throw new Error(`invalid range '${args[0]}'. Use e.g. 7d, 12h or all`);
+        return await handlers.report(range);
+      }
+    }
+  } catch (error) {
+    return { error: error instanceof Error ? error.message : 'unknown error' };
+  }
+}
+
+// One command per line in, one line of JSON out, answered in order
+export function createControlServer(handlers: ControlHandlers): Server {
+  return createServer(socket => {
+    let queue = Promise.resolve();
+    createInterface({ input: socket }).on('line', line => {
+      if (line.trim() === '') return;
+      queue = queue.then(async () => {
+        const response = await handleControlLine(line, handlers);
+        if (!socket.destroyed) socket.write(JSON.stringify(response) + '\n');
+      });
+    });
+    socket.on('error', () => socket.destroy());
+  });
+}
+
+function isSocketInUse(path: string): Promise<boolean> {
+  return new Promise(resolve => {
+    const probe: Socket = createConnection(path)
+      .on('connect', () => {
+        probe.end();
+        resolve(true);
+      })
+      .on('error', () => resolve(false));
+  });
+}
+
+// Listen on a unix socket, replacing one left behind by a process that didn't shut down cleanly.
+// Anything other than a socket at the path is left alone.
+export async function listenOnSocket(server: Server, path: string): Promise<void> {
+  if (existsSync(path)) {
+    if (!(await lstat(path)).isSocket()) {
+      throw new Error(`${path} exists and is not a socket`);
+    }
+    if (await isSocketInUse(path)) {
+      throw new Error(`${path} is in use by another ccstat process`);
+    }
+    await unlink(path);
+  }
+
+  await new Promise<void>((resolve, reject) => {
+    server.once('error', reject);
+    server.listen(path, () => {
+      server.off('error', reject);
+      resolve();
+    });
+  });
+}
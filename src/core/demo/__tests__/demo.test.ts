@@ -0,0 +1,83 @@
+import { generateDemoTimelines } from '../index';
+
+describe('generateDemoTimelines', () => {
+  const startTime = new Date('2025-01-01T00:00:00Z');
+  const endTime = new Date('2025-01-08T00:00:00Z');
+
+  it('should generate the requested number of projects', () => {
+    const timelines = generateDemoTimelines(startTime, endTime, { projects: 4, seed: 1 });
+    expect(timelines).toHaveLength(4);
+    expect(new Set(timelines.map(t => t.projectName)).size).toBe(4);
+  });
+
+  it('should be reproducible with the same seed', () => {
+    const first = generateDemoTimelines(startTime, endTime, { projects: 3, seed: 42 });
+    const second = generateDemoTimelines(startTime, endTime, { projects: 3, seed: 42 });
+    expect(second).toEqual(first);
+  });
+
+  it('should produce different data with different seeds', () => {
+    const first = generateDemoTimelines(startTime, endTime, { projects: 3, seed: 1 });
+    const second = generateDemoTimelines(startTime, endTime, { projects: 3, seed: 2 });
+    expect(second).not.toEqual(first);
+  });
+
+  it('should keep all events within the requested range', () => {
+    const timelines = generateDemoTimelines(startTime, endTime, { projects: 5, seed: 7 });
+
+    for (const timeline of timelines) {
+      expect(timeline.eventCount).toBe(timeline.events.length);
+      for (const event of timeline.events) {
+        const time = new Date(event.timestamp).getTime();
+        expect(time).toBeGreaterThanOrEqual(startTime.getTime());
+        expect(time).toBeLessThanOrEqual(endTime.getTime());
+      }
+    }
+  });
+
+  it('should suffix project names when exceeding the built-in name list', () => {
+    const timelines = generateDemoTimelines(startTime, endTime, { projects: 12, seed: 3 });
+    expect(timelines.map(t => t.projectName)).toContain('web-dashboard-2');
+  });
+
+  it('should scale event volume with events per day', () => {
+    const countEvents = (eventsPerDay: number) =>
+      generateDemoTimelines(startTime, endTime, { projects: 5, seed: 11, eventsPerDay }).reduce(
+        (sum, t) => sum + t.eventCount,
+        0
+      );
+
+    expect(countEvents(1000)).toBeGreaterThan(countEvents(50));
+  });
+
+  it('should attach token usage to assistant events only', () => {
+    const timelines = generateDemoTimelines(startTime, endTime, {
+      projects: 2,
+      seed: 5,
+      averageTokens: 500,
+    });
+
+    for (const event of timelines.flatMap(t => t.events)) {
+      if (event.type === 'user') {
+        expect(event.usage).toBeUndefined();
+      } else {
+        expect(event.usage?.outputTokens).toBeGreaterThanOrEqual(0);
+      }
+    }
+  });
+
+  it('should spread sessions across worktree directories', () => {
+    const timelines = generateDemoTimelines(startTime, endTime, {
+      projects: 1,
+      seed: 9,
+      eventsPerDay: 500,
+      worktrees: 3,
+    });
+    const directories = new Set(timelines[0].events.map(e => e.cwd));
+
+    expect(directories.size).toBeGreaterThan(1);
+    for (const directory of directories) {
+      expect(directory).toMatch(/^\/home\/demo\/web-dashboard(\/\.worktree\/feature-\d)?$/);
+    }
+  });
+});
@@ -0,0 +1,130 @@
+import { Event, Timeline } from '../../models/models';
+import { createTimeline } from '../parser';
+
+export interface DemoOptions {
+  projects: number;
+  seed?: number;
+  eventsPerDay?: number;
+  averageTokens?: number;
+  worktrees?: number;
+}
+
+const DEFAULT_EVENTS_PER_DAY = 150;
+const DEFAULT_AVERAGE_TOKENS = 800;
+const AVERAGE_SESSION_EVENTS = 55;
+
+const DEMO_PROJECT_NAMES = [
+  'web-dashboard',
+  'api-server',
+  'mobile-app',
+  'data-pipeline',
+  'infra-terraform',
+  'docs-site',
+  'cli-tools',
+  'ml-experiments',
+  'auth-service',
+  'design-system',
+];
+
+const MINUTE_MS = 60 * 1000;
+const DAY_MS = 24 * 60 * MINUTE_MS;
+
+// Small seeded PRNG (mulberry32) so demo output and fuzz runs are reproducible
+export function createRandom(seed: number): () => number {
+  let state = seed >>> 0;
+  return () => {
+    state = (state + 0x6d2b79f5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
+
+// Exponentially distributed token count around the configured average
+function pickTokenCount(random: () => number, average: number): number {
+  return Math.round(-Math.log(1 - random()) * average);
+}
+
+function getDemoDirectory(projectName: string, worktree: number): string {
+  const baseDir = `/home/demo/${projectName}`;
+  return worktree === 0 ? baseDir : `${baseDir}/.worktree/feature-${worktree}`;
+}
+
+function getDemoProjectName(index: number): string {
+  const baseName = DEMO_PROJECT_NAMES[index % DEMO_PROJECT_NAMES.length];
+  const round = Math.floor(index / DEMO_PROJECT_NAMES.length);
+  return round === 0 ? baseName : `${baseName}-${round + 1}`;
+}
+
+// Pick a session start, preferring daytime hours like a real working pattern
+function pickSessionStart(random: () => number, startMs: number, rangeMs: number): number {
+  let candidate = startMs + random() * rangeMs;
+  for (let attempt = 0; attempt < 3; attempt++) {
+    const hour = new Date(candidate).getHours();
+    if (hour >= 8 && hour < 23) break;
+    candidate = startMs + random() * rangeMs;
+  }
+  return candidate;
+}
+
+// Synthesize realistic in-memory timelines without touching real log directories
+export function generateDemoTimelines(
+  startTime: Date,
+  endTime: Date,
+  options: DemoOptions
+): Timeline[] {
+  const random = createRandom(options.seed ?? Date.now());
+  const eventsPerDay = options.eventsPerDay ?? DEFAULT_EVENTS_PER_DAY;
+  const averageTokens = options.averageTokens ?? DEFAULT_AVERAGE_TOKENS;
+  const worktrees = options.worktrees ?? 0;
+  const startMs = startTime.getTime();
+  const endMs = endTime.getTime();
+  const rangeMs = Math.max(endMs - startMs, MINUTE_MS);
+  const timelines: Timeline[] = [];
+
+  for (let i = 0; i < options.projects; i++) {
+    const projectName = getDemoProjectName(i);
+    // Busier projects get more sessions; the weight averages to 1 across projects
+    const weight = 0.25 + 2.25 * random() ** 2;
+    const expectedEvents = (rangeMs / DAY_MS) * eventsPerDay * weight;
+    const sessionCount = Math.max(1, Math.round(expectedEvents / AVERAGE_SESSION_EVENTS));
+    const events: Event[] = [];
+
+    for (let s = 0; s < sessionCount; s++) {
+      const sessionId = `demo-${i}-${s}`;
+      const cwd = getDemoDirectory(projectName, Math.floor(random() * (worktrees + 1)));
+      const eventCount = 10 + Math.floor(random() * 90);
+      let current = pickSessionStart(random, startMs, rangeMs);
+
+      for (let e = 0; e < eventCount && current <= endMs; e++) {
+        const isUser = random() < 0.4;
+        const event: Event = {
+          timestamp: new Date(current).toISOString(),
+          sessionId,
+          cwd,
+          type: isUser ? 'user' : 'assistant',
+        };
+
+        if (!isUser) {
+          event.usage = {
+            inputTokens: pickTokenCount(random, averageTokens * 4),
+            outputTokens: pickTokenCount(random, averageTokens),
+          };
+        }
+
+        events.push(event);
+
+        // Mostly short intervals with occasional breaks
+        const isBreak = random() < 0.05;
+        current += isBreak ? (10 + random() * 20) * MINUTE_MS : (5 + random() * 115) * 1000;
+      }
+    }
+
+    if (events.length === 0) continue;
+
+    timelines.push(createTimeline(projectName, events));
+  }
+
+  return timelines;
+}
@@ -0,0 +1,48 @@
+import { Event } from '../../../models/models';
+import { calculateActiveDuration, DEFAULT_DURATION_MODEL, getEventKind } from '../index';
+
+const at = (minute: number, extra: Partial<Event> = {}): Event => ({
+  timestamp: new Date(Date.UTC(2025, 0, 1, 10, minute)).toISOString(),
+  ...extra,
+});
+
+const toolResult = { type: 'user', message: { content: [{ type: 'tool_result' }] } };
+
+describe('calculateActiveDuration', () => {
+  // Two bursts of work with a 20 minute break between them
+  const events = [at(0), at(3), at(4, toolResult), at(24, { type: 'user' }), at(26)];
+
+  it('should count gaps up to the idle threshold by default', () => {
+    expect(calculateActiveDuration(events, DEFAULT_DURATION_MODEL)).toBe(6);
+    expect(calculateActiveDuration([at(0)], DEFAULT_DURATION_MODEL)).toBe(5);
+  });
+
+  it('should replace the gap before an event with its fixed minutes', () => {
+    const model = { ...DEFAULT_DURATION_MODEL, eventMinutes: { user: 1, tool_result: 0 } };
+    // 3 + 0 (tool result) + 1 (prompt after the break) + 2
+    expect(calculateActiveDuration(events, model)).toBe(6);
+  });
+
+  it('should count clock slots with activity', () => {
+    const model = { ...DEFAULT_DURATION_MODEL, algorithm: 'buckets' as const };
+    // The 10:00, 10:20 and 10:25 slots
+    expect(calculateActiveDuration(events, model)).toBe(15);
+  });
+
+  it('should count fixed minutes per event', () => {
+    const model = {
+      ...DEFAULT_DURATION_MODEL,
+      algorithm: 'events' as const,
+      eventMinutes: { tool_result: 0, user: 2 },
+    };
+    expect(calculateActiveDuration(events, model)).toBe(5);
+  });
+});
+
+describe('getEventKind', () => {
+  it('should tell tool results apart from prompts', () => {
+    expect(getEventKind(at(0, toolResult))).toBe('tool_result');
+    expect(getEventKind(at(0, { type: 'user', message: { content: 'hi' } }))).toBe('user');
+    expect(getEventKind(at(0))).toBe('unknown');
+  });
+});
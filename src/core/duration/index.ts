@@ -0,0 +1,91 @@
+import { Event } from '../../models/models';
+
+// How active minutes are estimated from event timestamps:
+// - interval: gaps between consecutive events up to idleMinutes count as work
+// - buckets: every idleMinutes-long slot of the clock with an event counts in full
+// - events: each event counts a fixed number of minutes (eventMinutes, 1 by default)
+export const DURATION_ALGORITHM_VALUES = ['interval', 'buckets', 'events'] as const;
+export type DurationAlgorithm = (typeof DURATION_ALGORITHM_VALUES)[number];
+
+export function isValidDurationAlgorithm(value: string): value is DurationAlgorithm {
+  return DURATION_ALGORITHM_VALUES.includes(value as DurationAlgorithm);
+}
+
+export interface DurationModel {
+  algorithm: DurationAlgorithm;
+  idleMinutes: number;
+  // Fixed minutes per event kind (an event type, or "tool_result"). With the interval
+  // algorithm they replace the gap before such an event, e.g. { user: 1, tool_result: 0 }.
+  eventMinutes: Record<string, number>;
+}
+
+export const DEFAULT_DURATION_MODEL: DurationModel = {
+  algorithm: 'interval',
+  idleMinutes: 5,
+  eventMinutes: {},
+};
+
+const MINUTE_MS = 60 * 1000;
+
+// Every timeline is measured the same way, so the model is set once per run from the config
+let currentModel = DEFAULT_DURATION_MODEL;
+
+export function setDurationModel(model: Partial<DurationModel> = {}): void {
+  currentModel = { ...DEFAULT_DURATION_MODEL, ...model };
+}
+
+export function getDurationModel(): DurationModel {
+  return currentModel;
+}
+
+// Tool results are logged as user messages but take no time from the user
+export function getEventKind(event: Event): string {
+  const content = event.message?.content;
+  if (
+    event.type === 'user' &&
+    Array.isArray(content) &&
+    content.some(part => part?.type === 'tool_result')
+  ) {
+    return 'tool_result';
+  }
+  return event.type ?? 'unknown';
+}
+
+// Active minutes of events sorted by timestamp
+export function calculateActiveDuration(events: Event[], model = currentModel): number {
+  const fixedMinutes = (event: Event) => model.eventMinutes[getEventKind(event)];
+
+  switch (model.algorithm) {
+    case 'events':
+      return Math.round(events.reduce((sum, event) => sum + (fixedMinutes(event) ?? 1), 0));
+
+    case 'buckets': {
+      const slotMs = model.idleMinutes * MINUTE_MS;
+      const slots = new Set(events.map(e => Math.floor(new Date(e.timestamp).getTime() / slotMs)));
+      return slots.size * model.idleMinutes;
+    }
+
+    case 'interval': {
+      if (events.length <= 1) return model.idleMinutes; // Minimum for a single event
+
+      let activeMinutes = 0;
+      for (let i = 1; i < events.length; i++) {
+        const fixed = fixedMinutes(events[i]);
+        if (fixed !== undefined) {
+          activeMinutes += fixed;
+          continue;
+        }
+
+        const prevTime = new Date(events[i - 1].timestamp);
+        const currTime = new Date(events[i].timestamp);
+        const intervalMinutes = (currTime.getTime() - prevTime.getTime()) / MINUTE_MS;
+
+        // Only count intervals up to the threshold as active time
+        if (intervalMinutes <= model.idleMinutes) {
+          activeMinutes += intervalMinutes;
+        }
+      }
+      return Math.round(activeMinutes);
+    }
+  }
+}
@@ -0,0 +1,42 @@
+import { mkdir, mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { collectEnvironmentReport, formatEnvironmentReport } from '../index';
+
+describe('environment report', () => {
+  let root: string;
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-environment-'));
+    await mkdir(join(root, 'projects', '-home-user-web'), { recursive: true });
+    await writeFile(join(root, 'projects', '-home-user-web', 'a.jsonl'), '{}\n');
+    await writeFile(join(root, 'projects', '-home-user-web', 'b.jsonl'), '{}\n');
+    await mkdir(join(root, 'data'));
+    await writeFile(join(root, 'data', 'events.db'), 'x'.repeat(1024));
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should describe config, data and log directories', async () => {
+    const report = await collectEnvironmentReport({
+      version: '1.2.3',
+      configPath: join(root, 'config.json'),
+      dataDir: join(root, 'data'),
+      logDirs: [join(root, 'projects'), join(root, 'missing')],
+    });
+
+    expect(report.configExists).toBe(false);
+    expect(report.dataDirBytes).toBe(1024);
+    expect(report.logDirs).toEqual([
+      { path: join(root, 'projects'), exists: true, projects: 1, logFiles: 2 },
+      { path: join(root, 'missing'), exists: false, projects: 0, logFiles: 0 },
+    ]);
+
+    const text = formatEnvironmentReport(report);
+    expect(text).toContain('ccstat 1.2.3');
+    expect(text).toContain(`${join(root, 'projects')} (1 projects, 2 log files)`);
+    expect(text).toContain(`${join(root, 'missing')} (not found)`);
+  });
+});
@@ -0,0 +1,127 @@
+import { readdir, stat } from 'fs/promises';
+import { join } from 'path';
+import { getProjectsDirs } from '../parser';
+
+export interface LogDirReport {
+  path: string;
+  exists: boolean;
+  projects: number;
+  logFiles: number;
+}
+
+export interface EnvironmentReport {
+  version: string;
+  node: string;
+  platform: string;
+  configPath: string;
+  configExists: boolean;
+  configError?: string;
+  profile?: string;
+  dataDir: string;
+  dataDirBytes: number;
+  logDirs: LogDirReport[];
+}
+
+export interface EnvironmentOptions {
+  version: string;
+  configPath: string;
+  // Why the config file couldn't be loaded, if it couldn't
+  configError?: string;
+  dataDir: string;
+  profile?: string;
+  logDirs?: string[];
+}
+
+async function exists(path: string): Promise<boolean> {
+  try {
+    await stat(path);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+// Total size of the files under a directory, 0 when it doesn't exist
+async function getDirectorySize(directory: string): Promise<number> {
+  let entries;
+  try {
+    entries = await readdir(directory, { withFileTypes: true });
+  } catch {
+    return 0;
+  }
+
+  let total = 0;
+  for (const entry of entries) {
+    const path = join(directory, entry.name);
+    if (entry.isDirectory()) {
+      total += await getDirectorySize(path);
+    } else if (entry.isFile()) {
+      total += (await stat(path)).size;
+    }
+  }
+  return total;
+}
+
+export async function inspectLogDir(path: string): Promise<LogDirReport> {
+  let projects: string[];
+  try {
+    projects = await readdir(path);
+  } catch {
+    return { path, exists: false, projects: 0, logFiles: 0 };
+  }
+
+  let projectCount = 0;
+  let logFiles = 0;
+  for (const project of projects) {
+    try {
+      const files = await readdir(join(path, project));
+      projectCount++;
+      logFiles += files.filter(file => file.endsWith('.jsonl')).length;
+    } catch {
+      // Not a project directory
+    }
+  }
+  return { path, exists: true, projects: projectCount, logFiles };
+}
+
+// Everything worth attaching to a bug report, without reading any log contents
+export async function collectEnvironmentReport(
+  options: EnvironmentOptions
+): Promise<EnvironmentReport> {
+  return {
+    version: options.version,
+    node: process.version,
+    platform: `${process.platform} ${process.arch}`,
+    configPath: options.configPath,
+    configExists: await exists(options.configPath),
+    configError: options.configError,
+    profile: options.profile,
+    dataDir: options.dataDir,
+    dataDirBytes: await getDirectorySize(options.dataDir),
+    logDirs: await Promise.all(getProjectsDirs(options.logDirs).map(inspectLogDir)),
+  };
+}
+
+function formatMegabytes(bytes: number): string {
+  return `${(bytes / (1024 * 1024)).toFixed(1)} MB`;
+}
+
+export function formatEnvironmentReport(report: EnvironmentReport): string {
+  const lines = [
+    `ccstat ${report.version}`,
+    `Node.js ${report.node} (${report.platform})`,
+    '',
+    `Config:   ${report.configPath}${report.configExists ? '' : ' (not found)'}`,
+    ...(report.configError ? [`          ${report.configError}`] : []),
+    `Profile:  ${report.profile ?? '(none)'}`,
+    `Data dir: ${report.dataDir} (${formatMegabytes(report.dataDirBytes)})`,
+    'Log dirs:',
+  ];
+  for (const dir of report.logDirs) {
+    lines.push(
+      `  ${dir.path} ` +
+        (dir.exists ? `(${dir.projects} projects, ${dir.logFiles} log files)` : '(not found)')
+    );
+  }
+  return lines.join('\n');
+}
@@ -0,0 +1,87 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import {
+  ActivityWatchEvent,
+  createActivityWatchEvents,
+  createActivityWatchImport,
+  pushActivityWatchEvents,
+} from '../activitywatch';
+
+const event = (minute: number): Event => ({
+  timestamp: new Date(Date.UTC(2025, 5, 9, 10, minute)).toISOString(),
+});
+
+describe('activitywatch export', () => {
+  const timelines = [
+    createTimeline('web', [event(0), event(3), event(5), event(30)]),
+    createTimeline('api', [event(10), event(12)]),
+  ];
+
+  it('should turn runs of events within the idle threshold into spans', () => {
+    expect(createActivityWatchEvents(timelines, 5)).toEqual([
+      { timestamp: '2025-06-09T10:00:00.000Z', duration: 300, data: { project: 'web', events: 3 } },
+      { timestamp: '2025-06-09T10:10:00.000Z', duration: 120, data: { project: 'api', events: 2 } },
+      // A lone event still gets a visible span
+      { timestamp: '2025-06-09T10:30:00.000Z', duration: 60, data: { project: 'web', events: 1 } },
+    ]);
+  });
+
+  it('should wrap the spans in an importable bucket', () => {
+    const data = createActivityWatchImport([], 'laptop', new Date(Date.UTC(2025, 5, 9)));
+
+    expect(data.buckets['ccstat-ai-coding_laptop']).toEqual({
+      id: 'ccstat-ai-coding_laptop',
+      created: '2025-06-09T00:00:00.000Z',
+      type: 'app.ai.coding',
+      client: 'ccstat',
+      hostname: 'laptop',
+      events: [],
+    });
+  });
+
+  describe('pushActivityWatchEvents', () => {
+    afterEach(() => {
+      jest.restoreAllMocks();
+    });
+
+    it('should send spans from the latest stored one on, replacing it', async () => {
+      const latest: ActivityWatchEvent = {
+        id: 7,
+        timestamp: '2025-06-09T10:10:00.000Z',
+        duration: 60,
+        data: { project: 'api', events: 1 },
+      };
+      const fetchMock = jest
+        .spyOn(global, 'fetch')
+        .mockResolvedValueOnce(new Response(null, { status: 304 }))
+        .mockResolvedValueOnce(new Response(JSON.stringify([latest])))
+        .mockResolvedValueOnce(new Response('[]'));
+
+      const sent = await pushActivityWatchEvents(
+        'http://localhost:5600',
+        createActivityWatchEvents(timelines, 5),
+        'laptop'
+      );
+
+      expect(sent).toBe(2);
+      expect(fetchMock.mock.calls[1][0].toString()).toBe(
+        'http://localhost:5600/api/0/buckets/ccstat-ai-coding_laptop/events?limit=1'
+      );
+      const body = JSON.parse(fetchMock.mock.calls[2][1]!.body as string);
+      expect(body.map((e: ActivityWatchEvent) => [e.id, e.data.project, e.duration])).toEqual([
+        [7, 'api', 120],
+        [undefined, 'web', 60],
+      ]);
+    });
+
+    it('should report server errors', async () => {
+      jest
+        .spyOn(global, 'fetch')
+        .mockResolvedValue(new Response(null, { status: 500, statusText: 'Server Error' }));
+
+      await expect(pushActivityWatchEvents('http://localhost:5600', [], 'laptop')).rejects.toThrow(
+        'ActivityWatch responded with 500 Server Error'
+      );
+    });
+  });
+});
@@ -0,0 +1,55 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import {
+  createActivityCard,
+  formatReadmeMarkdown,
+  formatReadmeSvg,
+  README_END_MARKER,
+  README_START_MARKER,
+} from '../githubReadme';
+
+// 2025-06-12 is a Thursday, so the card runs from Friday 06-06
+const event = (day: number, hour: number, minute: number): Event => ({
+  timestamp: new Date(2025, 5, day, hour, minute).toISOString(),
+});
+
+describe('github readme card', () => {
+  const now = new Date(2025, 5, 12, 20);
+  const timelines = [
+    createTimeline('web', [event(9, 10, 0), event(9, 10, 4), event(12, 9, 0), event(12, 9, 3)]),
+    createTimeline('a<b>', [event(10, 14, 0), event(10, 14, 2)]),
+    // Before the card's first day
+    createTimeline('old', [event(5, 10, 0), event(5, 10, 4)]),
+  ];
+
+  it('should total the last seven days', () => {
+    const card = createActivityCard(timelines, now);
+
+    expect(card.days.map(day => day.duration)).toEqual([0, 0, 0, 4, 2, 0, 3]);
+    expect(card).toMatchObject({ totalDuration: 9, activeDays: 3, projectCount: 2 });
+    expect(card.topProjects).toEqual([
+      { name: 'web', duration: 7 },
+      { name: 'a<b>', duration: 2 },
+    ]);
+  });
+
+  it('should format a Markdown snippet between replaceable markers', () => {
+    const lines = formatReadmeMarkdown(createActivityCard(timelines, now)).split('\n');
+
+    expect(lines[0]).toBe(README_START_MARKER);
+    expect(lines[1]).toBe('**Claude Code this week** · 9m active · 3 of 7 days · 2 projects');
+    expect(lines[3]).toBe('| Fri | Sat | Sun | Mon | Tue | Wed | Thu |');
+    expect(lines[5]).toBe('| - | - | - | 4m | 2m | - | 3m |');
+    expect(lines[7]).toBe('Top projects: web (7m), a<b> (2m)');
+    expect(lines[lines.length - 1]).toBe(README_END_MARKER);
+  });
+
+  it('should draw an SVG with one bar per day and escaped names', () => {
+    const svg = formatReadmeSvg(createActivityCard(timelines, now));
+
+    expect(svg.startsWith('<svg xmlns="http://www.w3.org/2000/svg"')).toBe(true);
+    expect(svg.match(/<rect x=/g)).toHaveLength(7);
+    expect(svg).toContain('a&#60;b&#62; 2m');
+    expect(svg).not.toContain('a<b>');
+  });
+});
@@ -0,0 +1,39 @@
+import { Timeline } from '../../../models/models';
+import { formatInfluxLines } from '../influx';
+
+describe('formatInfluxLines', () => {
+  const timeline: Timeline = {
+    projectName: 'my project,v2',
+    events: [
+      { timestamp: '2025-01-01T10:00:00Z', usage: { inputTokens: 10, outputTokens: 5 } },
+      { timestamp: '2025-01-01T10:03:00Z' },
+      { timestamp: '2025-01-01T12:30:00Z', usage: { outputTokens: 7 } },
+    ],
+    eventCount: 3,
+    activeDuration: 4,
+    startTime: new Date('2025-01-01T10:00:00Z'),
+    endTime: new Date('2025-01-01T12:30:00Z'),
+  };
+
+  it('should emit one line per project per hour', () => {
+    const lines = formatInfluxLines([timeline]);
+    const hour10 = new Date('2025-01-01T10:00:00Z').getTime();
+    const hour12 = new Date('2025-01-01T12:00:00Z').getTime();
+
+    expect(lines).toHaveLength(2);
+    expect(lines[0].startsWith('ccstat,project=my\\ project\\,v2 ')).toBe(true);
+    expect(lines[0]).toMatch(/ events=2i,tokens=15i,duration=\d+i /);
+    expect(lines[0].endsWith(` ${hour10}000000`)).toBe(true);
+    expect(lines[1]).toMatch(/ events=1i,tokens=7i,/);
+    expect(lines[1].endsWith(` ${hour12}000000`)).toBe(true);
+  });
+
+  it('should tag projects without a name with a placeholder', () => {
+    const [line] = formatInfluxLines([{ ...timeline, projectName: '' }]);
+    expect(line.startsWith('ccstat,project=(unnamed) ')).toBe(true);
+  });
+
+  it('should emit nothing without timelines', () => {
+    expect(formatInfluxLines([])).toEqual([]);
+  });
+});
@@ -0,0 +1,39 @@
+import { Timeline } from '../../../models/models';
+import { createUsageExport, UsageExportSchema } from '../index';
+import { EXPORT_SCHEMA_VERSION, USAGE_EXPORT_JSON_SCHEMA } from '../schema';
+
+describe('USAGE_EXPORT_JSON_SCHEMA', () => {
+  const timeline: Timeline = {
+    projectName: 'api',
+    events: [{ timestamp: '2025-01-01T10:00:00Z', cwd: '/work/api' }],
+    eventCount: 1,
+    activeDuration: 5,
+    startTime: new Date('2025-01-01T10:00:00Z'),
+    endTime: new Date('2025-01-01T10:00:00Z'),
+  };
+  const range = { start: new Date('2025-01-01T00:00:00Z'), end: new Date('2025-01-02T00:00:00Z') };
+  const data = createUsageExport([timeline], range, 'alice', true);
+  const projectSchema = USAGE_EXPORT_JSON_SCHEMA.properties.projects.items;
+
+  it('should stamp exports with the current version', () => {
+    expect(data.schemaVersion).toBe(EXPORT_SCHEMA_VERSION);
+  });
+
+  it('should declare every field an export contains', () => {
+    expect(Object.keys(USAGE_EXPORT_JSON_SCHEMA.properties).sort()).toEqual(
+      Object.keys(UsageExportSchema.shape).sort()
+    );
+    expect(Object.keys(projectSchema.properties)).toEqual(
+      expect.arrayContaining(Object.keys(data.projects[0]))
+    );
+  });
+
+  it('should require the fields every export has', () => {
+    for (const key of USAGE_EXPORT_JSON_SCHEMA.required) {
+      expect(data).toHaveProperty(key);
+    }
+    for (const key of projectSchema.required) {
+      expect(data.projects[0]).toHaveProperty(key);
+    }
+  });
+});
@@ -0,0 +1,139 @@
+import { Event, Timeline } from '../../models/models';
+
+export const ACTIVITYWATCH_DEFAULT_URL = 'http://localhost:5600';
+export const ACTIVITYWATCH_BUCKET_TYPE = 'app.ai.coding';
+const CLIENT = 'ccstat';
+
+// Shortest span reported, so a lone prompt still shows up on the ActivityWatch timeline
+const MIN_SPAN_SECONDS = 60;
+
+export interface ActivityWatchEvent {
+  // Set on events read back from ActivityWatch; sending it replaces that event
+  id?: number;
+  timestamp: string;
+  // Seconds
+  duration: number;
+  data: { project: string; events: number };
+}
+
+export interface ActivityWatchBucket {
+  id: string;
+  created: string;
+  type: string;
+  client: string;
+  hostname: string;
+  events: ActivityWatchEvent[];
+}
+
+// Bucket ids follow the watcher convention of <name>_<hostname>
+export function getActivityWatchBucketId(host: string): string {
+  return `ccstat-ai-coding_${host}`;
+}
+
+// Spans of continuous work per project: events no more than idleMinutes apart, as for the
+// interval duration model
+export function createActivityWatchEvents(
+  timelines: Timeline[],
+  idleMinutes: number
+): ActivityWatchEvent[] {
+  const result: ActivityWatchEvent[] = [];
+
+  for (const timeline of timelines) {
+    let span: Event[] = [];
+    const flush = () => {
+      if (span.length === 0) return;
+      const start = new Date(span[0].timestamp).getTime();
+      const end = new Date(span[span.length - 1].timestamp).getTime();
+      result.push({
+        timestamp: new Date(start).toISOString(),
+        duration: Math.max((end - start) / 1000, MIN_SPAN_SECONDS),
+        data: { project: timeline.projectName, events: span.length },
+      });
+      span = [];
+    };
+
+    for (const event of timeline.events) {
+      const previous = span[span.length - 1];
+      const gap = previous
+        ? new Date(event.timestamp).getTime() - new Date(previous.timestamp).getTime()
+        : 0;
+      if (gap > idleMinutes * 60 * 1000) flush();
+      span.push(event);
+    }
+    flush();
+  }
+
+  return result.sort((a, b) => a.timestamp.localeCompare(b.timestamp));
+}
+
+// The JSON ActivityWatch imports under Settings > Import, or from POST /api/0/import
+export function createActivityWatchImport(
+  events: ActivityWatchEvent[],
+  host: string,
+  now: Date
+): { buckets: Record<string, ActivityWatchBucket> } {
+  const id = getActivityWatchBucketId(host);
+  return {
+    buckets: {
+      [id]: {
+        id,
+        created: now.toISOString(),
+        type: ACTIVITYWATCH_BUCKET_TYPE,
+        client: CLIENT,
+        hostname: host,
+        events,
+      },
+    },
+  };
+}
+
+async function request(url: URL, init?: RequestInit): Promise<Response> {
+  const response = await fetch(url, {
+    ...init,
+    headers: { 'Content-Type': 'application/json' },
+  });
+  // Creating a bucket that already exists answers 304
+  if (!response.ok && response.status !== 304) {
+    throw new Error(`ActivityWatch responded with ${response.status} ${response.statusText}`);
+  }
+  return response;
+}
+
+// Create the bucket if needed and add the spans starting from its latest event on, so repeated
+// pushes over overlapping ranges don't duplicate anything. The latest span may have grown since
+// and is replaced. Returns the number of events sent.
+export async function pushActivityWatchEvents(
+  baseUrl: string,
+  events: ActivityWatchEvent[],
+  host: string
+): Promise<number> {
+  const id = getActivityWatchBucketId(host);
+  const bucketUrl = new URL(`/api/0/buckets/${encodeURIComponent(id)}`, baseUrl);
+
+  await request(bucketUrl, {
+    method: 'POST',
+    body: JSON.stringify({ client: CLIENT, type: ACTIVITYWATCH_BUCKET_TYPE, hostname: host }),
+  });
+
+  const latestUrl = new URL(`${bucketUrl.pathname}/events`, baseUrl);
+  latestUrl.searchParams.set('limit', '1');
+  const [latest] = (await (await request(latestUrl)).json()) as ActivityWatchEvent[];
+  const latestStart = latest ? new Date(latest.timestamp).getTime() : -Infinity;
+
+  const pending = events
+    .filter(event => new Date(event.timestamp).getTime() >= latestStart)
+    .map(event =>
+      latest &&
+      new Date(event.timestamp).getTime() === latestStart &&
+      event.data.project === latest.data.project
+        ? { ...event, id: latest.id }
+        : event
+    );
+  if (pending.length > 0) {
+    await request(new URL(`${bucketUrl.pathname}/events`, baseUrl), {
+      method: 'POST',
+      body: JSON.stringify(pending),
+    });
+  }
+  return pending.length;
+}
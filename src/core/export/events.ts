@@ -0,0 +1,54 @@
+import { Timeline } from '../../models/models';
+import { createTokenCounter } from '../../utils/tokens';
+import { getProjectId, stripRemoteCredentials } from '../git';
+
+// One event with the project metadata ccstat resolved for it
+export interface EventRecord {
+  timestamp: string;
+  project: string;
+  projectId: string;
+  remote: string | null;
+  branch: string | null;
+  sessionId: string | null;
+  // Position of the event in its session, starting at 1
+  sessionSequence: number | null;
+  cwd: string | null;
+  type: string | null;
+  tokens: number;
+}
+
+// Records across all projects in time order; sessions are numbered across projects too
+export function createEventRecords(timelines: Timeline[]): EventRecord[] {
+  // Tokens of a reply go on its first line, so the records add up
+  const countTokens = createTokenCounter();
+  const records = timelines.flatMap(timeline =>
+    timeline.events.map(event => ({
+      timestamp: event.timestamp,
+      project: timeline.projectName,
+      projectId: timeline.projectId ?? getProjectId(timeline.projectName),
+      remote: timeline.remote ? stripRemoteCredentials(timeline.remote) : null,
+      branch: event.gitBranch ?? null,
+      sessionId: event.sessionId ?? null,
+      sessionSequence: null as number | null,
+      cwd: event.cwd ?? null,
+      type: event.type ?? null,
+      tokens: countTokens(event),
+    }))
+  );
+  records.sort((a, b) => a.timestamp.localeCompare(b.timestamp));
+
+  const sessionCounts = new Map<string, number>();
+  for (const record of records) {
+    if (!record.sessionId) continue;
+    const sequence = (sessionCounts.get(record.sessionId) ?? 0) + 1;
+    sessionCounts.set(record.sessionId, sequence);
+    record.sessionSequence = sequence;
+  }
+
+  return records;
+}
+
+// Newline-delimited JSON, one record per line
+export function formatEventLines(timelines: Timeline[]): string[] {
+  return createEventRecords(timelines).map(record => JSON.stringify(record));
+}
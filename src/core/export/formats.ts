@@ -0,0 +1,14 @@
+export const EXPORT_FORMAT_VALUES = [
+  'json',
+  'influx',
+  'events',
+  'github-readme',
+  'github-svg',
+  'activitywatch',
+] as const;
+
+export type ExportFormat = (typeof EXPORT_FORMAT_VALUES)[number];
+
+export function isValidExportFormat(value: string): value is ExportFormat {
+  return EXPORT_FORMAT_VALUES.includes(value as ExportFormat);
+}
@@ -0,0 +1,127 @@
+import { addDays, format, startOfDay, subDays } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { calculateDailyDurations } from '../trend';
+import { formatDuration } from '../../utils/durations';
+
+// Days a card covers, ending today
+export const CARD_DAYS = 7;
+const TOP_PROJECT_COUNT = 3;
+
+// Lines around the Markdown snippet, so a scheduled job can replace it in place
+export const README_START_MARKER = '<!-- ccstat:start -->';
+export const README_END_MARKER = '<!-- ccstat:end -->';
+
+export interface ActivityCard {
+  generatedAt: Date;
+  days: Array<{ date: Date; duration: number }>;
+  totalDuration: number;
+  activeDays: number;
+  projectCount: number;
+  // Busiest projects by active minutes
+  topProjects: Array<{ name: string; duration: number }>;
+}
+
+const sum = (values: number[]) => values.reduce((total, value) => total + value, 0);
+
+// Timelines are expected to cover the CARD_DAYS days ending at `now`
+export function createActivityCard(timelines: Timeline[], now: Date): ActivityCard {
+  const start = startOfDay(subDays(now, CARD_DAYS - 1));
+  const days = calculateDailyDurations(timelines, start, CARD_DAYS).map((duration, i) => ({
+    date: addDays(start, i),
+    duration,
+  }));
+  // Minutes are summed per day, as for the daily columns, so the projects add up to the total
+  const projects = timelines
+    .map(t => ({
+      name: t.projectName,
+      duration: sum(calculateDailyDurations([t], start, CARD_DAYS)),
+    }))
+    .filter(p => p.duration > 0)
+    .sort((a, b) => b.duration - a.duration);
+
+  return {
+    generatedAt: now,
+    days,
+    totalDuration: sum(days.map(day => day.duration)),
+    activeDays: days.filter(day => day.duration > 0).length,
+    projectCount: projects.length,
+    topProjects: projects.slice(0, TOP_PROJECT_COUNT),
+  };
+}
+
+function describeCard(card: ActivityCard): string {
+  return (
+    `${formatDuration(card.totalDuration)} active · ${card.activeDays} of ${CARD_DAYS} days · ` +
+    `${card.projectCount} ${card.projectCount === 1 ? 'project' : 'projects'}`
+  );
+}
+
+// Markdown for a profile README: a summary line, minutes per day and the busiest projects
+export function formatReadmeMarkdown(card: ActivityCard): string {
+  const durations = card.days.map(day => (day.duration > 0 ? formatDuration(day.duration) : '-'));
+  const lines = [
+    README_START_MARKER,
+    `**Claude Code this week** · ${describeCard(card)}`,
+    '',
+    `| ${card.days.map(day => format(day.date, 'EEE')).join(' | ')} |`,
+    `|${card.days.map(() => ' ---: ').join('|')}|`,
+    `| ${durations.join(' | ')} |`,
+  ];
+  if (card.topProjects.length > 0) {
+    lines.push('');
+    lines.push(
+      'Top projects: ' +
+        card.topProjects.map(p => `${p.name} (${formatDuration(p.duration)})`).join(', ')
+    );
+  }
+  lines.push('');
+  lines.push(
+    `<sub>Updated ${format(card.generatedAt, 'yyyy-MM-dd')} with ` +
+      '[ccstat](https://github.com/ktny/ccstat)</sub>'
+  );
+  lines.push(README_END_MARKER);
+  return lines.join('\n');
+}
+
+function escapeXml(text: string): string {
+  return text.replace(/[<>&"']/g, char => `&#${char.charCodeAt(0)};`);
+}
+
+const SVG_WIDTH = 420;
+const SVG_HEIGHT = 150;
+const BAR_AREA_TOP = 50;
+const BAR_AREA_HEIGHT = 60;
+
+// Standalone SVG of the same card, for READMEs that embed it as an image
+export function formatReadmeSvg(card: ActivityCard): string {
+  const max = Math.max(...card.days.map(day => day.duration), 1);
+  const slot = (SVG_WIDTH - 40) / card.days.length;
+
+  const bars = card.days.map((day, i) => {
+    const height = Math.round((day.duration / max) * BAR_AREA_HEIGHT);
+    const x = Math.round(20 + i * slot + slot * 0.2);
+    const y = BAR_AREA_TOP + BAR_AREA_HEIGHT - height;
+    const label = format(day.date, 'EEE');
+    const labelX = Math.round(20 + (i + 0.5) * slot);
+    return (
+      `  <rect x="${x}" y="${y}" width="${Math.round(slot * 0.6)}" height="${height}" rx="2" ` +
+      `fill="#d97757"><title>${label}: ${formatDuration(day.duration)}</title></rect>\n` +
+      `  <text x="${labelX}" y="${BAR_AREA_TOP + BAR_AREA_HEIGHT + 16}" text-anchor="middle">` +
+      `${label}</text>`
+    );
+  });
+
+  const top = card.topProjects.map(p => `${p.name} ${formatDuration(p.duration)}`).join(' · ');
+  return [
+    `<svg xmlns="http://www.w3.org/2000/svg" width="${SVG_WIDTH}" height="${SVG_HEIGHT}" ` +
+      `viewBox="0 0 ${SVG_WIDTH} ${SVG_HEIGHT}" font-family="sans-serif" font-size="11" ` +
+      'fill="#57606a">',
+    `  <rect width="${SVG_WIDTH}" height="${SVG_HEIGHT}" rx="6" fill="#ffffff" stroke="#d0d7de"/>`,
+    '  <text x="20" y="24" font-size="14" font-weight="bold" fill="#24292f">' +
+      'Claude Code this week</text>',
+    `  <text x="20" y="40">${escapeXml(describeCard(card))}</text>`,
+    ...bars,
+    `  <text x="20" y="${SVG_HEIGHT - 10}">${escapeXml(top)}</text>`,
+    '</svg>',
+  ].join('\n');
+}
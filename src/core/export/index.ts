@@ -0,0 +1,78 @@
+import { z } from 'zod';
+import { Timeline } from '../../models/models';
+import { getDirectoryBreakdown } from '../../utils/projectActions';
+import { getProjectId } from '../git';
+import { EXPORT_SCHEMA_VERSION } from './schema';
+
+export const ExportedProjectSchema = z.object({
+  // Missing in exports from older versions
+  id: z.string().optional(),
+  name: z.string(),
+  events: z.number(),
+  duration: z.number(),
+  firstActivity: z.string(),
+  lastActivity: z.string(),
+  // Only with `ccstat export --directories`, since paths can reveal more than project names
+  directories: z.array(z.object({ path: z.string().nullable(), events: z.number() })).optional(),
+});
+
+// Aggregated usage without raw log contents, safe to share with teammates
+export const UsageExportSchema = z.object({
+  // Missing in exports from older versions, which are version 1
+  schemaVersion: z.number().int().optional(),
+  user: z.string().optional(),
+  generatedAt: z.string(),
+  range: z.object({ start: z.string(), end: z.string() }).nullable(),
+  projects: z.array(ExportedProjectSchema),
+});
+
+export type ExportedProject = z.infer<typeof ExportedProjectSchema>;
+export type UsageExport = z.infer<typeof UsageExportSchema>;
+
+export interface ExportRange {
+  start: Date;
+  end: Date;
+}
+
+export function createUsageExport(
+  timelines: Timeline[],
+  range: ExportRange | null,
+  user?: string,
+  includeDirectories = false
+): UsageExport {
+  return {
+    schemaVersion: EXPORT_SCHEMA_VERSION,
+    user,
+    generatedAt: new Date().toISOString(),
+    range: range ? { start: range.start.toISOString(), end: range.end.toISOString() } : null,
+    projects: timelines.map(timeline => ({
+      // Timelines not read from logs, e.g. demo data, are identified by name
+      id: timeline.projectId ?? getProjectId(timeline.projectName),
+      name: timeline.projectName,
+      events: timeline.eventCount,
+      duration: timeline.activeDuration,
+      firstActivity: timeline.startTime.toISOString(),
+      lastActivity: timeline.endTime.toISOString(),
+      directories: includeDirectories ? getDirectoryBreakdown(timeline) : undefined,
+    })),
+  };
+}
+
+// Parse and validate an export, naming the source in error messages
+export function parseUsageExport(content: string, source: string): UsageExport {
+  let data: unknown;
+  try {
+    data = JSON.parse(content);
+  } catch {
+    throw new Error(`${source}: not valid JSON`);
+  }
+
+  const result = UsageExportSchema.safeParse(data);
+  if (!result.success) {
+    throw new Error(`${source}: not a ccstat export (${result.error.issues[0]?.message})`);
+  }
+  if ((result.data.schemaVersion ?? 1) > EXPORT_SCHEMA_VERSION) {
+    throw new Error(`${source}: made by a newer ccstat, update ccstat to read it`);
+  }
+  return result.data;
+}
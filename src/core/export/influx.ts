@@ -0,0 +1,42 @@
+import { Timeline } from '../../models/models';
+import { createTimeline } from '../parser';
+import { createTokenCounter } from '../../utils/tokens';
+
+const HOUR_MS = 60 * 60 * 1000;
+const MEASUREMENT = 'ccstat';
+// Line protocol rejects empty tag values, which would fail the whole write
+const UNNAMED_PROJECT = '(unnamed)';
+
+// Tag values escape commas, spaces and equals signs
+function escapeTag(value: string): string {
+  return value.replace(/[,= ]/g, match => `\\${match}`);
+}
+
+// One line per project per hour: events, tokens and active minutes in that hour
+export function formatInfluxLines(timelines: Timeline[]): string[] {
+  const lines: string[] = [];
+  const countTokens = createTokenCounter();
+
+  for (const timeline of timelines) {
+    const buckets = new Map<number, Timeline['events']>();
+    for (const event of timeline.events) {
+      const hour = Math.floor(new Date(event.timestamp).getTime() / HOUR_MS) * HOUR_MS;
+      const bucket = buckets.get(hour) ?? [];
+      bucket.push(event);
+      buckets.set(hour, bucket);
+    }
+
+    const tags = `project=${escapeTag(timeline.projectName || UNNAMED_PROJECT)}`;
+    for (const [hour, events] of Array.from(buckets.entries()).sort(([a], [b]) => a - b)) {
+      const tokens = events.reduce((sum, event) => sum + countTokens(event), 0);
+      const duration = createTimeline(timeline.projectName, events).activeDuration;
+      // Line protocol timestamps default to nanosecond precision
+      lines.push(
+        `${MEASUREMENT},${tags} events=${events.length}i,tokens=${tokens}i,duration=${duration}i ` +
+          `${hour}000000`
+      );
+    }
+  }
+
+  return lines;
+}
@@ -0,0 +1,64 @@
+// Version of the `ccstat export` JSON format. Adding fields keeps the version; removing,
+// renaming or changing the meaning of a field increments it.
+export const EXPORT_SCHEMA_VERSION = 1;
+
+const isoDate = { type: 'string', format: 'date-time' };
+
+// JSON Schema of UsageExport for integrators, printed by `ccstat export --schema`.
+// Kept in sync with UsageExportSchema, see the schema test.
+export const USAGE_EXPORT_JSON_SCHEMA = {
+  $schema: 'https://json-schema.org/draft/2020-12/schema',
+  $id: `https://github.com/ktny/ccstat/schemas/export-v${EXPORT_SCHEMA_VERSION}.json`,
+  title: 'ccstat export',
+  type: 'object',
+  required: ['generatedAt', 'range', 'projects'],
+  properties: {
+    schemaVersion: {
+      const: EXPORT_SCHEMA_VERSION,
+      description: 'Missing in exports from ccstat 2.0.5 and earlier',
+    },
+    user: { type: 'string' },
+    generatedAt: isoDate,
+    range: {
+      description: 'null for --all-time exports',
+      oneOf: [
+        {
+          type: 'object',
+          required: ['start', 'end'],
+          properties: { start: isoDate, end: isoDate },
+        },
+        { type: 'null' },
+      ],
+    },
+    projects: {
+      type: 'array',
+      items: {
+        type: 'object',
+        required: ['name', 'events', 'duration', 'firstActivity', 'lastActivity'],
+        properties: {
+          id: {
+            type: 'string',
+            description: 'Stable across renames: hash of the git remote URL or project path',
+          },
+          name: { type: 'string' },
+          events: { type: 'integer' },
+          duration: { type: 'integer', description: 'Active minutes' },
+          firstActivity: isoDate,
+          lastActivity: isoDate,
+          directories: {
+            description: 'Only with --directories',
+            type: 'array',
+            items: {
+              type: 'object',
+              required: ['path', 'events'],
+              properties: {
+                path: { type: ['string', 'null'] },
+                events: { type: 'integer' },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+};
@@ -0,0 +1,87 @@
+import { Timeline } from '../../../models/models';
+import {
+  averageForecast,
+  createForecast,
+  formatForecast,
+  getForecastPeriods,
+  linearForecast,
+} from '../index';
+
+describe('forecast methods', () => {
+  it('should extend a linear fit by one step, never below zero', () => {
+    expect(linearForecast([10, 20, 30])).toBeCloseTo(40);
+    expect(linearForecast([30, 20, 10])).toBeCloseTo(0);
+    expect(linearForecast([30, 10, 0])).toBe(0);
+    expect(linearForecast([7])).toBe(7);
+  });
+
+  it('should average values', () => {
+    expect(averageForecast([10, 20, 30])).toBe(20);
+    expect(averageForecast([])).toBe(0);
+  });
+});
+
+describe('createForecast', () => {
+  // Thursday; the current week is left out
+  const now = new Date(2025, 5, 12, 15);
+
+  it('should only use complete weeks', () => {
+    const periods = getForecastPeriods(2, now);
+
+    expect(periods.map(period => period.start)).toEqual([
+      new Date(2025, 4, 26),
+      new Date(2025, 5, 2),
+    ]);
+  });
+
+  it('should forecast per project and estimate cost from tokens', () => {
+    const event = (day: number, tokens: number) => ({
+      timestamp: new Date(2025, 4, day, 10).toISOString(),
+      usage: { outputTokens: tokens },
+    });
+    const timeline: Timeline = {
+      projectName: 'api',
+      // One event per week: 5 minutes and 1M then 3M tokens
+      events: [event(27, 1_000_000), event(34, 3_000_000), event(40, 9_000_000)],
+      eventCount: 3,
+      activeDuration: 10,
+      startTime: new Date(2025, 4, 27, 10),
+      endTime: new Date(2025, 5, 9, 10),
+    };
+
+    const forecast = createForecast([timeline], 2, 'linear', now, 2);
+
+    expect(forecast.projects).toEqual([
+      { projectName: 'api', duration: 5, tokens: 5_000_000, cost: 10 },
+    ]);
+    expect(formatForecast(forecast, 'USD')).toContain('10.00 USD');
+  });
+
+  it('should scale weeks with days off up to full weeks and skip weeks off', () => {
+    const event = (day: number, tokens: number) => ({
+      timestamp: new Date(2025, 4, day, 10).toISOString(),
+      usage: { outputTokens: tokens },
+    });
+    const timeline: Timeline = {
+      projectName: 'api',
+      events: [event(27, 1_000_000), event(34, 3_000_000)],
+      eventCount: 2,
+      activeDuration: 10,
+      startTime: new Date(2025, 4, 27, 10),
+      endTime: new Date(2025, 5, 3, 10),
+    };
+
+    // Three of seven days off: the second week counts as 7/4 of what was done
+    const partial = createForecast([timeline], 2, 'average', now, undefined, [
+      { start: '2025-06-02', end: '2025-06-04' },
+    ]);
+    expect(partial.projects).toEqual([
+      { projectName: 'api', duration: 7, tokens: 3_125_000, cost: undefined },
+    ]);
+
+    const weekOff = createForecast([timeline], 2, 'average', now, undefined, [
+      { start: '2025-06-02', end: '2025-06-08' },
+    ]);
+    expect(weekOff.projects[0]).toMatchObject({ duration: 5, tokens: 1_000_000 });
+  });
+});
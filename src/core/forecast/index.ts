@@ -0,0 +1,127 @@
+import { Timeline } from '../../models/models';
+import { countVacationDays, Vacation } from '../../utils/vacations';
+import { createTrendRows, getTrendPeriods, TrendPeriod } from '../trend';
+import { formatHours } from '../../utils/durations';
+
+export const FORECAST_METHOD_VALUES = ['average', 'linear'] as const;
+export type ForecastMethod = (typeof FORECAST_METHOD_VALUES)[number];
+
+export function isValidForecastMethod(value: string): value is ForecastMethod {
+  return FORECAST_METHOD_VALUES.includes(value as ForecastMethod);
+}
+
+export interface ProjectForecast {
+  projectName: string;
+  duration: number;
+  tokens: number;
+  cost?: number;
+}
+
+export interface Forecast {
+  weeks: number;
+  method: ForecastMethod;
+  projects: ProjectForecast[];
+  totalDuration: number;
+  totalTokens: number;
+  totalCost?: number;
+}
+
+// Least-squares line through the values, evaluated one step past the end
+export function linearForecast(values: number[]): number {
+  const n = values.length;
+  if (n === 0) return 0;
+  if (n === 1) return values[0];
+
+  const meanX = (n - 1) / 2;
+  const meanY = values.reduce((sum, value) => sum + value, 0) / n;
+  let covariance = 0;
+  let variance = 0;
+  values.forEach((value, x) => {
+    covariance += (x - meanX) * (value - meanY);
+    variance += (x - meanX) ** 2;
+  });
+
+  return Math.max(meanY + (covariance / variance) * (n - meanX), 0);
+}
+
+export function averageForecast(values: number[]): number {
+  return values.length > 0 ? values.reduce((sum, value) => sum + value, 0) / values.length : 0;
+}
+
+// The complete weeks before the current one, oldest first
+export function getForecastPeriods(weeks: number, now: Date): TrendPeriod[] {
+  return getTrendPeriods('week', weeks + 1, now).slice(0, weeks);
+}
+
+// Scale a week with days off up to a full week; weeks taken off entirely are left out
+function createWeekScaler(periods: TrendPeriod[], vacations: Vacation[]) {
+  const scales = periods.map(period => {
+    const workDays = 7 - countVacationDays(period.start, period.end, vacations);
+    return workDays > 0 ? 7 / workDays : 0;
+  });
+  return (values: number[]) =>
+    values.map((value, i) => value * scales[i]).filter((_, i) => scales[i] > 0);
+}
+
+// Timelines are expected to cover the periods from getForecastPeriods
+export function createForecast(
+  timelines: Timeline[],
+  weeks: number,
+  method: ForecastMethod,
+  now: Date,
+  pricePerMillionTokens?: number,
+  vacations: Vacation[] = []
+): Forecast {
+  const periods = getForecastPeriods(weeks, now);
+  const predict = method === 'linear' ? linearForecast : averageForecast;
+  const scaleWeeks = createWeekScaler(periods, vacations);
+  const priceTokens = (tokens: number) =>
+    pricePerMillionTokens !== undefined ? (tokens / 1_000_000) * pricePerMillionTokens : undefined;
+
+  const projects = timelines
+    .map(timeline => {
+      const rows = createTrendRows([timeline], periods, 'week');
+      const duration = Math.round(predict(scaleWeeks(rows.map(row => row.duration))));
+      const tokens = Math.round(predict(scaleWeeks(rows.map(row => row.tokens))));
+      return { projectName: timeline.projectName, duration, tokens, cost: priceTokens(tokens) };
+    })
+    .filter(project => project.duration > 0 || project.tokens > 0)
+    .sort((a, b) => b.duration - a.duration);
+
+  const totalTokens = projects.reduce((sum, project) => sum + project.tokens, 0);
+  return {
+    weeks,
+    method,
+    projects,
+    totalDuration: projects.reduce((sum, project) => sum + project.duration, 0),
+    totalTokens,
+    totalCost: priceTokens(totalTokens),
+  };
+}
+
+export function formatForecast(forecast: Forecast, currency: string): string {
+  const lines: string[] = [
+    `Next week forecast (${forecast.method === 'linear' ? 'linear fit' : 'average'} ` +
+      `of the last ${forecast.weeks} weeks)`,
+    '',
+  ];
+
+  const nameWidth = Math.max(...forecast.projects.map(p => p.projectName.length), 7);
+  const withCost = forecast.totalCost !== undefined;
+  const formatLine = (name: string, duration: number, tokens: number, cost?: number) =>
+    `${name.padEnd(nameWidth)}${formatHours(duration).padStart(10)}${`${tokens}`.padStart(12)}` +
+    (withCost ? `${(cost ?? 0).toFixed(2)} ${currency}`.padStart(14) : '');
+
+  lines.push(
+    `${'Project'.padEnd(nameWidth)}${'Duration'.padStart(10)}${'Tokens'.padStart(12)}` +
+      (withCost ? 'Cost'.padStart(14) : '')
+  );
+  for (const project of forecast.projects) {
+    lines.push(formatLine(project.projectName, project.duration, project.tokens, project.cost));
+  }
+  lines.push('');
+  lines.push(formatLine('Total', forecast.totalDuration, forecast.totalTokens, forecast.totalCost));
+  lines.push('Forecasts assume next week looks like the recent trend.');
+
+  return lines.join('\n');
+}
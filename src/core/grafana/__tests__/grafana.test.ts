@@ -0,0 +1,72 @@
+import { Timeline } from '../../../models/models';
+import { listGrafanaTargets, queryGrafanaTargets } from '../index';
+
+const createTimeline = (projectName: string, timestamps: string[]): Timeline => ({
+  projectName,
+  events: timestamps.map(timestamp => ({
+    timestamp,
+    usage: { inputTokens: 10, outputTokens: 5 },
+  })),
+  eventCount: timestamps.length,
+  activeDuration: 0,
+  startTime: new Date(timestamps[0]),
+  endTime: new Date(timestamps[timestamps.length - 1]),
+});
+
+describe('Grafana datasource', () => {
+  const timelines = [
+    createTimeline('web', ['2025-01-01T10:10:00Z', '2025-01-01T11:30:00Z']),
+    createTimeline('api', ['2025-01-01T10:20:00Z', '2025-01-01T10:40:00Z']),
+  ];
+  const range = { from: '2025-01-01T10:00:00Z', to: '2025-01-01T12:00:00Z' };
+  const from = new Date(range.from).getTime();
+  const hour = 60 * 60 * 1000;
+
+  it('should list totals and per-project targets', () => {
+    expect(listGrafanaTargets(timelines)).toEqual([
+      'events',
+      'events:api',
+      'events:web',
+      'tokens',
+      'tokens:api',
+      'tokens:web',
+    ]);
+  });
+
+  it('should bucket events and tokens per interval', () => {
+    const series = queryGrafanaTargets(timelines, {
+      range,
+      intervalMs: hour,
+      targets: [{ target: 'events' }, { target: 'tokens:api' }],
+    });
+
+    expect(series).toEqual([
+      {
+        target: 'events',
+        datapoints: [
+          [3, from],
+          [1, from + hour],
+        ],
+      },
+      {
+        target: 'tokens:api',
+        datapoints: [
+          [30, from],
+          [0, from + hour],
+        ],
+      },
+    ]);
+  });
+
+  it('should skip unknown targets and respect maxDataPoints', () => {
+    const series = queryGrafanaTargets(timelines, {
+      range,
+      intervalMs: 60 * 1000,
+      maxDataPoints: 4,
+      targets: [{ target: 'cost' }, { target: 'events:web' }],
+    });
+
+    expect(series).toHaveLength(1);
+    expect(series[0].datapoints).toHaveLength(4);
+  });
+});
@@ -0,0 +1,83 @@
+import { z } from 'zod';
+import { Timeline } from '../../models/models';
+import { createTokenCounter } from '../../utils/tokens';
+
+// Series are named "<metric>" for all projects or "<metric>:<project>"
+export const GRAFANA_METRICS = ['events', 'tokens'] as const;
+export type GrafanaMetric = (typeof GRAFANA_METRICS)[number];
+
+export const GrafanaQuerySchema = z.object({
+  range: z.object({ from: z.string(), to: z.string() }),
+  intervalMs: z.number().positive().optional(),
+  maxDataPoints: z.number().positive().optional(),
+  targets: z.array(z.object({ target: z.string().optional() })),
+});
+
+export type GrafanaQuery = z.infer<typeof GrafanaQuerySchema>;
+
+export interface GrafanaSeries {
+  target: string;
+  // [value, timestamp in ms], as expected by the SimpleJSON datasource
+  datapoints: Array<[number, number]>;
+}
+
+const DEFAULT_INTERVAL_MS = 60 * 60 * 1000;
+const MAX_DATAPOINTS = 10000;
+
+export function listGrafanaTargets(timelines: Timeline[]): string[] {
+  const projects = timelines.map(t => t.projectName).sort();
+  return GRAFANA_METRICS.flatMap(metric => [
+    metric,
+    ...projects.map(project => `${metric}:${project}`),
+  ]);
+}
+
+function parseTarget(target: string): { metric: GrafanaMetric; project?: string } | null {
+  const separator = target.indexOf(':');
+  const metric = separator >= 0 ? target.slice(0, separator) : target;
+  const project = separator >= 0 ? target.slice(separator + 1) : undefined;
+
+  if (!(GRAFANA_METRICS as readonly string[]).includes(metric)) return null;
+  return { metric: metric as GrafanaMetric, project };
+}
+
+export function queryGrafanaTargets(timelines: Timeline[], query: GrafanaQuery): GrafanaSeries[] {
+  const from = new Date(query.range.from).getTime();
+  const to = new Date(query.range.to).getTime();
+  if (isNaN(from) || isNaN(to) || to <= from) return [];
+
+  // Widen the interval rather than returning more points than Grafana asked for
+  const maxPoints = Math.min(query.maxDataPoints ?? MAX_DATAPOINTS, MAX_DATAPOINTS);
+  const intervalMs = Math.max(
+    query.intervalMs ?? DEFAULT_INTERVAL_MS,
+    Math.ceil((to - from) / maxPoints)
+  );
+  const bucketCount = Math.ceil((to - from) / intervalMs);
+
+  const series: GrafanaSeries[] = [];
+  for (const { target } of query.targets) {
+    const parsed = target ? parseTarget(target) : null;
+    if (!target || !parsed) continue;
+
+    const values = new Array<number>(bucketCount).fill(0);
+    const countTokens = createTokenCounter();
+    for (const timeline of timelines) {
+      if (parsed.project !== undefined && timeline.projectName !== parsed.project) continue;
+
+      for (const event of timeline.events) {
+        const time = new Date(event.timestamp).getTime();
+        if (time < from || time >= to) continue;
+
+        const bucket = Math.floor((time - from) / intervalMs);
+        values[bucket] += parsed.metric === 'tokens' ? countTokens(event) : 1;
+      }
+    }
+
+    series.push({
+      target,
+      datapoints: values.map((value, index) => [value, from + index * intervalMs]),
+    });
+  }
+
+  return series;
+}
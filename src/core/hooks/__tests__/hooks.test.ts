@@ -0,0 +1,70 @@
+import { mkdtemp, rm } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import {
+  appendHookEvent,
+  createHookEvent,
+  loadHookTimelines,
+  mergeTimelines,
+  parseHookInput,
+} from '../index';
+
+const input = {
+  session_id: 'abc',
+  cwd: '/nonexistent/ccstat-hooks/web',
+  hook_event_name: 'PostToolUse',
+  tool_name: 'Edit',
+  tool_input: { file_path: 'secret.ts' },
+};
+
+describe('hook events', () => {
+  let dataDir: string;
+
+  beforeEach(async () => {
+    dataDir = await mkdtemp(join(tmpdir(), 'ccstat-hooks-'));
+  });
+
+  afterEach(async () => {
+    await rm(dataDir, { recursive: true, force: true });
+  });
+
+  it('should keep only the session, directory and event name', () => {
+    const event = createHookEvent(parseHookInput(JSON.stringify(input)), new Date(0));
+
+    expect(event).toEqual({
+      timestamp: '1970-01-01T00:00:00.000Z',
+      sessionId: 'abc',
+      cwd: '/nonexistent/ccstat-hooks/web',
+      type: 'hook:PostToolUse:Edit',
+    });
+    expect(() => parseHookInput('{"cwd":"/tmp"}')).toThrow('session_id');
+  });
+
+  it('should load appended events in range as project timelines', async () => {
+    const path = join(dataDir, 'nested', 'hook-events.jsonl');
+    const parsed = parseHookInput(JSON.stringify(input));
+    await appendHookEvent(path, createHookEvent(parsed, new Date(2025, 0, 1, 10)));
+    await appendHookEvent(path, createHookEvent(parsed, new Date(2025, 0, 1, 11)));
+    await appendHookEvent(path, createHookEvent(parsed, new Date(2025, 0, 2, 10)));
+
+    const timelines = await loadHookTimelines(path, new Date(2025, 0, 1), new Date(2025, 0, 2));
+
+    expect(timelines.map(t => [t.projectName, t.eventCount])).toEqual([['web', 2]]);
+    expect(
+      await loadHookTimelines(path, new Date(2025, 0, 1), new Date(2025, 0, 2), ['**/web'])
+    ).toEqual([]);
+    expect(await loadHookTimelines(join(dataDir, 'missing'), new Date(0), new Date())).toEqual([]);
+  });
+
+  it('should merge timelines of the same project', async () => {
+    const path = join(dataDir, 'hook-events.jsonl');
+    const event = createHookEvent(parseHookInput(JSON.stringify(input)), new Date(2025, 0, 1));
+    await appendHookEvent(path, event);
+    const [timeline] = await loadHookTimelines(path, new Date(0), new Date(2026, 0, 1));
+
+    const merged = mergeTimelines([timeline], [{ ...timeline, events: [...timeline.events] }]);
+
+    expect(merged).toHaveLength(1);
+    expect(merged[0].eventCount).toBe(2);
+  });
+});
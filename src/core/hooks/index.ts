@@ -0,0 +1,108 @@
+import { appendFile, mkdir, readFile } from 'fs/promises';
+import { dirname, join } from 'path';
+import { z } from 'zod';
+import { Event, Timeline } from '../../models/models';
+import { getDataDir } from '../../utils/config';
+import { createIgnoreMatcher } from '../../utils/ignore';
+import {
+  createTimeline,
+  groupEventsByRepository,
+  mapDirectoriesToRepositories,
+  parseJSONLLine,
+} from '../parser';
+
+// Fields of the JSON Claude Code passes to hook commands on stdin
+export const HookInputSchema = z
+  .object({
+    session_id: z.string(),
+    cwd: z.string(),
+    hook_event_name: z.string(),
+    tool_name: z.string().optional(),
+  })
+  .passthrough();
+
+export type HookInput = z.infer<typeof HookInputSchema>;
+
+export function getHookEventsPath(): string {
+  return join(getDataDir(), 'hook-events.jsonl');
+}
+
+export function parseHookInput(content: string): HookInput {
+  let data: unknown;
+  try {
+    data = JSON.parse(content);
+  } catch {
+    throw new Error('hook input is not valid JSON');
+  }
+
+  const result = HookInputSchema.safeParse(data);
+  if (!result.success) {
+    throw new Error('hook input must include session_id, cwd and hook_event_name');
+  }
+  return result.data;
+}
+
+// Keep only what ccstat needs; tool input and output never reach the store
+export function createHookEvent(input: HookInput, now: Date): Event {
+  return {
+    timestamp: now.toISOString(),
+    sessionId: input.session_id,
+    cwd: input.cwd,
+    type: input.tool_name
+      ? `hook:${input.hook_event_name}:${input.tool_name}`
+      : `hook:${input.hook_event_name}`,
+  };
+}
+
+export async function appendHookEvent(path: string, event: Event): Promise<void> {
+  await mkdir(dirname(path), { recursive: true });
+  await appendFile(path, JSON.stringify(event) + '\n');
+}
+
+// Hook events in [startTime, endTime), grouped into timelines like log events
+export async function loadHookTimelines(
+  path: string,
+  startTime: Date,
+  endTime: Date,
+  ignore: string[] = []
+): Promise<Timeline[]> {
+  let content: string;
+  try {
+    content = await readFile(path, 'utf-8');
+  } catch {
+    return [];
+  }
+
+  const isIgnored = createIgnoreMatcher(ignore);
+  const directoryEventMap = new Map<string, Event[]>();
+  for (const line of content.split('\n')) {
+    const result = parseJSONLLine(line);
+    if (result.status !== 'event' || !result.event.cwd || isIgnored(result.event.cwd)) continue;
+
+    const time = new Date(result.event.timestamp);
+    if (time < startTime || time >= endTime) continue;
+
+    const events = directoryEventMap.get(result.event.cwd) ?? [];
+    events.push(result.event);
+    directoryEventMap.set(result.event.cwd, events);
+  }
+
+  const repoDirectoryMap = await mapDirectoriesToRepositories(directoryEventMap);
+  const grouped = await groupEventsByRepository(directoryEventMap, repoDirectoryMap);
+  return Array.from(grouped.values());
+}
+
+// Combine timelines of the same project, e.g. log and hook events
+export function mergeTimelines(...sources: Timeline[][]): Timeline[] {
+  const events = new Map<string, Event[]>();
+  for (const timeline of sources.flat()) {
+    events.set(timeline.projectName, [
+      ...(events.get(timeline.projectName) ?? []),
+      ...timeline.events,
+    ]);
+  }
+
+  return Array.from(events, ([projectName, projectEvents]) =>
+    createTimeline(projectName, projectEvents)
+  );
+}
@@ -0,0 +1,58 @@
+import { Timeline } from '../../../models/models';
+import { createInvoice, formatInvoice, parseMonth } from '../index';
+
+// Local times so day boundaries don't depend on the test machine's time zone
+const at = (day: number, hour: number, minute: number) =>
+  new Date(2025, 5, day, hour, minute).toISOString();
+
+const createTimeline = (projectName: string, timestamps: string[]): Timeline => ({
+  projectName,
+  events: timestamps.map(timestamp => ({ timestamp })),
+  eventCount: timestamps.length,
+  activeDuration: 0,
+  startTime: new Date(timestamps[0]),
+  endTime: new Date(timestamps[timestamps.length - 1]),
+});
+
+describe('invoice', () => {
+  const timelines = [
+    createTimeline('webapp', [at(2, 9, 0), at(2, 9, 2), at(3, 10, 0), at(3, 10, 4)]),
+    createTimeline('api', [at(2, 14, 0), at(2, 14, 3)]),
+  ];
+
+  it('should parse months into local ranges', () => {
+    expect(parseMonth('2025-06')).toEqual({
+      start: new Date(2025, 5, 1),
+      end: new Date(2025, 6, 1),
+    });
+    expect(parseMonth('2025-13')).toBeNull();
+    expect(parseMonth('June')).toBeNull();
+  });
+
+  it('should itemize days across projects', () => {
+    const invoice = createInvoice(timelines, '2025-06');
+
+    expect(invoice.lines.map(line => [line.date, line.events])).toEqual([
+      ['2025-06-02', 4],
+      ['2025-06-03', 2],
+    ]);
+    expect(invoice.totalDuration).toBe(
+      invoice.lines.reduce((sum, line) => sum + line.duration, 0)
+    );
+    expect(invoice.totalCost).toBeUndefined();
+  });
+
+  it('should bill durations at the hourly rate', () => {
+    const invoice = createInvoice(timelines, '2025-06', 60);
+
+    for (const line of invoice.lines) {
+      expect(line.cost).toBeCloseTo(line.duration);
+    }
+    expect(formatInvoice(invoice, 'client:acme', 'EUR')).toContain('Total amount:');
+  });
+
+  it('should skip events outside the month', () => {
+    const invoice = createInvoice([createTimeline('webapp', [at(2, 9, 0)])], '2025-07');
+    expect(invoice.lines).toEqual([]);
+  });
+});
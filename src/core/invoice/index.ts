@@ -0,0 +1,94 @@
+import { addMonths, format, isValid, parse } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { createTimeline } from '../parser';
+import { formatHours } from '../../utils/durations';
+
+export interface InvoiceLine {
+  date: string;
+  events: number;
+  duration: number;
+  cost?: number;
+}
+
+export interface Invoice {
+  month: string;
+  lines: InvoiceLine[];
+  totalDuration: number;
+  totalCost?: number;
+}
+
+// Parse "yyyy-MM" into the local month's [start, end) range
+export function parseMonth(month: string): { start: Date; end: Date } | null {
+  if (!/^\d{4}-\d{2}$/.test(month)) return null;
+
+  const start = parse(month, 'yyyy-MM', new Date());
+  if (!isValid(start)) return null;
+
+  return { start, end: addMonths(start, 1) };
+}
+
+// Itemize active minutes per local day; cost is duration billed at an hourly rate
+export function createInvoice(timelines: Timeline[], month: string, hourlyRate?: number): Invoice {
+  const days = new Map<string, InvoiceLine>();
+
+  for (const timeline of timelines) {
+    const dayEvents = new Map<string, Timeline['events']>();
+    for (const event of timeline.events) {
+      const date = format(new Date(event.timestamp), 'yyyy-MM-dd');
+      if (!date.startsWith(month)) continue;
+
+      const events = dayEvents.get(date) ?? [];
+      events.push(event);
+      dayEvents.set(date, events);
+    }
+
+    for (const [date, events] of dayEvents) {
+      const line = days.get(date) ?? { date, events: 0, duration: 0 };
+      line.events += events.length;
+      line.duration += createTimeline(timeline.projectName, events).activeDuration;
+      days.set(date, line);
+    }
+  }
+
+  const lines = Array.from(days.values()).sort((a, b) => a.date.localeCompare(b.date));
+  if (hourlyRate !== undefined) {
+    for (const line of lines) {
+      line.cost = (line.duration / 60) * hourlyRate;
+    }
+  }
+
+  return {
+    month,
+    lines,
+    totalDuration: lines.reduce((sum, line) => sum + line.duration, 0),
+    totalCost:
+      hourlyRate !== undefined ? lines.reduce((sum, line) => sum + (line.cost ?? 0), 0) : undefined,
+  };
+}
+
+function formatCost(cost: number, currency: string): string {
+  return `${cost.toFixed(2)} ${currency}`;
+}
+
+export function formatInvoice(invoice: Invoice, title: string, currency: string): string {
+  const lines: string[] = [`Invoice: ${title} (${invoice.month})`, ''];
+
+  const header = `${'Date'.padEnd(12)}${'Events'.padStart(8)}${'Duration'.padStart(10)}`;
+  lines.push(invoice.totalCost !== undefined ? header + 'Amount'.padStart(14) : header);
+  for (const line of invoice.lines) {
+    const amount = line.cost !== undefined ? formatCost(line.cost, currency).padStart(14) : '';
+    lines.push(
+      `${line.date.padEnd(12)}${`${line.events}`.padStart(8)}` +
+        `${formatHours(line.duration).padStart(10)}${amount}`
+    );
+  }
+
+  lines.push('');
+  lines.push(`Total duration: ${formatHours(invoice.totalDuration)}`);
+  if (invoice.totalCost !== undefined) {
+    lines.push(`Total amount: ${formatCost(invoice.totalCost, currency)}`);
+  }
+  lines.push('Durations are estimated from session activity.');
+
+  return lines.join('\n');
+}
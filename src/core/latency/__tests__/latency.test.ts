@@ -0,0 +1,56 @@
+import { Event, Timeline } from '../../../models/models';
+import { calculateLatencies, formatLatencyReport, percentile } from '../index';
+
+const at = (second: number, type: string, model?: string): Event => ({
+  timestamp: new Date(Date.UTC(2025, 0, 1, 10, 0, second)).toISOString(),
+  sessionId: 'session-1',
+  type,
+  message: model ? { model } : {},
+});
+
+const timeline = (projectName: string, events: Event[]): Timeline => ({
+  projectName,
+  events,
+  eventCount: events.length,
+  activeDuration: 1,
+  startTime: new Date(events[0].timestamp),
+  endTime: new Date(events[events.length - 1].timestamp),
+});
+
+describe('percentile', () => {
+  it('should use the nearest rank', () => {
+    const values = Array.from({ length: 20 }, (_, i) => i + 1);
+    expect(percentile(values, 0.5)).toBe(10);
+    expect(percentile(values, 0.95)).toBe(19);
+    expect(percentile([], 0.5)).toBe(0);
+  });
+});
+
+describe('calculateLatencies', () => {
+  const events = [
+    at(0, 'user'),
+    at(2, 'assistant', 'claude-sonnet'),
+    // A tool result waits on the next reply as well
+    at(3, 'user'),
+    at(4, 'user'),
+    at(9, 'assistant', 'claude-opus'),
+    // Follow-up assistant messages without a user message in between are not replies
+    at(10, 'assistant', 'claude-opus'),
+  ];
+
+  it('should measure each user message to the next assistant message', () => {
+    const report = calculateLatencies([timeline('web', events)]);
+
+    expect(report.projects).toEqual([{ name: 'web', count: 2, medianMs: 2000, p95Ms: 6000 }]);
+    expect(report.models.map(m => [m.name, m.medianMs])).toEqual([
+      ['claude-opus', 6000],
+      ['claude-sonnet', 2000],
+    ]);
+  });
+
+  it('should format project and model sections', () => {
+    const output = formatLatencyReport(calculateLatencies([timeline('web', events)]));
+    expect(output).toContain('claude-opus');
+    expect(output).toMatch(/web\s+2\s+2\.0s\s+6\.0s/);
+  });
+});
@@ -0,0 +1,101 @@
+import { Event, Timeline } from '../../models/models';
+
+// Longer waits mean the user came back later, not that the model was slow
+const MAX_LATENCY_MS = 30 * 60 * 1000;
+
+export interface LatencyStats {
+  name: string;
+  count: number;
+  medianMs: number;
+  p95Ms: number;
+}
+
+export interface LatencyReport {
+  projects: LatencyStats[];
+  models: LatencyStats[];
+}
+
+function getModel(event: Event): string {
+  const model = event.message?.model;
+  return typeof model === 'string' ? model : 'unknown';
+}
+
+// Nearest-rank percentile of sorted values
+export function percentile(sorted: number[], fraction: number): number {
+  if (sorted.length === 0) return 0;
+  const rank = Math.ceil(fraction * sorted.length);
+  return sorted[Math.min(sorted.length, Math.max(1, rank)) - 1];
+}
+
+function summarize(samples: Map<string, number[]>): LatencyStats[] {
+  return Array.from(samples, ([name, values]) => {
+    const sorted = [...values].sort((a, b) => a - b);
+    return {
+      name,
+      count: sorted.length,
+      medianMs: percentile(sorted, 0.5),
+      p95Ms: percentile(sorted, 0.95),
+    };
+  }).sort((a, b) => b.p95Ms - a.p95Ms);
+}
+
+// Time from each user message (a prompt or tool result) to the next assistant message in the
+// same session, attributed to the project and to the model that answered
+export function calculateLatencies(timelines: Timeline[]): LatencyReport {
+  const byProject = new Map<string, number[]>();
+  const byModel = new Map<string, number[]>();
+  const add = (samples: Map<string, number[]>, name: string, value: number) => {
+    const values = samples.get(name) ?? [];
+    values.push(value);
+    samples.set(name, values);
+  };
+
+  for (const timeline of timelines) {
+    const pending = new Map<string, number>();
+    for (const event of timeline.events) {
+      if (!event.sessionId) continue;
+      const time = new Date(event.timestamp).getTime();
+
+      if (event.type === 'user') {
+        // Several user messages in a row wait on the same answer, so keep the first one
+        if (!pending.has(event.sessionId)) pending.set(event.sessionId, time);
+      } else if (event.type === 'assistant' && pending.has(event.sessionId)) {
+        const latency = time - pending.get(event.sessionId)!;
+        pending.delete(event.sessionId);
+        if (latency < 0 || latency > MAX_LATENCY_MS) continue;
+
+        add(byProject, timeline.projectName, latency);
+        add(byModel, getModel(event), latency);
+      }
+    }
+  }
+
+  return { projects: summarize(byProject), models: summarize(byModel) };
+}
+
+export function formatLatency(ms: number): string {
+  return ms < 60000 ? `${(ms / 1000).toFixed(1)}s` : `${(ms / 60000).toFixed(1)}m`;
+}
+
+function formatSection(title: string, stats: LatencyStats[]): string[] {
+  const nameWidth = Math.max(title.length, ...stats.map(s => s.name.length)) + 2;
+  return [
+    `${title.padEnd(nameWidth)}${'Replies'.padStart(9)}${'Median'.padStart(9)}${'p95'.padStart(9)}`,
+    ...stats.map(
+      s =>
+        `${s.name.padEnd(nameWidth)}${`${s.count}`.padStart(9)}` +
+        `${formatLatency(s.medianMs).padStart(9)}${formatLatency(s.p95Ms).padStart(9)}`
+    ),
+  ];
+}
+
+export function formatLatencyReport(report: LatencyReport): string {
+  if (report.projects.length === 0) {
+    return 'No replies found in the specified time range';
+  }
+  return [
+    ...formatSection('Project', report.projects),
+    '',
+    ...formatSection('Model', report.models),
+  ].join('\n');
+}
@@ -0,0 +1,50 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import { collectMcpStats, formatMcpStats, getMcpToolCalls, parseMcpToolName } from '../index';
+
+const toolUse = (minute: number, ...names: string[]): Event => ({
+  timestamp: new Date(Date.UTC(2025, 0, 1, 10, minute)).toISOString(),
+  type: 'assistant',
+  message: { content: names.map(name => ({ type: 'tool_use', name, input: {} })) },
+});
+
+describe('mcp stats', () => {
+  it('should split MCP tool names into server and tool', () => {
+    expect(parseMcpToolName('mcp__github__create_issue')).toEqual({
+      server: 'github',
+      tool: 'create_issue',
+    });
+    expect(parseMcpToolName('Bash')).toBeNull();
+  });
+
+  it('should only read tool calls from assistant messages', () => {
+    expect(getMcpToolCalls(toolUse(0, 'mcp__github__list_prs', 'Read'))).toEqual([
+      { server: 'github', tool: 'list_prs' },
+    ]);
+    expect(getMcpToolCalls({ ...toolUse(0, 'mcp__github__list_prs'), type: 'user' })).toEqual([]);
+  });
+
+  it('should count calls per tool and project', () => {
+    const stats = collectMcpStats([
+      createTimeline('web', [
+        toolUse(0, 'mcp__github__list_prs'),
+        toolUse(1, 'mcp__github__list_prs', 'mcp__sentry__get_issue'),
+      ]),
+      createTimeline('api', [toolUse(2, 'mcp__github__list_prs')]),
+    ]);
+
+    expect(stats[0]).toEqual({
+      server: 'github',
+      tool: 'list_prs',
+      calls: 3,
+      projects: [
+        { name: 'web', calls: 2 },
+        { name: 'api', calls: 1 },
+      ],
+    });
+
+    const output = formatMcpStats(stats);
+    expect(output.split('\n')[0]).toBe('github (3 calls)');
+    expect(output).toContain('sentry (1 call)');
+  });
+});
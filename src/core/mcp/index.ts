@@ -0,0 +1,87 @@
+import { Event, Timeline } from '../../models/models';
+
+// Claude Code names MCP tools mcp__<server>__<tool>
+const MCP_TOOL_PATTERN = /^mcp__(.+?)__(.+)$/;
+
+export interface McpToolCall {
+  server: string;
+  tool: string;
+}
+
+export interface McpToolStats {
+  server: string;
+  tool: string;
+  calls: number;
+  // Calls per project, most first
+  projects: Array<{ name: string; calls: number }>;
+}
+
+export function parseMcpToolName(name: string): McpToolCall | null {
+  const match = name.match(MCP_TOOL_PATTERN);
+  return match ? { server: match[1], tool: match[2] } : null;
+}
+
+// MCP tool invocations in an assistant message
+export function getMcpToolCalls(event: Event): McpToolCall[] {
+  const content = event.message?.content;
+  if (event.type !== 'assistant' || !Array.isArray(content)) return [];
+
+  return content
+    .filter(part => part?.type === 'tool_use' && typeof part.name === 'string')
+    .map(part => parseMcpToolName(part.name))
+    .filter((call): call is McpToolCall => call !== null);
+}
+
+export function collectMcpStats(timelines: Timeline[]): McpToolStats[] {
+  const tools = new Map<string, { server: string; tool: string; projects: Map<string, number> }>();
+
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      for (const call of getMcpToolCalls(event)) {
+        const key = `${call.server}\u0000${call.tool}`;
+        const entry = tools.get(key) ?? { ...call, projects: new Map<string, number>() };
+        const projectCalls = entry.projects.get(timeline.projectName) ?? 0;
+        entry.projects.set(timeline.projectName, projectCalls + 1);
+        tools.set(key, entry);
+      }
+    }
+  }
+
+  return Array.from(tools.values())
+    .map(({ server, tool, projects }) => ({
+      server,
+      tool,
+      calls: Array.from(projects.values()).reduce((sum, calls) => sum + calls, 0),
+      projects: Array.from(projects, ([name, calls]) => ({ name, calls })).sort(
+        (a, b) => b.calls - a.calls
+      ),
+    }))
+    .sort((a, b) => b.calls - a.calls);
+}
+
+// Servers ordered by total calls, each followed by its tools and the projects using them
+export function formatMcpStats(stats: McpToolStats[]): string {
+  if (stats.length === 0) {
+    return 'No MCP tool calls found in the specified time range';
+  }
+
+  const servers = new Map<string, McpToolStats[]>();
+  for (const stat of stats) {
+    servers.set(stat.server, [...(servers.get(stat.server) ?? []), stat]);
+  }
+  const serverCalls = (tools: McpToolStats[]) => tools.reduce((sum, t) => sum + t.calls, 0);
+
+  const lines: string[] = [];
+  for (const [server, tools] of Array.from(servers).sort(
+    ([, a], [, b]) => serverCalls(b) - serverCalls(a)
+  )) {
+    const calls = serverCalls(tools);
+    lines.push(`${server} (${calls} ${calls === 1 ? 'call' : 'calls'})`);
+    for (const tool of tools) {
+      const projects = tool.projects.map(p => `${p.name} ${p.calls}`).join(', ');
+      lines.push(`  ${tool.tool.padEnd(30)}${`${tool.calls}`.padStart(7)}  ${projects}`);
+    }
+  }
+
+  return lines.join('\n');
+}
@@ -0,0 +1,76 @@
+import { parseUsageExport, UsageExport } from '../../export';
+import { mergeUsageExports, formatTeamReport } from '../index';
+
+const createExport = (projects: Array<[string, number, number]>): UsageExport => ({
+  generatedAt: '2025-01-01T00:00:00.000Z',
+  range: null,
+  projects: projects.map(([name, events, duration]) => ({
+    name,
+    events,
+    duration,
+    firstActivity: '2025-01-01T09:00:00.000Z',
+    lastActivity: '2025-01-01T10:00:00.000Z',
+  })),
+});
+
+describe('mergeUsageExports', () => {
+  const exports = [
+    { name: 'alice', data: createExport([['api', 100, 60], ['web', 20, 10]]) },
+    { name: 'bob', data: createExport([['api', 300, 90]]) },
+  ];
+
+  it('should aggregate members and projects', () => {
+    const report = mergeUsageExports(exports);
+
+    expect(report.members).toEqual([
+      { name: 'bob', events: 300, duration: 90, projectCount: 1 },
+      { name: 'alice', events: 120, duration: 70, projectCount: 2 },
+    ]);
+    expect(report.projects).toEqual([
+      { name: 'api', events: 400, duration: 150, memberCount: 2 },
+      { name: 'web', events: 20, duration: 10, memberCount: 1 },
+    ]);
+    expect(report.totalEvents).toBe(420);
+    expect(report.totalDuration).toBe(160);
+  });
+
+  it('should combine exports from the same member', () => {
+    const report = mergeUsageExports([
+      ...exports,
+      { name: 'bob', data: createExport([['cli', 5, 1]]) },
+    ]);
+
+    expect(report.members).toHaveLength(2);
+    expect(report.members[0]).toEqual({ name: 'bob', events: 305, duration: 91, projectCount: 2 });
+  });
+
+  it('should hide member names when anonymous', () => {
+    const report = mergeUsageExports(exports, { anonymous: true });
+    const output = formatTeamReport(report);
+
+    expect(report.members.map(m => m.name)).toEqual(['Member 1', 'Member 2']);
+    expect(output).not.toContain('alice');
+    expect(output).not.toContain('bob');
+  });
+});
+
+describe('parseUsageExport', () => {
+  it('should round-trip a valid export', () => {
+    const data = createExport([['api', 1, 1]]);
+    expect(parseUsageExport(JSON.stringify(data), 'a.json')).toEqual(data);
+  });
+
+  it('should reject exports from a newer schema version', () => {
+    const data = { ...createExport([['api', 1, 1]]), schemaVersion: 99 };
+    expect(() => parseUsageExport(JSON.stringify(data), 'new.json')).toThrow(
+      'new.json: made by a newer ccstat'
+    );
+  });
+
+  it('should name the source of invalid input', () => {
+    expect(() => parseUsageExport('{', 'broken.json')).toThrow('broken.json: not valid JSON');
+    expect(() => parseUsageExport('{"projects": 1}', 'other.json')).toThrow(
+      'other.json: not a ccstat export'
+    );
+  });
+});
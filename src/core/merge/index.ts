@@ -0,0 +1,119 @@
+import { UsageExport } from '../export';
+
+export interface TeamMember {
+  name: string;
+  events: number;
+  duration: number;
+  projectCount: number;
+}
+
+export interface TeamProject {
+  name: string;
+  events: number;
+  duration: number;
+  memberCount: number;
+}
+
+export interface TeamReport {
+  members: TeamMember[];
+  projects: TeamProject[];
+  totalEvents: number;
+  totalDuration: number;
+}
+
+export interface MergeOptions {
+  // Replace member names with "Member N" so individuals can't be singled out
+  anonymous?: boolean;
+}
+
+export interface NamedExport {
+  name: string;
+  data: UsageExport;
+}
+
+// Combine several teammates' exports into one report
+export function mergeUsageExports(exports: NamedExport[], options: MergeOptions = {}): TeamReport {
+  const memberMap = new Map<string, TeamMember & { projects: Set<string> }>();
+  const projectMap = new Map<string, TeamProject & { members: Set<string> }>();
+
+  for (const { name, data } of exports) {
+    const member = memberMap.get(name) ?? {
+      name,
+      events: 0,
+      duration: 0,
+      projectCount: 0,
+      projects: new Set<string>(),
+    };
+    memberMap.set(name, member);
+
+    for (const project of data.projects) {
+      member.events += project.events;
+      member.duration += project.duration;
+      member.projects.add(project.name);
+
+      const entry = projectMap.get(project.name) ?? {
+        name: project.name,
+        events: 0,
+        duration: 0,
+        memberCount: 0,
+        members: new Set<string>(),
+      };
+      entry.events += project.events;
+      entry.duration += project.duration;
+      entry.members.add(name);
+      projectMap.set(project.name, entry);
+    }
+  }
+
+  const byEvents = <T extends { events: number }>(a: T, b: T) => b.events - a.events;
+
+  const members = Array.from(memberMap.values())
+    .map(({ projects, ...member }) => ({ ...member, projectCount: projects.size }))
+    .sort(byEvents);
+  if (options.anonymous) {
+    members.forEach((member, index) => {
+      member.name = `Member ${index + 1}`;
+    });
+  }
+
+  const projects = Array.from(projectMap.values())
+    .map(({ members, ...project }) => ({ ...project, memberCount: members.size }))
+    .sort(byEvents);
+
+  return {
+    members,
+    projects,
+    totalEvents: members.reduce((sum, m) => sum + m.events, 0),
+    totalDuration: members.reduce((sum, m) => sum + m.duration, 0),
+  };
+}
+
+export function formatTeamReport(report: TeamReport): string {
+  const lines: string[] = ['Team Report:'];
+
+  const memberWidth = Math.max(...report.members.map(m => m.name.length), 6);
+  lines.push('Members:');
+  for (const member of report.members) {
+    lines.push(
+      ` - ${member.name.padEnd(memberWidth)} ${`${member.events}`.padStart(8)} events` +
+        ` ${`${member.duration}m`.padStart(8)}   ${member.projectCount} projects`
+    );
+  }
+
+  const projectWidth = Math.max(...report.projects.map(p => p.name.length), 7);
+  lines.push('Projects:');
+  for (const project of report.projects) {
+    lines.push(
+      ` - ${project.name.padEnd(projectWidth)} ${`${project.events}`.padStart(8)} events` +
+        ` ${`${project.duration}m`.padStart(8)}   ${project.memberCount} members`
+    );
+  }
+
+  lines.push('');
+  lines.push(
+    `Members: ${report.members.length}, Projects: ${report.projects.length}, ` +
+      `Events: ${report.totalEvents}, Duration: ${report.totalDuration}m`
+  );
+
+  return lines.join('\n');
+}
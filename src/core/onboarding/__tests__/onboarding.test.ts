@@ -0,0 +1,74 @@
+import { mkdtemp, readFile, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { ConfigSchema } from '../../../utils/config';
+import {
+  createDefaultConfig,
+  formatLogDirsFound,
+  formatMainFlags,
+  markOnboarded,
+  shouldOnboard,
+} from '../index';
+
+describe('onboarding', () => {
+  let root: string;
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-onboarding-'));
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should only run without a config file or an earlier onboarding', async () => {
+    const configPath = join(root, 'config.json');
+    const markerPath = join(root, 'data', 'onboarded');
+    expect(shouldOnboard(configPath, markerPath)).toBe(true);
+
+    await markOnboarded(markerPath, new Date('2025-06-12T09:00:00Z'));
+    expect(await readFile(markerPath, 'utf-8')).toBe('2025-06-12T09:00:00.000Z\n');
+    expect(shouldOnboard(configPath, markerPath)).toBe(false);
+
+    await rm(markerPath);
+    await writeFile(configPath, '{}');
+    expect(shouldOnboard(configPath, markerPath)).toBe(false);
+  });
+
+  it('should create a valid config file', () => {
+    expect(ConfigSchema.safeParse(createDefaultConfig()).success).toBe(true);
+  });
+
+  it('should list the log directories found', () => {
+    const text = formatLogDirsFound([
+      { path: '/home/user/.claude/projects', exists: true, projects: 3, logFiles: 12 },
+      { path: '/home/user/.config/claude/projects', exists: false, projects: 0, logFiles: 0 },
+    ]);
+
+    expect(text.split('\n')).toEqual([
+      'Looking for Claude Code logs:',
+      '  ✓ /home/user/.claude/projects (3 projects, 12 log files)',
+      '  ✗ /home/user/.config/claude/projects (not found)',
+    ]);
+  });
+
+  it('should explain where logs come from when none are found', () => {
+    const text = formatLogDirsFound([
+      { path: '/home/user/.claude/projects', exists: false, projects: 0, logFiles: 0 },
+    ]);
+
+    expect(text).toContain('No session logs yet');
+    expect(text).toContain('CCSTAT_LOG_DIRS');
+  });
+
+  it('should align the main options', () => {
+    const lines = formatMainFlags().split('\n');
+
+    expect(lines[0]).toBe('Getting started:');
+    expect(lines[1]).toMatch(/^ {2}ccstat {17}today's activity per project$/);
+    // Explanations start after the longest example, "ccstat -s duration -r"
+    for (const line of lines.slice(1)) {
+      expect(line.slice(23, 26)).toMatch(/^  \S$/);
+    }
+  });
+});
@@ -0,0 +1,73 @@
+import { existsSync } from 'fs';
+import { mkdir, writeFile } from 'fs/promises';
+import { dirname, join } from 'path';
+import { Config, getDataDir } from '../../utils/config';
+import { LogDirReport } from '../environment';
+
+// Written once the first-run flow has been shown, whether or not a config file was created
+export function getOnboardingMarkerPath(): string {
+  return join(getDataDir(), 'onboarded');
+}
+
+// First run: neither a config file nor a finished onboarding
+export function shouldOnboard(configPath: string, markerPath: string): boolean {
+  return !existsSync(configPath) && !existsSync(markerPath);
+}
+
+export async function markOnboarded(markerPath: string, now: Date): Promise<void> {
+  await mkdir(dirname(markerPath), { recursive: true });
+  await writeFile(markerPath, now.toISOString() + '\n');
+}
+
+// Starting point for a new config file: the defaults spelled out, plus a weekly view to show
+// how views work
+export function createDefaultConfig(): Config {
+  return {
+    color: 'forest',
+    pinned: [],
+    ignore: ['/tmp/**'],
+    views: {
+      week: ['--days', '7', '--shade-weekends'],
+    },
+  };
+}
+
+export function formatLogDirsFound(logDirs: LogDirReport[]): string {
+  const lines = ['Looking for Claude Code logs:'];
+  for (const dir of logDirs) {
+    lines.push(
+      dir.exists
+        ? `  ✓ ${dir.path} (${dir.projects} projects, ${dir.logFiles} log files)`
+        : `  ✗ ${dir.path} (not found)`
+    );
+  }
+
+  if (!logDirs.some(dir => dir.logFiles > 0)) {
+    lines.push(
+      '',
+      'No session logs yet. Use Claude Code for a bit and run ccstat again, or point ccstat at',
+      'your logs with "logDirs" in the config file or CCSTAT_LOG_DIRS.'
+    );
+  }
+  return lines.join('\n');
+}
+
+// The options most people need first, as [example, explanation]
+const MAIN_FLAGS: Array<[string, string]> = [
+  ['ccstat', "today's activity per project"],
+  ['ccstat -d 7', 'the last 7 days (-H 12 for hours, -a for all time)'],
+  ['ccstat -p webapp api', 'only these projects'],
+  ['ccstat -s duration -r', 'sort by project, events, duration or hot, descending'],
+  ['ccstat -c ocean', 'color theme: forest, ocean, honey, sunset, violet'],
+  ['ccstat -i', 'pick projects with the keyboard to open or copy their paths'],
+  ['ccstat view week', 'run a view saved in the config file'],
+  ['ccstat --help', 'every option and command'],
+];
+
+export function formatMainFlags(): string {
+  const width = Math.max(...MAIN_FLAGS.map(([example]) => example.length));
+  return [
+    'Getting started:',
+    ...MAIN_FLAGS.map(([example, explanation]) => `  ${example.padEnd(width)}  ${explanation}`),
+  ].join('\n');
+}
@@ -0,0 +1,134 @@
+import { createRandom } from '../../demo';
+import { parseJSONLLine, LineParseResult, MalformedLineError, MalformedReason } from '../index';
+
+const VALID_LINE = JSON.stringify({
+  timestamp: '2025-01-01T09:00:00.000Z',
+  sessionId: 'session-1',
+  cwd: '/home/user/project',
+  type: 'user',
+  message: { role: 'user', content: 'hello' },
+});
+
+type ExpectedStatus = 'event' | 'summary' | 'ignored' | MalformedReason;
+
+// Real-world malformed lines the parser must survive and classify
+const CORPUS: Array<{ name: string; line: string; expected: ExpectedStatus }> = [
+  { name: 'valid event', line: VALID_LINE, expected: 'event' },
+  { name: 'byte order mark', line: `\uFEFF${VALID_LINE}`, expected: 'event' },
+  { name: 'CRLF line ending', line: `${VALID_LINE}\r`, expected: 'event' },
+  {
+    name: 'huge string',
+    line: VALID_LINE.replace('hello', 'x'.repeat(1_000_000)),
+    expected: 'event',
+  },
+  {
+    name: 'summary record',
+    line: '{"type":"summary","summary":"Fix bug","leafUuid":"u1"}',
+    expected: 'summary',
+  },
+  {
+    name: 'summary without leaf',
+    line: '{"type":"summary","summary":"Fix bug"}',
+    expected: 'ignored',
+  },
+  { name: 'empty object', line: '{}', expected: 'ignored' },
+  { name: 'truncated JSON', line: VALID_LINE.slice(0, 60), expected: 'invalid-json' },
+  { name: 'trailing garbage', line: `${VALID_LINE}garbage`, expected: 'invalid-json' },
+  { name: 'concatenated objects', line: `${VALID_LINE}${VALID_LINE}`, expected: 'invalid-json' },
+  { name: 'NUL bytes', line: '\u0000\u0000\u0000', expected: 'invalid-json' },
+  { name: 'nested arrays', line: '[[1,2],[3,[4]]]', expected: 'not-object' },
+  { name: 'null literal', line: 'null', expected: 'not-object' },
+  { name: 'string literal', line: '"just a string"', expected: 'not-object' },
+  { name: 'number literal', line: '42', expected: 'not-object' },
+  {
+    name: 'numeric timestamp',
+    line: '{"timestamp":1735722000000,"sessionId":"session-1"}',
+    expected: 'schema-mismatch',
+  },
+  {
+    name: 'numeric session id',
+    line: '{"timestamp":"2025-01-01T09:00:00Z","sessionId":123}',
+    expected: 'schema-mismatch',
+  },
+  {
+    name: 'invalid usage type',
+    line: '{"timestamp":"2025-01-01T09:00:00Z","sessionId":"s","usage":{"inputTokens":"many"}}',
+    expected: 'schema-mismatch',
+  },
+  {
+    name: 'unparseable timestamp',
+    line: '{"timestamp":"not a date","sessionId":"session-1"}',
+    expected: 'invalid-timestamp',
+  },
+];
+
+function statusOf(result: LineParseResult): ExpectedStatus {
+  return result.status === 'malformed' ? result.reason : result.status;
+}
+
+function mutate(line: string, random: () => number): string {
+  const position = Math.floor(random() * line.length);
+  const noise = ['{', '}', '"', ',', ':', '\\', '\r', '\uFEFF', '\u0000', '[', ']', 'é'];
+
+  switch (Math.floor(random() * 4)) {
+    case 0:
+      return line.slice(0, position);
+    case 1:
+      return line.slice(0, position) + line.slice(position + 1);
+    case 2: {
+      const char = noise[Math.floor(random() * noise.length)];
+      return line.slice(0, position) + char + line.slice(position);
+    }
+    default:
+      return line.slice(position) + line.slice(0, position);
+  }
+}
+
+describe('JSONL line parser corpus', () => {
+  it.each(CORPUS)('should classify $name as $expected', ({ line, expected }) => {
+    expect(statusOf(parseJSONLLine(line))).toBe(expected);
+  });
+
+  it('should normalize event timestamps to ISO format', () => {
+    const result = parseJSONLLine('{"timestamp":"2025-01-01T09:00:00Z","sessionId":"s"}');
+    expect(result).toEqual({
+      status: 'event',
+      event: { timestamp: '2025-01-01T09:00:00.000Z', sessionId: 's' },
+    });
+  });
+
+  it('should extract conversation titles from summary records', () => {
+    const result = parseJSONLLine('{"type":"summary","summary":"Fix bug","leafUuid":"u1"}');
+    expect(result).toEqual({ status: 'summary', title: 'Fix bug', leafUuid: 'u1' });
+  });
+});
+
+describe('JSONL line parser fuzzing', () => {
+  it('should never throw on randomly mutated lines', () => {
+    const random = createRandom(1234);
+
+    for (let i = 0; i < 2000; i++) {
+      let line = VALID_LINE;
+      const mutations = 1 + Math.floor(random() * 5);
+      for (let m = 0; m < mutations; m++) {
+        line = mutate(line, random);
+      }
+
+      const result = parseJSONLLine(line);
+      expect(['event', 'summary', 'ignored', 'malformed']).toContain(result.status);
+
+      if (result.status === 'event') {
+        expect(new Date(result.event.timestamp).toISOString()).toBe(result.event.timestamp);
+      }
+    }
+  });
+});
+
+describe('MalformedLineError', () => {
+  it('should report file, line number and reason', () => {
+    const error = new MalformedLineError('/logs/session.jsonl', 7, 'invalid-json');
+    expect(error.message).toBe('Malformed log line (invalid-json) at /logs/session.jsonl:7');
+    expect(error.lineNumber).toBe(7);
+    expect(error.reason).toBe('invalid-json');
+  });
+});
@@ -0,0 +1,117 @@
+import { mkdir, mkdtemp, rm, symlink, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { Event } from '../../../models/models';
+import { mapDirectoriesToRepositories } from '../index';
+
+describe('nested repositories', () => {
+  let root: string;
+
+  beforeEach(async () => {
+    // A fresh root per test, since resolved names are cached by directory
+    root = await mkdtemp(join(tmpdir(), 'ccstat-nested-'));
+    for (const [path, name] of [
+      ['outer', 'outer'],
+      [join('outer', 'vendor', 'lib'), 'lib'],
+    ]) {
+      await mkdir(join(root, path, '.git'), { recursive: true });
+      await writeFile(
+        join(root, path, '.git', 'config'),
+        `[remote "origin"]\n\turl = https://github.com/acme/${name}.git\n`
+      );
+    }
+    await mkdir(join(root, 'outer', 'vendor', 'lib', 'src'), { recursive: true });
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  function createDirectoryEventMap(): Map<string, Event[]> {
+    const timestamp = '2025-01-01T10:00:00.000Z';
+    return new Map([
+      ['-outer', [{ timestamp, cwd: join(root, 'outer') }]],
+      ['-lib', [{ timestamp, cwd: join(root, 'outer', 'vendor', 'lib', 'src') }]],
+    ]);
+  }
+
+  it('should attribute a nested repository to itself by default', async () => {
+    const directoryEventMap = createDirectoryEventMap();
+
+    expect(await mapDirectoriesToRepositories(directoryEventMap)).toEqual(
+      new Map([
+        ['outer', ['-outer']],
+        ['lib', ['-lib']],
+      ])
+    );
+  });
+
+  it('should attribute a nested repository to the outermost one when asked', async () => {
+    const directoryEventMap = createDirectoryEventMap();
+    const options = { nestedRepositories: 'outermost' as const };
+
+    expect(await mapDirectoriesToRepositories(directoryEventMap, options)).toEqual(
+      new Map([['outer', ['-outer', '-lib']]])
+    );
+  });
+});
+
+describe('repositories sharing a name', () => {
+  let root: string;
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-shared-name-'));
+    for (const owner of ['org-a', 'org-b']) {
+      await mkdir(join(root, owner, 'api', '.git'), { recursive: true });
+      await writeFile(
+        join(root, owner, 'api', '.git', 'config'),
+        `[remote "origin"]\n\turl = git@github.com:${owner}/api.git\n`
+      );
+    }
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should keep them apart whichever is seen first', async () => {
+    const timestamp = '2025-01-01T10:00:00.000Z';
+    for (const owners of [
+      ['org-a', 'org-b'],
+      ['org-b', 'org-a'],
+    ]) {
+      const directoryEventMap = new Map<string, Event[]>(
+        owners.map(owner => [`-${owner}`, [{ timestamp, cwd: join(root, owner, 'api') }]])
+      );
+      const repoDirectoryMap = await mapDirectoriesToRepositories(directoryEventMap);
+      expect(repoDirectoryMap.get('org-a/api')).toEqual(['-org-a']);
+      expect(repoDirectoryMap.get('org-b/api')).toEqual(['-org-b']);
+    }
+  });
+});
+
+describe('symlinked directories', () => {
+  let root: string;
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-symlink-'));
+    await mkdir(join(root, 'projects', 'notes'), { recursive: true });
+    await symlink(join(root, 'projects', 'notes'), join(root, 'current'));
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should group paths to the same directory as one project', async () => {
+    const timestamp = '2025-01-01T10:00:00.000Z';
+    const directoryEventMap = new Map<string, Event[]>([
+      ['-notes', [{ timestamp, cwd: join(root, 'projects', 'notes') }]],
+      ['-current', [{ timestamp, cwd: join(root, 'current') }]],
+    ]);
+
+    expect(await mapDirectoriesToRepositories(directoryEventMap)).toEqual(
+      new Map([['notes', ['-notes', '-current']]])
+    );
+  });
+});
@@ -0,0 +1,35 @@
+import { Timeline } from '../../../models/models';
+import { splitTimelines } from '../index';
+
+describe('splitTimelines', () => {
+  const timeline: Timeline = {
+    projectName: 'webapp',
+    projectId: 'abc123',
+    remote: 'git@github.com:acme/webapp.git',
+    localOnly: false,
+    events: [
+      { timestamp: '2025-01-01T09:00:00.000Z', sessionId: 's1' },
+      { timestamp: '2025-01-02T09:00:00.000Z', sessionId: 's2' },
+    ],
+    eventCount: 2,
+    activeDuration: 10,
+    startTime: new Date('2025-01-01T09:00:00.000Z'),
+    endTime: new Date('2025-01-02T09:00:00.000Z'),
+  };
+
+  it('should keep the project details on both sides', () => {
+    const { before, after } = splitTimelines([timeline], new Date('2025-01-02T00:00:00.000Z'));
+
+    for (const part of [...before, ...after]) {
+      expect(part).toMatchObject({
+        projectName: 'webapp',
+        projectId: 'abc123',
+        remote: 'git@github.com:acme/webapp.git',
+        localOnly: false,
+        eventCount: 1,
+      });
+    }
+    expect(before[0].startTime).toEqual(new Date('2025-01-01T09:00:00.000Z'));
+    expect(after[0].startTime).toEqual(new Date('2025-01-02T09:00:00.000Z'));
+  });
+});
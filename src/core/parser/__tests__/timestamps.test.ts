@@ -0,0 +1,52 @@
+import { mkdir, mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { LoadStats, loadTimelines } from '../index';
+
+describe('out-of-range timestamps', () => {
+  let root: string;
+  let logDirs: string[];
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-timestamps-'));
+    logDirs = [join(root, 'projects')];
+    const cwd = join(root, 'webapp');
+    const line = (timestamp: string) => JSON.stringify({ timestamp, sessionId: 'session-1', cwd });
+
+    await mkdir(join(root, 'projects', '-webapp'), { recursive: true });
+    await writeFile(
+      join(root, 'projects', '-webapp', 'session-1.jsonl'),
+      [
+        line('1970-01-01T00:00:00.000Z'),
+        line('2025-01-01T10:00:00.000Z'),
+        line('2025-01-01T11:00:00.000Z'),
+      ].join('\n')
+    );
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should only count events in the range', async () => {
+    const stats: LoadStats = { outOfRangeTimestamps: 0 };
+    const startTime = new Date('2025-01-01T00:00:00');
+    const endTime = new Date('2025-01-02T00:00:00');
+    await loadTimelines(startTime, endTime, undefined, { logDirs, stats });
+
+    expect(stats.outOfRangeTimestamps).toBe(0);
+  });
+
+  it('should clamp to the span of the plausible timestamps', async () => {
+    const stats: LoadStats = { outOfRangeTimestamps: 0 };
+    const [timeline] = await loadTimelines(undefined, undefined, undefined, {
+      logDirs,
+      stats,
+      timestampPolicy: 'clamp',
+    });
+
+    expect(stats.outOfRangeTimestamps).toBe(1);
+    expect(timeline.eventCount).toBe(3);
+    expect(timeline.startTime).toEqual(new Date('2025-01-01T10:00:00.000Z'));
+  });
+});
@@ -0,0 +1,50 @@
+import { mkdir, mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { loadTimelines } from '../index';
+
+describe('userEventsOnly', () => {
+  let root: string;
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-user-events-'));
+    const cwd = join(root, 'webapp');
+    const line = (minute: number, type: string, content: unknown) =>
+      JSON.stringify({
+        timestamp: `2025-01-01T10:0${minute}:00.000Z`,
+        sessionId: 'session-1',
+        cwd,
+        type,
+        message: { content },
+      });
+
+    await mkdir(join(root, 'projects', '-webapp'), { recursive: true });
+    await writeFile(
+      join(root, 'projects', '-webapp', 'session-1.jsonl'),
+      [
+        line(0, 'user', 'Add a login page'),
+        line(1, 'assistant', [{ type: 'text', text: 'Sure' }]),
+        line(2, 'user', [{ type: 'tool_result', content: 'ok' }]),
+        line(3, 'assistant', [{ type: 'text', text: 'Done' }]),
+        line(4, 'user', 'Thanks'),
+      ].join('\n')
+    );
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should keep only prompts typed by the user', async () => {
+    const logDirs = [join(root, 'projects')];
+
+    const [all] = await loadTimelines(undefined, undefined, undefined, { logDirs });
+    const [prompts] = await loadTimelines(undefined, undefined, undefined, {
+      logDirs,
+      userEventsOnly: true,
+    });
+
+    expect(all.eventCount).toBe(5);
+    expect(prompts.eventCount).toBe(2);
+  });
+});
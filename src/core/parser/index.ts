@@ -1,91 +1,223 @@
-import { readdir, readFile, stat } from 'fs/promises';
-import { join, dirname, basename } from 'path';
+import { readdir, readFile, realpath, stat } from 'fs/promises';
+import { join, dirname, basename, resolve } from 'path';
 import { homedir } from 'os';
 import { Event, EventSchema, Timeline } from '../../models/models';
-import { getRepositoryName } from '../git';
+import {
+  getRepositoryNameAsync,
+  getRepositoryNameFromGit,
+  getProjectId,
+  getQualifiedName,
+  NestedRepositoryMode,
+  RepositoryInfo,
+} from '../git';
 import { ProgressTracker } from '../../utils/progressTracker';
+import { TimestampPolicy, checkTimestampSanity, clampTimestamp } from '../../utils/timestampSanity';
+import { createIgnoreMatcher } from '../../utils/ignore';
+import { createSingleflight, mapWithConcurrency } from '../../utils/concurrency';
+import { getStoreDay, readStoreBatches, readStoreIndex } from '../store';
+import { calculateActiveDuration, getEventKind } from '../duration';
+
+const REPOSITORY_RESOLUTION_CONCURRENCY = 16;
+
+export interface LoadOptions {
+  // Fail on malformed lines instead of silently skipping them
+  strict?: boolean;
+  // How to treat events with implausible timestamps (default: warn and keep them)
+  timestampPolicy?: TimestampPolicy;
+  // Populated with counts of notable conditions encountered while loading
+  stats?: LoadStats;
+  // Directories or glob patterns whose sessions are left out entirely
+  ignore?: string[];
+  // Claude projects directories to scan instead of the defaults
+  logDirs?: string[];
+  // Populated with conversation titles from summary records, keyed by leaf message uuid
+  summaries?: Map<string, string>;
+  // Read events from this ccstat store (see `ccstat ingest`) instead of the session logs
+  store?: string;
+  // Called when the range reaches into days the store only keeps as daily totals, whose
+  // events are missing from the result
+  onCompactedRange?: (compactedBefore: string) => void;
+  // Run git when a repository layout can't be read from .git directly
+  gitFallback?: boolean;
+  // Attribute nested repositories to themselves (default) or to the repository around them
+  nestedRepositories?: NestedRepositoryMode;
+  // Keep only prompts typed by the user, leaving out assistant turns and tool results
+  userEventsOnly?: boolean;
+}
 
-const INACTIVE_THRESHOLD_MINUTES = 5; // Changed to 5 minutes to match Go version
+export type RepositoryOptions = Pick<LoadOptions, 'gitFallback' | 'nestedRepositories'>;
 
-// Repository cache to avoid redundant git operations
-const repositoryCache = new Map<string, string>();
+export interface LoadStats {
+  outOfRangeTimestamps: number;
+  // First day with raw events in a compacted store the range reaches past
+  compactedBefore?: string;
+}
 
-// Get cached repository name
-function getCachedRepositoryName(directory: string): string {
-  if (repositoryCache.has(directory)) {
-    return repositoryCache.get(directory)!;
+export type MalformedReason =
+  | 'invalid-json'
+  | 'not-object'
+  | 'schema-mismatch'
+  | 'invalid-timestamp';
+
+export type LineParseResult =
+  | { status: 'event'; event: Event }
+  | { status: 'summary'; title: string; leafUuid: string } // Conversation title record
+  | { status: 'ignored' } // Valid JSON that is not a timestamped session event
+  | { status: 'malformed'; reason: MalformedReason };
+
+export class MalformedLineError extends Error {
+  constructor(
+    public readonly filePath: string,
+    public readonly lineNumber: number,
+    public readonly reason: MalformedReason
+  ) {
+    super(`Malformed log line (${reason}) at ${filePath}:${lineNumber}`);
+    this.name = 'MalformedLineError';
   }
+}
 
-  let repoName = getRepositoryName(directory);
+// Repository of each directory, to avoid redundant git operations
+const repositoryCache = new Map<string, RepositoryInfo>();
 
-  if (!repoName) {
-    // Try to find parent repository
-    repoName = findParentRepository(directory);
-    if (!repoName) {
-      repoName = basename(directory);
-    }
-  }
+// Forget resolved repositories, so a long-running process picks up moved or re-cloned ones
+export function clearRepositoryCache(): void {
+  repositoryCache.clear();
+}
 
-  if (repoName) {
-    repositoryCache.set(directory, repoName);
-  }
+// Directories outside any repository are named, and identified, by their path
+function getDirectoryProject(directory: string): RepositoryInfo {
+  return { name: basename(directory), id: getProjectId(directory) };
+}
 
-  return repoName;
+// Concurrent lookups of the same directory, e.g. a parent shared by many cwds, share one read
+const repositoryLookups = createSingleflight<string, RepositoryInfo | null>();
+
+function lookupRepository(directory: string, gitFallback: boolean) {
+  return repositoryLookups(directory, () => getRepositoryNameAsync(directory, gitFallback));
 }
 
-// Find parent repository by walking up the directory tree
-function findParentRepository(directory: string): string | null {
-  let currentDir = directory;
+// Repository of a directory, looked up once and cached
+async function resolveRepository(
+  directory: string,
+  options: RepositoryOptions
+): Promise<RepositoryInfo> {
+  const cached = repositoryCache.get(directory);
+  if (cached) return cached;
+
+  // Paths reaching the same checkout through symlinks share one lookup and fallback name
+  const canonical = await canonicalizePath(directory);
+  const repository = repositoryCache.get(canonical) ?? (await findRepository(canonical, options));
+  repositoryCache.set(canonical, repository);
+  repositoryCache.set(directory, repository);
+  return repository;
+}
 
-  while (currentDir !== '/' && currentDir !== '.') {
-    const parentDir = dirname(currentDir);
+// Directories of old sessions may be gone, in which case the recorded path is used as is
+async function canonicalizePath(directory: string): Promise<string> {
+  try {
+    return await realpath(directory);
+  } catch {
+    return directory;
+  }
+}
 
-    if (parentDir === currentDir) {
-      break;
-    }
+async function findRepository(
+  directory: string,
+  options: RepositoryOptions
+): Promise<RepositoryInfo> {
+  const gitFallback = options.gitFallback ?? false;
+  // Outermost keeps walking past repositories found on the way up
+  const outermost = options.nestedRepositories === 'outermost';
+  let repository = await lookupRepository(directory, gitFallback);
 
-    if (repositoryCache.has(parentDir)) {
-      const repoName = repositoryCache.get(parentDir)!;
-      if (repoName) {
-        repositoryCache.set(directory, repoName);
-        return repoName;
-      }
-    } else {
-      const repoName = getRepositoryName(parentDir);
-      if (repoName) {
-        repositoryCache.set(parentDir, repoName);
-        repositoryCache.set(directory, repoName);
-        return repoName;
-      }
+  // Try to find parent repository
+  let currentDir = directory;
+  while ((!repository || outermost) && currentDir !== '/' && currentDir !== '.') {
+    const parentDir = dirname(currentDir);
+    if (parentDir === currentDir) break;
+
+    // The cache holds the innermost repository of each directory, so outermost can't use it
+    const parent = outermost
+      ? await lookupRepository(parentDir, gitFallback)
+      : repositoryCache.get(parentDir) ?? (await lookupRepository(parentDir, gitFallback));
+    if (parent) {
+      if (!outermost) repositoryCache.set(parentDir, parent);
+      repository = parent;
     }
-
     currentDir = parentDir;
   }
 
-  return null;
+  // No .git anywhere above, but GIT_DIR may still point at a repository
+  if (!repository && gitFallback) {
+    repository = await getRepositoryNameFromGit(directory);
+  }
+
+  return repository ?? getDirectoryProject(directory);
+}
+
+// Repository of a directory given by the user, e.g. the current one, resolved the same way as
+// the directories of sessions so the project matches the loaded timelines
+export function resolveDirectoryRepository(
+  directory: string,
+  options: RepositoryOptions = {}
+): Promise<RepositoryInfo> {
+  return resolveRepository(resolve(directory), options);
+}
+
+// Resolve repositories for all directories in parallel so that grouping only hits the cache
+async function resolveRepositoryNames(
+  directoryEventMap: Map<string, Event[]>,
+  options: RepositoryOptions = {}
+): Promise<void> {
+  const directories = new Set<string>();
+  for (const [directory, events] of directoryEventMap.entries()) {
+    directories.add(getLookupDirectory(directory, events));
+  }
+
+  await mapWithConcurrency(
+    Array.from(directories),
+    REPOSITORY_RESOLUTION_CONCURRENCY,
+    directory => resolveRepository(directory, options)
+  );
 }
 
 export async function loadTimelines(
   startTime?: Date,
   endTime?: Date,
-  progressTracker?: ProgressTracker
+  progressTracker?: ProgressTracker,
+  options: LoadOptions = {}
 ): Promise<Timeline[]> {
-  const directoryEventMap = await loadEvents(startTime, endTime, progressTracker);
-  const grouped = await groupEventsByRepository(directoryEventMap);
+  const directoryEventMap = options.store
+    ? await loadStoreEvents(options.store, startTime, endTime, options)
+    : await parseLogFiles(
+        await discoverLogFiles(options.logDirs),
+        startTime,
+        endTime,
+        progressTracker,
+        options
+      );
+  if (options.timestampPolicy === 'clamp') clampEventTimestamps(directoryEventMap);
+  const repoDirectoryMap = await mapDirectoriesToRepositories(directoryEventMap, options);
+  const grouped = await groupEventsByRepository(directoryEventMap, repoDirectoryMap);
 
   return Array.from(grouped.values());
 }
 
-async function loadEvents(
-  startTime?: Date,
-  endTime?: Date,
-  progressTracker?: ProgressTracker
-): Promise<Map<string, Event[]>> {
-  // Check both possible directories
-  const projectsDirs = [
-    join(homedir(), '.claude', 'projects'),
-    join(homedir(), '.config', 'claude', 'projects'),
-  ];
+// Check both possible directories by default
+function getDefaultProjectsDirs(): string[] {
+  return [join(homedir(), '.claude', 'projects'), join(homedir(), '.config', 'claude', 'projects')];
+}
+
+// Claude projects directories to scan, with "~" expanded
+export function getProjectsDirs(logDirs?: string[]): string[] {
+  return logDirs
+    ? logDirs.map(dir => dir.replace(/^~(?=\/|$)/, homedir()))
+    : getDefaultProjectsDirs();
+}
+
+// Find all JSONL log files, mapped to their project directory
+export async function discoverLogFiles(logDirs?: string[]): Promise<Map<string, string>> {
+  const projectsDirs = getProjectsDirs(logDirs);
 
   const fileToDirectoryMap = new Map<string, string>();
 
@@ -122,6 +254,17 @@ async function loadEvents(
     }
   }
 
+  return fileToDirectoryMap;
+}
+
+// Parse log files and group their events by project directory
+export async function parseLogFiles(
+  fileToDirectoryMap: Map<string, string>,
+  startTime?: Date,
+  endTime?: Date,
+  progressTracker?: ProgressTracker,
+  options: LoadOptions = {}
+): Promise<Map<string, Event[]>> {
   const allFilePaths = Array.from(fileToDirectoryMap.keys());
 
   // Set total files count
@@ -129,9 +272,13 @@ async function loadEvents(
     progressTracker.setTotalFiles(allFilePaths.length);
   }
 
+  // Share one matcher so each directory is only tested once
+  const isIgnored =
+    options.ignore && options.ignore.length > 0 ? createIgnoreMatcher(options.ignore) : undefined;
+
   // Process files with progress tracking
   const fileProcessingTasks: Promise<Event[]>[] = allFilePaths.map(filePath => {
-    return parseJSONLFile(filePath, startTime, endTime, progressTracker);
+    return parseJSONLFile(filePath, startTime, endTime, progressTracker, options, isIgnored);
   });
 
   // Process all files in parallel
@@ -154,11 +301,153 @@ async function loadEvents(
   return directoryEventMap;
 }
 
+// Group stored events by project directory, filtered the same way as log files
+async function loadStoreEvents(
+  storePath: string,
+  startTime: Date | undefined,
+  endTime: Date | undefined,
+  options: LoadOptions
+): Promise<Map<string, Event[]>> {
+  const isIgnored =
+    options.ignore && options.ignore.length > 0 ? createIgnoreMatcher(options.ignore) : undefined;
+  const directoryEventMap = new Map<string, Event[]>();
+  const now = new Date();
+
+  const index = await readStoreIndex(storePath);
+  for (const [leafUuid, title] of index.summaries) options.summaries?.set(leafUuid, title);
+  // Compacted days only exist as daily totals, which only `ccstat trend` reads
+  const { compactedBefore } = index;
+  if (compactedBefore && (!startTime || getStoreDay(startTime.toISOString()) < compactedBefore)) {
+    if (options.stats) options.stats.compactedBefore = compactedBefore;
+    options.onCompactedRange?.(compactedBefore);
+  }
+
+  // Only the days in the range are read
+  const batches = await readStoreBatches(storePath, startTime, endTime, index.compactedBefore);
+  for (const batch of batches) {
+    const events = batch.events.filter(event =>
+      acceptEvent(event, startTime, endTime, options, isIgnored, now)
+    );
+    if (events.length === 0) continue;
+
+    const existingEvents = directoryEventMap.get(batch.directory) || [];
+    directoryEventMap.set(batch.directory, [...existingEvents, ...events]);
+  }
+
+  return directoryEventMap;
+}
+
+// Apply ignore patterns, the timestamp policy and the time range to a parsed event
+function acceptEvent(
+  event: Event,
+  startTime: Date | undefined,
+  endTime: Date | undefined,
+  options: LoadOptions,
+  isIgnored: ((directory: string) => boolean) | undefined,
+  now: Date
+): boolean {
+  if (isIgnored && event.cwd && isIgnored(event.cwd)) return false;
+  if (options.userEventsOnly && getEventKind(event) !== 'user') return false;
+
+  // Apply time filtering if provided
+  const eventTime = new Date(event.timestamp);
+  if (startTime && endTime) {
+    // Convert to local time
+    const localEventTime = new Date(eventTime.toLocaleString());
+    if (localEventTime < startTime || localEventTime > endTime) return false;
+  }
+
+  // Detect clock skew, zero epochs and future dates that would distort the time axis. Only
+  // events in the range are counted; clamping waits for the span of the other events, see
+  // clampEventTimestamps.
+  if (checkTimestampSanity(eventTime, now) !== 'valid') {
+    if (options.stats) {
+      options.stats.outOfRangeTimestamps++;
+    }
+    if (options.timestampPolicy === 'exclude') return false;
+  }
+
+  return true;
+}
+
+// Move implausible timestamps to the nearest end of the span of the plausible ones, so they
+// neither stretch the time axis nor fall outside it
+function clampEventTimestamps(directoryEventMap: Map<string, Event[]>): void {
+  const now = new Date();
+  const events = Array.from(directoryEventMap.values()).flat();
+  const validTimes = events
+    .map(event => new Date(event.timestamp).getTime())
+    .filter(time => checkTimestampSanity(new Date(time), now) === 'valid');
+  // Without any plausible timestamp, fall back to the plausible instants
+  const valid =
+    validTimes.length > 0
+      ? {
+          start: new Date(validTimes.reduce((min, time) => Math.min(min, time))),
+          end: new Date(validTimes.reduce((max, time) => Math.max(max, time))),
+        }
+      : undefined;
+
+  for (const event of events) {
+    const eventTime = new Date(event.timestamp);
+    if (checkTimestampSanity(eventTime, now) !== 'valid') {
+      event.timestamp = clampTimestamp(eventTime, now, valid).toISOString();
+    }
+  }
+}
+
+// Parse and classify a single JSONL line without ever throwing
+export function parseJSONLLine(line: string): LineParseResult {
+  // Tolerate byte order marks and CRLF line endings
+  const trimmed = line.replace(/^\uFEFF/, '').trim();
+
+  let data;
+  try {
+    data = JSON.parse(trimmed);
+  } catch (error) {
+    return { status: 'malformed', reason: 'invalid-json' };
+  }
+
+  if (!data || typeof data !== 'object' || Array.isArray(data)) {
+    return { status: 'malformed', reason: 'not-object' };
+  }
+
+  // Summary records title the conversation ending at leafUuid
+  if (data.type === 'summary') {
+    if (typeof data.summary === 'string' && typeof data.leafUuid === 'string') {
+      return { status: 'summary', title: data.summary, leafUuid: data.leafUuid };
+    }
+    return { status: 'ignored' };
+  }
+
+  // Fast check for required fields before expensive validation
+  if (!data.timestamp || !data.sessionId) {
+    return { status: 'ignored' };
+  }
+
+  // Validate and parse event
+  const validationResult = EventSchema.safeParse(data);
+  if (!validationResult.success) {
+    return { status: 'malformed', reason: 'schema-mismatch' };
+  }
+
+  const event = validationResult.data;
+  const eventTime = new Date(event.timestamp);
+  if (isNaN(eventTime.getTime())) {
+    return { status: 'malformed', reason: 'invalid-timestamp' };
+  }
+
+  // Optimize object creation by directly modifying timestamp
+  event.timestamp = eventTime.toISOString();
+  return { status: 'event', event };
+}
+
 async function parseJSONLFile(
   filePath: string,
   startTime?: Date,
   endTime?: Date,
-  progressTracker?: ProgressTracker
+  progressTracker?: ProgressTracker,
+  options: LoadOptions = {},
+  isIgnored?: (directory: string) => boolean
 ): Promise<Event[]> {
   // Check file modification time for performance optimization
   // Skip stat check for --all-time (when no time filter is specified)
@@ -170,48 +459,33 @@ async function parseJSONLFile(
   }
 
   const content = await readFile(filePath, 'utf-8');
-  const lines = content.trim().split('\n');
+  const lines = content.split('\n');
   const events: Event[] = [];
+  const now = new Date();
 
-  for (const line of lines) {
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i];
     if (!line.trim()) continue;
 
-    try {
-      const data = JSON.parse(line);
-
-      // Fast check for required fields before expensive validation
-      if (!data || typeof data !== 'object' || !data.timestamp || !data.sessionId) {
-        continue;
-      }
+    const result = parseJSONLLine(line);
 
-      // Validate and parse event
-      const validationResult = EventSchema.safeParse(data);
-      if (!validationResult.success) {
-        continue;
+    if (result.status === 'malformed') {
+      if (options.strict) {
+        throw new MalformedLineError(filePath, i + 1, result.reason);
       }
+      // Skip invalid lines
+      continue;
+    }
 
-      const event = validationResult.data;
-      const eventTime = new Date(event.timestamp);
+    if (result.status === 'summary') {
+      options.summaries?.set(result.leafUuid, result.title);
+      continue;
+    }
 
-      // Apply time filtering if provided
-      if (startTime && endTime) {
-        // Convert to local time
-        const localEventTime = new Date(eventTime.toLocaleString());
+    if (result.status === 'ignored') continue;
 
-        if (localEventTime >= startTime && localEventTime <= endTime) {
-          // Optimize object creation by directly modifying timestamp
-          event.timestamp = eventTime.toISOString();
-          events.push(event);
-        }
-      } else {
-        // No time filtering, include all events
-        // Optimize object creation by directly modifying timestamp
-        event.timestamp = eventTime.toISOString();
-        events.push(event);
-      }
-    } catch (error) {
-      // Skip invalid lines
-      continue;
+    if (acceptEvent(result.event, startTime, endTime, options, isIgnored, now)) {
+      events.push(result.event);
     }
   }
 
@@ -223,34 +497,62 @@ async function parseJSONLFile(
   return events;
 }
 
-// Map directories to repositories
-function mapDirectoriesToRepositories(
-  directoryEventMap: Map<string, Event[]>
-): Map<string, string[]> {
-  const repoDirectoryMap = new Map<string, string[]>();
+// The working directory recorded in the events, falling back to the log directory
+function getLookupDirectory(directory: string, events: Event[]): string {
+  for (const event of events) {
+    if (event.cwd) return event.cwd;
+  }
+  return directory;
+}
+
+// Map directories to repositories, keyed by project name. Directories are grouped by repository
+// id; repositories sharing a name, e.g. org-a/api and org-b/api, are told apart by their owner
+export async function mapDirectoriesToRepositories(
+  directoryEventMap: Map<string, Event[]>,
+  options: RepositoryOptions = {}
+): Promise<Map<string, string[]>> {
+  await resolveRepositoryNames(directoryEventMap, options);
+  const groups = new Map<string, { repository: RepositoryInfo; directories: string[] }>();
 
   for (const [directory, events] of directoryEventMap.entries()) {
-    let eventCwd: string | undefined;
-    for (const event of events) {
-      if (event.cwd) {
-        eventCwd = event.cwd;
-        break;
-      }
+    const repository = getCachedRepository(getLookupDirectory(directory, events));
+
+    if (!groups.has(repository.id)) {
+      groups.set(repository.id, { repository, directories: [] });
     }
+    groups.get(repository.id)!.directories.push(directory);
+  }
 
-    const repoName = getCachedRepositoryName(eventCwd || directory);
+  const nameCounts = new Map<string, number>();
+  for (const { repository } of groups.values()) {
+    nameCounts.set(repository.name, (nameCounts.get(repository.name) ?? 0) + 1);
+  }
 
-    if (!repoDirectoryMap.has(repoName)) {
-      repoDirectoryMap.set(repoName, []);
-    }
-    repoDirectoryMap.get(repoName)!.push(directory);
+  const repoDirectoryMap = new Map<string, string[]>();
+  for (const { repository, directories } of groups.values()) {
+    const shared = nameCounts.get(repository.name)! > 1;
+    repoDirectoryMap.set(shared ? getQualifiedName(repository) : repository.name, directories);
   }
 
   return repoDirectoryMap;
 }
 
+// Repository of a directory resolved by resolveRepositoryNames
+function getCachedRepository(directory: string): RepositoryInfo {
+  return repositoryCache.get(directory) ?? getDirectoryProject(directory);
+}
+
+// Repository a group of directories from mapDirectoriesToRepositories belongs to
+function getGroupRepository(
+  directoryEventMap: Map<string, Event[]>,
+  directories: string[]
+): RepositoryInfo {
+  const directory = directories[0];
+  return getCachedRepository(getLookupDirectory(directory, directoryEventMap.get(directory) ?? []));
+}
+
 // Create session timeline from repository events
-function createTimeline(repoName: string, repoEvents: Event[]): Timeline {
+export function createTimeline(repoName: string, repoEvents: Event[]): Timeline {
   // Sort events by timestamp
   repoEvents.sort((a, b) => new Date(a.timestamp).getTime() - new Date(b.timestamp).getTime());
 
@@ -264,11 +566,35 @@ function createTimeline(repoName: string, repoEvents: Event[]): Timeline {
   };
 }
 
+// Split timelines into the parts before splitTime and from splitTime onwards
+export function splitTimelines(
+  timelines: Timeline[],
+  splitTime: Date
+): { before: Timeline[]; after: Timeline[] } {
+  const before: Timeline[] = [];
+  const after: Timeline[] = [];
+
+  for (const timeline of timelines) {
+    const beforeEvents = timeline.events.filter(e => new Date(e.timestamp) < splitTime);
+    const afterEvents = timeline.events.filter(e => new Date(e.timestamp) >= splitTime);
+
+    // Keep the project id, remote and local-only mark of the whole timeline
+    if (beforeEvents.length > 0) {
+      before.push({ ...timeline, ...createTimeline(timeline.projectName, beforeEvents) });
+    }
+    if (afterEvents.length > 0) {
+      after.push({ ...timeline, ...createTimeline(timeline.projectName, afterEvents) });
+    }
+  }
+
+  return { before, after };
+}
+
 // Main grouping function for consolidated mode (default)
-async function groupEventsByRepository(
-  directoryEventMap: Map<string, Event[]>
+export async function groupEventsByRepository(
+  directoryEventMap: Map<string, Event[]>,
+  repoDirectoryMap: Map<string, string[]>
 ): Promise<Map<string, Timeline>> {
-  const repoDirectoryMap = mapDirectoriesToRepositories(directoryEventMap);
   const timelines = new Map<string, Timeline>();
 
   for (const [repoName, directories] of repoDirectoryMap.entries()) {
@@ -282,28 +608,12 @@ async function groupEventsByRepository(
     if (repoEvents.length === 0) continue;
 
     const timeline = createTimeline(repoName, repoEvents);
+    const repository = getGroupRepository(directoryEventMap, directories);
+    timeline.projectId = repository.id;
+    timeline.remote = repository.remote;
+    if (repository.localOnly) timeline.localOnly = true;
     timelines.set(repoName, timeline);
   }
 
   return timelines;
 }
-
-function calculateActiveDuration(events: Event[]): number {
-  if (events.length <= 1) return 5; // Minimum 5 minutes for single event
-
-  // Assume events are already sorted by timestamp
-  let activeMinutes = 0;
-
-  for (let i = 1; i < events.length; i++) {
-    const prevTime = new Date(events[i - 1].timestamp);
-    const currTime = new Date(events[i].timestamp);
-    const intervalMinutes = (currTime.getTime() - prevTime.getTime()) / (1000 * 60);
-
-    // Only count intervals up to the threshold as active time
-    if (intervalMinutes <= INACTIVE_THRESHOLD_MINUTES) {
-      activeMinutes += intervalMinutes;
-    }
-  }
-
-  return Math.round(activeMinutes);
-}
@@ -0,0 +1,73 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import { createPrSummary, formatPrSummaryMarkdown } from '../index';
+
+const at = (hour: number, minute: number) => new Date(2025, 5, 12, hour, minute);
+
+const prompt = (time: Date, sessionId: string, text: string): Event => ({
+  timestamp: time.toISOString(),
+  sessionId,
+  uuid: `${sessionId}-${time.getTime()}`,
+  type: 'user',
+  message: { role: 'user', content: text },
+});
+
+const toolCalls = (time: Date, sessionId: string, names: string[]): Event => ({
+  timestamp: time.toISOString(),
+  sessionId,
+  type: 'assistant',
+  message: { role: 'assistant', content: names.map(name => ({ type: 'tool_use', name })) },
+});
+
+describe('pr summary', () => {
+  const timeline = createTimeline('webapp', [
+    prompt(at(9, 0), 'aaaaaaaa-1', 'add retries to the upload client'),
+    toolCalls(at(9, 1), 'aaaaaaaa-1', ['Read', 'Edit']),
+    toolCalls(at(9, 3), 'aaaaaaaa-1', ['Edit', 'Bash']),
+    prompt(at(14, 0), 'bbbbbbbb-2', 'fix the flaky upload test'),
+    toolCalls(at(14, 2), 'bbbbbbbb-2', ['Edit']),
+  ]);
+  const titles = new Map([['bbbbbbbb-2', 'Flaky upload test']]);
+
+  it('should aggregate sessions, prompts and tool calls', () => {
+    const summary = createPrSummary('webapp', timeline, titles, 'main', at(8, 0), at(15, 0));
+
+    expect(summary.sessions.map(s => [s.sessionId, s.title, s.events])).toEqual([
+      ['aaaaaaaa-1', undefined, 3],
+      ['bbbbbbbb-2', 'Flaky upload test', 2],
+    ]);
+    expect(summary.prompts).toEqual([
+      'add retries to the upload client',
+      'fix the flaky upload test',
+    ]);
+    expect(summary.tools).toEqual([
+      { name: 'Edit', calls: 3 },
+      { name: 'Bash', calls: 1 },
+      { name: 'Read', calls: 1 },
+    ]);
+  });
+
+  it('should format a disclosure section', () => {
+    const summary = createPrSummary('webapp', timeline, titles, 'main', at(8, 0), at(15, 0));
+    const markdown = formatPrSummaryMarkdown(summary, 1);
+
+    expect(markdown).toContain(
+      `Claude Code was used for 0h ${`${timeline.activeDuration}`.padStart(2, '0')}m across ` +
+        '2 session(s) since `main` (2025-06-12 08:00).'
+    );
+    expect(markdown).toContain('- 06/12 09:00, ');
+    expect(markdown).toContain('events: aaaaaaaa\n');
+    expect(markdown).toContain('events: Flaky upload test');
+    expect(markdown).toContain('**Tools:** Edit ×3, Bash ×1, Read ×1');
+    expect(markdown).toContain('<summary>Prompts (2)</summary>');
+    expect(markdown).toContain('- … and 1 more');
+  });
+
+  it('should say when the branch saw no activity', () => {
+    const summary = createPrSummary('api', undefined, new Map(), 'v1.2.0', at(8, 0), at(15, 0));
+
+    expect(formatPrSummaryMarkdown(summary)).toBe(
+      '## AI assistance\n\nNo Claude Code activity in api since `v1.2.0`.'
+    );
+  });
+});
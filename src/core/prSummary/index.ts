@@ -0,0 +1,124 @@
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { collectPrompts } from '../commitContext';
+import { groupSessions } from '../sessions';
+import { formatHours } from '../../utils/durations';
+
+export const DEFAULT_PR_PROMPT_LIMIT = 20;
+
+export interface PrSummarySession {
+  sessionId: string;
+  title?: string;
+  startTime: Date;
+  endTime: Date;
+  events: number;
+  minutes: number;
+}
+
+export interface PrSummary {
+  projectName: string;
+  // Ref the branch is compared against, and the time of the commit it forked from
+  base: string;
+  since: Date;
+  until: Date;
+  minutes: number;
+  sessions: PrSummarySession[];
+  // Distinct prompts, redacted, oldest first
+  prompts: string[];
+  // Tool calls by tool name, most first
+  tools: Array<{ name: string; calls: number }>;
+}
+
+function countToolCalls(timeline: Timeline | undefined): Array<{ name: string; calls: number }> {
+  const calls = new Map<string, number>();
+  for (const event of timeline?.events ?? []) {
+    const content = event.message?.content;
+    if (event.type !== 'assistant' || !Array.isArray(content)) continue;
+
+    for (const part of content) {
+      if (part?.type === 'tool_use' && typeof part.name === 'string') {
+        calls.set(part.name, (calls.get(part.name) ?? 0) + 1);
+      }
+    }
+  }
+  return Array.from(calls, ([name, count]) => ({ name, calls: count })).sort(
+    (a, b) => b.calls - a.calls || a.name.localeCompare(b.name)
+  );
+}
+
+// Everything Claude did on the project since the branch left its base; the timeline is expected
+// to cover that range only. Resumed sessions count as one conversation.
+export function createPrSummary(
+  projectName: string,
+  timeline: Timeline | undefined,
+  titles: Map<string, string>,
+  base: string,
+  since: Date,
+  until: Date
+): PrSummary {
+  const sessions = timeline ? groupSessions([timeline], titles, true) : [];
+
+  return {
+    projectName,
+    base,
+    since,
+    until,
+    minutes: timeline?.activeDuration ?? 0,
+    sessions: sessions.map(session => ({
+      sessionId: session.sessionId,
+      title: session.title,
+      startTime: session.startTime,
+      endTime: session.endTime,
+      events: session.eventCount,
+      minutes: session.activeDuration,
+    })),
+    prompts: collectPrompts(timeline),
+    tools: countToolCalls(timeline),
+  };
+}
+
+// Markdown section for the AI-assistance disclosure of a pull request
+export function formatPrSummaryMarkdown(
+  summary: PrSummary,
+  promptLimit = DEFAULT_PR_PROMPT_LIMIT
+): string {
+  const lines = ['## AI assistance', ''];
+  if (summary.sessions.length === 0) {
+    lines.push(`No Claude Code activity in ${summary.projectName} since \`${summary.base}\`.`);
+    return lines.join('\n');
+  }
+
+  lines.push(
+    `Claude Code was used for ${formatHours(summary.minutes)} across ` +
+      `${summary.sessions.length} session(s) since \`${summary.base}\` ` +
+      `(${format(summary.since, 'yyyy-MM-dd HH:mm')}).`,
+    '',
+    '**Sessions**',
+    ''
+  );
+  for (const session of summary.sessions) {
+    // Titled sessions show their title, others a short session ID as in `ccstat sessions`
+    const label = session.title ?? session.sessionId.slice(0, 8);
+    lines.push(
+      `- ${format(session.startTime, 'MM/dd HH:mm')}, ${session.minutes}m, ` +
+        `${session.events} events: ${label}`
+    );
+  }
+
+  if (summary.tools.length > 0) {
+    const tools = summary.tools.map(tool => `${tool.name} ×${tool.calls}`).join(', ');
+    lines.push('', `**Tools:** ${tools}`);
+  }
+
+  if (summary.prompts.length > 0) {
+    lines.push('', `<details><summary>Prompts (${summary.prompts.length})</summary>`, '');
+    for (const prompt of summary.prompts.slice(0, promptLimit)) {
+      lines.push(`- ${prompt}`);
+    }
+    if (summary.prompts.length > promptLimit) {
+      lines.push(`- … and ${summary.prompts.length - promptLimit} more`);
+    }
+    lines.push('', '</details>');
+  }
+  return lines.join('\n');
+}
@@ -0,0 +1,51 @@
+import { Timeline } from '../../../models/models';
+import { formatProjection, getLimitPeriod, projectUsage } from '../index';
+
+describe('getLimitPeriod', () => {
+  it('should start weekly periods on Monday', () => {
+    // Thursday
+    const now = new Date(2025, 5, 12, 15);
+
+    expect(getLimitPeriod('weekly', now)).toEqual({
+      start: new Date(2025, 5, 9),
+      end: new Date(2025, 5, 16),
+    });
+    expect(getLimitPeriod('daily', now)).toEqual({
+      start: new Date(2025, 5, 12),
+      end: new Date(2025, 5, 13),
+    });
+  });
+});
+
+describe('projectUsage', () => {
+  // Noon, halfway through the day
+  const now = new Date(2025, 5, 12, 12);
+  const timeline: Timeline = {
+    projectName: 'web',
+    events: [
+      { timestamp: new Date(2025, 5, 12, 9).toISOString(), usage: { outputTokens: 400 } },
+      { timestamp: new Date(2025, 5, 12, 10).toISOString(), usage: { outputTokens: 200 } },
+    ],
+    eventCount: 2,
+    activeDuration: 180,
+    startTime: new Date(2025, 5, 12, 9),
+    endTime: new Date(2025, 5, 12, 10),
+  };
+
+  it('should project tokens and hours at the average rate', () => {
+    const projection = projectUsage([timeline], { period: 'daily', tokens: 1000, hours: 8 }, now);
+
+    const [tokens, hours] = projection.metrics;
+    expect(tokens).toMatchObject({ used: 600, ratePerHour: 50, projected: 1200 });
+    expect(tokens.limitReachedAt).toEqual(new Date(2025, 5, 12, 20));
+    expect(hours).toMatchObject({ used: 3, ratePerHour: 0.25, projected: 6 });
+    expect(hours.limitReachedAt).toBeNull();
+  });
+
+  it('should report a limit that is already reached', () => {
+    const projection = projectUsage([timeline], { period: 'daily', tokens: 500 }, now);
+
+    expect(projection.metrics[0].limitReachedAt).toEqual(now);
+    expect(formatProjection(projection, now)).toContain('Limit reached');
+  });
+});
@@ -0,0 +1,136 @@
+import { addDays, addWeeks, startOfDay, startOfWeek } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { formatDuration } from '../../utils/durations';
+import { sumEventTokens } from '../../utils/tokens';
+
+export const LIMIT_PERIOD_VALUES = ['daily', 'weekly'] as const;
+export type LimitPeriod = (typeof LIMIT_PERIOD_VALUES)[number];
+
+export function isValidLimitPeriod(value: string): value is LimitPeriod {
+  return LIMIT_PERIOD_VALUES.includes(value as LimitPeriod);
+}
+
+export interface PlanLimit {
+  period: LimitPeriod;
+  tokens?: number;
+  hours?: number;
+}
+
+export interface MetricProjection {
+  metric: 'tokens' | 'hours';
+  used: number;
+  limit: number;
+  // Average use per hour since the period started
+  ratePerHour: number;
+  projected: number;
+  // When the limit is reached at the current rate; null if not within the period
+  limitReachedAt: Date | null;
+}
+
+export interface UsageProjection {
+  period: LimitPeriod;
+  start: Date;
+  end: Date;
+  metrics: MetricProjection[];
+}
+
+// Periods reset at local midnight, weekly ones on Monday
+export function getLimitPeriod(period: LimitPeriod, now: Date): { start: Date; end: Date } {
+  if (period === 'weekly') {
+    const start = startOfWeek(now, { weekStartsOn: 1 });
+    return { start, end: addWeeks(start, 1) };
+  }
+  const start = startOfDay(now);
+  return { start, end: addDays(start, 1) };
+}
+
+const HOUR_MS = 60 * 60 * 1000;
+
+function projectMetric(
+  metric: MetricProjection['metric'],
+  used: number,
+  limit: number,
+  start: Date,
+  end: Date,
+  now: Date
+): MetricProjection {
+  const elapsedHours = Math.max((now.getTime() - start.getTime()) / HOUR_MS, 1 / 60);
+  const remainingHours = Math.max((end.getTime() - now.getTime()) / HOUR_MS, 0);
+  const ratePerHour = used / elapsedHours;
+
+  let limitReachedAt: Date | null = null;
+  if (used >= limit) {
+    limitReachedAt = now;
+  } else if (ratePerHour > 0) {
+    const hoursLeft = (limit - used) / ratePerHour;
+    if (hoursLeft <= remainingHours) {
+      limitReachedAt = new Date(now.getTime() + hoursLeft * HOUR_MS);
+    }
+  }
+
+  return {
+    metric,
+    used,
+    limit,
+    ratePerHour,
+    projected: used + ratePerHour * remainingHours,
+    limitReachedAt,
+  };
+}
+
+// Timelines are expected to cover the period from its start up to now
+export function projectUsage(timelines: Timeline[], limit: PlanLimit, now: Date): UsageProjection {
+  const { start, end } = getLimitPeriod(limit.period, now);
+  const metrics: MetricProjection[] = [];
+
+  if (limit.tokens !== undefined) {
+    const used = sumEventTokens(timelines.flatMap(timeline => timeline.events));
+    metrics.push(projectMetric('tokens', used, limit.tokens, start, end, now));
+  }
+
+  if (limit.hours !== undefined) {
+    const used = timelines.reduce((sum, timeline) => sum + timeline.activeDuration, 0) / 60;
+    metrics.push(projectMetric('hours', used, limit.hours, start, end, now));
+  }
+
+  return { period: limit.period, start, end, metrics };
+}
+
+function formatAmount(metric: MetricProjection['metric'], value: number): string {
+  return metric === 'hours' ? `${value.toFixed(1)}h` : `${Math.round(value)}`;
+}
+
+function formatTimeLeft(ms: number): string {
+  return formatDuration(Math.max(Math.round(ms / 60000), 0));
+}
+
+export function formatProjection(projection: UsageProjection, now: Date): string {
+  const lines: string[] = [
+    `${projection.period === 'weekly' ? 'Weekly' : 'Daily'} limit, resets in ` +
+      formatTimeLeft(projection.end.getTime() - now.getTime()),
+    '',
+  ];
+
+  for (const m of projection.metrics) {
+    const percent = m.limit > 0 ? Math.round((m.used / m.limit) * 100) : 100;
+    lines.push(
+      `${m.metric === 'tokens' ? 'Tokens' : 'Hours'}: ${formatAmount(m.metric, m.used)} of ` +
+        `${formatAmount(m.metric, m.limit)} (${percent}%)`
+    );
+    lines.push(`  Burn rate: ${formatAmount(m.metric, m.ratePerHour)}/h`);
+    lines.push(`  Projected by reset: ${formatAmount(m.metric, m.projected)}`);
+
+    if (m.limitReachedAt === null) {
+      lines.push('  On track to stay within the limit');
+    } else if (m.limitReachedAt.getTime() <= now.getTime()) {
+      lines.push('  Limit reached');
+    } else {
+      lines.push(
+        `  Limit reached in ${formatTimeLeft(m.limitReachedAt.getTime() - now.getTime())} ` +
+          `at this rate`
+      );
+    }
+  }
+
+  return lines.join('\n');
+}
@@ -0,0 +1,49 @@
+import { ProjectDayStats } from '../../status';
+import { createPromptScript, formatPromptSegment, PROMPT_SHELL_VALUES } from '../index';
+
+const stats: ProjectDayStats = {
+  projectName: 'webapp',
+  events: 42,
+  minutes: 65,
+  sessions: 2,
+  active: true,
+  lastEvent: '2025-06-12T10:05:00.000Z',
+};
+
+describe('prompt', () => {
+  it("should show today's active time on the project", () => {
+    expect(formatPromptSegment(stats)).toBe('● 1h 05m');
+    expect(formatPromptSegment({ ...stats, active: false, minutes: 7 })).toBe('○ 0h 07m');
+  });
+
+  it('should leave the prompt unchanged without activity today', () => {
+    expect(formatPromptSegment({ ...stats, events: 0, minutes: 0, active: false })).toBe('');
+  });
+
+  it.each(PROMPT_SHELL_VALUES)('should refresh the %s segment in the background', shell => {
+    const script = createPromptScript(shell, 30);
+
+    expect(script).toContain('ccstat prompt --directory');
+    expect(script).toMatch(/(>=|-ge) 30\b/);
+    expect(script).not.toContain('{interval}');
+  });
+
+  it('should hook into each shell before the prompt is drawn', () => {
+    expect(createPromptScript('zsh')).toContain('add-zsh-hook precmd _ccstat_prompt_update');
+    expect(createPromptScript('bash')).toContain(
+      'PROMPT_COMMAND="_ccstat_prompt_update${PROMPT_COMMAND:+;$PROMPT_COMMAND}"'
+    );
+    expect(createPromptScript('fish')).toContain('function ccstat_prompt_segment');
+  });
+
+  it.each(PROMPT_SHELL_VALUES)('should keep the %s cache in the given directory', shell => {
+    const script = createPromptScript(shell, 30, "/home/o'neil/.local/share/ccstat/prompt");
+
+    expect(script).toContain(`'/home/o'\\''neil/.local/share/ccstat/prompt'/`);
+    expect(script).not.toMatch(/TMPDIR|\/tmp/);
+  });
+
+  it('should not need bash 4.2 to read the time', () => {
+    expect(createPromptScript('bash')).not.toContain('printf -v');
+  });
+});
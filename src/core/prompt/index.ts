@@ -0,0 +1,137 @@
+import { join } from 'path';
+import { getDataDir } from '../../utils/config';
+import { formatHours } from '../../utils/durations';
+import { quoteShellArgument } from '../../utils/projectActions';
+import { ProjectDayStats } from '../status';
+
+export const PROMPT_SHELL_VALUES = ['zsh', 'bash', 'fish'] as const;
+
+export type PromptShell = (typeof PROMPT_SHELL_VALUES)[number];
+
+export function isValidPromptShell(value: string): value is PromptShell {
+  return PROMPT_SHELL_VALUES.includes(value as PromptShell);
+}
+
+// Shells ask ccstat for a new segment at most this often
+export const DEFAULT_PROMPT_INTERVAL_SECONDS = 60;
+
+// Cache files of the prompt scripts, one per shell. Kept in the user's own data directory: a
+// predictable name in a shared temp directory could be replaced by another user's symlink.
+export function getPromptCacheDir(): string {
+  return join(getDataDir(), 'prompt');
+}
+
+// Today's active time on the project, e.g. "● 1h 05m"; empty without activity so the prompt
+// stays unchanged
+export function formatPromptSegment(stats: ProjectDayStats): string {
+  if (stats.events === 0) return '';
+  return `${stats.active ? '●' : '○'} ${formatHours(stats.minutes)}`;
+}
+
+// The scripts never wait for ccstat: each prompt shows the last segment written to a cache file
+// for the current directory, and at most every `interval` seconds a background job refreshes it.
+// Cache lines are "<directory>\t<segment>", so a refresh for a directory left meanwhile is ignored.
+
+const ZSH_SCRIPT = `# ccstat: put \${CCSTAT_PROMPT_SEGMENT} in PROMPT, with prompt_subst set
+zmodload zsh/datetime
+typeset -g CCSTAT_PROMPT_SEGMENT=''
+typeset -g _ccstat_prompt_dir=''
+typeset -gi _ccstat_prompt_time=0
+typeset -g _ccstat_prompt_cache={cacheDir}/$$
+
+_ccstat_prompt_update() {
+  local line
+  if [[ $PWD != "$_ccstat_prompt_dir" ]]; then
+    _ccstat_prompt_dir=$PWD
+    _ccstat_prompt_time=0
+    CCSTAT_PROMPT_SEGMENT=''
+  elif [[ -r $_ccstat_prompt_cache ]] && IFS= read -r line < $_ccstat_prompt_cache &&
+    [[ \${line%%$'\\t'*} == "$PWD" ]]; then
+    CCSTAT_PROMPT_SEGMENT=\${line#*$'\\t'}
+  fi
+
+  if (( EPOCHSECONDS - _ccstat_prompt_time >= {interval} )); then
+    _ccstat_prompt_time=$EPOCHSECONDS
+    { print -r -- "$PWD"$'\\t'"$(ccstat prompt --directory "$PWD" 2>/dev/null)" \\
+        >| $_ccstat_prompt_cache; } &!
+  fi
+}
+
+autoload -Uz add-zsh-hook
+add-zsh-hook precmd _ccstat_prompt_update
+`;
+
+const BASH_SCRIPT = `# ccstat: put \${CCSTAT_PROMPT_SEGMENT} in PS1
+CCSTAT_PROMPT_SEGMENT=''
+_ccstat_prompt_dir=''
+_ccstat_prompt_time=0
+_ccstat_prompt_cache={cacheDir}/$$
+
+_ccstat_prompt_update() {
+  local now line
+  now=$(date +%s)
+  if [[ $PWD != "$_ccstat_prompt_dir" ]]; then
+    _ccstat_prompt_dir=$PWD
+    _ccstat_prompt_time=0
+    CCSTAT_PROMPT_SEGMENT=''
+  elif [[ -r $_ccstat_prompt_cache ]] && IFS= read -r line < "$_ccstat_prompt_cache" &&
+    [[ \${line%%$'\\t'*} == "$PWD" ]]; then
+    CCSTAT_PROMPT_SEGMENT=\${line#*$'\\t'}
+  fi
+
+  if (( now - _ccstat_prompt_time >= {interval} )); then
+    _ccstat_prompt_time=$now
+    (printf '%s\\t%s\\n' "$PWD" "$(ccstat prompt --directory "$PWD" 2>/dev/null)" \\
+      > "$_ccstat_prompt_cache" &)
+  fi
+}
+
+PROMPT_COMMAND="_ccstat_prompt_update\${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+`;
+
+const FISH_SCRIPT = `# ccstat: call ccstat_prompt_segment from fish_prompt
+set -g _ccstat_prompt_dir ''
+set -g _ccstat_prompt_time 0
+set -g _ccstat_prompt_segment ''
+set -g _ccstat_prompt_cache {cacheDir}/$fish_pid
+
+function ccstat_prompt_segment --description "Today's Claude Code time in this project"
+    set -l now (date +%s)
+    set -l line
+    if test "$PWD" != "$_ccstat_prompt_dir"
+        set -g _ccstat_prompt_dir $PWD
+        set -g _ccstat_prompt_time 0
+        set -g _ccstat_prompt_segment ''
+    else if test -r $_ccstat_prompt_cache; and read line < $_ccstat_prompt_cache
+        set -l parts (string split -m 1 \\t -- $line)
+        if test "$parts[1]" = "$PWD"
+            set -g _ccstat_prompt_segment $parts[2]
+        end
+    end
+
+    if test (math $now - $_ccstat_prompt_time) -ge {interval}
+        set -g _ccstat_prompt_time $now
+        sh -c 'printf "%s\\t%s\\n" "$1" "$(ccstat prompt --directory "$1" 2>/dev/null)" > "$2"' \\
+            sh $PWD $_ccstat_prompt_cache &
+        disown
+    end
+    printf '%s' $_ccstat_prompt_segment
+end
+`;
+
+const PROMPT_SCRIPTS: Record<PromptShell, string> = {
+  zsh: ZSH_SCRIPT,
+  bash: BASH_SCRIPT,
+  fish: FISH_SCRIPT,
+};
+
+// Script to eval from the shell's rc file
+export function createPromptScript(
+  shell: PromptShell,
+  intervalSeconds = DEFAULT_PROMPT_INTERVAL_SECONDS,
+  cacheDir = getPromptCacheDir()
+): string {
+  return PROMPT_SCRIPTS[shell]
+    .replace('{interval}', `${intervalSeconds}`)
+    .replace('{cacheDir}', quoteShellArgument(cacheDir));
+}
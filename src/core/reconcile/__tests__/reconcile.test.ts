@@ -0,0 +1,79 @@
+import { Timeline } from '../../../models/models';
+import {
+  calculateDailyTokens,
+  formatReconciliation,
+  getCcusageRange,
+  parseCcusageExport,
+  reconcileDaily,
+} from '../index';
+
+const at = (day: number, hour: number) => new Date(2025, 5, day, hour).toISOString();
+
+const timeline: Timeline = {
+  projectName: 'web',
+  events: [
+    { timestamp: at(1, 9), usage: { inputTokens: 100, outputTokens: 50 } },
+    { timestamp: at(1, 10), message: { usage: { input_tokens: 40, output_tokens: 10 } } },
+    { timestamp: at(2, 9), usage: { inputTokens: 90, outputTokens: 10 } },
+    { timestamp: at(2, 10) },
+  ],
+  eventCount: 4,
+  activeDuration: 2,
+  startTime: new Date(at(1, 9)),
+  endTime: new Date(at(2, 10)),
+};
+
+const ccusage = {
+  daily: [
+    { date: '2025-06-01', inputTokens: 140, outputTokens: 60, totalCost: 0.5 },
+    { date: '2025-06-02', inputTokens: 150, outputTokens: 50, totalCost: 0.75 },
+  ],
+};
+
+describe('reconcile', () => {
+  it('should parse ccusage daily output and its range', () => {
+    const data = parseCcusageExport(JSON.stringify(ccusage), 'ccusage.json');
+
+    expect(getCcusageRange(data)).toEqual({
+      start: new Date(2025, 5, 1),
+      end: new Date(2025, 5, 3),
+    });
+    expect(() => parseCcusageExport('{"monthly":[]}', 'other.json')).toThrow(
+      'other.json: expected the output of `ccusage daily --json`'
+    );
+  });
+
+  it('should total input and output tokens per local day', () => {
+    expect(calculateDailyTokens([timeline])).toEqual(
+      new Map([
+        ['2025-06-01', 200],
+        ['2025-06-02', 100],
+      ])
+    );
+  });
+
+  it('should count a reply logged as several content blocks once', () => {
+    const reply = (text: string) => ({
+      timestamp: at(3, 9),
+      requestId: 'req_1',
+      message: {
+        id: 'msg_1',
+        content: [{ type: 'text', text }],
+        usage: { input_tokens: 40, output_tokens: 10 },
+      },
+    });
+    const multiBlock = { ...timeline, events: [reply('Reading the file'), reply('Done')] };
+
+    expect(calculateDailyTokens([multiBlock])).toEqual(new Map([['2025-06-03', 50]]));
+  });
+
+  it('should flag days outside the tolerance', () => {
+    const rows = reconcileDaily(calculateDailyTokens([timeline]), ccusage, 0.01);
+
+    expect(rows.map(row => [row.date, row.ccusageTokens, row.matches])).toEqual([
+      ['2025-06-01', 200, true],
+      ['2025-06-02', 200, false],
+    ]);
+    expect(formatReconciliation(rows)).toContain('1 of 2 days differ');
+  });
+});
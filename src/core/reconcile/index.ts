@@ -0,0 +1,126 @@
+import { addDays, format, parse } from 'date-fns';
+import { z } from 'zod';
+import { Timeline } from '../../models/models';
+import { createTokenCounter } from '../../utils/tokens';
+
+// Subset of `ccusage daily --json` output used for cross-checking
+export const CcusageDailySchema = z.object({
+  daily: z.array(
+    z
+      .object({
+        date: z.string().regex(/^\d{4}-\d{2}-\d{2}$/),
+        inputTokens: z.number(),
+        outputTokens: z.number(),
+        totalCost: z.number().optional(),
+      })
+      .passthrough()
+  ),
+});
+
+export type CcusageDaily = z.infer<typeof CcusageDailySchema>;
+
+export interface ReconcileRow {
+  date: string;
+  // Input plus output tokens; cache tokens are left out on both sides
+  ccstatTokens: number;
+  ccusageTokens: number | null;
+  ccusageCost: number | null;
+  matches: boolean;
+}
+
+export function parseCcusageExport(content: string, source: string): CcusageDaily {
+  let data: unknown;
+  try {
+    data = JSON.parse(content);
+  } catch {
+    throw new Error(`${source}: not valid JSON`);
+  }
+
+  const result = CcusageDailySchema.safeParse(data);
+  if (!result.success) {
+    throw new Error(`${source}: expected the output of \`ccusage daily --json\``);
+  }
+  return result.data;
+}
+
+// Local date range covered by the export, for loading matching events
+export function getCcusageRange(data: CcusageDaily): { start: Date; end: Date } | null {
+  if (data.daily.length === 0) return null;
+
+  const dates = data.daily.map(day => day.date).sort();
+  const start = parse(dates[0], 'yyyy-MM-dd', new Date());
+  const end = addDays(parse(dates[dates.length - 1], 'yyyy-MM-dd', new Date()), 1);
+  return { start, end };
+}
+
+// Each reply is counted once, as ccusage does, however many lines it was logged as
+export function calculateDailyTokens(timelines: Timeline[]): Map<string, number> {
+  const daily = new Map<string, number>();
+  const countTokens = createTokenCounter();
+
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      const tokens = countTokens(event);
+      if (tokens === 0) continue;
+
+      const date = format(new Date(event.timestamp), 'yyyy-MM-dd');
+      daily.set(date, (daily.get(date) ?? 0) + tokens);
+    }
+  }
+
+  return daily;
+}
+
+// Compare per day; differences within the tolerance (a fraction) count as matching
+export function reconcileDaily(
+  ccstatDaily: Map<string, number>,
+  ccusage: CcusageDaily,
+  tolerance: number
+): ReconcileRow[] {
+  const ccusageByDate = new Map(ccusage.daily.map(day => [day.date, day]));
+  const dates = Array.from(new Set([...ccusageByDate.keys(), ...ccstatDaily.keys()])).sort();
+
+  return dates.map(date => {
+    const theirs = ccusageByDate.get(date);
+    const ccstatTokens = ccstatDaily.get(date) ?? 0;
+    const ccusageTokens = theirs ? theirs.inputTokens + theirs.outputTokens : null;
+    const reference = ccusageTokens ?? 0;
+
+    return {
+      date,
+      ccstatTokens,
+      ccusageTokens,
+      ccusageCost: theirs?.totalCost ?? null,
+      matches: Math.abs(ccstatTokens - reference) <= Math.max(reference, ccstatTokens) * tolerance,
+    };
+  });
+}
+
+export function formatReconciliation(rows: ReconcileRow[]): string {
+  const lines: string[] = [
+    `${'Date'.padEnd(12)}${'ccstat'.padStart(14)}${'ccusage'.padStart(14)}` +
+      `${'Diff'.padStart(10)}${'Cost'.padStart(10)}`,
+  ];
+
+  for (const row of rows) {
+    const ccusage = row.ccusageTokens ?? 0;
+    const diff =
+      ccusage > 0 ? `${(((row.ccstatTokens - ccusage) / ccusage) * 100).toFixed(1)}%` : '-';
+    const cost = row.ccusageCost !== null ? `$${row.ccusageCost.toFixed(2)}` : '-';
+    lines.push(
+      `${row.date.padEnd(12)}${`${row.ccstatTokens}`.padStart(14)}` +
+        `${row.ccusageTokens !== null ? `${row.ccusageTokens}` : '-'}`.padStart(14) +
+        `${diff.padStart(10)}${cost.padStart(10)}  ${row.matches ? '✓' : '✗'}`
+    );
+  }
+
+  const mismatches = rows.filter(row => !row.matches).length;
+  lines.push('');
+  lines.push(
+    mismatches === 0
+      ? `All ${rows.length} days match`
+      : `${mismatches} of ${rows.length} days differ (tokens are input + output, without cache)`
+  );
+
+  return lines.join('\n');
+}
@@ -0,0 +1,94 @@
+import { mkdtemp, readFile, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { repairLogContent, repairLogFile } from '../index';
+
+const line = (timestamp: string) => JSON.stringify({ timestamp, sessionId: 's1', type: 'user' });
+
+const summary = JSON.stringify({ type: 'summary', summary: 'Fix login', leafUuid: 'u1' });
+
+describe('repair', () => {
+  it('should leave an intact log unchanged', () => {
+    const content = [line('2025-06-12T09:00:00Z'), summary, line('2025-06-12T09:05:00Z')]
+      .map(l => l + '\n')
+      .join('');
+
+    expect(repairLogContent(content)).toEqual({
+      content,
+      kept: 3,
+      dropped: 0,
+      salvaged: 0,
+      reencoded: 0,
+      undecodable: 0,
+      moved: 0,
+    });
+  });
+
+  it('should drop, salvage, clean and sort records', () => {
+    const content = [
+      line('2025-06-12T09:05:00Z'),
+      '\uFEFF' + line('2025-06-12T09:00:00Z'),
+      // Stays after the record before it
+      summary,
+      // Cut off by a crash, with the next record appended to the same line
+      '{"timestamp": "2025-06-12T09:1' + line('2025-06-12T09:10:00Z'),
+      'garbage',
+      '\0\0\0',
+      line('2025-06-12T09:07:00Z') + '\0\0',
+      line('not a date'),
+    ].join('\r\n');
+
+    const result = repairLogContent(content);
+    expect(result.content.split('\n')).toEqual([
+      line('2025-06-12T09:00:00Z'),
+      summary,
+      line('2025-06-12T09:05:00Z'),
+      line('2025-06-12T09:07:00Z'),
+      line('2025-06-12T09:10:00Z'),
+      '',
+    ]);
+    expect(result).toMatchObject({ kept: 5, dropped: 2, salvaged: 1, reencoded: 2, moved: 2 });
+  });
+
+  it('should count only the records out of order as moved', () => {
+    const content = [
+      line('2025-06-12T09:10:00Z'),
+      line('2025-06-12T09:00:00Z'),
+      line('2025-06-12T09:01:00Z'),
+      line('2025-06-12T09:02:00Z'),
+    ].join('\n');
+
+    expect(repairLogContent(content).moved).toBe(1);
+  });
+
+  it('should report undecodable bytes without changing the line', () => {
+    const undecodable = JSON.stringify({
+      timestamp: '2025-06-12T09:00:00Z',
+      sessionId: 's1',
+      type: 'user',
+      message: { content: 'caf\uFFFD' },
+    });
+
+    const result = repairLogContent(undecodable + '\n');
+    expect(result).toMatchObject({ content: undecodable + '\n', reencoded: 0, undecodable: 1 });
+  });
+
+  it('should write a copy next to the original', async () => {
+    const root = await mkdtemp(join(tmpdir(), 'ccstat-repair-'));
+    const path = join(root, 'session.jsonl');
+    const original = `${line('2025-06-12T09:05:00Z')}\n{"broken\n${line('2025-06-12T09:00:00Z')}\n`;
+
+    try {
+      await writeFile(path, original);
+      const result = await repairLogFile(path);
+
+      expect(result.output).toBe(`${path}.repaired`);
+      expect(await readFile(path, 'utf-8')).toBe(original);
+      expect(await readFile(result.output, 'utf-8')).toBe(
+        `${line('2025-06-12T09:00:00Z')}\n${line('2025-06-12T09:05:00Z')}\n`
+      );
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+});
@@ -0,0 +1,129 @@
+import { readFile, writeFile } from 'fs/promises';
+import { parseJSONLLine } from '../parser';
+
+export interface RepairResult {
+  // Repaired file content, one record per line
+  content: string;
+  kept: number;
+  // Lines nothing could be recovered from
+  dropped: number;
+  // Records cut out of a line holding a partial write followed by a complete record
+  salvaged: number;
+  // Lines whose encoding was fixed: byte order marks, NUL bytes
+  reencoded: number;
+  // Lines with invalid UTF-8, read as replacement characters (U+FFFD) and kept as they are
+  undecodable: number;
+  // Records out of time order: all but the longest run of records already in order
+  moved: number;
+}
+
+// Whether a line is a record the parser would read: an event with a usable timestamp, or any
+// other JSON object such as a conversation title
+function isRecoverable(line: string): boolean {
+  const result = parseJSONLLine(line);
+  return result.status !== 'malformed';
+}
+
+// The complete record at the end of a line that starts with a partial one, as left when a write
+// was cut off and the next record appended to the same line
+function salvageRecord(line: string): string | null {
+  for (let index = line.indexOf('{', 1); index > 0; index = line.indexOf('{', index + 1)) {
+    const candidate = line.slice(index);
+    // Only whole records: the tail of a cut-off record can still hold a complete nested object
+    const status = parseJSONLLine(candidate).status;
+    if (status === 'event' || status === 'summary') return candidate;
+  }
+  return null;
+}
+
+// Records that have to move to put the others in order: all but the longest non-decreasing
+// subsequence of times
+function countOutOfOrder(times: number[]): number {
+  // tails[k] is the smallest last time of a non-decreasing subsequence of length k + 1
+  const tails: number[] = [];
+  for (const time of times) {
+    let low = 0;
+    let high = tails.length;
+    while (low < high) {
+      const middle = (low + high) >> 1;
+      if (tails[middle] <= time) low = middle + 1;
+      else high = middle;
+    }
+    tails[low] = time;
+  }
+  return times.length - tails.length;
+}
+
+function getTimestamp(line: string): number | null {
+  const result = parseJSONLLine(line);
+  return result.status === 'event' ? new Date(result.event.timestamp).getTime() : null;
+}
+
+// Drop what can't be read, fix encoding damage and put events back in time order. Records
+// without a timestamp, such as conversation titles, stay right after the record before them.
+export function repairLogContent(content: string): RepairResult {
+  const result: RepairResult = {
+    content: '',
+    kept: 0,
+    dropped: 0,
+    salvaged: 0,
+    reencoded: 0,
+    undecodable: 0,
+    moved: 0,
+  };
+  const records: Array<{ line: string; time: number }> = [];
+
+  for (const rawLine of content.split('\n')) {
+    // NUL bytes are left by partial writes after a crash, or as padding by some sync tools
+    let line = rawLine.replace(/^\uFEFF/, '').replaceAll('\0', '').trim();
+    if (!line) continue;
+
+    if (rawLine.includes('\0') || rawLine.startsWith('\uFEFF')) result.reencoded++;
+    // Invalid UTF-8 is read as replacement characters, which keep the line valid JSON; the
+    // original bytes are lost, so these lines can only be reported
+    if (rawLine.includes('\uFFFD')) result.undecodable++;
+
+    if (!isRecoverable(line)) {
+      const salvaged = salvageRecord(line);
+      if (!salvaged) {
+        result.dropped++;
+        continue;
+      }
+      line = salvaged;
+      result.salvaged++;
+    }
+
+    const previous = records[records.length - 1];
+    records.push({ line, time: getTimestamp(line) ?? previous?.time ?? -Infinity });
+  }
+
+  // Array sort is stable, so records with equal timestamps keep their order
+  const sorted = [...records].sort((a, b) => a.time - b.time);
+  result.moved = countOutOfOrder(records.map(record => record.time));
+  result.kept = sorted.length;
+  result.content = sorted.map(record => record.line + '\n').join('');
+  return result;
+}
+
+export function getRepairedPath(path: string): string {
+  return `${path}.repaired`;
+}
+
+// Write a repaired copy next to the file; the original is never changed
+export async function repairLogFile(path: string): Promise<RepairResult & { output: string }> {
+  const result = repairLogContent(await readFile(path, 'utf-8'));
+  const output = getRepairedPath(path);
+  await writeFile(output, result.content);
+  return { ...result, output };
+}
+
+export function formatRepairResult(path: string, result: RepairResult, output: string): string {
+  return [
+    `${path} → ${output}`,
+    `  ${result.kept} records kept, ${result.dropped} lines dropped`,
+    `  ${result.salvaged} records salvaged from partial writes`,
+    `  ${result.reencoded} lines with encoding fixes`,
+    `  ${result.undecodable} lines with undecodable bytes`,
+    `  ${result.moved} records moved into time order`,
+  ].join('\n');
+}
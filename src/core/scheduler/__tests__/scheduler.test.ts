@@ -0,0 +1,70 @@
+import { matchesCron, parseCron } from '../cron';
+import { parseDailyTime, parseScheduleEntry } from '../index';
+
+describe('parseCron', () => {
+  it('should reject malformed expressions', () => {
+    expect(parseCron('* * * *')).toBeNull();
+    expect(parseCron('60 * * * *')).toBeNull();
+    expect(parseCron('5-1 * * * *')).toBeNull();
+    expect(parseCron('*/0 * * * *')).toBeNull();
+    expect(parseCron('a * * * *')).toBeNull();
+  });
+
+  it('should expand lists, ranges and steps', () => {
+    const schedule = parseCron('*/15 9-17 1,15 * 1-5');
+    expect(schedule).not.toBeNull();
+    expect(Array.from(schedule!.minutes)).toEqual([0, 15, 30, 45]);
+    expect(schedule!.hours.size).toBe(9);
+    expect(Array.from(schedule!.daysOfMonth)).toEqual([1, 15]);
+  });
+
+  it('should treat 7 as Sunday', () => {
+    expect(parseCron('0 0 * * 7')!.daysOfWeek.has(0)).toBe(true);
+  });
+});
+
+describe('matchesCron', () => {
+  // 2025-01-03 is a Friday
+  const friday1800 = new Date(2025, 0, 3, 18, 0);
+
+  it('should match the weekly report schedule', () => {
+    const schedule = parseCron('0 18 * * 5')!;
+    expect(matchesCron(schedule, friday1800)).toBe(true);
+    expect(matchesCron(schedule, new Date(2025, 0, 3, 18, 1))).toBe(false);
+    expect(matchesCron(schedule, new Date(2025, 0, 4, 18, 0))).toBe(false);
+  });
+
+  it('should match either day field when both are restricted', () => {
+    const schedule = parseCron('0 18 1 * 5')!;
+    expect(matchesCron(schedule, friday1800)).toBe(true);
+    expect(matchesCron(schedule, new Date(2025, 1, 1, 18, 0))).toBe(true);
+    expect(matchesCron(schedule, new Date(2025, 0, 2, 18, 0))).toBe(false);
+  });
+});
+
+describe('parseScheduleEntry', () => {
+  it('should split the cron expression from the task', () => {
+    const entry = parseScheduleEntry('0 18 * * 5 report');
+    expect(entry?.expression).toBe('0 18 * * 5');
+    expect(entry?.task).toBe('report');
+  });
+
+  it('should reject unknown tasks and missing fields', () => {
+    expect(parseScheduleEntry('0 18 * * 5 deploy')).toBeNull();
+    expect(parseScheduleEntry('0 18 * * 5')).toBeNull();
+  });
+});
+
+describe('parseDailyTime', () => {
+  it('should schedule a task every day at a local time', () => {
+    const entry = parseDailyTime('18:30', 'summary');
+    expect(entry?.expression).toBe('30 18 * * *');
+    expect(entry?.task).toBe('summary');
+  });
+
+  it('should reject invalid times', () => {
+    expect(parseDailyTime('24:00', 'summary')).toBeNull();
+    expect(parseDailyTime('18:60', 'summary')).toBeNull();
+    expect(parseDailyTime('6pm', 'summary')).toBeNull();
+  });
+});
@@ -0,0 +1,78 @@
+// Standard five-field cron expressions: minute hour day-of-month month day-of-week
+export interface CronSchedule {
+  minutes: Set<number>;
+  hours: Set<number>;
+  daysOfMonth: Set<number>;
+  months: Set<number>;
+  daysOfWeek: Set<number>;
+  // Cron matches either day field when both are restricted
+  restrictedDays: { dayOfMonth: boolean; dayOfWeek: boolean };
+}
+
+const FIELD_RANGES: Array<[number, number]> = [
+  [0, 59],
+  [0, 23],
+  [1, 31],
+  [1, 12],
+  [0, 7],
+];
+
+function parseField(field: string, min: number, max: number): Set<number> | null {
+  const values = new Set<number>();
+
+  for (const part of field.split(',')) {
+    const match = part.match(/^(\*|(\d+)(?:-(\d+))?)(?:\/(\d+))?$/);
+    if (!match) return null;
+
+    const start = match[1] === '*' ? min : parseInt(match[2]);
+    const end = match[1] === '*' ? max : match[3] !== undefined ? parseInt(match[3]) : start;
+    // A step on a single value (e.g. 5/15) runs from that value to the end
+    const rangeEnd = match[4] !== undefined && match[3] === undefined ? max : end;
+    const step = match[4] !== undefined ? parseInt(match[4]) : 1;
+
+    if (start < min || rangeEnd > max || start > rangeEnd || step <= 0) return null;
+    for (let value = start; value <= rangeEnd; value += step) {
+      values.add(value);
+    }
+  }
+
+  return values;
+}
+
+// Returns null for invalid expressions
+export function parseCron(expression: string): CronSchedule | null {
+  const fields = expression.trim().split(/\s+/);
+  if (fields.length !== 5) return null;
+
+  const parsed = fields.map((field, i) => parseField(field, ...FIELD_RANGES[i]));
+  if (parsed.some(values => values === null)) return null;
+
+  const [minutes, hours, daysOfMonth, months, daysOfWeek] = parsed as Set<number>[];
+  // Both 0 and 7 mean Sunday
+  if (daysOfWeek.has(7)) daysOfWeek.add(0);
+
+  return {
+    minutes,
+    hours,
+    daysOfMonth,
+    months,
+    daysOfWeek,
+    restrictedDays: { dayOfMonth: fields[2] !== '*', dayOfWeek: fields[4] !== '*' },
+  };
+}
+
+// Check a local time against the schedule, to minute precision
+export function matchesCron(schedule: CronSchedule, date: Date): boolean {
+  if (!schedule.minutes.has(date.getMinutes())) return false;
+  if (!schedule.hours.has(date.getHours())) return false;
+  if (!schedule.months.has(date.getMonth() + 1)) return false;
+
+  const dayOfMonth = schedule.daysOfMonth.has(date.getDate());
+  const dayOfWeek = schedule.daysOfWeek.has(date.getDay());
+  const { restrictedDays } = schedule;
+
+  if (restrictedDays.dayOfMonth && restrictedDays.dayOfWeek) {
+    return dayOfMonth || dayOfWeek;
+  }
+  return dayOfMonth && dayOfWeek;
+}
@@ -0,0 +1,70 @@
+import { CronSchedule, matchesCron, parseCron } from './cron';
+
+export const SCHEDULED_TASK_VALUES = ['snapshot', 'push', 'report', 'summary'] as const;
+
+export type ScheduledTask = (typeof SCHEDULED_TASK_VALUES)[number];
+
+export function isValidScheduledTask(value: string): value is ScheduledTask {
+  return SCHEDULED_TASK_VALUES.includes(value as ScheduledTask);
+}
+
+export interface ScheduleEntry {
+  expression: string;
+  schedule: CronSchedule;
+  task: ScheduledTask;
+}
+
+// Parse "<five cron fields> <task>", e.g. "0 18 * * 5 report"
+export function parseScheduleEntry(entry: string): ScheduleEntry | null {
+  const fields = entry.trim().split(/\s+/);
+  if (fields.length !== 6) return null;
+
+  const task = fields[5];
+  const expression = fields.slice(0, 5).join(' ');
+  const schedule = parseCron(expression);
+  if (!schedule || !isValidScheduledTask(task)) return null;
+
+  return { expression, schedule, task };
+}
+
+// Run a task every day at a local "HH:MM" time
+export function parseDailyTime(time: string, task: ScheduledTask): ScheduleEntry | null {
+  const match = /^(\d{1,2}):(\d{2})$/.exec(time.trim());
+  if (!match) return null;
+
+  const hour = parseInt(match[1]);
+  const minute = parseInt(match[2]);
+  if (hour > 23 || minute > 59) return null;
+
+  return parseScheduleEntry(`${minute} ${hour} * * * ${task}`);
+}
+
+const MINUTE_MS = 60 * 1000;
+
+// Run matching tasks at the start of every minute until the returned stop function is called
+export function startScheduler(
+  entries: ScheduleEntry[],
+  runTask: (task: ScheduledTask) => Promise<void>,
+  onError: (entry: ScheduleEntry, error: unknown) => void
+): () => void {
+  let timer: NodeJS.Timeout | undefined;
+
+  const tick = () => {
+    const now = new Date();
+    for (const entry of entries) {
+      if (matchesCron(entry.schedule, now)) {
+        runTask(entry.task).catch(error => onError(entry, error));
+      }
+    }
+    scheduleNext();
+  };
+
+  const scheduleNext = () => {
+    // Align to the next minute boundary so drift never skips a minute
+    const delay = MINUTE_MS - (Date.now() % MINUTE_MS);
+    timer = setTimeout(tick, delay);
+  };
+
+  scheduleNext();
+  return () => clearTimeout(timer);
+}
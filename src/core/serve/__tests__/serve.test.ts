@@ -0,0 +1,66 @@
+import { mkdtemp, rm } from 'fs/promises';
+import { Server } from 'http';
+import { AddressInfo } from 'net';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { createUsageServer, ServeOptions } from '../index';
+
+const localExport = {
+  generatedAt: '2025-01-01T00:00:00.000Z',
+  range: null,
+  projects: [],
+};
+
+describe('usage server', () => {
+  let dataDir: string;
+  let server: Server;
+  let baseUrl: string;
+
+  const start = async (options: Partial<ServeOptions>) => {
+    server = createUsageServer({
+      loadExport: async () => localExport,
+      loadTimelines: async () => [],
+      searchRange: () => ({ startTime: new Date(0), endTime: new Date(0) }),
+      dataDir,
+      ...options,
+    });
+    await new Promise<void>(resolve => server.listen(0, '127.0.0.1', resolve));
+    baseUrl = `http://127.0.0.1:${(server.address() as AddressInfo).port}`;
+  };
+
+  const request = (path: string, token?: string, body?: unknown) =>
+    fetch(`${baseUrl}${path}`, {
+      method: body ? 'POST' : 'GET',
+      headers: token ? { Authorization: `Bearer ${token}` } : {},
+      body: body ? JSON.stringify(body) : undefined,
+    });
+
+  beforeEach(async () => {
+    dataDir = await mkdtemp(join(tmpdir(), 'ccstat-serve-'));
+  });
+
+  afterEach(async () => {
+    await new Promise(resolve => server.close(resolve));
+    await rm(dataDir, { recursive: true, force: true });
+  });
+
+  it('should reject requests without the token', async () => {
+    await start({ token: 'secret' });
+
+    expect((await request('/api/v1/usage')).status).toBe(401);
+    expect((await request('/api/v1/usage', 'wrong')).status).toBe(401);
+    expect((await request('/api/v1/usage', 'secret')).status).toBe(200);
+  });
+
+  it('should only answer team routes as a collector', async () => {
+    await start({ token: 'secret', collector: true });
+
+    const submission = { ...localExport, user: 'alice' };
+    expect((await request('/api/v1/usage', 'secret', submission)).status).toBe(201);
+    expect((await request('/api/v1/team', 'secret')).status).toBe(200);
+
+    expect((await request('/api/v1/usage', 'secret')).status).toBe(404);
+    expect((await request('/api/v1/status', 'secret')).status).toBe(404);
+    expect((await request('/grafana/search', 'secret', {})).status).toBe(404);
+  });
+});
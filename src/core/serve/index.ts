@@ -0,0 +1,163 @@
+import { createHash, timingSafeEqual } from 'crypto';
+import { createServer, IncomingMessage, Server, ServerResponse } from 'http';
+import { Timeline } from '../../models/models';
+import { UsageExport, UsageExportSchema } from '../export';
+import { GrafanaQuerySchema, listGrafanaTargets, queryGrafanaTargets } from '../grafana';
+import { mergeUsageExports } from '../merge';
+import { loadSubmissions, saveSubmission } from '../collector';
+import { DEFAULT_IDLE_MINUTES, getActivityStatus, getStatusRange } from '../status';
+
+export interface ServeOptions {
+  // Produce the local usage export on demand
+  loadExport: () => Promise<UsageExport>;
+  // Load events in an arbitrary range, for Grafana queries
+  loadTimelines: (startTime: Date, endTime: Date) => Promise<Timeline[]>;
+  // Range used to list projects for Grafana's metric picker
+  searchRange: () => { startTime: Date; endTime: Date };
+  // Accept pushed submissions and serve the merged team report, and nothing about this machine
+  collector?: boolean;
+  dataDir: string;
+  // Shared secret every request must send as "Authorization: Bearer <token>"
+  token?: string;
+}
+
+// Submissions are small aggregates; anything larger is rejected
+const MAX_BODY_BYTES = 1024 * 1024;
+
+class HttpError extends Error {
+  constructor(
+    public readonly status: number,
+    message: string
+  ) {
+    super(message);
+    this.name = 'HttpError';
+  }
+}
+
+function sendJson(res: ServerResponse, status: number, body: unknown): void {
+  res.writeHead(status, { 'Content-Type': 'application/json' });
+  res.end(JSON.stringify(body));
+}
+
+async function readJsonBody(req: IncomingMessage): Promise<unknown> {
+  const chunks: Buffer[] = [];
+  let size = 0;
+
+  for await (const chunk of req) {
+    size += chunk.length;
+    if (size > MAX_BODY_BYTES) {
+      throw new HttpError(413, 'request body too large');
+    }
+    chunks.push(chunk);
+  }
+
+  try {
+    return JSON.parse(Buffer.concat(chunks).toString('utf-8'));
+  } catch {
+    throw new HttpError(400, 'request body is not valid JSON');
+  }
+}
+
+// Compare digests so the time taken doesn't reveal how much of the token matched
+function isAuthorized(req: IncomingMessage, token: string): boolean {
+  const digest = (value: string) => createHash('sha256').update(value).digest();
+  return timingSafeEqual(digest(req.headers.authorization ?? ''), digest(`Bearer ${token}`));
+}
+
+// A collector is reachable by the team, so it only answers the team routes
+async function handleCollectorRequest(
+  options: ServeOptions,
+  route: string,
+  req: IncomingMessage,
+  res: ServerResponse
+): Promise<void> {
+  if (route === 'POST /api/v1/usage') {
+    const result = UsageExportSchema.safeParse(await readJsonBody(req));
+    if (!result.success || !result.data.user) {
+      throw new HttpError(400, 'body must be a ccstat export with a user');
+    }
+    await saveSubmission(options.dataDir, result.data);
+    sendJson(res, 201, { ok: true });
+    return;
+  }
+
+  if (route === 'GET /api/v1/team') {
+    sendJson(res, 200, mergeUsageExports(await loadSubmissions(options.dataDir)));
+    return;
+  }
+
+  throw new HttpError(404, `no route for ${route}`);
+}
+
+async function handleRequest(
+  options: ServeOptions,
+  req: IncomingMessage,
+  res: ServerResponse
+): Promise<void> {
+  if (options.token && !isAuthorized(req, options.token)) {
+    throw new HttpError(401, 'missing or invalid token');
+  }
+
+  const { pathname, searchParams } = new URL(req.url ?? '/', 'http://localhost');
+  const route = `${req.method} ${pathname}`;
+
+  if (options.collector) {
+    await handleCollectorRequest(options, route, req, res);
+    return;
+  }
+
+  if (route === 'GET /api/v1/usage') {
+    sendJson(res, 200, await options.loadExport());
+    return;
+  }
+
+  // Whether a Claude session is working right now, e.g. for window manager indicators
+  if (route === 'GET /api/v1/status') {
+    const idle = searchParams.get('idle');
+    const idleMinutes = idle !== null ? parseInt(idle) : DEFAULT_IDLE_MINUTES;
+    if (!(idleMinutes > 0)) {
+      throw new HttpError(400, 'idle must be a positive number of minutes');
+    }
+    const now = new Date();
+    const { startTime, endTime } = getStatusRange(now);
+    const timelines = await options.loadTimelines(startTime, endTime);
+    sendJson(res, 200, getActivityStatus(timelines, now, idleMinutes));
+    return;
+  }
+
+  // SimpleJSON/Infinity datasource: point Grafana at http://host:port/grafana
+  if (route === 'GET /grafana' || route === 'GET /grafana/') {
+    sendJson(res, 200, { ok: true });
+    return;
+  }
+
+  if (route === 'POST /grafana/search') {
+    const { startTime, endTime } = options.searchRange();
+    sendJson(res, 200, listGrafanaTargets(await options.loadTimelines(startTime, endTime)));
+    return;
+  }
+
+  if (route === 'POST /grafana/query') {
+    const result = GrafanaQuerySchema.safeParse(await readJsonBody(req));
+    if (!result.success) {
+      throw new HttpError(400, 'body must be a Grafana query with range and targets');
+    }
+    const timelines = await options.loadTimelines(
+      new Date(result.data.range.from),
+      new Date(result.data.range.to)
+    );
+    sendJson(res, 200, queryGrafanaTargets(timelines, result.data));
+    return;
+  }
+
+  throw new HttpError(404, `no route for ${route}`);
+}
+
+export function createUsageServer(options: ServeOptions): Server {
+  return createServer((req, res) => {
+    handleRequest(options, req, res).catch(error => {
+      const status = error instanceof HttpError ? error.status : 500;
+      sendJson(res, status, { error: error instanceof Error ? error.message : 'unknown error' });
+    });
+  });
+}
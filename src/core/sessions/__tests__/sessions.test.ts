@@ -0,0 +1,96 @@
+import { Timeline } from '../../../models/models';
+import {
+  formatSessionList,
+  getSessionLabel,
+  groupSessions,
+  linkContinuedSessions,
+  resolveSessionTitles,
+} from '../index';
+
+const timeline: Timeline = {
+  projectName: 'web',
+  events: [
+    { timestamp: '2025-01-01T09:00:00.000Z', sessionId: 'session-a', uuid: 'a1' },
+    { timestamp: '2025-01-01T09:02:00.000Z', sessionId: 'session-a', uuid: 'a2' },
+    {
+      timestamp: '2025-01-01T11:00:00.000Z',
+      sessionId: 'session-b',
+      uuid: 'b1',
+      cwd: "/home/user/it's web",
+    },
+  ],
+  eventCount: 3,
+  activeDuration: 3,
+  startTime: new Date('2025-01-01T09:00:00.000Z'),
+  endTime: new Date('2025-01-01T11:00:00.000Z'),
+};
+
+describe('sessions', () => {
+  it('should resolve summary titles through leaf message uuids', () => {
+    const titles = resolveSessionTitles([timeline], new Map([['a2', 'Fix login redirect']]));
+    expect(titles).toEqual(new Map([['session-a', 'Fix login redirect']]));
+  });
+
+  it('should group events by session in start order', () => {
+    const sessions = groupSessions([timeline], new Map([['session-b', 'Add dark mode']]));
+
+    expect(sessions.map(s => [s.sessionId, s.eventCount, s.title])).toEqual([
+      ['session-a', 2, undefined],
+      ['session-b', 1, 'Add dark mode'],
+    ]);
+  });
+
+  it('should label untitled sessions with a short ID', () => {
+    const [untitled, titled] = groupSessions([timeline], new Map([['session-b', 'Add dark mode']]));
+
+    expect(getSessionLabel(untitled)).toBe('session-');
+    expect(getSessionLabel(titled)).toBe('Add dark mode');
+  });
+
+  describe('continued sessions', () => {
+    // session-c resumes session-b, which started as a continuation of session-a
+    const continued: Timeline = {
+      ...timeline,
+      events: [
+        ...timeline.events.map((event, i) => (i === 2 ? { ...event, parentUuid: 'a2' } : event)),
+        {
+          timestamp: '2025-01-02T09:00:00.000Z',
+          sessionId: 'session-c',
+          uuid: 'c1',
+          parentUuid: 'b1',
+        },
+        { timestamp: '2025-01-02T10:00:00.000Z', sessionId: 'session-d', parentUuid: null },
+        // Repeated from session-b when session-c was resumed
+        { timestamp: '2025-01-01T11:00:00.000Z', sessionId: 'session-c', uuid: 'b1' },
+      ],
+    };
+
+    it('should link sessions through parent message uuids', () => {
+      expect(linkContinuedSessions([continued])).toEqual(
+        new Map([
+          ['session-a', 'session-a'],
+          ['session-b', 'session-a'],
+          ['session-c', 'session-a'],
+        ])
+      );
+    });
+
+    it('should merge a conversation under its latest session', () => {
+      const titles = new Map([['session-b', 'Fix login redirect']]);
+      const sessions = groupSessions([continued], titles, true);
+
+      expect(sessions.map(s => [s.sessionId, s.eventCount, s.resumes, s.title])).toEqual([
+        ['session-c', 4, 2, 'Fix login redirect'],
+        ['session-d', 1, 0, undefined],
+      ]);
+      expect(formatSessionList(sessions)).toContain('Fix login redirect (resumed 2×)');
+    });
+  });
+
+  it('should show how to resume each session', () => {
+    const output = formatSessionList(groupSessions([timeline]));
+
+    expect(output).toContain('  ↳ claude --resume session-a');
+    expect(output).toContain(`  ↳ cd '/home/user/it'\\''s web' && claude --resume session-b`);
+  });
+});
@@ -0,0 +1,150 @@
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { createTimeline } from '../parser';
+import { getProjectDirectory, getResumeCommand } from '../../utils/projectActions';
+
+export interface SessionSummary {
+  sessionId: string;
+  // Conversation title from summary records, when Claude Code wrote one
+  title?: string;
+  projectName: string;
+  // Working directory the session was last run in
+  directory?: string;
+  startTime: Date;
+  endTime: Date;
+  eventCount: number;
+  activeDuration: number;
+  // Earlier sessions this conversation was resumed from, when continued sessions are merged
+  resumes?: number;
+}
+
+// Resolve summary titles (keyed by leaf message uuid) to the sessions containing those messages
+export function resolveSessionTitles(
+  timelines: Timeline[],
+  summaries: Map<string, string>
+): Map<string, string> {
+  const titles = new Map<string, string>();
+
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      if (!event.uuid || !event.sessionId) continue;
+
+      const title = summaries.get(event.uuid);
+      if (title !== undefined) {
+        titles.set(event.sessionId, title);
+      }
+    }
+  }
+
+  return titles;
+}
+
+// Resuming or continuing a session starts a new session ID whose first message points at
+// the last message of the previous one. Maps every session ID to its conversation's first one.
+export function linkContinuedSessions(timelines: Timeline[]): Map<string, string> {
+  const uuidSessions = new Map<string, string>();
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      // Resumed sessions may repeat earlier messages; they belong to the original session
+      if (event.uuid && event.sessionId && !uuidSessions.has(event.uuid)) {
+        uuidSessions.set(event.uuid, event.sessionId);
+      }
+    }
+  }
+
+  const parents = new Map<string, string>();
+  const find = (sessionId: string): string => {
+    const parent = parents.get(sessionId);
+    return parent ? find(parent) : sessionId;
+  };
+
+  const events = timelines
+    .flatMap(timeline => timeline.events)
+    .sort((a, b) => a.timestamp.localeCompare(b.timestamp));
+  for (const event of events) {
+    const previous = event.parentUuid && uuidSessions.get(event.parentUuid);
+    if (!event.sessionId || !previous) continue;
+
+    const [root, child] = [find(previous), find(event.sessionId)];
+    if (root !== child) parents.set(child, root);
+  }
+
+  const conversations = new Map<string, string>();
+  for (const sessionId of new Set(uuidSessions.values())) {
+    conversations.set(sessionId, find(sessionId));
+  }
+  return conversations;
+}
+
+export function groupSessions(
+  timelines: Timeline[],
+  titles: Map<string, string> = new Map(),
+  mergeContinued = false
+): SessionSummary[] {
+  const sessions: SessionSummary[] = [];
+  const conversations = mergeContinued ? linkContinuedSessions(timelines) : new Map();
+
+  for (const timeline of timelines) {
+    const sessionEvents = new Map<string, Timeline['events']>();
+    const seenUuids = new Set<string>();
+    for (const event of timeline.events) {
+      if (!event.sessionId) continue;
+      // Messages repeated by a resumed session are counted once in the merged conversation
+      if (mergeContinued && event.uuid) {
+        if (seenUuids.has(event.uuid)) continue;
+        seenUuids.add(event.uuid);
+      }
+      const key = conversations.get(event.sessionId) ?? event.sessionId;
+      const events = sessionEvents.get(key) ?? [];
+      events.push(event);
+      sessionEvents.set(key, events);
+    }
+
+    for (const events of sessionEvents.values()) {
+      const session = createTimeline(timeline.projectName, events);
+      // The latest session continues the conversation, so resume and title that one
+      const sessionIds = Array.from(new Set(events.map(event => event.sessionId!)));
+      const sessionId = sessionIds[sessionIds.length - 1];
+      const title = [...sessionIds].reverse().find(id => titles.has(id));
+      sessions.push({
+        sessionId,
+        title: title && titles.get(title),
+        projectName: timeline.projectName,
+        directory: getProjectDirectory(session) ?? undefined,
+        startTime: session.startTime,
+        endTime: session.endTime,
+        eventCount: session.eventCount,
+        activeDuration: session.activeDuration,
+        resumes: mergeContinued ? sessionIds.length - 1 : undefined,
+      });
+    }
+  }
+
+  return sessions.sort((a, b) => a.startTime.getTime() - b.startTime.getTime());
+}
+
+// Titled sessions show their title; others fall back to a short session ID
+export function getSessionLabel(session: SessionSummary): string {
+  return session.title ?? session.sessionId.slice(0, 8);
+}
+
+export function formatSessionList(sessions: SessionSummary[]): string {
+  if (sessions.length === 0) {
+    return 'No sessions found in the specified time range';
+  }
+
+  const projectWidth = Math.max(...sessions.map(s => s.projectName.length), 7);
+  const lines = sessions.flatMap(session => {
+    const started = format(session.startTime, 'MM/dd HH:mm');
+    const events = `${session.eventCount}`.padStart(6);
+    const duration = `${session.activeDuration}m`.padStart(6);
+    const project = session.projectName.padEnd(projectWidth);
+    const resumes = session.resumes ? ` (resumed ${session.resumes}×)` : '';
+    return [
+      `${started}  ${project} ${events} ${duration}  ${getSessionLabel(session)}${resumes}`,
+      `  ↳ ${getResumeCommand(session.sessionId, session.directory)}`,
+    ];
+  });
+
+  return lines.join('\n');
+}
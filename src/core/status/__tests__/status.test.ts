@@ -0,0 +1,126 @@
+import { mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { Timeline } from '../../../models/models';
+import {
+  createStatusSnapshot,
+  formatActivityStatus,
+  formatProjectStatus,
+  getActivityStatus,
+  getProjectDayStats,
+  readStatusSnapshot,
+  writeStatusSnapshot,
+} from '../index';
+
+const at = (hour: number, minute: number, second = 0) =>
+  new Date(2025, 5, 12, hour, minute, second);
+
+function createTestTimeline(projectName: string, events: [string, Date][]): Timeline {
+  return {
+    projectName,
+    events: events.map(([sessionId, time]) => ({ sessionId, timestamp: time.toISOString() })),
+    eventCount: events.length,
+    activeDuration: 0,
+    startTime: events[0][1],
+    endTime: events[events.length - 1][1],
+  };
+}
+
+describe('getActivityStatus', () => {
+  const now = at(12, 0);
+  const timelines = [
+    createTestTimeline('web', [
+      ['a', at(10, 0)],
+      // The gap before this event starts the current streak
+      ['a', at(11, 30)],
+      ['a', at(11, 34)],
+      ['a', at(11, 58)],
+    ]),
+    createTestTimeline('api', [['b', at(11, 50)]]),
+  ];
+
+  it('should report sessions with recent events and their current streak', () => {
+    expect(getActivityStatus(timelines, now, 5).sessions).toEqual([
+      { projectName: 'web', sessionId: 'a', since: at(11, 58), lastEvent: at(11, 58), minutes: 0 },
+    ]);
+    expect(getActivityStatus(timelines, now, 25).sessions).toEqual([
+      { projectName: 'web', sessionId: 'a', since: at(11, 30), lastEvent: at(11, 58), minutes: 28 },
+      { projectName: 'api', sessionId: 'b', since: at(11, 50), lastEvent: at(11, 50), minutes: 0 },
+    ]);
+  });
+
+  it('should describe idle and active states', () => {
+    expect(formatActivityStatus(getActivityStatus(timelines, now, 1))).toBe(
+      'Idle (no Claude activity in the last 1m)'
+    );
+    expect(formatActivityStatus(getActivityStatus(timelines, now, 25))).toContain(
+      'web: working for 28m, last event 2m ago'
+    );
+  });
+});
+
+describe('status snapshot', () => {
+  const now = at(12, 0);
+  const timelines = [
+    {
+      ...createTestTimeline('web', [['a', at(9, 0)], ['b', at(11, 58)]]),
+      activeDuration: 65,
+    },
+  ];
+  let directory: string;
+
+  beforeEach(async () => {
+    directory = await mkdtemp(join(tmpdir(), 'ccstat-status-'));
+  });
+
+  afterEach(async () => {
+    await rm(directory, { recursive: true, force: true });
+  });
+
+  it("should summarize today's activity per project", () => {
+    const snapshot = createStatusSnapshot(timelines, now);
+
+    expect(getProjectDayStats(snapshot, 'web')).toEqual({
+      projectName: 'web',
+      events: 2,
+      minutes: 65,
+      sessions: 2,
+      active: true,
+      lastEvent: at(11, 58).toISOString(),
+    });
+    expect(formatProjectStatus(getProjectDayStats(snapshot, 'web'))).toBe(
+      '● web 1h 05m · 2 events'
+    );
+    expect(formatProjectStatus(getProjectDayStats(snapshot, 'api'))).toBe(
+      '○ api 0h 00m · 0 events'
+    );
+  });
+
+  it('should find a project by its qualified name, or else its bare name', () => {
+    const snapshot = createStatusSnapshot(
+      [
+        { ...timelines[0], projectName: 'org-a/web' },
+        { ...timelines[0], projectName: 'org-b/web', activeDuration: 5 },
+      ],
+      now
+    );
+
+    expect(getProjectDayStats(snapshot, 'web', 'org-b/web').minutes).toBe(5);
+    const unshared = createStatusSnapshot(timelines, now);
+    expect(getProjectDayStats(unshared, 'web', 'org-b/web').minutes).toBe(65);
+  });
+
+  it('should only read back fresh snapshots from the same day', async () => {
+    const path = join(directory, 'status.json');
+    const snapshot = createStatusSnapshot(timelines, now);
+    await writeStatusSnapshot(path, snapshot);
+
+    expect(await readStatusSnapshot(path, at(12, 0, 30))).toEqual(snapshot);
+    expect(await readStatusSnapshot(path, at(12, 2))).toBeNull();
+    expect(await readStatusSnapshot(path, new Date(2025, 5, 13, 12, 0))).toBeNull();
+    expect(await readStatusSnapshot(join(directory, 'missing.json'), now)).toBeNull();
+
+    await writeFile(path, '{"date":');
+    expect(await readStatusSnapshot(path, now)).toBeNull();
+  });
+});
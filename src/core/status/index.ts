@@ -0,0 +1,198 @@
+import { format, subHours } from 'date-fns';
+import { mkdir, readFile, rename, writeFile } from 'fs/promises';
+import { dirname, join } from 'path';
+import { z } from 'zod';
+import { Timeline } from '../../models/models';
+import { countSessions } from '../../ui/utils/columns';
+import { getDataDir } from '../../utils/config';
+import { formatHours } from '../../utils/durations';
+
+export const DEFAULT_IDLE_MINUTES = 5;
+
+// Activity streaks are only looked up this far back
+export const STATUS_LOOKBACK_HOURS = 24;
+
+export interface ActiveSession {
+  projectName: string;
+  sessionId?: string;
+  // Start of the current streak without an idle gap
+  since: Date;
+  lastEvent: Date;
+  minutes: number;
+}
+
+export interface ActivityStatus {
+  active: boolean;
+  checkedAt: Date;
+  idleMinutes: number;
+  sessions: ActiveSession[];
+}
+
+export function getStatusRange(now: Date): { startTime: Date; endTime: Date } {
+  return { startTime: subHours(now, STATUS_LOOKBACK_HOURS), endTime: now };
+}
+
+// Sessions with events in the last `idleMinutes`, longest-running first
+export function getActivityStatus(
+  timelines: Timeline[],
+  now: Date,
+  idleMinutes = DEFAULT_IDLE_MINUTES
+): ActivityStatus {
+  const idleMs = idleMinutes * 60 * 1000;
+  const sessions: ActiveSession[] = [];
+
+  for (const timeline of timelines) {
+    const bySession = new Map<string, number[]>();
+    for (const event of timeline.events) {
+      const time = new Date(event.timestamp).getTime();
+      if (time > now.getTime()) continue;
+
+      const key = event.sessionId ?? '';
+      const times = bySession.get(key) ?? [];
+      times.push(time);
+      bySession.set(key, times);
+    }
+
+    for (const [sessionId, times] of bySession) {
+      times.sort((a, b) => a - b);
+      const last = times[times.length - 1];
+      if (now.getTime() - last > idleMs) continue;
+
+      let start = times.length - 1;
+      while (start > 0 && times[start] - times[start - 1] <= idleMs) start--;
+
+      sessions.push({
+        projectName: timeline.projectName,
+        sessionId: sessionId || undefined,
+        since: new Date(times[start]),
+        lastEvent: new Date(last),
+        minutes: Math.round((last - times[start]) / 60000),
+      });
+    }
+  }
+
+  sessions.sort((a, b) => a.since.getTime() - b.since.getTime());
+  return { active: sessions.length > 0, checkedAt: now, idleMinutes, sessions };
+}
+
+export function formatActivityStatus(status: ActivityStatus): string {
+  if (!status.active) return `Idle (no Claude activity in the last ${status.idleMinutes}m)`;
+
+  const lines = [`Active: ${status.sessions.length} session(s)`];
+  for (const session of status.sessions) {
+    const ago = Math.round((status.checkedAt.getTime() - session.lastEvent.getTime()) / 60000);
+    lines.push(
+      ` - ${session.projectName}: working for ${session.minutes}m, last event ` +
+        (ago === 0 ? 'just now' : `${ago}m ago`)
+    );
+  }
+  return lines.join('\n');
+}
+
+// Snapshots older than this are recomputed rather than trusted
+export const SNAPSHOT_MAX_AGE_SECONDS = 60;
+
+const ProjectDayStatsSchema = z.object({
+  projectName: z.string(),
+  events: z.number(),
+  minutes: z.number(),
+  sessions: z.number(),
+  // A session on the project had events within the idle threshold
+  active: z.boolean(),
+  lastEvent: z.string().nullable(),
+});
+
+// Today's totals per project, cached on disk so editor status bars can read them without
+// parsing any logs
+const StatusSnapshotSchema = z.object({
+  date: z.string(),
+  generatedAt: z.string(),
+  projects: z.array(ProjectDayStatsSchema),
+});
+
+export type ProjectDayStats = z.infer<typeof ProjectDayStatsSchema>;
+export type StatusSnapshot = z.infer<typeof StatusSnapshotSchema>;
+
+export function getStatusSnapshotPath(): string {
+  return join(getDataDir(), 'status.json');
+}
+
+// Timelines are expected to cover today only
+export function createStatusSnapshot(
+  timelines: Timeline[],
+  now: Date,
+  idleMinutes = DEFAULT_IDLE_MINUTES
+): StatusSnapshot {
+  const activeProjects = new Set(
+    getActivityStatus(timelines, now, idleMinutes).sessions.map(s => s.projectName)
+  );
+
+  return {
+    date: format(now, 'yyyy-MM-dd'),
+    generatedAt: now.toISOString(),
+    projects: timelines.map(timeline => ({
+      projectName: timeline.projectName,
+      events: timeline.eventCount,
+      minutes: timeline.activeDuration,
+      sessions: countSessions([timeline]),
+      active: activeProjects.has(timeline.projectName),
+      lastEvent: timeline.events.length > 0 ? timeline.endTime.toISOString() : null,
+    })),
+  };
+}
+
+// Missing, unreadable, stale or yesterday's snapshots all read as null
+export async function readStatusSnapshot(
+  path: string,
+  now: Date,
+  maxAgeSeconds = SNAPSHOT_MAX_AGE_SECONDS
+): Promise<StatusSnapshot | null> {
+  let data: unknown;
+  try {
+    data = JSON.parse(await readFile(path, 'utf-8'));
+  } catch {
+    return null;
+  }
+
+  const result = StatusSnapshotSchema.safeParse(data);
+  if (!result.success || result.data.date !== format(now, 'yyyy-MM-dd')) return null;
+
+  const age = now.getTime() - new Date(result.data.generatedAt).getTime();
+  return age >= 0 && age <= maxAgeSeconds * 1000 ? result.data : null;
+}
+
+// Written to a temporary file first, so readers never see a partial snapshot
+export async function writeStatusSnapshot(path: string, snapshot: StatusSnapshot): Promise<void> {
+  await mkdir(dirname(path), { recursive: true });
+  const temporaryPath = `${path}.${process.pid}.tmp`;
+  await writeFile(temporaryPath, JSON.stringify(snapshot, null, 2));
+  await rename(temporaryPath, path);
+}
+
+// Looked up by the qualified name (e.g. org-a/api) in case another repository shares the
+// bare name, which the project goes by otherwise
+export function getProjectDayStats(
+  snapshot: StatusSnapshot,
+  projectName: string,
+  qualifiedName?: string
+): ProjectDayStats {
+  return (
+    snapshot.projects.find(p => p.projectName === qualifiedName) ??
+    snapshot.projects.find(p => p.projectName === projectName) ?? {
+      projectName,
+      events: 0,
+      minutes: 0,
+      sessions: 0,
+      active: false,
+      lastEvent: null,
+    }
+  );
+}
+
+// One short line for a status bar, e.g. "● webapp 1h 05m · 42 events"
+export function formatProjectStatus(stats: ProjectDayStats): string {
+  return (
+    `${stats.active ? '●' : '○'} ${stats.projectName} ${formatHours(stats.minutes)} · ` +
+    `${stats.events} events`
+  );
+}
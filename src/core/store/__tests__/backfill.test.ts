@@ -0,0 +1,104 @@
+import { appendFile, mkdir, mkdtemp, readFile, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { backfillArchive, BackfillOptions } from '../backfill';
+import { createTimeline } from '../../parser';
+import { readDailyAggregateFile, readDailyAggregates } from '../index';
+
+const line = (day: number, minute: number, project: string) =>
+  JSON.stringify({
+    timestamp: new Date(2023, 4, day, 10, minute).toISOString(),
+    sessionId: 'abc',
+    cwd: `/nonexistent/ccstat-backfill/${project}`,
+  }) + '\n';
+
+describe('backfillArchive', () => {
+  let root: string;
+  let options: BackfillOptions;
+
+  const writeLog = async (project: string, name: string, content: string) => {
+    await mkdir(join(root, 'archive', `-${project}`), { recursive: true });
+    await writeFile(join(root, 'archive', `-${project}`, name), content);
+  };
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-backfill-'));
+    options = {
+      storePath: join(root, 'data', 'backfill.db'),
+      checkpointPath: join(root, 'data', 'backfill-checkpoint.json'),
+      logDirs: [join(root, 'archive')],
+      batchSize: 2,
+    };
+    await writeLog('web', 'a.jsonl', line(1, 0, 'web') + line(1, 2, 'web'));
+    await writeLog('web', 'b.jsonl', line(2, 0, 'web'));
+    await writeLog('api', 'c.jsonl', line(1, 0, 'api'));
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should store daily totals in batches and report progress', async () => {
+    const progress: number[] = [];
+    const result = await backfillArchive({
+      ...options,
+      onProgress: processed => progress.push(processed),
+    });
+
+    expect(result).toEqual({ files: 3, skipped: 0, events: 4, aggregates: 3 });
+    expect(progress).toEqual([2, 3]);
+    const aggregates = await readDailyAggregateFile(options.storePath);
+    expect(aggregates.map(a => [a.date, a.projectName, a.events, a.duration]).sort()).toEqual([
+      ['2023-05-01', 'api', 1, 5],
+      ['2023-05-01', 'web', 2, 2],
+      ['2023-05-02', 'web', 1, 5],
+    ]);
+  });
+
+  it('should resume with new files only and drop writes from an interrupted batch', async () => {
+    await backfillArchive(options);
+    const committed = await readFile(options.storePath, 'utf-8');
+
+    // Left behind by a batch that never reached its checkpoint
+    await appendFile(options.storePath, '{"kind":"daily","date":"2023-05-09"');
+    await writeLog('api', 'd.jsonl', line(3, 0, 'api'));
+
+    const result = await backfillArchive(options);
+
+    expect(result).toMatchObject({ files: 1, skipped: 3, events: 1 });
+    const content = await readFile(options.storePath, 'utf-8');
+    expect(content.startsWith(committed)).toBe(true);
+    expect(content).not.toContain('2023-05-09');
+  });
+
+  it('should replace the totals of earlier runs when restarting', async () => {
+    await backfillArchive(options);
+    const aggregates = await readDailyAggregateFile(options.storePath);
+
+    await backfillArchive({ ...options, restart: true });
+
+    expect(await readDailyAggregateFile(options.storePath)).toEqual(aggregates);
+  });
+
+  it('should start over when the checkpoint is lost', async () => {
+    await backfillArchive(options);
+    const aggregates = await readDailyAggregateFile(options.storePath);
+    await rm(options.checkpointPath);
+
+    expect(await backfillArchive(options)).toMatchObject({ files: 3, skipped: 0 });
+    expect(await readDailyAggregateFile(options.storePath)).toEqual(aggregates);
+  });
+
+  it('should leave out backfilled days the logs still hold', async () => {
+    await backfillArchive(options);
+    // The archive is an older copy of logs that are still in place
+    const web = createTimeline('web', [{ timestamp: new Date(2023, 4, 1, 10).toISOString() }]);
+
+    const aggregates = await readDailyAggregates([web], undefined, options.storePath);
+
+    expect(aggregates.map(a => [a.date, a.projectName]).sort()).toEqual([
+      ['2023-05-01', 'api'],
+      ['2023-05-02', 'web'],
+    ]);
+  });
+});
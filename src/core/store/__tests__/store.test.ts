@@ -0,0 +1,163 @@
+import { appendFile, mkdir, mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { loadTimelines } from '../../parser';
+import { compactStore } from '../compact';
+import {
+  getStoreDay,
+  listStoreDays,
+  readStoreBatches,
+  readStoreIndex,
+  toStoredEvent,
+} from '../index';
+import { ingestLogFiles } from '../ingest';
+
+const line = (minute: number, day = 1) =>
+  JSON.stringify({
+    timestamp: new Date(2025, 0, day, 10, minute).toISOString(),
+    sessionId: 'abc',
+    cwd: '/nonexistent/ccstat-store/web',
+  }) + '\n';
+
+describe('event store', () => {
+  let root: string;
+  let logDir: string;
+  let logFile: string;
+  let storePath: string;
+
+  beforeEach(async () => {
+    root = await mkdtemp(join(tmpdir(), 'ccstat-store-'));
+    logDir = join(root, 'projects');
+    logFile = join(logDir, '-web', 'session.jsonl');
+    storePath = join(root, 'data', 'store');
+    await mkdir(join(logDir, '-web'), { recursive: true });
+  });
+
+  afterEach(async () => {
+    await rm(root, { recursive: true, force: true });
+  });
+
+  it('should ingest complete lines and resume from the stored offset', async () => {
+    // The last line is still being written
+    await writeFile(logFile, line(0) + line(1) + '{"timestamp":');
+
+    expect(await ingestLogFiles(storePath, [logDir])).toEqual({ files: 1, events: 2 });
+    expect(await ingestLogFiles(storePath, [logDir])).toEqual({ files: 0, events: 0 });
+
+    await writeFile(logFile, line(0) + line(1) + line(2) + line(3));
+    expect(await ingestLogFiles(storePath, [logDir])).toEqual({ files: 1, events: 2 });
+
+    expect((await readStoreIndex(storePath)).offsets.get(logFile)).toBe(line(0).length * 4);
+  });
+
+  it('should ignore a torn batch left by an interrupted write', async () => {
+    await writeFile(logFile, line(0));
+    await ingestLogFiles(storePath, [logDir]);
+    const day = getStoreDay(new Date(2025, 0, 1).toISOString());
+    await appendFile(join(storePath, 'days', `${day}.jsonl`), '{"file":"' + logFile);
+
+    await appendFile(logFile, line(1));
+    await ingestLogFiles(storePath, [logDir]);
+
+    expect((await readStoreBatches(storePath)).map(batch => batch.events.length)).toEqual([1, 1]);
+  });
+
+  it('should load timelines from the store', async () => {
+    await writeFile(logFile, line(0) + line(10));
+    await ingestLogFiles(storePath, [logDir]);
+
+    const timelines = await loadTimelines(new Date(2025, 0, 1), new Date(2025, 0, 2), undefined, {
+      store: storePath,
+    });
+
+    expect(timelines.map(t => [t.projectName, t.eventCount, t.activeDuration])).toEqual([
+      ['web', 2, 0],
+    ]);
+  });
+
+  it('should compact old events into daily totals and keep offsets', async () => {
+    await writeFile(logFile, line(0, 1) + line(3, 1) + line(0, 2) + line(0, 20));
+    await ingestLogFiles(storePath, [logDir]);
+    await appendFile(logFile, line(5, 20));
+    await ingestLogFiles(storePath, [logDir]);
+
+    const result = await compactStore(storePath, 10, new Date(2025, 0, 21, 12));
+
+    expect(result).toEqual({ compactedEvents: 3, keptDays: 1, aggregates: 2 });
+    expect(await listStoreDays(storePath)).toEqual(['2025-01-20']);
+    const { aggregates, compactedBefore } = await readStoreIndex(storePath);
+    expect(compactedBefore).toBe('2025-01-11');
+    expect(aggregates.map(a => [a.date, a.projectName, a.events, a.duration])).toEqual([
+      ['2025-01-01', 'web', 2, 3],
+      ['2025-01-02', 'web', 1, 5],
+    ]);
+    expect(await ingestLogFiles(storePath, [logDir])).toEqual({ files: 0, events: 0 });
+  });
+
+  it('should report ranges that reach into compacted days', async () => {
+    await writeFile(logFile, line(0, 1) + line(0, 20));
+    await ingestLogFiles(storePath, [logDir]);
+    await compactStore(storePath, 10, new Date(2025, 0, 21, 12));
+
+    const reported: string[] = [];
+    const load = (startTime: Date) =>
+      loadTimelines(startTime, new Date(2025, 0, 21), undefined, {
+        store: storePath,
+        onCompactedRange: before => reported.push(before),
+      });
+
+    await load(new Date(2025, 0, 15));
+    expect(reported).toEqual([]);
+    await load(new Date(2025, 0, 1));
+    expect(reported).toEqual(['2025-01-11']);
+  });
+
+  it('should only read the days in the range', async () => {
+    await writeFile(logFile, line(0, 1) + line(0, 5) + line(0, 20));
+    await ingestLogFiles(storePath, [logDir]);
+
+    const batches = await readStoreBatches(
+      storePath,
+      new Date(2025, 0, 20),
+      new Date(2025, 0, 20, 23)
+    );
+
+    expect(batches.flatMap(batch => batch.events.map(event => event.timestamp))).toEqual([
+      new Date(2025, 0, 20, 10, 0).toISOString(),
+    ]);
+  });
+
+  it('should store only the fields reports read', () => {
+    const event = {
+      timestamp: '2025-01-01T10:00:00.000Z',
+      sessionId: 'abc',
+      type: 'assistant',
+      requestId: 'req_1',
+      message: {
+        id: 'msg_1',
+        model: 'claude-sonnet-4',
+        usage: { input_tokens: 10, output_tokens: 5, service_tier: 'standard' },
+        content: [
+          { type: 'text', text: `Here is the file${'.'.repeat(300)}` },
+          { type: 'tool_use', id: 'tool_1', name: 'Read', input: { file_path: '/tmp/a' } },
+        ],
+      },
+    };
+
+    expect(toStoredEvent(event)).toMatchObject({
+      timestamp: event.timestamp,
+      sessionId: 'abc',
+      requestId: 'req_1',
+      message: {
+        id: 'msg_1',
+        model: 'claude-sonnet-4',
+        usage: { input_tokens: 10, output_tokens: 5 },
+        content: [
+          { type: 'text', text: `Here is the file${'.'.repeat(184)}` },
+          { type: 'tool_use', name: 'Read' },
+        ],
+      },
+    });
+    expect(JSON.stringify(toStoredEvent(event))).not.toMatch(/file_path/);
+  });
+});
@@ -0,0 +1,120 @@
+import { mkdir, readFile, rename, rm, stat, truncate, writeFile } from 'fs/promises';
+import { dirname, join } from 'path';
+import { z } from 'zod';
+import { getDataDir } from '../../utils/config';
+import { NestedRepositoryMode } from '../git';
+import { discoverLogFiles, parseLogFiles } from '../parser';
+import { aggregateDailyTotals, mergeDailyAggregates } from './compact';
+import { appendRecords } from './index';
+
+export const DEFAULT_BACKFILL_BATCH_SIZE = 200;
+
+const BackfillCheckpointSchema = z.object({
+  // Log files whose totals are in the store
+  processed: z.array(z.string()),
+  // Store size after the last committed batch; anything beyond it is from an interrupted batch
+  storeSize: z.number(),
+});
+
+type BackfillCheckpoint = z.infer<typeof BackfillCheckpointSchema>;
+
+export interface BackfillOptions {
+  // Written by backfills only, see getBackfillStorePath
+  storePath: string;
+  checkpointPath: string;
+  logDirs: string[];
+  batchSize: number;
+  // Forget previously processed files and their totals, and start over
+  restart?: boolean;
+  // Run git when a repository layout can't be read from .git directly
+  gitFallback?: boolean;
+  nestedRepositories?: NestedRepositoryMode;
+  onProgress?: (processed: number, total: number) => void;
+}
+
+export interface BackfillResult {
+  files: number;
+  skipped: number;
+  events: number;
+  aggregates: number;
+}
+
+export function getBackfillCheckpointPath(): string {
+  return join(getDataDir(), 'backfill-checkpoint.json');
+}
+
+async function loadCheckpoint(checkpointPath: string): Promise<BackfillCheckpoint | null> {
+  try {
+    const result = BackfillCheckpointSchema.safeParse(
+      JSON.parse(await readFile(checkpointPath, 'utf-8'))
+    );
+    return result.success ? result.data : null;
+  } catch {
+    return null;
+  }
+}
+
+async function saveCheckpoint(checkpointPath: string, checkpoint: BackfillCheckpoint) {
+  await mkdir(dirname(checkpointPath), { recursive: true });
+  await writeFile(`${checkpointPath}.tmp`, JSON.stringify(checkpoint));
+  await rename(`${checkpointPath}.tmp`, checkpointPath);
+}
+
+async function getFileSize(path: string): Promise<number> {
+  try {
+    return (await stat(path)).size;
+  } catch {
+    return 0;
+  }
+}
+
+// Add daily totals for an archive of session logs, a batch of files at a time so memory
+// stays bounded. Each batch is checkpointed, so an interrupted run resumes where it stopped.
+export async function backfillArchive(options: BackfillOptions): Promise<BackfillResult> {
+  const checkpoint = options.restart ? null : await loadCheckpoint(options.checkpointPath);
+  const processed = new Set(checkpoint?.processed ?? []);
+
+  // Only backfills write this file, so it can be cut back: without a checkpoint every file is
+  // processed again and earlier totals would be counted twice, and with one, whatever an
+  // interrupted batch appended after it is dropped
+  if (!checkpoint) {
+    await rm(options.storePath, { force: true });
+  } else if ((await getFileSize(options.storePath)) > checkpoint.storeSize) {
+    await truncate(options.storePath, checkpoint.storeSize);
+  }
+
+  const fileToDirectoryMap = await discoverLogFiles(options.logDirs);
+  const files = Array.from(fileToDirectoryMap.keys()).sort();
+  const pending = files.filter(file => !processed.has(file));
+  const result: BackfillResult = {
+    files: 0,
+    skipped: files.length - pending.length,
+    events: 0,
+    aggregates: 0,
+  };
+
+  for (let i = 0; i < pending.length; i += options.batchSize) {
+    const batchFiles = pending.slice(i, i + options.batchSize);
+    const directoryEventMap = await parseLogFiles(
+      new Map(batchFiles.map(file => [file, fileToDirectoryMap.get(file)!]))
+    );
+
+    const aggregates = mergeDailyAggregates(await aggregateDailyTotals(directoryEventMap, options));
+    await appendRecords(options.storePath, aggregates);
+
+    batchFiles.forEach(file => processed.add(file));
+    await saveCheckpoint(options.checkpointPath, {
+      processed: Array.from(processed),
+      storeSize: await getFileSize(options.storePath),
+    });
+
+    result.files += batchFiles.length;
+    result.aggregates += aggregates.length;
+    for (const events of directoryEventMap.values()) {
+      result.events += events.length;
+    }
+    options.onProgress?.(result.skipped + result.files, files.length);
+  }
+
+  return result;
+}
@@ -0,0 +1,121 @@
+import { format, startOfDay, subDays } from 'date-fns';
+import { Event } from '../../models/models';
+import { sumEventTokens } from '../../utils/tokens';
+import { createTimeline, mapDirectoriesToRepositories, RepositoryOptions } from '../parser';
+import {
+  DailyAggregate,
+  listStoreDays,
+  readStoreDay,
+  readStoreIndex,
+  removeStoreDays,
+  StoreIndexRecord,
+  writeStoreIndex,
+} from './index';
+
+export const DEFAULT_RETENTION_DAYS = 90;
+
+export interface CompactResult {
+  compactedEvents: number;
+  keptDays: number;
+  aggregates: number;
+}
+
+// Daily totals per project for events grouped by log directory
+export async function aggregateDailyTotals(
+  directoryEventMap: Map<string, Event[]>,
+  options: RepositoryOptions = {}
+): Promise<DailyAggregate[]> {
+  const aggregates: DailyAggregate[] = [];
+  const repoDirectoryMap = await mapDirectoriesToRepositories(directoryEventMap, options);
+
+  for (const [projectName, directories] of repoDirectoryMap) {
+    const byDate = new Map<string, Event[]>();
+    for (const event of directories.flatMap(directory => directoryEventMap.get(directory) ?? [])) {
+      const date = format(new Date(event.timestamp), 'yyyy-MM-dd');
+      const events = byDate.get(date) ?? [];
+      events.push(event);
+      byDate.set(date, events);
+    }
+
+    for (const [date, events] of byDate) {
+      aggregates.push({
+        kind: 'daily',
+        date,
+        projectName,
+        events: events.length,
+        duration: createTimeline(projectName, events).activeDuration,
+        tokens: sumEventTokens(events),
+      });
+    }
+  }
+
+  return aggregates;
+}
+
+// Sum records for the same project and day, sorted by date
+export function mergeDailyAggregates(aggregates: DailyAggregate[]): DailyAggregate[] {
+  const totals = new Map<string, DailyAggregate>();
+
+  for (const aggregate of aggregates) {
+    const key = `${aggregate.date}\0${aggregate.projectName}`;
+    const total = totals.get(key);
+    if (total) {
+      total.events += aggregate.events;
+      total.duration += aggregate.duration;
+      total.tokens += aggregate.tokens;
+    } else {
+      totals.set(key, { ...aggregate });
+    }
+  }
+
+  return Array.from(totals.values()).sort((a, b) => a.date.localeCompare(b.date));
+}
+
+// Turn the days older than the retention window into daily totals per project
+export async function compactStore(
+  storePath: string,
+  retentionDays: number,
+  now: Date,
+  options: RepositoryOptions = {}
+): Promise<CompactResult> {
+  const index = await readStoreIndex(storePath);
+  const cutoff = format(startOfDay(subDays(now, retentionDays)), 'yyyy-MM-dd');
+  const days = await listStoreDays(storePath);
+  const oldDays = days.filter(day => day < cutoff);
+
+  const oldEvents = new Map<string, Event[]>();
+  for (const day of oldDays) {
+    // Days before an earlier cutoff are left over from an interrupted run and already counted
+    if (index.compactedBefore && day < index.compactedBefore) continue;
+
+    for (const batch of await readStoreDay(storePath, day)) {
+      const events = oldEvents.get(batch.directory) ?? [];
+      events.push(...batch.events);
+      oldEvents.set(batch.directory, events);
+    }
+  }
+
+  // One offset record per log file keeps the index bounded
+  const merged = mergeDailyAggregates([
+    ...index.aggregates,
+    ...(await aggregateDailyTotals(oldEvents, options)),
+  ]);
+  const summaries = Array.from(index.summaries, ([leafUuid, title]) => ({ leafUuid, title }));
+  const records: StoreIndexRecord[] = [
+    ...Array.from(index.offsets, ([file, offset]) => ({ kind: 'offset' as const, file, offset })),
+    { kind: 'summaries', summaries },
+    ...merged,
+    { kind: 'compacted', before: cutoff },
+  ];
+
+  // The index is written before the days are removed, so an interrupted run never counts a
+  // day twice: the marker tells the next run which leftover days to skip
+  await writeStoreIndex(storePath, records);
+  await removeStoreDays(storePath, oldDays);
+
+  return {
+    compactedEvents: Array.from(oldEvents.values()).reduce((sum, events) => sum + events.length, 0),
+    keptDays: days.length - oldDays.length,
+    aggregates: merged.length,
+  };
+}
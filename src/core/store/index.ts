@@ -0,0 +1,336 @@
+import { addDays, format, subDays } from 'date-fns';
+import { appendFile, mkdir, readdir, readFile, rename, rm, writeFile } from 'fs/promises';
+import { dirname, join } from 'path';
+import { z } from 'zod';
+import { Event, EventSchema, Timeline } from '../../models/models';
+import { getDataDir } from '../../utils/config';
+
+// The store is a directory holding
+// - days/<yyyy-MM-dd>.jsonl: events by local day, so a report only reads the days in its range
+// - index.jsonl: how far each log file was ingested, conversation titles, and daily totals of
+//   compacted days
+// Records are appended one line each; a torn last line left by a crash is ignored.
+const DAYS_DIR = 'days';
+const INDEX_FILE = 'index.jsonl';
+const DAY_FILE_PATTERN = /^(\d{4}-\d{2}-\d{2})\.jsonl$/;
+
+// Events of one day read from a range of a log file. Ingest records the offset in the index
+// only after writing its batches, so a run interrupted in between reads the same range again:
+// a later batch with the same start replaces the earlier one.
+export const StoreBatchSchema = z.object({
+  file: z.string(),
+  directory: z.string(),
+  // Byte range of the log file the events were read from
+  from: z.number(),
+  offset: z.number(),
+  events: z.array(EventSchema),
+});
+
+// Byte offset in a log file up to which lines have been ingested
+export const StoreOffsetSchema = z.object({
+  kind: z.literal('offset'),
+  file: z.string(),
+  offset: z.number(),
+});
+
+// Conversation titles read from summary lines
+export const StoreSummariesSchema = z.object({
+  kind: z.literal('summaries'),
+  summaries: z.array(z.object({ leafUuid: z.string(), title: z.string() })),
+});
+
+// Totals for one project and local day, kept after the raw events are compacted away
+export const DailyAggregateSchema = z.object({
+  kind: z.literal('daily'),
+  date: z.string(),
+  projectName: z.string(),
+  events: z.number(),
+  duration: z.number(),
+  tokens: z.number(),
+});
+
+// Days before this one only exist as daily totals
+export const CompactionSchema = z.object({
+  kind: z.literal('compacted'),
+  before: z.string(),
+});
+
+export type StoreBatch = z.infer<typeof StoreBatchSchema>;
+export type StoreOffset = z.infer<typeof StoreOffsetSchema>;
+export type StoreSummaries = z.infer<typeof StoreSummariesSchema>;
+export type DailyAggregate = z.infer<typeof DailyAggregateSchema>;
+export type Compaction = z.infer<typeof CompactionSchema>;
+export type StoreIndexRecord = StoreOffset | StoreSummaries | DailyAggregate | Compaction;
+
+export interface StoreIndex {
+  offsets: Map<string, number>;
+  summaries: Map<string, string>;
+  aggregates: DailyAggregate[];
+  // First day with raw events, when the store has been compacted
+  compactedBefore?: string;
+}
+
+export function getStorePath(): string {
+  return join(getDataDir(), 'store');
+}
+
+// Daily totals from `ccstat backfill`, kept apart so backfills never rewrite the event store
+export function getBackfillStorePath(): string {
+  return join(getDataDir(), 'backfill.db');
+}
+
+export function getStoreDay(timestamp: string): string {
+  return format(new Date(timestamp), 'yyyy-MM-dd');
+}
+
+// Token counts of message.usage, see getEventTokens
+const USAGE_FIELDS = [
+  'input_tokens',
+  'output_tokens',
+  'cache_creation_input_tokens',
+  'cache_read_input_tokens',
+];
+
+function toStoredUsage(usage: Record<string, unknown>): Record<string, unknown> {
+  return Object.fromEntries(
+    USAGE_FIELDS.filter(field => typeof usage[field] === 'number').map(field => [
+      field,
+      usage[field],
+    ])
+  );
+}
+
+// Responses are cut to the start a preview shows, see getEventPreview
+const STORED_RESPONSE_LENGTH = 200;
+
+function toStoredText(type: string | undefined, text: string): string {
+  return type === 'user' ? text : text.slice(0, STORED_RESPONSE_LENGTH);
+}
+
+// Prompts the user typed are kept for previews, responses only as far as previews show them;
+// tool input and tool output, which make up most of a log, are not read by any report
+function toStoredContent(type: string | undefined, content: unknown): unknown {
+  if (typeof content === 'string') return toStoredText(type, content);
+  if (!Array.isArray(content)) return undefined;
+
+  const parts = content.flatMap(part => {
+    if (part?.type === 'tool_use' && typeof part.name === 'string') {
+      return [{ type: 'tool_use', name: part.name }];
+    }
+    if (part?.type === 'tool_result') return [{ type: 'tool_result' }];
+    if (part?.type === 'text' && typeof part.text === 'string') {
+      return [{ type: 'text', text: toStoredText(type, part.text) }];
+    }
+    return [];
+  });
+  return parts.length > 0 ? parts : undefined;
+}
+
+// The fields of an event that reports read
+export function toStoredEvent(event: Event): Event {
+  const message = event.message && typeof event.message === 'object' ? event.message : undefined;
+  const storedMessage = message && {
+    // Lines of one reply repeat its usage, see getMessageKey
+    id: typeof message.id === 'string' ? message.id : undefined,
+    model: typeof message.model === 'string' ? message.model : undefined,
+    usage:
+      message.usage && typeof message.usage === 'object' ? toStoredUsage(message.usage) : undefined,
+    content: toStoredContent(event.type, message.content),
+  };
+
+  return {
+    timestamp: event.timestamp,
+    sessionId: event.sessionId,
+    cwd: event.cwd,
+    usage: event.usage,
+    type: event.type,
+    uuid: event.uuid,
+    requestId: event.requestId,
+    parentUuid: event.parentUuid,
+    gitBranch: event.gitBranch,
+    isSidechain: event.isSidechain,
+    subtype: event.subtype,
+    isCompactSummary: event.isCompactSummary,
+    message: storedMessage,
+  };
+}
+
+// JSON lines of a file, skipping torn or otherwise unreadable ones
+async function readRecords(path: string): Promise<unknown[]> {
+  let content: string;
+  try {
+    content = await readFile(path, 'utf-8');
+  } catch {
+    return [];
+  }
+
+  const records: unknown[] = [];
+  for (const line of content.split('\n')) {
+    if (!line.trim()) continue;
+    try {
+      records.push(JSON.parse(line));
+    } catch {
+      continue;
+    }
+  }
+  return records;
+}
+
+export async function appendRecords(path: string, records: unknown[]): Promise<void> {
+  if (records.length === 0) return;
+
+  await mkdir(dirname(path), { recursive: true });
+  // A torn line left by an interrupted write must not swallow the next record
+  const content = records.map(record => JSON.stringify(record)).join('\n');
+  await appendFile(path, '\n' + content + '\n');
+}
+
+// Daily totals in a file of records, such as the backfill store
+export async function readDailyAggregateFile(path: string): Promise<DailyAggregate[]> {
+  const aggregates: DailyAggregate[] = [];
+  for (const record of await readRecords(path)) {
+    const aggregate = DailyAggregateSchema.safeParse(record);
+    if (aggregate.success) aggregates.push(aggregate.data);
+  }
+  return aggregates;
+}
+
+export async function readStoreIndex(storePath: string): Promise<StoreIndex> {
+  const index: StoreIndex = { offsets: new Map(), summaries: new Map(), aggregates: [] };
+
+  for (const record of await readRecords(join(storePath, INDEX_FILE))) {
+    const offset = StoreOffsetSchema.safeParse(record);
+    if (offset.success) {
+      const { file, offset: fileOffset } = offset.data;
+      index.offsets.set(file, Math.max(index.offsets.get(file) ?? 0, fileOffset));
+      continue;
+    }
+    const summaries = StoreSummariesSchema.safeParse(record);
+    if (summaries.success) {
+      for (const { leafUuid, title } of summaries.data.summaries) {
+        index.summaries.set(leafUuid, title);
+      }
+      continue;
+    }
+    const aggregate = DailyAggregateSchema.safeParse(record);
+    if (aggregate.success) {
+      index.aggregates.push(aggregate.data);
+      continue;
+    }
+    const compaction = CompactionSchema.safeParse(record);
+    if (compaction.success) index.compactedBefore = compaction.data.before;
+  }
+  return index;
+}
+
+export async function appendStoreIndex(
+  storePath: string,
+  records: StoreIndexRecord[]
+): Promise<void> {
+  await appendRecords(join(storePath, INDEX_FILE), records);
+}
+
+// Replace the whole index; the rename keeps readers from seeing a half-written file
+export async function writeStoreIndex(
+  storePath: string,
+  records: StoreIndexRecord[]
+): Promise<void> {
+  const indexPath = join(storePath, INDEX_FILE);
+  await mkdir(storePath, { recursive: true });
+  const content = records.map(record => JSON.stringify(record) + '\n').join('');
+  await writeFile(`${indexPath}.tmp`, content);
+  await rename(`${indexPath}.tmp`, indexPath);
+}
+
+// Days with raw events, oldest first
+export async function listStoreDays(storePath: string): Promise<string[]> {
+  let files: string[];
+  try {
+    files = await readdir(join(storePath, DAYS_DIR));
+  } catch {
+    return [];
+  }
+  return files
+    .map(file => DAY_FILE_PATTERN.exec(file)?.[1])
+    .filter((day): day is string => day !== undefined)
+    .sort();
+}
+
+export async function readStoreDay(storePath: string, day: string): Promise<StoreBatch[]> {
+  // Later batches for the same range of a log file replace earlier ones, see StoreBatchSchema
+  const batches = new Map<string, StoreBatch>();
+  for (const record of await readRecords(join(storePath, DAYS_DIR, `${day}.jsonl`))) {
+    const batch = StoreBatchSchema.safeParse(record);
+    if (batch.success) batches.set(`${batch.data.file}\0${batch.data.from}`, batch.data);
+  }
+  return Array.from(batches.values());
+}
+
+// Batches of the days that can hold events between startTime and endTime, or of every day.
+// A day of margin on each side covers events stored under another time zone. Days before
+// compactedBefore are only left over from an interrupted compaction and already counted.
+export async function readStoreBatches(
+  storePath: string,
+  startTime?: Date,
+  endTime?: Date,
+  compactedBefore?: string
+): Promise<StoreBatch[]> {
+  const first = startTime && format(subDays(startTime, 1), 'yyyy-MM-dd');
+  const last = endTime && format(addDays(endTime, 1), 'yyyy-MM-dd');
+  const days = (await listStoreDays(storePath)).filter(
+    day =>
+      (!first || day >= first) &&
+      (!last || day <= last) &&
+      (!compactedBefore || day >= compactedBefore)
+  );
+
+  const batches: StoreBatch[] = [];
+  for (const day of days) batches.push(...(await readStoreDay(storePath, day)));
+  return batches;
+}
+
+// Append batches to the files of their days; each batch holds events of one day
+export async function appendStoreBatches(storePath: string, batches: StoreBatch[]): Promise<void> {
+  const byDay = new Map<string, StoreBatch[]>();
+  for (const batch of batches) {
+    if (batch.events.length === 0) continue;
+    const day = getStoreDay(batch.events[0].timestamp);
+    const dayBatches = byDay.get(day) ?? [];
+    dayBatches.push(batch);
+    byDay.set(day, dayBatches);
+  }
+
+  for (const [day, dayBatches] of byDay) {
+    await appendRecords(join(storePath, DAYS_DIR, `${day}.jsonl`), dayBatches);
+  }
+}
+
+export async function removeStoreDays(storePath: string, days: string[]): Promise<void> {
+  for (const day of days) {
+    await rm(join(storePath, DAYS_DIR, `${day}.jsonl`), { force: true });
+  }
+}
+
+// Daily totals of the days the store compacted, when one is used, and of backfilled archives.
+// A backfilled project and day the timelines or the compacted totals already hold is left out,
+// so an archive overlapping the logs is not counted twice.
+export async function readDailyAggregates(
+  timelines: Timeline[],
+  storePath: string | undefined,
+  backfillStorePath = getBackfillStorePath()
+): Promise<DailyAggregate[]> {
+  const compacted = storePath ? (await readStoreIndex(storePath)).aggregates : [];
+  const getKey = (date: string, projectName: string) => `${date}\0${projectName}`;
+
+  const covered = new Set(compacted.map(({ date, projectName }) => getKey(date, projectName)));
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      covered.add(getKey(getStoreDay(event.timestamp), timeline.projectName));
+    }
+  }
+
+  const backfill = (await readDailyAggregateFile(backfillStorePath)).filter(
+    aggregate => !covered.has(getKey(aggregate.date, aggregate.projectName))
+  );
+  return [...compacted, ...backfill];
+}
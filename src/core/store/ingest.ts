@@ -0,0 +1,95 @@
+import { open, stat } from 'fs/promises';
+import { Event } from '../../models/models';
+import { discoverLogFiles, parseJSONLLine } from '../parser';
+import {
+  appendStoreBatches,
+  appendStoreIndex,
+  getStoreDay,
+  readStoreIndex,
+  StoreBatch,
+  StoreIndexRecord,
+  StoreSummaries,
+  toStoredEvent,
+} from './index';
+
+export interface IngestResult {
+  files: number;
+  events: number;
+}
+
+// Read complete lines appended to a file since `offset`
+export async function readNewLines(
+  filePath: string,
+  offset: number,
+  size: number
+): Promise<{ lines: string[]; offset: number }> {
+  const handle = await open(filePath, 'r');
+  try {
+    const buffer = Buffer.alloc(size - offset);
+    await handle.read(buffer, 0, buffer.length, offset);
+
+    // A line still being written has no newline yet; leave it for the next run
+    const end = buffer.lastIndexOf('\n') + 1;
+    return {
+      lines: buffer.subarray(0, end).toString('utf-8').split('\n'),
+      offset: offset + end,
+    };
+  } finally {
+    await handle.close();
+  }
+}
+
+// Append everything written to the session logs since the last ingest
+export async function ingestLogFiles(storePath: string, logDirs?: string[]): Promise<IngestResult> {
+  const { offsets } = await readStoreIndex(storePath);
+  const fileToDirectoryMap = await discoverLogFiles(logDirs);
+  const batches: StoreBatch[] = [];
+  const indexRecords: StoreIndexRecord[] = [];
+  let files = 0;
+
+  for (const [file, directory] of fileToDirectoryMap) {
+    const ingested = offsets.get(file) ?? 0;
+    let size: number;
+    try {
+      size = (await stat(file)).size;
+    } catch {
+      continue;
+    }
+    // Session logs only grow; a smaller file was rewritten and is left alone
+    if (size <= ingested) continue;
+
+    const { lines, offset } = await readNewLines(file, ingested, size);
+    if (offset === ingested) continue;
+
+    const eventsByDay = new Map<string, Event[]>();
+    const summaries: StoreSummaries['summaries'] = [];
+    for (const line of lines) {
+      if (!line.trim()) continue;
+
+      const result = parseJSONLLine(line);
+      if (result.status === 'event') {
+        const day = getStoreDay(result.event.timestamp);
+        const events = eventsByDay.get(day) ?? [];
+        events.push(toStoredEvent(result.event));
+        eventsByDay.set(day, events);
+      }
+      if (result.status === 'summary') {
+        summaries.push({ leafUuid: result.leafUuid, title: result.title });
+      }
+    }
+    for (const events of eventsByDay.values()) {
+      batches.push({ file, directory, from: ingested, offset, events });
+    }
+    if (summaries.length > 0) indexRecords.push({ kind: 'summaries', summaries });
+    indexRecords.push({ kind: 'offset', file, offset });
+    files++;
+  }
+
+  // Events first: a crash before the offsets are recorded only means reading the range again
+  await appendStoreBatches(storePath, batches);
+  await appendStoreIndex(storePath, indexRecords);
+  return {
+    files,
+    events: batches.reduce((sum, batch) => sum + batch.events.length, 0),
+  };
+}
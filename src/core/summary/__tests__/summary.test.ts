@@ -0,0 +1,37 @@
+import { Timeline } from '../../../models/models';
+import { formatDailySummary } from '../index';
+
+function createTestTimeline(projectName: string, activeDuration: number): Timeline {
+  const time = new Date(2025, 5, 12, 10);
+  return {
+    projectName,
+    events: [{ timestamp: time.toISOString() }],
+    eventCount: 1,
+    activeDuration,
+    startTime: time,
+    endTime: time,
+  };
+}
+
+describe('formatDailySummary', () => {
+  const day = new Date(2025, 5, 12, 18, 30);
+
+  it('should list projects by active time under a totals line', () => {
+    const summary = formatDailySummary(
+      [createTestTimeline('blog', 20), createTestTimeline('web', 125)],
+      day
+    );
+
+    expect(summary.split('\n')).toEqual([
+      'ccstat summary for Thu, Jun 12: 2h 25m active across 2 projects, 2 events',
+      ' - web: 2h 05m',
+      ' - blog: 0h 20m',
+    ]);
+  });
+
+  it('should mention days without activity', () => {
+    expect(formatDailySummary([], day)).toBe(
+      'ccstat summary for Thu, Jun 12: no Claude activity today'
+    );
+  });
+});
@@ -0,0 +1,30 @@
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { createCompactSummary } from '../compact';
+import { formatHours } from '../../utils/durations';
+
+const SUMMARY_PROJECT_LIMIT = 5;
+
+// Plain-text recap of one day, short enough for a notification or chat message
+export function formatDailySummary(timelines: Timeline[], day: Date): string {
+  const summary = createCompactSummary(timelines, day);
+  const title = `ccstat summary for ${format(day, 'EEE, MMM d')}`;
+  if (summary.events === 0) return `${title}: no Claude activity today`;
+
+  const lines = [
+    `${title}: ${formatHours(summary.minutes)} active across ${timelines.length} ` +
+      `project${timelines.length === 1 ? '' : 's'}, ${summary.events} events`,
+  ];
+
+  const top = [...timelines]
+    .sort((a, b) => b.activeDuration - a.activeDuration)
+    .slice(0, SUMMARY_PROJECT_LIMIT);
+  for (const timeline of top) {
+    lines.push(` - ${timeline.projectName}: ${formatHours(timeline.activeDuration)}`);
+  }
+  if (timelines.length > top.length) {
+    lines.push(` - and ${timelines.length - top.length} more`);
+  }
+
+  return lines.join('\n');
+}
@@ -0,0 +1,110 @@
+import { appendFile, mkdir, mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import {
+  createTailEntries,
+  formatTailEntry,
+  getEventPreview,
+  listTimelineEntries,
+  readAppendedEvents,
+} from '../index';
+
+const event = (minute: number, type: string, content: unknown): Event => ({
+  timestamp: new Date(2025, 0, 1, 10, minute).toISOString(),
+  sessionId: 'abc',
+  cwd: '/nonexistent/ccstat-tail/web',
+  type,
+  message: { content },
+});
+
+describe('tail', () => {
+  it('should preview prompts, assistant text and tool calls on one line', () => {
+    expect(getEventPreview(event(0, 'user', 'fix the\n  login bug'))).toBe('fix the login bug');
+    expect(
+      getEventPreview(
+        event(0, 'assistant', [
+          { type: 'text', text: 'Looking at it.' },
+          { type: 'tool_use', name: 'Read', input: {} },
+        ])
+      )
+    ).toBe('Looking at it. [Read]');
+    expect(getEventPreview(event(0, 'user', [{ type: 'tool_result', content: 'ok' }]))).toBe('');
+  });
+
+  it('should format entries as time, project, role and preview', () => {
+    const entry = {
+      timestamp: new Date(2025, 0, 1, 9, 5, 30).toISOString(),
+      project: 'web',
+      role: 'user',
+      preview: 'x'.repeat(100),
+    };
+    expect(formatTailEntry(entry)).toBe(`09:05:30  web  user         ${'x'.repeat(79)}…`);
+    expect(formatTailEntry({ ...entry, role: 'tool_result', preview: '' })).toBe(
+      '09:05:30  web  tool_result'
+    );
+  });
+
+  it('should keep only events of the given projects', async () => {
+    const directoryEventMap = new Map([
+      ['/logs/-web', [event(1, 'user', 'second'), event(0, 'user', 'first')]],
+      ['/logs/-api', [{ ...event(0, 'user', 'other'), cwd: '/nonexistent/ccstat-tail/api' }]],
+    ]);
+
+    const entries = await createTailEntries(directoryEventMap, ['web']);
+    expect(entries.map(e => [e.project, e.preview])).toEqual([
+      ['web', 'first'],
+      ['web', 'second'],
+    ]);
+  });
+
+  it('should list events of all timelines in time order, filtered by role', () => {
+    const timelines = [
+      createTimeline('web', [event(0, 'user', 'first'), event(2, 'assistant', 'reply')]),
+      createTimeline('api', [event(1, 'user', 'second')]),
+    ];
+
+    expect(listTimelineEntries(timelines).map(e => e.preview)).toEqual([
+      'first',
+      'second',
+      'reply',
+    ]);
+    expect(listTimelineEntries(timelines, ['user']).map(e => [e.project, e.preview])).toEqual([
+      ['web', 'first'],
+      ['api', 'second'],
+    ]);
+  });
+
+  describe('readAppendedEvents', () => {
+    let logDir: string;
+
+    beforeEach(async () => {
+      logDir = await mkdtemp(join(tmpdir(), 'ccstat-tail-'));
+      await mkdir(join(logDir, '-web'));
+    });
+
+    afterEach(async () => {
+      await rm(logDir, { recursive: true, force: true });
+    });
+
+    it('should only read lines appended after the initial call', async () => {
+      const logFile = join(logDir, '-web', 'session.jsonl');
+      const line = (minute: number) => JSON.stringify(event(minute, 'user', `${minute}`)) + '\n';
+      await writeFile(logFile, line(0));
+
+      const offsets = new Map<string, number>();
+      expect((await readAppendedEvents(offsets, { logDirs: [logDir] }, true)).size).toBe(0);
+
+      // The last line is still being written
+      await appendFile(logFile, line(1) + '{"timestamp":');
+      const appended = await readAppendedEvents(offsets, { logDirs: [logDir] });
+      expect(Array.from(appended.values()).flat().map(getEventPreview)).toEqual(['1']);
+
+      // Sessions started later are read from their first line
+      await writeFile(join(logDir, '-web', 'new.jsonl'), line(2));
+      const started = await readAppendedEvents(offsets, { logDirs: [logDir] });
+      expect(Array.from(started.values()).flat().map(getEventPreview)).toEqual(['2']);
+    });
+  });
+});
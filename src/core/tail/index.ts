@@ -0,0 +1,141 @@
+import { stat } from 'fs/promises';
+import { format } from 'date-fns';
+import { Event, Timeline } from '../../models/models';
+import {
+  discoverLogFiles,
+  LoadOptions,
+  mapDirectoriesToRepositories,
+  parseJSONLLine,
+} from '../parser';
+import { readNewLines } from '../store/ingest';
+import { createIgnoreMatcher } from '../../utils/ignore';
+import { getEventKind } from '../duration';
+import { getPromptText, redactPrompt, truncateText } from '../../utils/prompts';
+import { matchesProjectName } from '../../utils/projectNames';
+
+// Event kinds, see getEventKind; tool results are logged as user messages
+export const EVENT_ROLE_VALUES = ['user', 'assistant', 'tool_result', 'system'] as const;
+export type EventRole = (typeof EVENT_ROLE_VALUES)[number];
+
+export function isValidEventRole(value: string): value is EventRole {
+  return EVENT_ROLE_VALUES.includes(value as EventRole);
+}
+
+export interface TailEntry {
+  timestamp: string;
+  project: string;
+  role: string;
+  preview: string;
+}
+
+const PREVIEW_WIDTH = 80;
+
+// Short one-line description of what an event contains
+export function getEventPreview(event: Event): string {
+  const prompt = getPromptText(event);
+  if (prompt) return redactPrompt(prompt);
+
+  const content = event.message?.content;
+  if (typeof content === 'string') return redactPrompt(content);
+  if (!Array.isArray(content)) return '';
+
+  const parts: string[] = [];
+  for (const part of content) {
+    if (part?.type === 'text' && typeof part.text === 'string') parts.push(part.text);
+    if (part?.type === 'tool_use' && typeof part.name === 'string') parts.push(`[${part.name}]`);
+  }
+  return redactPrompt(parts.join(' '));
+}
+
+function createEntry(project: string, event: Event): TailEntry {
+  return {
+    timestamp: event.timestamp,
+    project,
+    role: getEventKind(event),
+    preview: getEventPreview(event),
+  };
+}
+
+export function formatTailEntry(entry: TailEntry, timeFormat = 'HH:mm:ss'): string {
+  const time = format(new Date(entry.timestamp), timeFormat);
+  const preview = truncateText(entry.preview, PREVIEW_WIDTH);
+  return `${time}  ${entry.project}  ${entry.role.padEnd(11)}  ${preview}`.trimEnd();
+}
+
+// Name new events after their repository, keeping those of the given projects
+export async function createTailEntries(
+  directoryEventMap: Map<string, Event[]>,
+  projects: string[] = [],
+  options: LoadOptions = {}
+): Promise<TailEntry[]> {
+  const repoDirectoryMap = await mapDirectoriesToRepositories(directoryEventMap, options);
+
+  const entries: TailEntry[] = [];
+  for (const [project, directories] of repoDirectoryMap) {
+    if (projects.length > 0 && !matchesProjectName(projects, project)) continue;
+
+    for (const directory of directories) {
+      for (const event of directoryEventMap.get(directory) ?? []) {
+        entries.push(createEntry(project, event));
+      }
+    }
+  }
+  return entries.sort((a, b) => a.timestamp.localeCompare(b.timestamp));
+}
+
+// Events of all timelines in one chronological list, optionally of the given roles only
+export function listTimelineEntries(timelines: Timeline[], roles: EventRole[] = []): TailEntry[] {
+  const entries: TailEntry[] = [];
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      const entry = createEntry(timeline.projectName, event);
+      if (roles.length === 0 || roles.includes(entry.role as EventRole)) entries.push(entry);
+    }
+  }
+  return entries.sort((a, b) => a.timestamp.localeCompare(b.timestamp));
+}
+
+// Events appended to the session logs since the sizes recorded in `offsets`. The initial
+// call only records where each file ends; files created later are read from the start.
+export async function readAppendedEvents(
+  offsets: Map<string, number>,
+  options: LoadOptions = {},
+  initial = false
+): Promise<Map<string, Event[]>> {
+  const directoryEventMap = new Map<string, Event[]>();
+  const isIgnored = createIgnoreMatcher(options.ignore ?? []);
+
+  for (const [file, directory] of await discoverLogFiles(options.logDirs)) {
+    let size: number;
+    try {
+      size = (await stat(file)).size;
+    } catch {
+      continue;
+    }
+    if (initial) {
+      offsets.set(file, size);
+      continue;
+    }
+
+    const previous = offsets.get(file) ?? 0;
+    // A smaller file was rewritten; follow it from its new end
+    if (size < previous) offsets.set(file, size);
+    if (size <= previous) continue;
+
+    const { lines, offset } = await readNewLines(file, previous, size);
+    offsets.set(file, offset);
+
+    for (const line of lines) {
+      if (!line.trim()) continue;
+      const result = parseJSONLLine(line);
+      if (result.status !== 'event') continue;
+      if (result.event.cwd && isIgnored(result.event.cwd)) continue;
+      if (options.userEventsOnly && getEventKind(result.event) !== 'user') continue;
+
+      const events = directoryEventMap.get(directory) ?? [];
+      events.push(result.event);
+      directoryEventMap.set(directory, events);
+    }
+  }
+  return directoryEventMap;
+}
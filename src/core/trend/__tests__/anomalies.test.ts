@@ -0,0 +1,76 @@
+import { Timeline } from '../../../models/models';
+import { getTrendPeriods } from '../index';
+import { detectAnomalies, findProjectAnomalies, markAnomalousRows } from '../anomalies';
+
+describe('detectAnomalies', () => {
+  it('should flag values far from the mean of the others', () => {
+    expect(detectAnomalies([10, 12, 9, 11, 10, 80], 3)).toEqual([
+      false,
+      false,
+      false,
+      false,
+      false,
+      true,
+    ]);
+  });
+
+  it('should not flag anything without enough varied history', () => {
+    expect(detectAnomalies([10, 80], 3)).toEqual([false, false]);
+    expect(detectAnomalies([10, 10, 10, 80], 3)).toEqual([false, false, false, false]);
+  });
+
+  it('should leave excluded values out of flags and baselines', () => {
+    const excluded = [false, false, false, false, true, false];
+    expect(detectAnomalies([10, 12, 9, 11, 0, 80], 3, excluded)).toEqual([
+      false,
+      false,
+      false,
+      false,
+      false,
+      true,
+    ]);
+  });
+});
+
+describe('trend anomalies', () => {
+  const now = new Date(2025, 5, 12, 15);
+  const periods = getTrendPeriods('day', 5, now);
+  const counts = [4, 5, 3, 4, 40];
+
+  const timeline: Timeline = {
+    projectName: 'agent',
+    events: counts.flatMap((count, day) =>
+      Array.from({ length: count }, (_, i) => ({
+        timestamp: new Date(2025, 5, 8 + day, 10, i).toISOString(),
+      }))
+    ),
+    eventCount: 56,
+    activeDuration: 0,
+    startTime: new Date(2025, 5, 8, 10),
+    endTime: new Date(2025, 5, 12, 10, 39),
+  };
+
+  it('should report the project and period of a spike', () => {
+    expect(findProjectAnomalies([timeline], periods, 'day', 3)).toEqual([
+      { projectName: 'agent', label: periods[4].label, events: 40, baseline: 4 },
+    ]);
+  });
+
+  it('should mark spiking rows', () => {
+    const rows = periods.map((period, i) => ({
+      label: period.label,
+      buckets: [],
+      events: counts[i],
+      duration: 0,
+      tokens: 0,
+    }));
+
+    expect(markAnomalousRows(rows, 3).map(row => row.anomaly)).toEqual([
+      false,
+      false,
+      false,
+      false,
+      true,
+    ]);
+  });
+});
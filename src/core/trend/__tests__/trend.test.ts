@@ -0,0 +1,137 @@
+import { Timeline } from '../../../models/models';
+import {
+  createTrendRows,
+  describeTrend,
+  formatTrend,
+  getHistoryStart,
+  getTrendPeriods,
+  parseSmoothingWindow,
+  rollingAverage,
+  sparkline,
+} from '../index';
+
+describe('sparkline', () => {
+  it('should scale values to block characters and leave zeros blank', () => {
+    expect(sparkline([0, 1, 4, 8])).toBe(' ▁▄█');
+    expect(sparkline([2], 8)).toBe('▂');
+    expect(sparkline([0, 0])).toBe('  ');
+  });
+});
+
+describe('trend', () => {
+  // Thursday
+  const now = new Date(2025, 5, 12, 15);
+
+  it('should list weeks starting on Monday, oldest first', () => {
+    const periods = getTrendPeriods('week', 2, now);
+
+    expect(periods.map(period => [period.start, period.end])).toEqual([
+      [new Date(2025, 5, 2), new Date(2025, 5, 9)],
+      [new Date(2025, 5, 9), new Date(2025, 5, 16)],
+    ]);
+    expect(periods[1].label).toBe('2025-06-09 wk');
+  });
+
+  it('should bucket events per day within each week', () => {
+    const timeline: Timeline = {
+      projectName: 'web',
+      events: [
+        { timestamp: new Date(2025, 5, 3, 10).toISOString() },
+        { timestamp: new Date(2025, 5, 9, 10).toISOString(), usage: { outputTokens: 1500 } },
+        { timestamp: new Date(2025, 5, 12, 10).toISOString() },
+      ],
+      eventCount: 3,
+      activeDuration: 0,
+      startTime: new Date(2025, 5, 3, 10),
+      endTime: new Date(2025, 5, 12, 10),
+    };
+
+    const rows = createTrendRows([timeline], getTrendPeriods('week', 2, now), 'week');
+
+    expect(rows[0].buckets).toEqual([0, 1, 0, 0, 0, 0, 0]);
+    expect(rows[1].buckets).toEqual([1, 0, 0, 1, 0, 0, 0]);
+    expect(rows[1]).toMatchObject({ events: 2, tokens: 1500 });
+    expect(formatTrend(rows)).toContain('Total: 3 events');
+  });
+
+  it('should describe growth between the halves of the range', () => {
+    const row = (duration: number) => ({ label: '', buckets: [], events: 0, duration, tokens: 0 });
+
+    expect(describeTrend([row(100), row(150)])).toBe('Usage is growing (+50%)');
+    expect(describeTrend([row(100), row(50)])).toBe('Usage is shrinking (-50%)');
+    expect(describeTrend([row(100), row(105)])).toBe('Usage is steady (+5%)');
+  });
+});
+
+describe('smoothing', () => {
+  it('should parse day and week windows', () => {
+    expect(parseSmoothingWindow('7d')).toBe(7);
+    expect(parseSmoothingWindow('2w')).toBe(14);
+    expect(parseSmoothingWindow('0d')).toBeNull();
+    expect(parseSmoothingWindow('7')).toBeNull();
+  });
+
+  it('should average over the window, using fewer values at the start', () => {
+    expect(rollingAverage([3, 6, 9, 0], 2)).toEqual([3, 4.5, 7.5, 4.5]);
+  });
+
+  it('should leave skipped values such as vacation days out of the average', () => {
+    expect(rollingAverage([3, 0, 9, 0], 3, [false, true, false, true])).toEqual([3, 3, 6, 9]);
+    expect(rollingAverage([0], 1, [true])[0]).toBeNaN();
+  });
+
+  it('should mark periods taken off entirely', () => {
+    const now = new Date(2025, 5, 12, 15);
+    const periods = getTrendPeriods('day', 3, now);
+    const rows = createTrendRows([], periods, 'day', {
+      vacations: [{ start: '2025-06-11', end: '2025-06-12' }],
+    });
+
+    expect(rows.map(row => row.vacation)).toEqual([undefined, true, true]);
+    expect(formatTrend(rows).split('\n')[2]).toMatch(/ {2}vacation$/);
+  });
+
+  it('should include days before the first period in its average', () => {
+    const now = new Date(2025, 5, 12, 15);
+    const timeline: Timeline = {
+      projectName: 'web',
+      events: [new Date(2025, 5, 10, 10), new Date(2025, 5, 12, 10)].map(time => ({
+        timestamp: time.toISOString(),
+      })),
+      eventCount: 2,
+      activeDuration: 0,
+      startTime: new Date(2025, 5, 10, 10),
+      endTime: new Date(2025, 5, 12, 10),
+    };
+
+    const periods = getTrendPeriods('day', 2, now);
+    const rows = createTrendRows([timeline], periods, 'day', { smoothDays: 3, now });
+
+    expect(getHistoryStart(periods, 3)).toEqual(new Date(2025, 5, 9));
+    // A day with a single event counts as five active minutes
+    expect(rows.map(row => row.average)).toEqual([5 / 3, 10 / 3]);
+    expect(formatTrend(rows)).toContain('Avg/day');
+  });
+});
+
+describe('compacted days', () => {
+  it('should add daily totals from the store to their period', () => {
+    const now = new Date(2025, 5, 12, 15);
+    const aggregate = {
+      kind: 'daily' as const,
+      date: '2025-06-03',
+      projectName: 'web',
+      events: 12,
+      duration: 40,
+      tokens: 900,
+    };
+
+    const rows = createTrendRows([], getTrendPeriods('week', 2, now), 'week', {
+      aggregates: [aggregate],
+    });
+
+    expect(rows[0]).toMatchObject({ events: 12, duration: 40, tokens: 900 });
+    expect(rows[0].buckets).toEqual([0, 12, 0, 0, 0, 0, 0]);
+    expect(rows[1].events).toBe(0);
+  });
+});
@@ -0,0 +1,87 @@
+import { Timeline } from '../../models/models';
+import { Vacation } from '../../utils/vacations';
+import { createTrendRows, TrendPeriod, TrendRow, TrendUnit } from './index';
+
+export const DEFAULT_ANOMALY_THRESHOLD = 3;
+
+// Fewer other periods than this are not enough to call anything unusual
+const MIN_BASELINE_SIZE = 3;
+
+export interface TrendAnomaly {
+  projectName: string;
+  label: string;
+  events: number;
+  baseline: number;
+}
+
+function meanAndDeviation(values: number[]): { mean: number; deviation: number } {
+  const mean = values.reduce((sum, value) => sum + value, 0) / values.length;
+  const variance = values.reduce((sum, value) => sum + (value - mean) ** 2, 0) / values.length;
+  return { mean, deviation: Math.sqrt(variance) };
+}
+
+// Compare each value with the mean of the others, so a spike doesn't inflate its own baseline.
+// Flat baselines (no deviation) are skipped rather than flagging every change, and excluded
+// values, such as vacations, are neither flagged nor part of any baseline.
+export function detectAnomalies(
+  values: number[],
+  threshold: number,
+  excluded: boolean[] = []
+): boolean[] {
+  return values.map((value, i) => {
+    if (excluded[i]) return false;
+    const others = values.filter((_, j) => j !== i && !excluded[j]);
+    if (others.length < MIN_BASELINE_SIZE) return false;
+
+    const { mean, deviation } = meanAndDeviation(others);
+    return deviation > 0 && Math.abs(value - mean) > threshold * deviation;
+  });
+}
+
+// Mark periods whose total events stand out from the rest of the range
+export function markAnomalousRows(rows: TrendRow[], threshold: number): TrendRow[] {
+  const flags = detectAnomalies(
+    rows.map(row => row.events),
+    threshold,
+    rows.map(row => row.vacation === true)
+  );
+  return rows.map((row, i) => ({ ...row, anomaly: flags[i] }));
+}
+
+export function findProjectAnomalies(
+  timelines: Timeline[],
+  periods: TrendPeriod[],
+  unit: TrendUnit,
+  threshold: number,
+  vacations: Vacation[] = []
+): TrendAnomaly[] {
+  const anomalies: TrendAnomaly[] = [];
+
+  for (const timeline of timelines) {
+    const rows = createTrendRows([timeline], periods, unit, { vacations });
+    const events = rows.map(row => row.events);
+    const excluded = rows.map(row => row.vacation === true);
+    detectAnomalies(events, threshold, excluded).forEach((flagged, i) => {
+      if (!flagged) return;
+      anomalies.push({
+        projectName: timeline.projectName,
+        label: periods[i].label,
+        events: events[i],
+        baseline: meanAndDeviation(events.filter((_, j) => j !== i && !excluded[j])).mean,
+      });
+    });
+  }
+
+  return anomalies.sort((a, b) => b.events - a.events);
+}
+
+export function formatAnomalies(anomalies: TrendAnomaly[]): string {
+  const lines = ['Unusual activity:'];
+  for (const anomaly of anomalies) {
+    lines.push(
+      ` ! ${anomaly.projectName} in ${anomaly.label}: ${anomaly.events} events ` +
+        `(usually ~${Math.round(anomaly.baseline)})`
+    );
+  }
+  return lines.join('\n');
+}
@@ -0,0 +1,266 @@
+import {
+  addDays,
+  addHours,
+  addWeeks,
+  differenceInCalendarDays,
+  format,
+  min,
+  parse,
+  startOfDay,
+  startOfWeek,
+  subDays,
+} from 'date-fns';
+import { Timeline } from '../../models/models';
+import { brailleSparkline } from '../../utils/braille';
+import { createTokenCounter, formatTokens } from '../../utils/tokens';
+import { countVacationDays, isVacationDay, Vacation } from '../../utils/vacations';
+import { createTimeline } from '../parser';
+import { DailyAggregate } from '../store';
+
+export type TrendUnit = 'week' | 'day';
+
+export interface TrendPeriod {
+  label: string;
+  start: Date;
+  end: Date;
+}
+
+export interface TrendRow {
+  label: string;
+  // Events per day of a week, or per hour of a day
+  buckets: number[];
+  events: number;
+  duration: number;
+  tokens: number;
+  // Rolling average of daily active minutes ending with this period
+  average?: number;
+  // Events far outside the other periods in the range
+  anomaly?: boolean;
+  // Every day of the period was taken off
+  vacation?: boolean;
+}
+
+export interface TrendOptions {
+  // Rolling average window in days
+  smoothDays?: number;
+  now?: Date;
+  // Daily totals from the compacted store, for days whose events are no longer kept
+  aggregates?: DailyAggregate[];
+  // Days off, marked on periods taken off entirely and left out of the rolling average
+  vacations?: Vacation[];
+}
+
+const SPARK_CHARS = '▁▂▃▄▅▆▇█';
+
+export function sparkline(values: number[], max = Math.max(...values, 0)): string {
+  return values
+    .map(value => {
+      if (value <= 0 || max <= 0) return ' ';
+      const index = Math.ceil((value / max) * SPARK_CHARS.length) - 1;
+      return SPARK_CHARS[Math.min(index, SPARK_CHARS.length - 1)];
+    })
+    .join('');
+}
+
+// The last `count` local weeks (Monday first) or days, oldest first, including the current one
+export function getTrendPeriods(unit: TrendUnit, count: number, now: Date): TrendPeriod[] {
+  const current = unit === 'week' ? startOfWeek(now, { weekStartsOn: 1 }) : startOfDay(now);
+  const step = unit === 'week' ? addWeeks : addDays;
+
+  return Array.from({ length: count }, (_, i) => {
+    const start = step(current, i - count + 1);
+    return {
+      label: format(start, unit === 'week' ? "yyyy-MM-dd 'wk'" : 'yyyy-MM-dd EEE'),
+      start,
+      end: step(start, 1),
+    };
+  });
+}
+
+// Parse a window such as "7d" or "2w" into days
+export function parseSmoothingWindow(value: string): number | null {
+  const match = /^(\d+)([dw])$/.exec(value);
+  if (!match || parseInt(match[1]) === 0) return null;
+  return parseInt(match[1]) * (match[2] === 'w' ? 7 : 1);
+}
+
+// Where to start loading so the first period has a full smoothing window
+export function getHistoryStart(periods: TrendPeriod[], smoothDays = 1): Date {
+  return subDays(periods[0].start, smoothDays - 1);
+}
+
+// Active minutes per local day starting at `start`
+export function calculateDailyDurations(
+  timelines: Timeline[],
+  start: Date,
+  days: number,
+  aggregates: DailyAggregate[] = []
+): number[] {
+  const durations: number[] = new Array(days).fill(0);
+
+  for (const aggregate of aggregates) {
+    const index = differenceInCalendarDays(parse(aggregate.date, 'yyyy-MM-dd', start), start);
+    if (index >= 0 && index < days) durations[index] += aggregate.duration;
+  }
+
+  for (const timeline of timelines) {
+    const dayEvents = new Map<number, Timeline['events']>();
+    for (const event of timeline.events) {
+      const index = differenceInCalendarDays(new Date(event.timestamp), start);
+      if (index < 0 || index >= days) continue;
+
+      const events = dayEvents.get(index) ?? [];
+      events.push(event);
+      dayEvents.set(index, events);
+    }
+
+    for (const [index, events] of dayEvents) {
+      durations[index] += createTimeline(timeline.projectName, events).activeDuration;
+    }
+  }
+
+  return durations;
+}
+
+// Mean of each value and the ones before it, using fewer values at the start. Skipped
+// values are left out; a window of nothing but skipped values has no mean (NaN).
+export function rollingAverage(
+  values: number[],
+  window: number,
+  skipped: boolean[] = []
+): number[] {
+  return values.map((_, i) => {
+    const first = Math.max(0, i - window + 1);
+    const slice = values.slice(first, i + 1).filter((_, j) => !skipped[first + j]);
+    return slice.reduce((sum, value) => sum + value, 0) / slice.length;
+  });
+}
+
+export function createTrendRows(
+  timelines: Timeline[],
+  periods: TrendPeriod[],
+  unit: TrendUnit,
+  options: TrendOptions = {}
+): TrendRow[] {
+  const bucketCount = unit === 'week' ? 7 : 24;
+  const vacations = options.vacations ?? [];
+  const bucketStart = (period: TrendPeriod, index: number) =>
+    unit === 'week' ? addDays(period.start, index) : addHours(period.start, index);
+
+  const rows = periods.map(period => {
+    const row: TrendRow = {
+      label: period.label,
+      buckets: new Array(bucketCount).fill(0),
+      events: 0,
+      duration: 0,
+      tokens: 0,
+    };
+
+    const countTokens = createTokenCounter();
+    for (const timeline of timelines) {
+      const events = timeline.events.filter(event => {
+        const time = new Date(event.timestamp);
+        return time >= period.start && time < period.end;
+      });
+      if (events.length === 0) continue;
+
+      for (const event of events) {
+        const time = new Date(event.timestamp);
+        let index = bucketCount - 1;
+        while (index > 0 && time < bucketStart(period, index)) index--;
+        row.buckets[index]++;
+        row.tokens += countTokens(event);
+      }
+      row.events += events.length;
+      row.duration += createTimeline(timeline.projectName, events).activeDuration;
+    }
+
+    // Compacted days keep their totals but not the time of day
+    for (const aggregate of options.aggregates ?? []) {
+      const day = parse(aggregate.date, 'yyyy-MM-dd', period.start);
+      if (day < period.start || day >= period.end) continue;
+
+      if (unit === 'week') {
+        row.buckets[differenceInCalendarDays(day, period.start)] += aggregate.events;
+      }
+      row.events += aggregate.events;
+      row.duration += aggregate.duration;
+      row.tokens += aggregate.tokens;
+    }
+
+    const days = differenceInCalendarDays(period.end, period.start);
+    if (days > 0 && countVacationDays(period.start, period.end, vacations) === days) {
+      row.vacation = true;
+    }
+    return row;
+  });
+
+  const { smoothDays, now = new Date(), aggregates } = options;
+  if (smoothDays && periods.length > 0) {
+    const historyStart = getHistoryStart(periods, smoothDays);
+    const days = differenceInCalendarDays(periods[periods.length - 1].end, historyStart);
+    const averages = rollingAverage(
+      calculateDailyDurations(timelines, historyStart, days, aggregates),
+      smoothDays,
+      Array.from({ length: days }, (_, i) => isVacationDay(addDays(historyStart, i), vacations))
+    );
+
+    // Average up to the period's last day, or today for the current period
+    periods.forEach((period, i) => {
+      const lastDay = subDays(min([period.end, addDays(startOfDay(now), 1)]), 1);
+      const average = averages[differenceInCalendarDays(lastDay, historyStart)];
+      if (!isNaN(average)) rows[i].average = average;
+    });
+  }
+
+  return rows;
+}
+
+// Compare the later half of the range with the earlier half
+export function describeTrend(rows: TrendRow[]): string {
+  const half = Math.floor(rows.length / 2);
+  const sum = (list: TrendRow[]) => list.reduce((total, row) => total + row.duration, 0);
+  const earlier = sum(rows.slice(0, half));
+  const later = sum(rows.slice(rows.length - half));
+
+  if (half === 0 || earlier === 0) return later > 0 ? 'Usage is new in this range' : 'No usage';
+  const change = Math.round(((later - earlier) / earlier) * 100);
+  if (Math.abs(change) < 10) return `Usage is steady (${change >= 0 ? '+' : ''}${change}%)`;
+  return change > 0 ? `Usage is growing (+${change}%)` : `Usage is shrinking (${change}%)`;
+}
+
+// With `braille`, sparklines pack two buckets into each character
+export function formatTrend(rows: TrendRow[], braille = false): string {
+  // One scale for every row so sparklines are comparable across periods
+  const max = Math.max(...rows.flatMap(row => row.buckets), 0);
+  const labelWidth = Math.max(...rows.map(row => row.label.length), 6);
+  const bucketCount = rows[0]?.buckets.length ?? 0;
+  const sparkWidth = braille ? Math.ceil(bucketCount / 2) : bucketCount;
+  const draw = braille ? brailleSparkline : sparkline;
+  const smoothed = rows.some(row => row.average !== undefined);
+
+  const lines: string[] = [
+    `${'Period'.padEnd(labelWidth)}  ${'Activity'.padEnd(sparkWidth)}` +
+      `${'Events'.padStart(8)}${'Duration'.padStart(10)}${'Tokens'.padStart(9)}` +
+      (smoothed ? 'Avg/day'.padStart(9) : ''),
+  ];
+  for (const row of rows) {
+    const average = row.average !== undefined ? `${Math.round(row.average)}m` : '';
+    lines.push(
+      `${row.label.padEnd(labelWidth)}  ${draw(row.buckets, max)}` +
+        `${`${row.events}`.padStart(8)}${`${row.duration}m`.padStart(10)}` +
+        `${formatTokens(row.tokens).padStart(9)}` +
+        (smoothed ? average.padStart(9) : '') +
+        (row.anomaly ? '  !' : '') +
+        (row.vacation ? '  vacation' : '')
+    );
+  }
+
+  lines.push('');
+  lines.push(
+    `Total: ${rows.reduce((sum, row) => sum + row.events, 0)} events, ` +
+      `${rows.reduce((sum, row) => sum + row.duration, 0)}m. ${describeTrend(rows)}`
+  );
+
+  return lines.join('\n');
+}
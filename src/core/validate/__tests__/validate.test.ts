@@ -0,0 +1,97 @@
+import { mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { formatValidationReport, validateLogContent, validateLogFile } from '../index';
+
+const now = new Date('2025-06-12T12:00:00Z');
+
+const line = (timestamp: string, extra: object = {}) =>
+  JSON.stringify({ timestamp, sessionId: 's1', type: 'user', ...extra });
+
+describe('validate', () => {
+  it('should accept a well-formed log', () => {
+    const content = [
+      line('2025-06-12T09:00:00Z'),
+      JSON.stringify({ type: 'summary', summary: 'Fix login', leafUuid: 'u1' }),
+      // Parallel tool results can be a few milliseconds out of order
+      line('2025-06-12T09:00:01.500Z'),
+      line('2025-06-12T09:00:01.200Z'),
+      '',
+    ].join('\n');
+
+    expect(validateLogContent('a.jsonl', content, now)).toEqual({
+      path: 'a.jsonl',
+      lines: 4,
+      events: 3,
+      issues: [],
+    });
+  });
+
+  it('should report each kind of problem with its line', () => {
+    const content = [
+      line('2025-06-12T09:00:00Z'),
+      '{"timestamp": "2025-06-12T09:01:00Z", "sessionId": ',
+      '[1, 2]',
+      JSON.stringify({ sessionId: 's1', type: 'assistant' }),
+      line('not a date'),
+      line('2019-01-01T00:00:00Z'),
+      line('2025-06-12T10:00:00Z'),
+      line('2025-06-12T09:30:00Z'),
+    ].join('\n');
+
+    expect(validateLogContent('b.jsonl', content, now).issues).toEqual([
+      { kind: 'invalid-json', line: 2 },
+      { kind: 'not-object', line: 3 },
+      { kind: 'missing-timestamp', line: 4 },
+      { kind: 'invalid-timestamp', line: 5 },
+      {
+        kind: 'implausible-timestamp',
+        line: 6,
+        detail: '2019-01-01T00:00:00.000Z is too old',
+      },
+      { kind: 'out-of-order', line: 8, detail: expect.stringMatching(/ after /) },
+    ]);
+  });
+
+  it('should report zero-byte files', async () => {
+    const root = await mkdtemp(join(tmpdir(), 'ccstat-validate-'));
+    try {
+      await writeFile(join(root, 'empty.jsonl'), '');
+      expect(await validateLogFile(join(root, 'empty.jsonl'), now)).toEqual({
+        path: join(root, 'empty.jsonl'),
+        lines: 0,
+        events: 0,
+        issues: [{ kind: 'empty-file' }],
+      });
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it('should list files with problems and count the issues', () => {
+    const reports = [
+      { path: 'ok.jsonl', lines: 3, events: 3, issues: [] },
+      {
+        path: 'bad.jsonl',
+        lines: 14,
+        events: 2,
+        issues: Array.from({ length: 12 }, (_, i) => ({ kind: 'invalid-json' as const, line: i })),
+      },
+      { path: 'empty.jsonl', lines: 0, events: 0, issues: [{ kind: 'empty-file' as const }] },
+    ];
+
+    const lines = formatValidationReport(reports).split('\n');
+    expect(lines[0]).toBe('bad.jsonl: 14 lines, 2 events');
+    expect(lines[1]).toBe('  line 0: invalid JSON');
+    expect(lines[11]).toBe('  … and 2 more');
+    expect(lines.slice(12)).toEqual([
+      'empty.jsonl: 0 lines, 0 events',
+      '  empty file',
+      '',
+      'Checked 3 file(s): 2 with problems (12 invalid JSON, 1 empty file)',
+    ]);
+    expect(formatValidationReport(reports.slice(0, 1), true)).toBe(
+      'ok.jsonl: ok (3 events)\n\nChecked 1 file(s): no problems found'
+    );
+  });
+});
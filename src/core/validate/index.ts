@@ -0,0 +1,153 @@
+import { format } from 'date-fns';
+import { readFile, stat } from 'fs/promises';
+import { checkTimestampSanity } from '../../utils/timestampSanity';
+import { MalformedReason, parseJSONLLine } from '../parser';
+
+export type LogIssueKind =
+  | MalformedReason
+  | 'missing-timestamp'
+  | 'implausible-timestamp'
+  | 'out-of-order'
+  | 'empty-file';
+
+export interface LogIssue {
+  kind: LogIssueKind;
+  // 1-based; absent for problems with the whole file
+  line?: number;
+  detail?: string;
+}
+
+export interface LogFileReport {
+  path: string;
+  lines: number;
+  events: number;
+  issues: LogIssue[];
+}
+
+// Issues listed per file before the rest are only counted
+export const MAX_LISTED_ISSUES = 10;
+
+// Events written in parallel (e.g. several tool results) can land slightly out of order, so only
+// larger steps back in time count
+const OUT_OF_ORDER_TOLERANCE_MS = 1000;
+
+// Record types that are session events and so should carry a timestamp
+const EVENT_TYPES = ['user', 'assistant', 'system'];
+
+const ISSUE_LABELS: Record<LogIssueKind, string> = {
+  'invalid-json': 'invalid JSON',
+  'not-object': 'not a JSON object',
+  'schema-mismatch': 'unexpected event fields',
+  'invalid-timestamp': 'unparseable timestamp',
+  'missing-timestamp': 'missing timestamp',
+  'implausible-timestamp': 'implausible timestamp',
+  'out-of-order': 'out of order',
+  'empty-file': 'empty file',
+};
+
+function isMissingTimestamp(line: string): boolean {
+  try {
+    const data = JSON.parse(line.replace(/^\uFEFF/, '').trim());
+    return !data.timestamp && (Boolean(data.sessionId) || EVENT_TYPES.includes(data.type));
+  } catch {
+    return false;
+  }
+}
+
+// Check the lines of one JSONL log file the way the parser reads them
+export function validateLogContent(path: string, content: string, now: Date): LogFileReport {
+  const report: LogFileReport = { path, lines: 0, events: 0, issues: [] };
+  if (content.trim() === '') {
+    report.issues.push({ kind: 'empty-file' });
+    return report;
+  }
+
+  let previous: Date | null = null;
+  const lines = content.split('\n');
+  for (let i = 0; i < lines.length; i++) {
+    if (!lines[i].trim()) continue;
+    report.lines++;
+
+    const result = parseJSONLLine(lines[i]);
+    if (result.status === 'malformed') {
+      report.issues.push({ kind: result.reason, line: i + 1 });
+      continue;
+    }
+    if (result.status === 'ignored' && isMissingTimestamp(lines[i])) {
+      report.issues.push({ kind: 'missing-timestamp', line: i + 1 });
+      continue;
+    }
+    if (result.status !== 'event') continue;
+
+    report.events++;
+    const time = new Date(result.event.timestamp);
+    const sanity = checkTimestampSanity(time, now);
+    if (sanity !== 'valid') {
+      report.issues.push({
+        kind: 'implausible-timestamp',
+        line: i + 1,
+        detail: `${result.event.timestamp} is ${sanity === 'future' ? 'in the future' : 'too old'}`,
+      });
+      continue;
+    }
+
+    if (previous && previous.getTime() - time.getTime() > OUT_OF_ORDER_TOLERANCE_MS) {
+      report.issues.push({
+        kind: 'out-of-order',
+        line: i + 1,
+        detail: `${format(time, 'yyyy-MM-dd HH:mm:ss')} after ${format(previous, 'HH:mm:ss')}`,
+      });
+    }
+    if (!previous || time > previous) previous = time;
+  }
+  return report;
+}
+
+export async function validateLogFile(path: string, now: Date): Promise<LogFileReport> {
+  // Zero-byte files are reported without reading them
+  if ((await stat(path)).size === 0) {
+    return { path, lines: 0, events: 0, issues: [{ kind: 'empty-file' }] };
+  }
+  return validateLogContent(path, await readFile(path, 'utf-8'), now);
+}
+
+function formatIssue(issue: LogIssue): string {
+  const label = ISSUE_LABELS[issue.kind];
+  const text = issue.detail ? `${label}: ${issue.detail}` : label;
+  return issue.line ? `line ${issue.line}: ${text}` : text;
+}
+
+// Files with problems, each with its first issues, then totals per kind of issue
+export function formatValidationReport(reports: LogFileReport[], verbose = false): string {
+  const lines: string[] = [];
+  for (const report of reports) {
+    if (report.issues.length === 0) {
+      if (verbose) lines.push(`${report.path}: ok (${report.events} events)`);
+      continue;
+    }
+
+    lines.push(`${report.path}: ${report.lines} lines, ${report.events} events`);
+    for (const issue of report.issues.slice(0, MAX_LISTED_ISSUES)) {
+      lines.push(`  ${formatIssue(issue)}`);
+    }
+    if (report.issues.length > MAX_LISTED_ISSUES) {
+      lines.push(`  … and ${report.issues.length - MAX_LISTED_ISSUES} more`);
+    }
+  }
+
+  const failed = reports.filter(report => report.issues.length > 0);
+  const counts = new Map<LogIssueKind, number>();
+  for (const issue of failed.flatMap(report => report.issues)) {
+    counts.set(issue.kind, (counts.get(issue.kind) ?? 0) + 1);
+  }
+
+  const totals = Array.from(counts, ([kind, count]) => `${count} ${ISSUE_LABELS[kind]}`);
+  const summary =
+    failed.length === 0
+      ? 'no problems found'
+      : `${failed.length} with problems (${totals.join(', ')})`;
+
+  if (lines.length > 0) lines.push('');
+  lines.push(`Checked ${reports.length} file(s): ${summary}`);
+  return lines.join('\n');
+}
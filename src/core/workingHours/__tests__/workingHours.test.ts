@@ -0,0 +1,57 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../parser';
+import {
+  formatWorkingHoursReport,
+  isWithinWorkingHours,
+  parseWorkingHours,
+  splitByWorkingHours,
+} from '../index';
+
+// 2025-06-09 is a Monday
+const event = (day: number, hour: number, minute = 0): Event => ({
+  timestamp: new Date(2025, 5, day, hour, minute).toISOString(),
+});
+
+describe('working hours', () => {
+  const office = parseWorkingHours('09:00-18:00')!;
+
+  it('should parse HH:MM ranges', () => {
+    expect(office).toEqual({ start: 540, end: 1080, days: [1, 2, 3, 4, 5] });
+    expect(parseWorkingHours('22:00-06:00', [0])).toEqual({ start: 1320, end: 360, days: [0] });
+    for (const value of ['9-18', '09:00', '09:00-09:00', '25:00-18:00']) {
+      expect(parseWorkingHours(value)).toBeNull();
+    }
+  });
+
+  it('should count working days within the window only', () => {
+    expect(isWithinWorkingHours(new Date(2025, 5, 9, 9, 0), office)).toBe(true);
+    expect(isWithinWorkingHours(new Date(2025, 5, 9, 18, 0), office)).toBe(false);
+    // Saturday
+    expect(isWithinWorkingHours(new Date(2025, 5, 14, 10, 0), office)).toBe(false);
+  });
+
+  it('should attribute night shifts to the day they started', () => {
+    const nights = parseWorkingHours('22:00-06:00', [1])!;
+    expect(isWithinWorkingHours(new Date(2025, 5, 9, 23, 0), nights)).toBe(true);
+    expect(isWithinWorkingHours(new Date(2025, 5, 10, 5, 0), nights)).toBe(true);
+    expect(isWithinWorkingHours(new Date(2025, 5, 9, 5, 0), nights)).toBe(false);
+  });
+
+  it('should split activity per project, most after-hours time first', () => {
+    const stats = splitByWorkingHours(
+      [
+        createTimeline('web', [event(9, 10), event(9, 10, 3)]),
+        createTimeline('side', [event(9, 22), event(9, 22, 4), event(9, 11)]),
+      ],
+      office
+    );
+
+    expect(stats.map(s => s.projectName)).toEqual(['side', 'web']);
+    expect(stats[0].outOfHours).toEqual({ events: 2, duration: 4 });
+    expect(stats[1]).toMatchObject({ inHours: { events: 2 }, outOfHours: { events: 0 } });
+
+    const report = formatWorkingHoursReport(stats, office);
+    expect(report).toContain('Working hours: 09:00-18:00, Mon Tue Wed Thu Fri');
+    expect(report).toMatch(/side\s+\d+m\s+4m\s+\d+%/);
+  });
+});
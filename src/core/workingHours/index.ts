@@ -0,0 +1,133 @@
+import { Event, Timeline } from '../../models/models';
+import { createTimeline } from '../parser';
+
+// Local working time; minutes since midnight, with `end` past midnight when end < start
+export interface WorkingHours {
+  start: number;
+  end: number;
+  // Days of the week worked, 0 being Sunday
+  days: number[];
+}
+
+export const DEFAULT_WORKING_DAYS = [1, 2, 3, 4, 5];
+
+export interface ActivitySplit {
+  events: number;
+  duration: number;
+}
+
+export interface WorkingHoursStats {
+  projectName: string;
+  inHours: ActivitySplit;
+  outOfHours: ActivitySplit;
+}
+
+const TIME_PATTERN = /^(\d{1,2}):(\d{2})$/;
+
+function parseClockTime(value: string): number | null {
+  const match = TIME_PATTERN.exec(value.trim());
+  if (!match) return null;
+
+  const hour = parseInt(match[1]);
+  const minute = parseInt(match[2]);
+  if (hour > 24 || minute > 59 || (hour === 24 && minute > 0)) return null;
+  return hour * 60 + minute;
+}
+
+// Parse "09:00-18:00"; working days default to Monday to Friday
+export function parseWorkingHours(
+  value: string,
+  days: number[] = DEFAULT_WORKING_DAYS
+): WorkingHours | null {
+  const [startText, endText, ...rest] = value.split('-');
+  if (endText === undefined || rest.length > 0) return null;
+
+  const start = parseClockTime(startText);
+  const end = parseClockTime(endText);
+  if (start === null || end === null || start === end) return null;
+  return { start, end, days };
+}
+
+export function isWithinWorkingHours(time: Date, hours: WorkingHours): boolean {
+  const minutes = time.getHours() * 60 + time.getMinutes();
+  if (hours.start < hours.end) {
+    return hours.days.includes(time.getDay()) && minutes >= hours.start && minutes < hours.end;
+  }
+
+  // Night shifts belong to the day they started on
+  if (minutes >= hours.start) return hours.days.includes(time.getDay());
+  return minutes < hours.end && hours.days.includes((time.getDay() + 6) % 7);
+}
+
+function summarize(projectName: string, events: Event[]): ActivitySplit {
+  if (events.length === 0) return { events: 0, duration: 0 };
+  return { events: events.length, duration: createTimeline(projectName, events).activeDuration };
+}
+
+// Activity per project inside and outside working hours, most after-hours time first
+export function splitByWorkingHours(
+  timelines: Timeline[],
+  hours: WorkingHours
+): WorkingHoursStats[] {
+  return timelines
+    .map(timeline => {
+      const inHours: Event[] = [];
+      const outOfHours: Event[] = [];
+      for (const event of timeline.events) {
+        const within = isWithinWorkingHours(new Date(event.timestamp), hours);
+        (within ? inHours : outOfHours).push(event);
+      }
+      return {
+        projectName: timeline.projectName,
+        inHours: summarize(timeline.projectName, inHours),
+        outOfHours: summarize(timeline.projectName, outOfHours),
+      };
+    })
+    .sort(
+      (a, b) =>
+        b.outOfHours.duration - a.outOfHours.duration || b.outOfHours.events - a.outOfHours.events
+    );
+}
+
+function formatClockTime(minutes: number): string {
+  return `${Math.floor(minutes / 60)}`.padStart(2, '0') + `:${`${minutes % 60}`.padStart(2, '0')}`;
+}
+
+const WEEKDAY_NAMES = ['Sun', 'Mon', 'Tue', 'Wed', 'Thu', 'Fri', 'Sat'];
+
+export function formatWorkingHoursReport(stats: WorkingHoursStats[], hours: WorkingHours): string {
+  if (stats.length === 0) {
+    return 'No activity found in the specified time range';
+  }
+
+  const days = [...hours.days].sort((a, b) => a - b).map(day => WEEKDAY_NAMES[day]);
+  const nameWidth = Math.max(7, ...stats.map(s => s.projectName.length)) + 2;
+  const lines = [
+    `Working hours: ${formatClockTime(hours.start)}-${formatClockTime(hours.end)}, ` +
+      days.join(' '),
+    '',
+    `${'Project'.padEnd(nameWidth)}${'In hours'.padStart(10)}${'After hours'.padStart(13)}` +
+      `${'After %'.padStart(9)}`,
+  ];
+
+  const formatRow = (name: string, inHours: ActivitySplit, outOfHours: ActivitySplit) => {
+    const total = inHours.duration + outOfHours.duration;
+    const share = total > 0 ? `${Math.round((outOfHours.duration / total) * 100)}%` : '-';
+    return (
+      `${name.padEnd(nameWidth)}${`${inHours.duration}m`.padStart(10)}` +
+      `${`${outOfHours.duration}m`.padStart(13)}${share.padStart(9)}`
+    );
+  };
+
+  const sum = (key: 'inHours' | 'outOfHours'): ActivitySplit => ({
+    events: stats.reduce((total, s) => total + s[key].events, 0),
+    duration: stats.reduce((total, s) => total + s[key].duration, 0),
+  });
+
+  for (const s of stats) {
+    lines.push(formatRow(s.projectName, s.inHours, s.outOfHours));
+  }
+  lines.push('');
+  lines.push(formatRow('Total', sum('inHours'), sum('outOfHours')));
+  return lines.join('\n');
+}
@@ -16,6 +16,18 @@ export const EventSchema = z
       .optional(),
     type: z.string().optional(),
     uuid: z.string().optional(),
+    // API request the event was logged for, shared by the lines of one assistant reply
+    requestId: z.string().optional(),
+    // Message this one follows; null at the start of a conversation
+    parentUuid: z.string().nullable().optional(),
+    // Branch checked out in cwd when the event was logged
+    gitBranch: z.string().optional(),
+    // Logged by a subagent working on its own rather than the main conversation
+    isSidechain: z.boolean().optional(),
+    // System records, e.g. "compact_boundary" where Claude Code compacted the context
+    subtype: z.string().optional(),
+    // The summary a compacted conversation continues from
+    isCompactSummary: z.boolean().optional(),
   })
   .passthrough(); // Allow additional properties
 
@@ -23,9 +35,15 @@ export type Event = z.infer<typeof EventSchema>;
 
 export interface Timeline {
   projectName: string;
+  // Stable across renames and exports, derived from the git remote or the path
+  projectId?: string;
+  // Git remote URL the project was named after
+  remote?: string;
   events: Event[];
   eventCount: number;
   activeDuration: number;
   startTime: Date;
   endTime: Date;
+  // The git repository has no remote, see RepositoryInfo
+  localOnly?: boolean;
 }
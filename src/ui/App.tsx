@@ -1,11 +1,23 @@
 import React, { useEffect, useState } from 'react';
 import { Box, Text } from 'ink';
 import { Timeline } from '../models/models';
-import { loadTimelines } from '../core/parser';
+import { loadTimelines, splitTimelines, LoadOptions, LoadStats } from '../core/parser';
+import { generateDemoTimelines, DemoOptions } from '../core/demo';
 import { ProjectTable } from './ProjectTable';
 import { ColorTheme } from './colorThemes';
+import { RenderMode } from './renderModes';
+import { ShadingOptions } from './utils/tableUtils';
+import { filterTimelinesByTags, ProjectTags } from '../utils/tags';
+import { matchesProjectName } from '../utils/projectNames';
+import { filterTimelinesBySessionMode, SessionMode } from '../utils/sessionModes';
+import { GroupBy } from '../utils/grouping';
+import { evaluateExitCode, ExitConditions } from '../utils/exitCodes';
 import { LoadingScreen } from './components/LoadingScreen';
 import { ProgressTracker, ProgressUpdate } from '../utils/progressTracker';
+import { calculateStartTime, TimeRange } from '../utils/timeRange';
+import { TimestampPolicy } from '../utils/timestampSanity';
+import { NestedRepositoryMode } from '../core/git';
+import { Annotation, loadAnnotations } from '../core/annotations';
 
 interface AppProps {
   days?: number;
@@ -15,8 +27,43 @@ interface AppProps {
   reverse?: boolean;
   allTime?: boolean;
   project?: string[];
+  width?: number;
+  demo?: DemoOptions;
+  strict?: boolean;
+  timestampPolicy?: TimestampPolicy;
+  renderMode?: RenderMode;
+  stackWeeks?: boolean;
+  gridlines?: boolean;
+  shading?: ShadingOptions;
+  colorByProject?: boolean;
+  accessible?: boolean;
+  heatNames?: boolean;
+  overview?: boolean;
+  lastPrompt?: boolean;
+  // Compare each project with the preceding window of the same length
+  trend?: boolean;
+  interactive?: boolean;
+  pinned?: string[];
+  ignore?: string[];
+  logDirs?: string[];
+  // ccstat store to read instead of the logs
+  store?: string;
+  gitFallback?: boolean;
+  nestedRepositories?: NestedRepositoryMode;
+  userEventsOnly?: boolean;
+  tags?: string[];
+  sessionMode?: SessionMode;
+  groupBy?: GroupBy;
+  projectTags?: ProjectTags;
+  // File of timeline markers, see `ccstat annotate`
+  annotationsPath?: string;
+  exitConditions?: ExitConditions;
+  onOpenInEditor?: (path: string) => void;
 }
 
+// Range used for demo data when --all-time is requested
+const DEMO_ALL_TIME_DAYS = 30;
+
 export const App: React.FC<AppProps> = ({
   days = 1,
   hours,
@@ -25,16 +72,56 @@ export const App: React.FC<AppProps> = ({
   reverse,
   allTime,
   project = [],
+  width,
+  demo,
+  strict,
+  timestampPolicy = 'warn',
+  renderMode,
+  stackWeeks,
+  gridlines,
+  shading,
+  colorByProject,
+  accessible,
+  heatNames,
+  overview,
+  lastPrompt,
+  trend,
+  interactive,
+  pinned,
+  ignore,
+  logDirs,
+  store,
+  gitFallback,
+  nestedRepositories,
+  userEventsOnly,
+  tags,
+  sessionMode,
+  groupBy,
+  projectTags,
+  annotationsPath,
+  exitConditions,
+  onOpenInEditor,
 }) => {
+  // Interactive mode can switch ranges without restarting
+  const [range, setRange] = useState<TimeRange>({ days, hours, allTime });
+  // The range the displayed timelines were loaded for
+  const [loadedRange, setLoadedRange] = useState<TimeRange>(range);
+  const [reloading, setReloading] = useState(false);
   const [timelines, setTimelines] = useState<Timeline[]>([]);
+  const [previousTimelines, setPreviousTimelines] = useState<Timeline[] | undefined>();
+  const [annotations, setAnnotations] = useState<Annotation[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
+  const [loadStats, setLoadStats] = useState<LoadStats>({ outOfRangeTimestamps: 0 });
   const [progress, setProgress] = useState<ProgressUpdate>({
     totalFiles: 0,
     processedFiles: 0,
   });
 
   useEffect(() => {
+    // Results of a load superseded by a newer range are dropped
+    let cancelled = false;
+
     async function loadData() {
       try {
         // Create progress tracker
@@ -43,28 +130,86 @@ export const App: React.FC<AppProps> = ({
         });
 
         let timelines: Timeline[];
+        let previousTimelines: Timeline[] | undefined;
+        const stats: LoadStats = { outOfRangeTimestamps: 0 };
+        const loadOptions: LoadOptions = {
+          strict,
+          timestampPolicy,
+          stats,
+          ignore,
+          logDirs,
+          store,
+          gitFallback,
+          nestedRepositories,
+          userEventsOnly,
+        };
 
-        if (allTime) {
+        if (range.allTime && demo) {
+          // Synthesize data in memory instead of reading real logs
+          const now = new Date();
+          timelines = generateDemoTimelines(calculateStartTime(now, DEMO_ALL_TIME_DAYS), now, demo);
+        } else if (range.allTime) {
           // Load all timelines without time filtering
-          timelines = await loadTimelines(undefined, undefined, progressTracker);
-        } else {
-          // Load timelines with time range filtering
+          timelines = await loadTimelines(undefined, undefined, progressTracker, loadOptions);
+        } else if (demo) {
           const now = new Date();
-          const startTime = new Date();
+          const startTime = calculateStartTime(now, range.days, range.hours);
+          timelines = generateDemoTimelines(startTime, now, demo);
 
-          if (hours) {
-            startTime.setHours(now.getHours() - hours);
-          } else {
-            startTime.setDate(now.getDate() - days);
+          // A seed of its own, so the displayed range is the same with or without the trend
+          if (trend) {
+            const previousStartTime = new Date(2 * startTime.getTime() - now.getTime());
+            const seed = demo.seed === undefined ? undefined : demo.seed + 1;
+            const previousDemo = { ...demo, seed };
+            previousTimelines = generateDemoTimelines(previousStartTime, startTime, previousDemo);
           }
+        } else if (trend) {
+          // Load timelines with time range filtering, plus the preceding window of the
+          // same length so each project can be compared against it
+          const now = new Date();
+          const startTime = calculateStartTime(now, range.days, range.hours);
+          const previousStartTime = new Date(2 * startTime.getTime() - now.getTime());
 
-          timelines = await loadTimelines(startTime, now, progressTracker);
+          const loaded = await loadTimelines(previousStartTime, now, progressTracker, loadOptions);
+          const { before, after } = splitTimelines(loaded, startTime);
+
+          timelines = after;
+          previousTimelines = before;
+        } else {
+          // Load timelines with time range filtering
+          const now = new Date();
+          const startTime = calculateStartTime(now, range.days, range.hours);
+          timelines = await loadTimelines(startTime, now, progressTracker, loadOptions);
         }
 
+        // Demo data is made up, so real markers would not line up with it
+        const loadedAnnotations =
+          annotationsPath && !demo ? await loadAnnotations(annotationsPath) : [];
+
+        if (cancelled) return;
+        setLoadedRange(range);
         setTimelines(timelines);
+        setPreviousTimelines(previousTimelines);
+        setAnnotations(loadedAnnotations);
+        setLoadStats(stats);
+
+        // Let scripts react to the displayed stats through the exit code
+        if (exitConditions) {
+          const selected =
+            project.length > 0
+              ? timelines.filter(t => matchesProjectName(project, t.projectName))
+              : timelines;
+          const tagged = filterTimelinesByTags(selected, tags ?? [], projectTags ?? {});
+          const filtered = sessionMode ? filterTimelinesBySessionMode(tagged, sessionMode) : tagged;
+          const exitCode = evaluateExitCode(filtered, exitConditions);
+          if (exitCode !== 0) {
+            process.exitCode = exitCode;
+          }
+        }
       } catch (err) {
         const errorMessage = err instanceof Error ? err.message : 'Unknown error occurred';
         setError(errorMessage);
+        process.exitCode = 1;
 
         // Reset progress on error
         setProgress({
@@ -72,12 +217,32 @@ export const App: React.FC<AppProps> = ({
           processedFiles: 0,
         });
       } finally {
-        setLoading(false);
+        if (!cancelled) {
+          setLoading(false);
+          setReloading(false);
+        }
       }
     }
 
     loadData();
-  }, [days, hours, allTime, project]);
+    return () => {
+      cancelled = true;
+    };
+  }, [
+    range,
+    project,
+    demo,
+    trend,
+    strict,
+    timestampPolicy,
+    ignore,
+    logDirs,
+    store,
+    gitFallback,
+    nestedRepositories,
+    userEventsOnly,
+    annotationsPath,
+  ]);
 
   if (loading) {
     return <LoadingScreen progress={progress} />;
@@ -87,18 +252,57 @@ export const App: React.FC<AppProps> = ({
     return <Text color="red">Error: {error}</Text>;
   }
 
+  const policyText =
+    timestampPolicy === 'warn' ? 'kept, use --bad-timestamps clamp|exclude' : timestampPolicy;
+
   return (
     <Box flexDirection="column">
       <ProjectTable
         timelines={timelines}
-        days={days}
-        hours={hours}
+        previousTimelines={previousTimelines}
+        days={loadedRange.days}
+        hours={loadedRange.hours}
         color={color}
         sort={sort}
         reverse={reverse}
-        allTime={allTime}
+        allTime={loadedRange.allTime}
         project={project}
+        width={width}
+        renderMode={renderMode}
+        stackWeeks={stackWeeks}
+        gridlines={gridlines}
+        shading={shading}
+        colorByProject={colorByProject}
+        accessible={accessible}
+        heatNames={heatNames}
+        overview={overview}
+        lastPrompt={lastPrompt}
+        interactive={interactive}
+        pinned={pinned}
+        logDirs={logDirs}
+        tags={tags}
+        sessionMode={sessionMode}
+        groupBy={groupBy}
+        projectTags={projectTags}
+        annotations={annotations}
+        onOpenInEditor={onOpenInEditor}
+        reloading={reloading ? range : undefined}
+        onRangeChange={newRange => {
+          setReloading(true);
+          setRange(newRange);
+        }}
       />
+      {loadStats.outOfRangeTimestamps > 0 && (
+        <Text color="yellow">
+          ⚠ {loadStats.outOfRangeTimestamps} events had out-of-range timestamps ({policyText})
+        </Text>
+      )}
+      {loadStats.compactedBefore && (
+        <Text color="yellow">
+          ⚠ Events before {loadStats.compactedBefore} are compacted into daily totals in the store
+          and not shown (ccstat trend still includes them)
+        </Text>
+      )}
     </Box>
   );
 };
@@ -0,0 +1,142 @@
+import React, { useState } from 'react';
+import { Box, Text, useApp, useInput } from 'ink';
+import { Config } from '../utils/config';
+import {
+  CONFIG_KEYS,
+  ConfigKey,
+  formatConfigValue,
+  getConfigValue,
+  parseConfigValue,
+  setConfigValue,
+  validateConfig,
+} from '../utils/configKeys';
+
+interface ConfigFormProps {
+  config: Config;
+  configPath: string;
+  onSave: (config: Config) => Promise<void>;
+}
+
+const KEY_WIDTH = Math.max(...CONFIG_KEYS.map(k => k.key.length));
+
+// Next value of a setting with a fixed set of values; after the last one it is unset again
+function cycleValue(configKey: ConfigKey, value: unknown): unknown {
+  const values: unknown[] = configKey.type === 'boolean' ? [true, false] : [...configKey.values!];
+  const index = values.indexOf(value);
+  return index === values.length - 1 ? undefined : values[index + 1];
+}
+
+function formatInput(value: unknown): string {
+  if (value === undefined) return '';
+  return Array.isArray(value) ? value.join(', ') : formatConfigValue(value);
+}
+
+// Form for the settings in CONFIG_KEYS: values are checked as they are entered, and the whole
+// file again before it is saved
+export const ConfigForm: React.FC<ConfigFormProps> = ({
+  config: initialConfig,
+  configPath,
+  onSave,
+}) => {
+  const { exit } = useApp();
+  const [config, setConfig] = useState(initialConfig);
+  const [selectedIndex, setSelectedIndex] = useState(0);
+  // Text being typed for the selected setting, null when not editing
+  const [input, setInput] = useState<string | null>(null);
+  const [status, setStatus] = useState<string | null>(null);
+  const [modified, setModified] = useState(false);
+
+  const selected = CONFIG_KEYS[selectedIndex];
+  const path = selected.key.split('.');
+
+  const update = (value: unknown) => {
+    setConfig(current => setConfigValue(current, path, value));
+    setModified(true);
+    setStatus(null);
+  };
+
+  useInput((character, key) => {
+    if (input !== null) {
+      if (key.escape) {
+        setInput(null);
+      } else if (key.return) {
+        try {
+          update(input.trim() === '' ? undefined : parseConfigValue(selected, input));
+          setInput(null);
+        } catch (error) {
+          setStatus(error instanceof Error ? error.message : String(error));
+        }
+      } else if (key.backspace || key.delete) {
+        setInput(text => text!.slice(0, -1));
+      } else if (character && !key.ctrl && !key.meta) {
+        setInput(text => text + character);
+      }
+      return;
+    }
+
+    if (key.upArrow || character === 'k') {
+      setSelectedIndex(index => Math.max(0, index - 1));
+    } else if (key.downArrow || character === 'j') {
+      setSelectedIndex(index => Math.min(CONFIG_KEYS.length - 1, index + 1));
+    } else if (key.return || character === ' ') {
+      if (selected.type === 'boolean' || selected.values) {
+        update(cycleValue(selected, getConfigValue(config, path)));
+      } else {
+        setInput(formatInput(getConfigValue(config, path)));
+      }
+    } else if (key.backspace || key.delete) {
+      update(undefined);
+    } else if (character === 's') {
+      const problem = validateConfig(config);
+      if (problem) {
+        setStatus(`Not saved: ${problem}`);
+        return;
+      }
+      onSave(config)
+        .then(() => exit())
+        .catch(error =>
+          setStatus(`Could not save: ${error instanceof Error ? error.message : error}`)
+        );
+    } else if (character === 'q' || key.escape) {
+      exit();
+    }
+  });
+
+  return (
+    <Box flexDirection="column">
+      <Text bold>
+        {configPath}
+        {modified ? ' (modified)' : ''}
+      </Text>
+      <Box flexDirection="column" marginTop={1}>
+        {CONFIG_KEYS.map((configKey, index) => {
+          const isSelected = index === selectedIndex;
+          const value = getConfigValue(config, configKey.key.split('.'));
+          return (
+            <Text key={configKey.key} inverse={isSelected && input === null}>
+              {isSelected ? '›' : ' '} {configKey.key.padEnd(KEY_WIDTH)}{' '}
+              {isSelected && input !== null ? (
+                <Text color="yellow">{input}█</Text>
+              ) : value === undefined ? (
+                <Text dimColor>(default)</Text>
+              ) : (
+                formatInput(value)
+              )}
+            </Text>
+          );
+        })}
+      </Box>
+      <Box marginTop={1} flexDirection="column">
+        <Text dimColor>
+          {selected.description}
+          {selected.values ? ` (${selected.values.join(', ')})` : ''}
+          {selected.type === 'list' ? ' (comma-separated)' : ''}
+        </Text>
+        {status && <Text color="red">{status}</Text>}
+        <Text dimColor>
+          ↑↓ select · enter edit or cycle · backspace reset to default · s save · q quit
+        </Text>
+      </Box>
+    </Box>
+  );
+};
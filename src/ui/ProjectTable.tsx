@@ -1,17 +1,85 @@
-import React, { useMemo } from 'react';
-import { Box, Text, useStdout } from 'ink';
+import React, { useEffect, useMemo, useRef, useState } from 'react';
+import { Box, DOMElement, measureElement, Text, useApp, useInput } from 'ink';
 import { Timeline } from '../models/models';
-import { ColorTheme, getColorScheme, getBorderColor } from './colorThemes';
-import { calculateProjectWidth } from './utils/tableUtils';
+import {
+  ColorTheme,
+  getColorScheme,
+  getBorderColor,
+  getProjectColorScheme,
+  getRecencyColor,
+} from './colorThemes';
+import { RenderMode } from './renderModes';
+import {
+  calculateProjectWidth,
+  calculateRowsEndTime,
+  calculateShadedCells,
+  calculateWeekRowStarts,
+  createOverviewTimeline,
+  getBucketIndex,
+  layoutTimeAxis,
+  layoutWeekdayAxis,
+  DAY_MS,
+  OVERVIEW_ROW_NAME,
+  ShadingOptions,
+  WEEK_MS,
+} from './utils/tableUtils';
+import {
+  countSessions,
+  DAYS_COLUMN,
+  EVENTS_COLUMN,
+  SESSIONS_COLUMN,
+  DURATION_COLUMN,
+  createShareColumn,
+  createTrendColumn,
+  getShareMetric,
+  LAST_PROMPT_COLUMN,
+} from './utils/columns';
+import { useTerminalWidth } from './utils/useTerminalWidth';
+import { useMouse } from './utils/useMouse';
+import { TableView, ViewExportFormat, writeViewExport } from './utils/viewExport';
 import { TitleRow } from './components/TitleRow';
 import { HeaderRow } from './components/HeaderRow';
 import { TableTimeAxis } from './components/TableTimeAxis';
 import { ProjectRow } from './components/ProjectRow';
 import { SummaryStatistics } from './components/SummaryStatistics';
-import { sortTimelines, createSortOptions } from '../utils/sort';
+import { AnnotationList } from './components/AnnotationList';
+import { AccessibleReport } from './components/AccessibleReport';
+import { sortTimelines, createSortOptions, pinTimelines } from '../utils/sort';
+import { filterTimelinesByTags, ProjectTags } from '../utils/tags';
+import { GroupBy, groupTimelines } from '../utils/grouping';
+import { filterTimelinesBySessionMode, SessionMode } from '../utils/sessionModes';
+import { calculateStartTime, formatTimeRange, stepTimeRange, TimeRange } from '../utils/timeRange';
+import { discoverLogFiles } from '../core/parser';
+import { Annotation, filterAnnotations } from '../core/annotations';
+import { getDurationModel } from '../core/duration';
+import {
+  copyToClipboard,
+  findSessionLogFiles,
+  getDirectoryBreakdown,
+  getLatestSessionId,
+  getProjectDirectory,
+  getResumeCommand,
+  openInFileManager,
+} from '../utils/projectActions';
+import { fuzzyMatch } from '../utils/fuzzy';
+import { matchesProjectName } from '../utils/projectNames';
+
+const RANGE_KEYS: Record<string, TimeRange> = {
+  '1': { days: 1 },
+  '7': { days: 7 },
+  '3': { days: 30 },
+};
+
+const VIEW_EXPORT_KEYS: Record<string, ViewExportFormat> = {
+  t: 'text',
+  m: 'markdown',
+  j: 'json',
+};
 
 interface ProjectTableProps {
   timelines: Timeline[];
+  // Same-length window preceding the displayed range, for trend markers
+  previousTimelines?: Timeline[];
   days?: number;
   hours?: number;
   color: ColorTheme;
@@ -19,10 +87,42 @@ interface ProjectTableProps {
   reverse?: boolean;
   allTime?: boolean;
   project?: string[];
+  width?: number;
+  renderMode?: RenderMode;
+  stackWeeks?: boolean;
+  gridlines?: boolean;
+  shading?: ShadingOptions;
+  colorByProject?: boolean;
+  // Describe each project in sentences instead of drawing the table, for screen readers
+  accessible?: boolean;
+  // Tint project names by how recently they were active
+  heatNames?: boolean;
+  overview?: boolean;
+  lastPrompt?: boolean;
+  interactive?: boolean;
+  // Projects kept at the top regardless of sort order
+  pinned?: string[];
+  // Claude projects directories, for locating session logs
+  logDirs?: string[];
+  // Only show projects with any of these tags
+  tags?: string[];
+  // Only count sessions of this mode
+  sessionMode?: SessionMode;
+  groupBy?: GroupBy;
+  projectTags?: ProjectTags;
+  // Markers drawn on the time axis and listed below the table
+  annotations?: Annotation[];
+  // Called before exiting when the user asks to open a project in $EDITOR
+  onOpenInEditor?: (path: string) => void;
+  // Switch the analyzed range from interactive mode; the table stays up while it reloads
+  onRangeChange?: (range: TimeRange) => void;
+  // The range being loaded in the background
+  reloading?: TimeRange;
 }
 
 export const ProjectTable: React.FC<ProjectTableProps> = ({
   timelines,
+  previousTimelines,
   days,
   hours,
   color,
@@ -30,36 +130,191 @@ export const ProjectTable: React.FC<ProjectTableProps> = ({
   reverse,
   allTime,
   project = [],
+  width,
+  renderMode,
+  stackWeeks,
+  gridlines,
+  shading,
+  colorByProject,
+  accessible,
+  heatNames,
+  overview,
+  lastPrompt,
+  interactive,
+  pinned = [],
+  logDirs,
+  tags = [],
+  sessionMode,
+  groupBy = 'project',
+  projectTags = {},
+  annotations = [],
+  onOpenInEditor,
+  onRangeChange,
+  reloading,
 }) => {
-  const { stdout } = useStdout();
-  const terminalWidth = stdout?.columns || 80;
+  const detectedWidth = useTerminalWidth();
+  const terminalWidth = width || detectedWidth;
 
   const activityColors = useMemo(() => getColorScheme(color), [color]);
   const borderColor = useMemo(() => getBorderColor(color), [color]);
 
-  // Apply project filtering and sorting
-  const filteredAndSortedTimelines = useMemo(() => {
-    // Filter by project names if specified
-    let filtered = timelines;
+  // Filter by project names, tags and session mode, then regroup rows, e.g. into tags or worktrees
+  const selectTimelines = (source: Timeline[]): Timeline[] => {
+    let filtered = source;
     if (project.length > 0) {
-      filtered = timelines.filter(timeline => project.includes(timeline.projectName));
+      filtered = filtered.filter(timeline => matchesProjectName(project, timeline.projectName));
+    }
+    filtered = filterTimelinesByTags(filtered, tags, projectTags);
+    if (sessionMode) {
+      filtered = filterTimelinesBySessionMode(filtered, sessionMode);
     }
 
-    // Apply sorting
+    return groupTimelines(filtered, groupBy, projectTags);
+  };
+
+  // Live filter typed after `/` in interactive mode
+  const [search, setSearch] = useState('');
+  const [searching, setSearching] = useState(false);
+
+  // Apply filtering and sorting
+  const filteredAndSortedTimelines = useMemo(() => {
     const sortOptions = createSortOptions(sort, reverse);
-    return sortTimelines(filtered, sortOptions);
-  }, [timelines, project, sort, reverse]);
+    const sorted = pinTimelines(sortTimelines(selectTimelines(timelines), sortOptions), pinned);
+    return search ? sorted.filter(timeline => fuzzyMatch(search, timeline.projectName)) : sorted;
+  }, [timelines, project, sort, reverse, pinned, tags, sessionMode, groupBy, projectTags, search]);
 
-  if (filteredAndSortedTimelines.length === 0) {
-    const message =
-      project.length > 0
-        ? `🔍 No Claude sessions found for project(s): ${project.join(', ')}`
-        : '🔍 No Claude sessions found in the specified time range';
-    return <Text>{message}</Text>;
-  }
+  const { exit } = useApp();
+  const [selectedIndex, setSelectedIndex] = useState(0);
+  const [status, setStatus] = useState<string | null>(null);
+  // Waiting for the format key after `x`
+  const [exportPending, setExportPending] = useState(false);
+  const view = useRef<TableView | null>(null);
+  // Lines above the first project row inside the border and lines per row, to map clicks to rows
+  const headerRef = useRef<DOMElement>(null);
+  const headerHeight = useRef(0);
+  const rowHeight = useRef(1);
 
-  const totalEvents = filteredAndSortedTimelines.reduce((sum, t) => sum + t.eventCount, 0);
-  const totalDuration = filteredAndSortedTimelines.reduce((sum, t) => sum + t.activeDuration, 0);
+  useEffect(() => {
+    if (headerRef.current) headerHeight.current = measureElement(headerRef.current).height;
+  });
+
+  // Interactive mode renders on the alternate screen, so the table starts at the first line
+  useMouse(action => {
+    const lastIndex = filteredAndSortedTimelines.length - 1;
+    if (action.type === 'wheel') {
+      const step = action.direction === 'up' ? -1 : 1;
+      setSelectedIndex(index => Math.min(lastIndex, Math.max(0, index + step)));
+      return;
+    }
+
+    // Skip the top border, then find the row under the pointer
+    const index = Math.floor((action.y - 2 - headerHeight.current) / rowHeight.current);
+    if (index >= 0 && index <= lastIndex) setSelectedIndex(index);
+  }, interactive === true);
+
+  const reportError = (action: string) => (error: unknown) =>
+    setStatus(`Could not ${action}: ${error instanceof Error ? error.message : error}`);
+
+  useInput(
+    (input, key) => {
+      const selected = filteredAndSortedTimelines[selectedIndex];
+
+      if (searching) {
+        // Escape sequences such as mouse reports are not part of the query
+        if (key.escape) {
+          setSearch('');
+          setSearching(false);
+        } else if (key.return) {
+          setSearching(false);
+        } else if (key.backspace || key.delete) {
+          setSearch(query => query.slice(0, -1));
+        } else if (input && !key.ctrl && !key.meta) {
+          setSearch(query => query + input);
+        }
+        setSelectedIndex(0);
+        return;
+      }
+
+      if (exportPending) {
+        setExportPending(false);
+        const viewFormat = VIEW_EXPORT_KEYS[input];
+        if (!viewFormat || !view.current) {
+          setStatus(null);
+          return;
+        }
+        writeViewExport(view.current, viewFormat, process.cwd())
+          .then(path => setStatus(`Saved ${path}`))
+          .catch(reportError('export the view'));
+        return;
+      }
+
+      if (key.upArrow || input === 'k') {
+        setSelectedIndex(index => Math.max(0, index - 1));
+      } else if (key.downArrow || input === 'j') {
+        setSelectedIndex(index => Math.min(filteredAndSortedTimelines.length - 1, index + 1));
+      } else if (key.escape && search) {
+        setSearch('');
+      } else if (input === 'q' || key.escape) {
+        exit();
+      } else if (selected && ['o', 'e', 'c'].includes(input)) {
+        const directory = getProjectDirectory(selected);
+        if (!directory) {
+          setStatus(`No directory recorded for ${selected.projectName}`);
+        } else if (input === 'o') {
+          openInFileManager(directory)
+            .then(() => setStatus(`Opened ${directory}`))
+            .catch(reportError('open'));
+        } else if (input === 'e') {
+          onOpenInEditor?.(directory);
+          exit();
+        } else {
+          copyToClipboard(directory)
+            .then(() => setStatus(`Copied ${directory}`))
+            .catch(reportError('copy'));
+        }
+      } else if (onRangeChange && (RANGE_KEYS[input] || input === '[' || input === ']')) {
+        const current = { days, hours, allTime };
+        onRangeChange(RANGE_KEYS[input] ?? stepTimeRange(current, input === ']' ? 1 : -1));
+        setSelectedIndex(0);
+      } else if (input === '/') {
+        setSearching(true);
+      } else if (input === 'x') {
+        setExportPending(true);
+        setStatus('Export view as: t text · m markdown · j JSON (any other key cancels)');
+      } else if (selected && input === 'd') {
+        setStatus(
+          getDirectoryBreakdown(selected)
+            .map(({ path, events }) => `${`${events}`.padStart(6)}  ${path ?? '(no directory)'}`)
+            .join('\n')
+        );
+      } else if (selected && input === 'r') {
+        const sessionId = getLatestSessionId(selected);
+        if (!sessionId) {
+          setStatus(`No session recorded for ${selected.projectName}`);
+          return;
+        }
+        const command = getResumeCommand(sessionId, getProjectDirectory(selected));
+        copyToClipboard(command)
+          .then(() => setStatus(`Copied: ${command}`))
+          .catch(() => setStatus(command));
+      } else if (selected && input === 'f') {
+        const sessionId = getLatestSessionId(selected);
+        if (!sessionId) {
+          setStatus(`No session recorded for ${selected.projectName}`);
+          return;
+        }
+        discoverLogFiles(logDirs)
+          .then(files => {
+            const logFiles = findSessionLogFiles(sessionId, files.keys());
+            setStatus(
+              logFiles.length > 0 ? logFiles.join('\n') : `No log file found for ${sessionId}`
+            );
+          })
+          .catch(reportError('find the session log'));
+      }
+    },
+    { isActive: interactive === true }
+  );
 
   const { startTime, endTime, timeRangeText } = useMemo(() => {
     if (allTime) {
@@ -84,13 +339,7 @@ export const ProjectTable: React.FC<ProjectTableProps> = ({
     } else {
       // Use provided time range
       const endTime = new Date();
-      const startTime = new Date();
-
-      if (hours) {
-        startTime.setHours(endTime.getHours() - hours);
-      } else {
-        startTime.setDate(endTime.getDate() - (days || 1));
-      }
+      const startTime = calculateStartTime(endTime, days, hours);
 
       return {
         startTime,
@@ -100,41 +349,164 @@ export const ProjectTable: React.FC<ProjectTableProps> = ({
     }
   }, [allTime, filteredAndSortedTimelines, hours, days]);
 
+  // All hooks must run above this point, since a filter or range change can empty the table
+  if (filteredAndSortedTimelines.length === 0) {
+    const message = search
+      ? `🔍 No projects match /${search} (Esc to clear)`
+      : project.length > 0
+        ? `🔍 No Claude sessions found for project(s): ${project.join(', ')}`
+        : '🔍 No Claude sessions found in the specified time range';
+    return <Text>{message}</Text>;
+  }
+
+  const totalEvents = filteredAndSortedTimelines.reduce((sum, t) => sum + t.eventCount, 0);
+  const totalDuration = filteredAndSortedTimelines.reduce((sum, t) => sum + t.activeDuration, 0);
+
+  if (accessible) {
+    const rangeAnnotations = filterAnnotations(annotations, startTime, endTime);
+    return (
+      <Box flexDirection="column">
+        <AccessibleReport
+          timelines={filteredAndSortedTimelines}
+          startTime={startTime}
+          endTime={endTime}
+          timeRangeText={timeRangeText}
+          idleMinutes={getDurationModel().idleMinutes}
+          multiDay={endTime.getTime() - startTime.getTime() > DAY_MS}
+        />
+        {rangeAnnotations.length > 0 && <AnnotationList annotations={rangeAnnotations} />}
+        <SummaryStatistics
+          projectCount={filteredAndSortedTimelines.length}
+          totalEvents={totalEvents}
+          totalSessions={countSessions(filteredAndSortedTimelines)}
+          totalDuration={totalDuration}
+        />
+      </Box>
+    );
+  }
+
+  // Stack long ranges into one row per week instead of squeezing them into one line
+  const weekRowStarts =
+    stackWeeks && endTime.getTime() - startTime.getTime() > WEEK_MS
+      ? calculateWeekRowStarts(startTime, endTime)
+      : undefined;
+
+  const shareMetric = getShareMetric(sort);
+  const columns = [
+    EVENTS_COLUMN,
+    SESSIONS_COLUMN,
+    DURATION_COLUMN,
+    createShareColumn(filteredAndSortedTimelines, shareMetric),
+  ];
+  if (endTime.getTime() - startTime.getTime() > DAY_MS) {
+    columns.splice(2, 0, DAYS_COLUMN);
+  }
+  if (previousTimelines) {
+    columns.push(
+      createTrendColumn(selectTimelines(previousTimelines), shareMetric, OVERVIEW_ROW_NAME)
+    );
+  }
+  if (lastPrompt) {
+    columns.push(LAST_PROMPT_COLUMN);
+  }
+
   // Calculate responsive column widths
   const projectWidth = calculateProjectWidth(filteredAndSortedTimelines);
-  const eventsWidth = 8;
-  const durationWidth = 10;
-  const timelineWidth = Math.max(
-    25,
-    terminalWidth - projectWidth - eventsWidth - durationWidth - 12
+  const columnsWidth = columns.reduce((sum, column) => sum + column.width, 0);
+  const availableWidth = Math.max(25, terminalWidth - projectWidth - columnsWidth - 12);
+  // Emoji are two columns wide, so rows need an even number of columns to hold whole emoji
+  const timelineWidth =
+    renderMode === 'emoji' && availableWidth % 2 === 1 ? availableWidth - 1 : availableWidth;
+
+  // Align gridlines with the ticks of whichever axis is displayed
+  const axisWidth = timelineWidth - 2;
+  const axisLayout = weekRowStarts
+    ? layoutWeekdayAxis(startTime, axisWidth)
+    : layoutTimeAxis(startTime, endTime, axisWidth);
+  const gridTicks = gridlines ? axisLayout.ticks : undefined;
+
+  // Locate the current time when the displayed range reaches it;
+  // ranges other than --all-time end at the moment they were computed
+  const now = allTime ? new Date() : endTime;
+  const overviewTimeline = overview ? createOverviewTimeline(filteredAndSortedTimelines) : null;
+  const rowCount = weekRowStarts?.length ?? 1;
+  rowHeight.current = rowCount;
+  view.current = { startTime, endTime, timelines: filteredAndSortedTimelines };
+  const rowsEndTime = calculateRowsEndTime(endTime, weekRowStarts);
+  const nowIndex = getBucketIndex(
+    now.getTime(),
+    startTime.getTime(),
+    rowsEndTime.getTime(),
+    axisWidth * rowCount
   );
 
+  const visibleAnnotations = filterAnnotations(annotations, startTime, rowsEndTime);
+  const annotationPositions = visibleAnnotations.map(
+    annotation =>
+      getBucketIndex(
+        new Date(annotation.timestamp).getTime(),
+        startTime.getTime(),
+        rowsEndTime.getTime(),
+        axisWidth * rowCount
+      ) % axisWidth
+  );
+
+  // Shading depends only on time, so compute it once for every row
+  const shadedCells =
+    shading && (shading.weekends || shading.offHours)
+      ? calculateShadedCells(startTime, rowsEndTime, axisWidth * rowCount, shading)
+      : undefined;
+
   return (
     <Box flexDirection="column">
       <Box borderStyle="round" flexDirection="column" borderColor={borderColor} paddingX={1}>
-        <TitleRow
-          startTime={startTime}
-          endTime={endTime}
-          timeRangeText={timeRangeText}
-          projectCount={filteredAndSortedTimelines.length}
-        />
+        <Box flexDirection="column" ref={headerRef}>
+          <TitleRow
+            startTime={startTime}
+            endTime={endTime}
+            timeRangeText={timeRangeText}
+            projectCount={filteredAndSortedTimelines.length}
+          />
 
-        <HeaderRow
-          projectWidth={projectWidth}
-          timelineWidth={timelineWidth}
-          eventsWidth={eventsWidth}
-          durationWidth={durationWidth}
-          activityColors={activityColors}
-        />
+          <HeaderRow
+            projectWidth={projectWidth}
+            timelineWidth={timelineWidth}
+            columns={columns}
+            activityColors={activityColors}
+            emoji={renderMode === 'emoji'}
+          />
 
-        <TableTimeAxis
-          startTime={startTime}
-          endTime={endTime}
-          projectWidth={projectWidth}
-          timelineWidth={timelineWidth}
-          eventsWidth={eventsWidth}
-          durationWidth={durationWidth}
-        />
+          <TableTimeAxis
+            startTime={startTime}
+            endTime={endTime}
+            projectWidth={projectWidth}
+            timelineWidth={timelineWidth}
+            columns={columns}
+            weekly={weekRowStarts !== undefined}
+            nowPosition={nowIndex >= 0 ? nowIndex % axisWidth : undefined}
+            annotationPositions={annotationPositions}
+            shaded={shadedCells?.slice(0, axisWidth)}
+          />
+
+          {/* Aggregate row showing the overall activity rhythm */}
+          {overviewTimeline && (
+            <ProjectRow
+              timeline={overviewTimeline}
+              startTime={startTime}
+              endTime={endTime}
+              projectWidth={projectWidth}
+              timelineWidth={timelineWidth}
+              columns={columns}
+              activityColors={activityColors}
+              renderMode={renderMode}
+              weekRowStarts={weekRowStarts}
+              gridTicks={gridTicks}
+              nowIndex={nowIndex}
+              shadedCells={shadedCells}
+              bold
+            />
+          )}
+        </Box>
 
         {/* Data rows */}
         {filteredAndSortedTimelines.map((timeline, index) => (
@@ -145,16 +517,52 @@ export const ProjectTable: React.FC<ProjectTableProps> = ({
             endTime={endTime}
             projectWidth={projectWidth}
             timelineWidth={timelineWidth}
-            eventsWidth={eventsWidth}
-            durationWidth={durationWidth}
-            activityColors={activityColors}
+            columns={columns}
+            activityColors={
+              colorByProject
+                ? getProjectColorScheme(timeline.projectId ?? timeline.projectName)
+                : activityColors
+            }
+            nameColor={
+              heatNames ? getRecencyColor(timeline.endTime, startTime, endTime) : undefined
+            }
+            renderMode={renderMode}
+            weekRowStarts={weekRowStarts}
+            gridTicks={gridTicks}
+            nowIndex={nowIndex}
+            shadedCells={shadedCells}
+            selected={interactive && index === selectedIndex}
           />
         ))}
       </Box>
 
+      {interactive && (
+        <Box flexDirection="column">
+          <Text dimColor>
+            ↑/↓/click select · o open · e editor · c copy path · d directories · r resume · f log
+            file · x export view · / filter · 1/7/3 or [/] range · q quit
+          </Text>
+          {(searching || search) && (
+            <Text>
+              /{search}
+              {searching ? (
+                <Text inverse> </Text>
+              ) : (
+                <Text dimColor> (/ to edit, Esc to clear)</Text>
+              )}
+            </Text>
+          )}
+          {reloading && <Text dimColor>Loading {formatTimeRange(reloading)}…</Text>}
+          {status && <Text>{status}</Text>}
+        </Box>
+      )}
+
+      {visibleAnnotations.length > 0 && <AnnotationList annotations={visibleAnnotations} />}
+
       <SummaryStatistics
         projectCount={filteredAndSortedTimelines.length}
         totalEvents={totalEvents}
+        totalSessions={countSessions(filteredAndSortedTimelines)}
         totalDuration={totalDuration}
       />
     </Box>
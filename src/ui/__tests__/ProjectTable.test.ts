@@ -0,0 +1,161 @@
+import { EventEmitter } from 'events';
+import { mkdirSync, readFileSync, writeFileSync } from 'fs';
+import { render } from 'ink';
+import { join } from 'path';
+import React from 'react';
+import { Timeline } from '../../models/models';
+import { ProjectTable } from '../ProjectTable';
+
+// Render in a fixed timezone so golden files are reproducible
+process.env.TZ = 'UTC';
+
+const GOLDEN_DIR = join(__dirname, '__golden__');
+
+// Compare output against a golden file (run with UPDATE_GOLDEN=1 to regenerate)
+function expectGolden(name: string, actual: string): void {
+  const goldenPath = join(GOLDEN_DIR, `${name}.golden`);
+
+  if (process.env.UPDATE_GOLDEN) {
+    mkdirSync(GOLDEN_DIR, { recursive: true });
+    writeFileSync(goldenPath, `${actual}\n`);
+    return;
+  }
+
+  expect(`${actual}\n`).toBe(readFileSync(goldenPath, 'utf-8'));
+}
+
+// Colors depend on the terminal the tests run in, so goldens hold the text only
+const stripColors = (frame: string) => frame.replace(/\u001B\[[0-9;]*m/g, '');
+
+// Minimal streams in place of the terminal, as in ink-testing-library
+class TestStdout extends EventEmitter {
+  columns: number;
+  frames: string[] = [];
+
+  constructor(columns: number) {
+    super();
+    this.columns = columns;
+  }
+
+  write = (frame: string) => {
+    this.frames.push(frame);
+    return true;
+  };
+
+  lastFrame(): string | undefined {
+    return this.frames[this.frames.length - 1];
+  }
+}
+
+class TestStdin extends EventEmitter {
+  isTTY = true;
+  private data: string | null = null;
+
+  write(data: string): void {
+    this.data = data;
+    this.emit('readable');
+    this.emit('data', data);
+  }
+
+  read = () => {
+    const data = this.data;
+    this.data = null;
+    return data;
+  };
+
+  setEncoding(): void {}
+  setRawMode(): void {}
+  ref(): void {}
+  unref(): void {}
+  resume(): void {}
+  pause(): void {}
+}
+
+// Let React commit the previous update and resubscribe input handlers
+const tick = () => new Promise(resolve => setTimeout(resolve, 50));
+
+const createTimeline = (projectName: string, timestamps: string[]): Timeline => ({
+  projectName,
+  events: timestamps.map(timestamp => ({ timestamp, sessionId: `${projectName}-1` })),
+  eventCount: timestamps.length,
+  activeDuration: 30,
+  startTime: new Date(timestamps[0]),
+  endTime: new Date(timestamps[timestamps.length - 1]),
+});
+
+const timelines = [
+  createTimeline('webapp', ['2025-01-01T09:00:00Z', '2025-01-01T09:30:00Z']),
+  createTimeline('api', ['2025-01-01T13:00:00Z', '2025-01-01T13:30:00Z']),
+];
+
+function renderTable(props: Partial<React.ComponentProps<typeof ProjectTable>> = {}) {
+  const stdout = new TestStdout(props.width ?? 100);
+  const stdin = new TestStdin();
+  const element = (extra: Partial<React.ComponentProps<typeof ProjectTable>>) =>
+    React.createElement(ProjectTable, {
+      timelines,
+      color: 'forest',
+      allTime: true,
+      width: 100,
+      ...props,
+      ...extra,
+    });
+
+  const instance = render(element({}), {
+    stdout: stdout as unknown as NodeJS.WriteStream,
+    stderr: new TestStdout(100) as unknown as NodeJS.WriteStream,
+    stdin: stdin as unknown as NodeJS.ReadStream,
+    debug: true,
+    exitOnCtrlC: false,
+    patchConsole: false,
+  });
+  return {
+    stdout,
+    stdin,
+    rerender: (extra: Partial<React.ComponentProps<typeof ProjectTable>>) =>
+      instance.rerender(element(extra)),
+    unmount: instance.unmount,
+  };
+}
+
+describe('ProjectTable', () => {
+  it('should show a message when the filter matches no project', async () => {
+    const table = renderTable({ interactive: true });
+    try {
+      await tick();
+      expect(table.stdout.frames.join('')).toContain('webapp');
+
+      table.stdin.write('/');
+      await tick();
+      table.stdin.write('zzz');
+      await tick();
+
+      expect(table.stdout.lastFrame()).toContain('No projects match /zzz (Esc to clear)');
+    } finally {
+      table.unmount();
+    }
+  });
+
+  it('should show a message when a new range has no sessions', async () => {
+    const table = renderTable();
+    try {
+      await tick();
+      table.rerender({ timelines: [] });
+      await tick();
+
+      expect(table.stdout.lastFrame()).toContain('No Claude sessions found');
+    } finally {
+      table.unmount();
+    }
+  });
+
+  it.each([60, 100, 140])('should render the table at a width of %i columns', async width => {
+    const table = renderTable({ width });
+    try {
+      await tick();
+      expectGolden(`project-table-${width}`, stripColors(table.stdout.lastFrame() ?? ''));
+    } finally {
+      table.unmount();
+    }
+  });
+});
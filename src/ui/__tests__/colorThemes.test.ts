@@ -0,0 +1,45 @@
+import { hslToHex, getProjectColorScheme, getProjectHue, getRecencyColor } from '../colorThemes';
+
+describe('color themes', () => {
+  describe('hslToHex', () => {
+    it('should convert primary hues', () => {
+      expect(hslToHex(0, 100, 50)).toBe('#ff0000');
+      expect(hslToHex(120, 100, 50)).toBe('#00ff00');
+      expect(hslToHex(240, 100, 50)).toBe('#0000ff');
+    });
+
+    it('should convert greys', () => {
+      expect(hslToHex(0, 0, 100)).toBe('#ffffff');
+      expect(hslToHex(0, 0, 0)).toBe('#000000');
+    });
+  });
+
+  describe('getProjectColorScheme', () => {
+    it('should provide a five-step intensity ramp like the themes', () => {
+      expect(getProjectColorScheme('webapp')).toHaveLength(5);
+    });
+
+    it('should give a project the same hue every time', () => {
+      expect(getProjectHue('webapp')).toBe(getProjectHue('webapp'));
+      expect(getProjectHue('webapp')).not.toBe(getProjectHue('api'));
+      expect(getProjectHue('webapp')).toBeGreaterThanOrEqual(0);
+      expect(getProjectHue('webapp')).toBeLessThan(360);
+    });
+  });
+
+  describe('getRecencyColor', () => {
+    const start = new Date(2025, 5, 12, 0, 0);
+    const end = new Date(2025, 5, 13, 0, 0);
+
+    it('should fade from white for recent activity to dim grey at the start of the range', () => {
+      expect(getRecencyColor(end, start, end)).toBe('#ffffff');
+      expect(getRecencyColor(new Date(2025, 5, 12, 12, 0), start, end)).toBe('#b3b3b3');
+      expect(getRecencyColor(start, start, end)).toBe('#666666');
+    });
+
+    it('should clamp activity outside the range', () => {
+      expect(getRecencyColor(new Date(2025, 5, 11), start, end)).toBe('#666666');
+      expect(getRecencyColor(end, end, end)).toBe('#ffffff');
+    });
+  });
+});
@@ -1,4 +1,5 @@
 import chalk from 'chalk';
+import { createHash } from 'crypto';
 
 export const COLOR_THEME_VALUES = ['forest', 'ocean', 'honey', 'sunset', 'violet'] as const;
 
@@ -14,14 +15,62 @@ export type ColorScheme = (string | ((text: string) => string))[];
 const createHexGradient = (hexColors: string[]): ColorScheme =>
   hexColors.map(color => (text: string) => chalk.hex(color)(text));
 
-export const COLOR_THEMES: Record<ColorTheme, ColorScheme> = {
-  forest: createHexGradient(['#edf8fb', '#b2e2e2', '#66c2a4', '#2ca25f', '#006d2c']),
-  ocean: createHexGradient(['#f1eef6', '#bdc9e1', '#74a9cf', '#2b8cbe', '#045a8d']),
-  honey: createHexGradient(['#FFF9CC', '#FFF176', '#FFEB3B', '#FBC02D', '#F57F17']),
-  sunset: createHexGradient(['#fef0d9', '#fdcc8a', '#fc8d59', '#e34a33', '#b30000']),
-  violet: createHexGradient(['#edf8fb', '#b3cde3', '#8c96c6', '#8856a7', '#810f7c']),
+// Hex colors per density level 0-4, also used for raster images
+export const THEME_HEX_COLORS: Record<ColorTheme, string[]> = {
+  forest: ['#edf8fb', '#b2e2e2', '#66c2a4', '#2ca25f', '#006d2c'],
+  ocean: ['#f1eef6', '#bdc9e1', '#74a9cf', '#2b8cbe', '#045a8d'],
+  honey: ['#FFF9CC', '#FFF176', '#FFEB3B', '#FBC02D', '#F57F17'],
+  sunset: ['#fef0d9', '#fdcc8a', '#fc8d59', '#e34a33', '#b30000'],
+  violet: ['#edf8fb', '#b3cde3', '#8c96c6', '#8856a7', '#810f7c'],
 };
 
+export const COLOR_THEMES = Object.fromEntries(
+  COLOR_THEME_VALUES.map(theme => [theme, createHexGradient(THEME_HEX_COLORS[theme])])
+) as Record<ColorTheme, ColorScheme>;
+
+// Convert HSL (hue in degrees, saturation/lightness in percent) to a hex color
+export function hslToHex(hue: number, saturation: number, lightness: number): string {
+  const s = saturation / 100;
+  const l = lightness / 100;
+  const a = s * Math.min(l, 1 - l);
+  const channel = (n: number) => {
+    const k = (n + hue / 30) % 12;
+    const value = l - a * Math.max(-1, Math.min(k - 3, 9 - k, 1));
+    return Math.round(value * 255)
+      .toString(16)
+      .padStart(2, '0');
+  };
+  return `#${channel(0)}${channel(8)}${channel(4)}`;
+}
+
+// Hue from a hash of the project, so it keeps its color whatever the sort order, filters or range
+export function getProjectHue(key: string): number {
+  return createHash('sha256').update(key).digest().readUInt32BE(0) % 360;
+}
+
+// Distinct hue per project, with the same light-to-dark intensity ramp as the themes. The key is
+// the project id where there is one, so renamed projects keep their color too.
+export function getProjectColorScheme(key: string): ColorScheme {
+  const hue = getProjectHue(key);
+  return createHexGradient([90, 75, 60, 45, 32].map(lightness => hslToHex(hue, 70, lightness)));
+}
+
+// Lightness of the name of a project last active at the very start of the range
+const RECENCY_MIN_LIGHTNESS = 40;
+
+// Name color for --heat-names: white for activity at the end of the range, fading to dim grey
+// the longer ago a project was last active
+export function getRecencyColor(lastActivity: Date, startTime: Date, endTime: Date): string {
+  const span = endTime.getTime() - startTime.getTime();
+  const recency = span > 0 ? (lastActivity.getTime() - startTime.getTime()) / span : 1;
+  const lightness =
+    RECENCY_MIN_LIGHTNESS + Math.min(Math.max(recency, 0), 1) * (100 - RECENCY_MIN_LIGHTNESS);
+  return hslToHex(0, 0, Math.round(lightness));
+}
+
+// Background for weekend and off-hours columns, subtle on both dark and light terminals
+export const SHADE_BACKGROUND_COLOR = '#303030';
+
 export function getColorScheme(theme: ColorTheme): ColorScheme {
   return COLOR_THEMES[theme];
 }
@@ -0,0 +1,38 @@
+import React from 'react';
+import { Box, Text } from 'ink';
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { describeTimeline } from '../utils/accessible';
+
+interface AccessibleReportProps {
+  timelines: Timeline[];
+  startTime: Date;
+  endTime: Date;
+  timeRangeText: string;
+  idleMinutes: number;
+  multiDay: boolean;
+}
+
+// Plain sentences in place of the table for screen readers: no borders, glyphs or colors
+export const AccessibleReport: React.FC<AccessibleReportProps> = ({
+  timelines,
+  startTime,
+  endTime,
+  timeRangeText,
+  idleMinutes,
+  multiDay,
+}) => {
+  return (
+    <Box flexDirection="column">
+      <Text>
+        Claude Code activity from {format(startTime, 'yyyy-MM-dd HH:mm')} to{' '}
+        {format(endTime, 'yyyy-MM-dd HH:mm')} ({timeRangeText}), {timelines.length} projects.
+      </Text>
+      {timelines.map(timeline => (
+        <Text key={timeline.projectName}>
+          {describeTimeline(timeline, idleMinutes, multiDay)}.
+        </Text>
+      ))}
+    </Box>
+  );
+};
@@ -0,0 +1,24 @@
+import React from 'react';
+import { Box, Text } from 'ink';
+import { format } from 'date-fns';
+import { Annotation } from '../../core/annotations';
+import { ANNOTATION_MARKER_CHAR } from '../utils/tableUtils';
+
+interface AnnotationListProps {
+  annotations: Annotation[];
+}
+
+export const AnnotationList: React.FC<AnnotationListProps> = ({ annotations }) => {
+  return (
+    <Box marginTop={1} flexDirection="column">
+      <Text>Annotations:</Text>
+      {annotations.map((annotation, index) => (
+        <Text key={index}>
+          {' '}
+          {ANNOTATION_MARKER_CHAR} {format(new Date(annotation.timestamp), 'MM/dd HH:mm')}{' '}
+          {annotation.label}
+        </Text>
+      ))}
+    </Box>
+  );
+};
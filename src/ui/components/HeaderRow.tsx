@@ -1,20 +1,23 @@
 import React from 'react';
 import { Box, Text } from 'ink';
+import { TableColumn, LEFT_COLUMN_PADDING } from '../utils/columns';
+import { EMOJI_CHARS } from '../utils/tableUtils';
 
 interface HeaderRowProps {
   projectWidth: number;
   timelineWidth: number;
-  eventsWidth: number;
-  durationWidth: number;
+  columns: TableColumn[];
   activityColors: (string | ((text: string) => string))[];
+  // Show the emoji ramp instead of the colored blocks
+  emoji?: boolean;
 }
 
 export const HeaderRow: React.FC<HeaderRowProps> = ({
   projectWidth,
   timelineWidth,
-  eventsWidth,
-  durationWidth,
+  columns,
   activityColors,
+  emoji,
 }) => {
   return (
     <Box paddingTop={1}>
@@ -24,25 +27,33 @@ export const HeaderRow: React.FC<HeaderRowProps> = ({
       <Box width={timelineWidth}>
         <Text bold>
           <Text>Timeline | less </Text>
-          {activityColors.map((color, index) => {
-            if (typeof color === 'function') {
-              return <Text key={index}>{color('■')}</Text>;
-            }
-            return (
-              <Text key={index} color={color}>
-                ■
-              </Text>
-            );
-          })}
+          {emoji ? (
+            <Text>{EMOJI_CHARS.join('')}</Text>
+          ) : (
+            activityColors.map((color, index) => {
+              if (typeof color === 'function') {
+                return <Text key={index}>{color('■')}</Text>;
+              }
+              return (
+                <Text key={index} color={color}>
+                  ■
+                </Text>
+              );
+            })
+          )}
           <Text> more</Text>
         </Text>
       </Box>
-      <Box width={eventsWidth} justifyContent="flex-end">
-        <Text bold>Events</Text>
-      </Box>
-      <Box width={durationWidth} justifyContent="flex-end">
-        <Text bold>Duration</Text>
-      </Box>
+      {columns.map(column => (
+        <Box
+          key={column.header}
+          width={column.width}
+          justifyContent={column.align === 'left' ? 'flex-start' : 'flex-end'}
+          paddingLeft={column.align === 'left' ? LEFT_COLUMN_PADDING : 0}
+        >
+          <Text bold>{column.header}</Text>
+        </Box>
+      ))}
     </Box>
   );
 };
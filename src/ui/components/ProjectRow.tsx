@@ -1,7 +1,13 @@
 import React from 'react';
 import { Box, Text } from 'ink';
+import { format } from 'date-fns';
 import { Timeline } from '../../models/models';
 import { TimelineBar } from './TimelineBar';
+import { RenderMode } from '../renderModes';
+import { calculateRowsEndTime, calculateTimelineCells } from '../utils/tableUtils';
+import { TableColumn, LEFT_COLUMN_PADDING } from '../utils/columns';
+
+const LOCAL_ONLY_MARK = ' (local)';
 
 interface ProjectRowProps {
   timeline: Timeline;
@@ -9,9 +15,19 @@ interface ProjectRowProps {
   endTime: Date;
   projectWidth: number;
   timelineWidth: number;
-  eventsWidth: number;
-  durationWidth: number;
+  columns: TableColumn[];
   activityColors: (string | ((text: string) => string))[];
+  renderMode?: RenderMode;
+  weekRowStarts?: Date[];
+  gridTicks?: number[];
+  // Cell index of the current time across all rows, or -1 when outside the range
+  nowIndex?: number;
+  shadedCells?: boolean[];
+  bold?: boolean;
+  // Project name color, e.g. a recency tint
+  nameColor?: string;
+  // Highlighted row in interactive mode
+  selected?: boolean;
 }
 
 export const ProjectRow: React.FC<ProjectRowProps> = ({
@@ -20,9 +36,16 @@ export const ProjectRow: React.FC<ProjectRowProps> = ({
   endTime,
   projectWidth,
   timelineWidth,
-  eventsWidth,
-  durationWidth,
+  columns,
   activityColors,
+  renderMode,
+  weekRowStarts,
+  gridTicks,
+  nowIndex = -1,
+  shadedCells,
+  bold,
+  nameColor,
+  selected,
 }) => {
   const projectName = timeline.projectName;
 
@@ -30,27 +53,61 @@ export const ProjectRow: React.FC<ProjectRowProps> = ({
     projectName.length > projectWidth - 2
       ? projectName.substring(0, projectWidth - 5) + '…'
       : projectName;
+  // Only when there is room, so the project name itself is never truncated for it
+  const localOnlyMark =
+    timeline.localOnly && truncatedName.length + LOCAL_ONLY_MARK.length <= projectWidth - 2
+      ? LOCAL_ONLY_MARK
+      : '';
+
+  // Stacked week rows share one density scale, so compute all cells at once and split them
+  const barWidth = timelineWidth - 2;
+  const rowStarts = weekRowStarts ?? [startTime];
+  const rowsEndTime = calculateRowsEndTime(endTime, weekRowStarts);
+  const cells = calculateTimelineCells(
+    timeline.events,
+    startTime,
+    rowsEndTime,
+    barWidth * rowStarts.length,
+    renderMode
+  );
 
   return (
-    <Box>
-      <Box width={projectWidth}>
-        <Text>{truncatedName}</Text>
-      </Box>
-      <Box width={timelineWidth}>
-        <TimelineBar
-          timeline={timeline}
-          startTime={startTime}
-          endTime={endTime}
-          width={timelineWidth - 2}
-          activityColors={activityColors}
-        />
-      </Box>
-      <Box width={eventsWidth} justifyContent="flex-end">
-        <Text>{timeline.eventCount}</Text>
-      </Box>
-      <Box width={durationWidth} justifyContent="flex-end">
-        <Text>{timeline.activeDuration}m</Text>
-      </Box>
+    <Box flexDirection="column">
+      {rowStarts.map((rowStart, index) => (
+        <Box key={index}>
+          <Box width={projectWidth}>
+            {index === 0 ? (
+              <Text>
+                <Text bold={bold} color={nameColor} inverse={selected}>
+                  {truncatedName}
+                </Text>
+                <Text dimColor>{localOnlyMark}</Text>
+              </Text>
+            ) : (
+              <Text dimColor>{`  └ ${format(rowStart, 'MM/dd')}`}</Text>
+            )}
+          </Box>
+          <Box width={timelineWidth}>
+            <TimelineBar
+              cells={cells.slice(index * barWidth, (index + 1) * barWidth)}
+              activityColors={activityColors}
+              gridTicks={gridTicks}
+              nowPosition={nowIndex >= 0 ? nowIndex - index * barWidth : undefined}
+              shaded={shadedCells?.slice(index * barWidth, (index + 1) * barWidth)}
+            />
+          </Box>
+          {columns.map(column => (
+            <Box
+              key={column.header}
+              width={column.width}
+              justifyContent={column.align === 'left' ? 'flex-start' : 'flex-end'}
+              paddingLeft={column.align === 'left' ? LEFT_COLUMN_PADDING : 0}
+            >
+              <Text wrap="truncate">{index === 0 ? column.value(timeline) : ''}</Text>
+            </Box>
+          ))}
+        </Box>
+      ))}
     </Box>
   );
 };
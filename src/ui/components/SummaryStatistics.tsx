@@ -4,12 +4,14 @@ import { Box, Text } from 'ink';
 interface SummaryStatisticsProps {
   projectCount: number;
   totalEvents: number;
+  totalSessions: number;
   totalDuration: number;
 }
 
 export const SummaryStatistics: React.FC<SummaryStatisticsProps> = ({
   projectCount,
   totalEvents,
+  totalSessions,
   totalDuration,
 }) => {
   return (
@@ -17,6 +19,7 @@ export const SummaryStatistics: React.FC<SummaryStatisticsProps> = ({
       <Text>
         Summary Statistics:{'\n'} - Total Projects: {projectCount}
         {'\n'} - Total Events: {totalEvents}
+        {'\n'} - Total Sessions: {totalSessions}
         {'\n'} - Total Duration: {totalDuration} minutes
       </Text>
     </Box>
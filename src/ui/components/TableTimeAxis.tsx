@@ -1,14 +1,25 @@
 import React from 'react';
 import { Box, Text } from 'ink';
-import { createTimeAxis } from '../utils/tableUtils';
+import {
+  createTimeAxis,
+  createWeekdayAxis,
+  placeAnnotationMarkers,
+  placeNowMarker,
+  splitShadedSegments,
+} from '../utils/tableUtils';
+import { SHADE_BACKGROUND_COLOR } from '../colorThemes';
+import { TableColumn } from '../utils/columns';
 
 interface TableTimeAxisProps {
   startTime: Date;
   endTime: Date;
   projectWidth: number;
   timelineWidth: number;
-  eventsWidth: number;
-  durationWidth: number;
+  columns: TableColumn[];
+  weekly?: boolean;
+  nowPosition?: number;
+  annotationPositions?: number[];
+  shaded?: boolean[];
 }
 
 export const TableTimeAxis: React.FC<TableTimeAxisProps> = ({
@@ -16,23 +27,44 @@ export const TableTimeAxis: React.FC<TableTimeAxisProps> = ({
   endTime,
   projectWidth,
   timelineWidth,
-  eventsWidth,
-  durationWidth,
+  columns,
+  weekly,
+  nowPosition,
+  annotationPositions = [],
+  shaded = [],
 }) => {
+  const axis = placeAnnotationMarkers(
+    placeNowMarker(
+      weekly
+        ? createWeekdayAxis(startTime, timelineWidth - 2)
+        : createTimeAxis(startTime, endTime, timelineWidth - 2),
+      nowPosition
+    ),
+    annotationPositions
+  );
+
   return (
     <Box>
       <Box width={projectWidth}>
         <Text> </Text>
       </Box>
       <Box width={timelineWidth}>
-        <Text>{createTimeAxis(startTime, endTime, timelineWidth - 2)}</Text>
-      </Box>
-      <Box width={eventsWidth}>
-        <Text> </Text>
-      </Box>
-      <Box width={durationWidth}>
-        <Text> </Text>
+        <Text>
+          {splitShadedSegments(axis, shaded).map((segment, index) => (
+            <Text
+              key={index}
+              backgroundColor={segment.shaded ? SHADE_BACKGROUND_COLOR : undefined}
+            >
+              {segment.text}
+            </Text>
+          ))}
+        </Text>
       </Box>
+      {columns.map(column => (
+        <Box key={column.header} width={column.width}>
+          <Text> </Text>
+        </Box>
+      ))}
     </Box>
   );
 };
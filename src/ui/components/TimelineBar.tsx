@@ -1,64 +1,79 @@
 import React from 'react';
 import { Text } from 'ink';
-import { Timeline } from '../../models/models';
+import { TimelineCell } from '../utils/tableUtils';
+import { SHADE_BACKGROUND_COLOR } from '../colorThemes';
 
 interface TimelineBarProps {
-  timeline: Timeline;
-  startTime: Date;
-  endTime: Date;
-  width: number;
+  cells: TimelineCell[];
   activityColors: (string | ((text: string) => string))[];
+  gridTicks?: number[];
+  // Cell containing the current time; cells after it are still in the future
+  nowPosition?: number;
+  // Weekend/off-hours cells get a distinct background
+  shaded?: boolean[];
 }
 
+const GRIDLINE_CHAR = '│';
+const NOW_CHAR = '┃';
+const FUTURE_CHAR = '·';
+
 export const TimelineBar: React.FC<TimelineBarProps> = ({
-  timeline,
-  startTime,
-  endTime,
-  width,
+  cells,
   activityColors,
+  gridTicks,
+  nowPosition,
+  shaded,
 }) => {
-  const totalDuration = endTime.getTime() - startTime.getTime();
-  const activityCounts = new Array(width).fill(0);
-
-  // Count events per time position
-  for (const event of timeline.events) {
-    const eventTime = new Date(event.timestamp);
-    const eventOffset = eventTime.getTime() - startTime.getTime();
-    const position = Math.floor((eventOffset / totalDuration) * width);
-
-    // Clamp position to valid range
-    const clampedPosition = Math.max(0, Math.min(width - 1, position));
-    activityCounts[clampedPosition]++;
-  }
-
-  // Find max activity for normalization
-  const maxActivity = Math.max(...activityCounts, 1);
+  const gridPositions = new Set(gridTicks);
 
   // Create timeline elements with density-based coloring
   const timelineElements: React.ReactNode[] = [];
 
-  for (let i = 0; i < width; i++) {
-    const count = activityCounts[i];
+  for (let i = 0; i < cells.length; i++) {
+    const { char, level, wide } = cells[i];
+    const backgroundColor = shaded?.[i] ? SHADE_BACKGROUND_COLOR : undefined;
 
-    if (count === 0) {
-      // No activity
+    if (wide) {
+      // Emoji carry their own color; markers would break the two-column alignment
+      timelineElements.push(<Text key={i}>{char}</Text>);
+    } else if (char === '') {
+      // Second column of the wide cell before it
+      continue;
+    } else if (level === 0 && nowPosition !== undefined && i === nowPosition) {
+      // Current time
+      timelineElements.push(
+        <Text key={i} backgroundColor={backgroundColor} color="yellow">
+          {NOW_CHAR}
+        </Text>
+      );
+    } else if (level === 0 && nowPosition !== undefined && i > nowPosition) {
+      // Not yet elapsed
+      timelineElements.push(
+        <Text key={i} backgroundColor={backgroundColor} color="dim">
+          {FUTURE_CHAR}
+        </Text>
+      );
+    } else if (level === 0) {
+      // No activity, with a subtle marker where an axis tick sits above
       timelineElements.push(
-        <Text key={i} color="dim">
-          ■
+        <Text key={i} backgroundColor={backgroundColor} color="dim">
+          {gridPositions.has(i) ? GRIDLINE_CHAR : char}
         </Text>
       );
     } else {
-      // Calculate density level (1-4 scale mapped to our colors)
-      const densityLevel = Math.min(4, Math.floor((count / maxActivity) * 4) + 1);
-      const colorIndex = densityLevel;
-      const color = activityColors[colorIndex];
+      // Density level (1-4 scale) maps directly to our colors
+      const color = activityColors[level];
 
       if (typeof color === 'function') {
-        timelineElements.push(<Text key={i}>{color('■')}</Text>);
+        timelineElements.push(
+          <Text key={i} backgroundColor={backgroundColor}>
+            {color(char)}
+          </Text>
+        );
       } else {
         timelineElements.push(
-          <Text key={i} color={color}>
-            ■
+          <Text key={i} backgroundColor={backgroundColor} color={color}>
+            {char}
           </Text>
         );
       }
@@ -0,0 +1,16 @@
+export const RENDER_MODE_VALUES = ['block', 'half-block', 'braille', 'emoji'] as const;
+
+export type RenderMode = (typeof RENDER_MODE_VALUES)[number];
+
+export function isValidRenderMode(value: string): value is RenderMode {
+  return RENDER_MODE_VALUES.includes(value as RenderMode);
+}
+
+// Sparklines in `ccstat trend` and `ccstat compare-projects` are either bars or braille dots
+export const SPARKLINE_RENDER_MODE_VALUES = ['block', 'braille'] as const;
+
+export type SparklineRenderMode = (typeof SPARKLINE_RENDER_MODE_VALUES)[number];
+
+export function isValidSparklineRenderMode(value: string): value is SparklineRenderMode {
+  return SPARKLINE_RENDER_MODE_VALUES.includes(value as SparklineRenderMode);
+}
@@ -0,0 +1,52 @@
+import { Event } from '../../../models/models';
+import { createTimeline } from '../../../core/parser';
+import { describeTimeline, findActivityPeriods, findPeakHour } from '../accessible';
+
+const at = (day: number, hour: number, minute: number): Event => ({
+  timestamp: new Date(2025, 5, day, hour, minute).toISOString(),
+  sessionId: hour < 12 ? 'morning' : 'afternoon',
+});
+
+describe('accessible output', () => {
+  const events = [
+    at(12, 9, 0),
+    at(12, 9, 4),
+    at(12, 10, 2),
+    at(12, 10, 5),
+    at(12, 10, 8),
+    at(12, 14, 0),
+    at(12, 14, 3),
+  ];
+
+  it('should merge events within the idle threshold into periods', () => {
+    expect(findActivityPeriods(events, 5)).toEqual([
+      { start: new Date(2025, 5, 12, 9, 0), end: new Date(2025, 5, 12, 9, 4) },
+      { start: new Date(2025, 5, 12, 10, 2), end: new Date(2025, 5, 12, 10, 8) },
+      { start: new Date(2025, 5, 12, 14, 0), end: new Date(2025, 5, 12, 14, 3) },
+    ]);
+    expect(findActivityPeriods(events, 60)).toHaveLength(2);
+  });
+
+  it('should find the busiest hour', () => {
+    expect(findPeakHour(events)).toEqual(new Date(2025, 5, 12, 10, 0));
+    expect(findPeakHour([at(12, 9, 0), at(12, 8, 0)])).toEqual(new Date(2025, 5, 12, 8, 0));
+    expect(findPeakHour([])).toBeNull();
+  });
+
+  it('should describe a project in one sentence', () => {
+    const timeline = { ...createTimeline('webapp', events), activeDuration: 95 };
+
+    expect(describeTimeline(timeline, 5, false)).toBe(
+      'webapp: 7 events, 1h 35m over 2 sessions; active 09:00–09:04, 10:02–10:08, ' +
+        '14:00–14:03; peak 10:00'
+    );
+  });
+
+  it('should add dates for multi-day ranges and count periods beyond the limit', () => {
+    const days = Array.from({ length: 10 }, (_, index) => at(index + 1, 9, 0));
+    const description = describeTimeline(createTimeline('api', days), 5, true);
+
+    expect(description).toContain('active 06/01 09:00, 06/02 09:00');
+    expect(description).toContain('06/08 09:00, and 2 more periods; peak 06/01 09:00');
+  });
+});
@@ -0,0 +1,127 @@
+import { Timeline } from '../../../models/models';
+import {
+  compareToPrevious,
+  createShareColumn,
+  formatShare,
+  getShareMetric,
+  countSessions,
+  DAYS_COLUMN,
+  EVENTS_COLUMN,
+  DURATION_COLUMN,
+  SESSIONS_COLUMN,
+} from '../columns';
+
+const createMockTimeline = (
+  projectName: string,
+  eventCount: number,
+  activeDuration: number
+): Timeline => ({
+  projectName,
+  events: [],
+  eventCount,
+  activeDuration,
+  startTime: new Date('2025-01-01T09:00:00Z'),
+  endTime: new Date('2025-01-01T10:00:00Z'),
+});
+
+describe('table columns', () => {
+  const timelines = [
+    createMockTimeline('alpha', 300, 10),
+    createMockTimeline('beta', 100, 30),
+    createMockTimeline('gamma', 1, 0),
+  ];
+
+  it('should render events and duration values', () => {
+    expect(EVENTS_COLUMN.value(timelines[0])).toBe('300');
+    expect(DURATION_COLUMN.value(timelines[1])).toBe('30m');
+  });
+
+  it('should count distinct sessions', () => {
+    const web = {
+      ...createMockTimeline('web', 3, 5),
+      events: [
+        { timestamp: '2025-01-01T09:00:00Z', sessionId: 'a' },
+        { timestamp: '2025-01-01T09:01:00Z', sessionId: 'a' },
+        { timestamp: '2025-01-01T09:30:00Z', sessionId: 'b' },
+      ],
+    };
+    const api = {
+      ...createMockTimeline('api', 1, 5),
+      events: [{ timestamp: '2025-01-01T09:02:00Z', sessionId: 'b' }],
+    };
+
+    expect(SESSIONS_COLUMN.value(web)).toBe('2');
+    expect(countSessions([web, api])).toBe(2);
+  });
+
+  it('should count distinct local days with activity', () => {
+    const timeline = {
+      ...createMockTimeline('web', 3, 5),
+      events: [
+        { timestamp: new Date(2025, 0, 1, 9).toISOString() },
+        { timestamp: new Date(2025, 0, 1, 23).toISOString() },
+        { timestamp: new Date(2025, 0, 3, 0, 30).toISOString() },
+      ],
+    };
+
+    expect(DAYS_COLUMN.value(timeline)).toBe('2');
+  });
+
+  describe('getShareMetric', () => {
+    it('should follow the duration sort', () => {
+      expect(getShareMetric('duration')).toBe('duration');
+    });
+
+    it('should default to events', () => {
+      expect(getShareMetric('events')).toBe('events');
+      expect(getShareMetric('timeline')).toBe('events');
+      expect(getShareMetric(undefined)).toBe('events');
+    });
+  });
+
+  describe('formatShare', () => {
+    it('should round to whole percentages', () => {
+      expect(formatShare(0.748)).toBe('75%');
+      expect(formatShare(1)).toBe('100%');
+      expect(formatShare(0)).toBe('0%');
+    });
+
+    it('should mark tiny non-zero shares', () => {
+      expect(formatShare(0.002)).toBe('<1%');
+    });
+  });
+
+  describe('createShareColumn', () => {
+    it('should compute event shares', () => {
+      const column = createShareColumn(timelines, 'events');
+      expect(timelines.map(column.value)).toEqual(['75%', '25%', '<1%']);
+    });
+
+    it('should compute duration shares', () => {
+      const column = createShareColumn(timelines, 'duration');
+      expect(timelines.map(column.value)).toEqual(['25%', '75%', '0%']);
+    });
+
+    it('should handle an all-zero total', () => {
+      const column = createShareColumn([createMockTimeline('empty', 0, 0)], 'duration');
+      expect(column.value(timelines[0])).toBe('0%');
+    });
+  });
+
+  describe('compareToPrevious', () => {
+    it('should detect increases and decreases beyond the threshold', () => {
+      expect(compareToPrevious(150, 100)).toBe('up');
+      expect(compareToPrevious(50, 100)).toBe('down');
+    });
+
+    it('should treat small changes as flat', () => {
+      expect(compareToPrevious(105, 100)).toBe('flat');
+      expect(compareToPrevious(95, 100)).toBe('flat');
+      expect(compareToPrevious(0, 0)).toBe('flat');
+    });
+
+    it('should mark projects without previous activity as new', () => {
+      expect(compareToPrevious(10, 0)).toBe('new');
+    });
+  });
+});
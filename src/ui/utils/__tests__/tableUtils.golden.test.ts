@@ -0,0 +1,121 @@
+import { readFileSync, writeFileSync } from 'fs';
+import { join } from 'path';
+import {
+  createTimeAxis,
+  calculateActivityLevels,
+  createWeekdayAxis,
+  layoutTimeAxis,
+  layoutWeekdayAxis,
+} from '../tableUtils';
+import { Event } from '../../../models/models';
+
+// Render in a fixed timezone so golden files are reproducible
+process.env.TZ = 'UTC';
+
+const GOLDEN_DIR = join(__dirname, '__golden__');
+
+// Compare output against a golden file (run with UPDATE_GOLDEN=1 to regenerate)
+function expectGolden(name: string, actual: string): void {
+  const goldenPath = join(GOLDEN_DIR, `${name}.golden`);
+
+  if (process.env.UPDATE_GOLDEN) {
+    writeFileSync(goldenPath, `${actual}\n`);
+    return;
+  }
+
+  expect(`${actual}\n`).toBe(readFileSync(goldenPath, 'utf-8'));
+}
+
+const createMockEvents = (timestamps: string[]): Event[] =>
+  timestamps.map(timestamp => ({ timestamp, sessionId: 'session-1' }));
+
+describe('golden rendering', () => {
+  describe('createTimeAxis', () => {
+    it('should render a 6 hour range', () => {
+      const axis = createTimeAxis(
+        new Date('2025-01-01T09:00:00Z'),
+        new Date('2025-01-01T15:00:00Z'),
+        48
+      );
+      expectGolden('time-axis-6-hours', axis);
+    });
+
+    it('should render a 1 day range', () => {
+      const axis = createTimeAxis(
+        new Date('2025-01-01T00:00:00Z'),
+        new Date('2025-01-02T00:00:00Z'),
+        48
+      );
+      expectGolden('time-axis-1-day', axis);
+    });
+
+    it('should render a 7 day range', () => {
+      const axis = createTimeAxis(
+        new Date('2025-01-01T00:00:00Z'),
+        new Date('2025-01-08T00:00:00Z'),
+        60
+      );
+      expectGolden('time-axis-7-days', axis);
+    });
+
+    it('should always match the requested width', () => {
+      const axis = createTimeAxis(
+        new Date('2025-01-01T00:00:00Z'),
+        new Date('2025-01-08T00:00:00Z'),
+        60
+      );
+      expect(axis.length).toBe(60);
+    });
+  });
+
+  describe('axis ticks', () => {
+    it('should report tick positions under each time axis label', () => {
+      const { axis, ticks } = layoutTimeAxis(
+        new Date('2025-01-01T00:00:00Z'),
+        new Date('2025-01-02T00:00:00Z'),
+        48
+      );
+      expect(ticks).toEqual([0, 8, 16, 24, 32, 40]);
+      for (const tick of ticks) {
+        expect(axis[tick]).not.toBe(' ');
+      }
+    });
+
+    it('should report tick positions at each weekday label', () => {
+      const { ticks } = layoutWeekdayAxis(new Date('2025-01-01T00:00:00Z'), 35);
+      expect(ticks).toEqual([0, 5, 10, 15, 20, 25, 30]);
+    });
+  });
+
+  describe('createWeekdayAxis', () => {
+    it('should label each day of a week row', () => {
+      expectGolden('weekday-axis', createWeekdayAxis(new Date('2025-01-01T00:00:00Z'), 35));
+    });
+
+    it('should skip labels that would overlap', () => {
+      expect(createWeekdayAxis(new Date('2025-01-01T12:00:00Z'), 14)).toBe(' Thu Sat Mon  ');
+    });
+  });
+
+  describe('calculateActivityLevels', () => {
+    it('should render density levels for a 6 hour range', () => {
+      const events = createMockEvents([
+        '2025-01-01T09:05:00Z',
+        '2025-01-01T09:10:00Z',
+        '2025-01-01T09:12:00Z',
+        '2025-01-01T10:30:00Z',
+        '2025-01-01T12:00:00Z',
+        '2025-01-01T12:01:00Z',
+        '2025-01-01T14:59:00Z',
+        '2025-01-01T15:00:00Z',
+      ]);
+      const levels = calculateActivityLevels(
+        events,
+        new Date('2025-01-01T09:00:00Z'),
+        new Date('2025-01-01T15:00:00Z'),
+        48
+      );
+      expectGolden('activity-levels-6-hours', levels.join(''));
+    });
+  });
+});
@@ -0,0 +1,273 @@
+import {
+  calculateActivityLevels,
+  calculateTimelineCells,
+  calculateWeekRowStarts,
+  calculateRowsEndTime,
+  calculateShadedCells,
+  createOverviewTimeline,
+  getBucketIndex,
+  isWithinHourRange,
+  parseHourRange,
+  placeAnnotationMarkers,
+  placeNowMarker,
+  splitShadedSegments,
+} from '../tableUtils';
+import { Event, Timeline } from '../../../models/models';
+
+const createMockEvents = (timestamps: string[]): Event[] =>
+  timestamps.map(timestamp => ({ timestamp, sessionId: 'session-1' }));
+
+describe('timeline bucketing', () => {
+  // One hour split into six 10-minute buckets
+  const startTime = new Date('2025-01-01T09:00:00Z');
+  const endTime = new Date('2025-01-01T10:00:00Z');
+  const startMs = startTime.getTime();
+  const endMs = endTime.getTime();
+  const minute = 60 * 1000;
+
+  describe('getBucketIndex', () => {
+    it('should place the start instant in the first bucket', () => {
+      expect(getBucketIndex(startMs, startMs, endMs, 6)).toBe(0);
+    });
+
+    it('should place the end instant in the final bucket', () => {
+      expect(getBucketIndex(endMs, startMs, endMs, 6)).toBe(5);
+    });
+
+    it('should treat bucket boundaries as the start of the next bucket', () => {
+      expect(getBucketIndex(startMs + 10 * minute - 1, startMs, endMs, 6)).toBe(0);
+      expect(getBucketIndex(startMs + 10 * minute, startMs, endMs, 6)).toBe(1);
+      expect(getBucketIndex(startMs + 50 * minute, startMs, endMs, 6)).toBe(5);
+    });
+
+    it('should reject instants outside the range', () => {
+      expect(getBucketIndex(startMs - 1, startMs, endMs, 6)).toBe(-1);
+      expect(getBucketIndex(endMs + 1, startMs, endMs, 6)).toBe(-1);
+    });
+
+    it('should allocate evenly sized buckets', () => {
+      const bucketSizes = new Array(6).fill(0);
+      for (let t = startMs; t < endMs; t += minute) {
+        bucketSizes[getBucketIndex(t, startMs, endMs, 6)]++;
+      }
+      expect(bucketSizes).toEqual([10, 10, 10, 10, 10, 10]);
+    });
+  });
+
+  describe('calculateActivityLevels', () => {
+    it('should not pile events at the end instant into an over-full last cell', () => {
+      const events = createMockEvents([
+        '2025-01-01T09:00:00Z',
+        '2025-01-01T09:55:00Z',
+        '2025-01-01T10:00:00Z',
+      ]);
+      expect(calculateActivityLevels(events, startTime, endTime, 6)).toEqual([3, 0, 0, 0, 0, 4]);
+    });
+
+    it('should ignore events outside the displayed range', () => {
+      const events = createMockEvents(['2025-01-01T08:59:59Z', '2025-01-01T10:00:01Z']);
+      expect(calculateActivityLevels(events, startTime, endTime, 6)).toEqual([0, 0, 0, 0, 0, 0]);
+    });
+  });
+});
+
+describe('calculateTimelineCells', () => {
+  const startTime = new Date('2025-01-01T09:00:00Z');
+  const endTime = new Date('2025-01-01T10:00:00Z');
+  const events: Event[] = [
+    { timestamp: '2025-01-01T09:05:00Z', sessionId: 'session-1' },
+    { timestamp: '2025-01-01T09:35:00Z', sessionId: 'session-1' },
+    { timestamp: '2025-01-01T09:50:00Z', sessionId: 'session-1' },
+  ];
+
+  it('should render one block per bucket by default', () => {
+    const cells = calculateTimelineCells(events, startTime, endTime, 3);
+    expect(cells.map(c => c.char).join('')).toBe('■■■');
+    expect(cells.map(c => c.level)).toEqual([4, 4, 4]);
+  });
+
+  it('should show which half of each cell was active in half-block mode', () => {
+    const cells = calculateTimelineCells(events, startTime, endTime, 3, 'half-block');
+    expect(cells.map(c => c.char).join('')).toBe('▌▐▐');
+    expect(cells.map(c => c.level)).toEqual([4, 4, 4]);
+  });
+
+  it('should use a full block when both halves are active', () => {
+    const cells = calculateTimelineCells(
+      [...events, { timestamp: '2025-01-01T09:15:00Z', sessionId: 'session-1' }],
+      startTime,
+      endTime,
+      3,
+      'half-block'
+    );
+    expect(cells[0].char).toBe('█');
+  });
+
+  it('should draw each half as a dot bar as high as its density in braille mode', () => {
+    const cells = calculateTimelineCells(
+      [...events, { timestamp: '2025-01-01T09:06:00Z', sessionId: 'session-1' }],
+      startTime,
+      endTime,
+      4,
+      'braille'
+    );
+    expect(cells.map(c => c.char).join('')).toBe('⡇⣀⡆⡆');
+    expect(cells.map(c => c.level)).toEqual([4, 0, 3, 3]);
+  });
+
+  it('should spread each emoji over two cells in emoji mode', () => {
+    const cells = calculateTimelineCells(events, startTime, endTime, 5, 'emoji');
+    expect(cells.map(c => c.char)).toEqual(['🟧', '', '🟥', '', ' ']);
+    expect(cells.map(c => c.wide ?? false)).toEqual([true, false, true, false, false]);
+  });
+});
+
+describe('calculateWeekRowStarts', () => {
+  it('should use a single row for ranges up to a week', () => {
+    const startTime = new Date('2025-01-01T00:00:00Z');
+    const rows = calculateWeekRowStarts(startTime, new Date('2025-01-08T00:00:00Z'));
+    expect(rows).toEqual([startTime]);
+  });
+
+  it('should add a row for each started week', () => {
+    const rows = calculateWeekRowStarts(
+      new Date('2025-01-01T00:00:00Z'),
+      new Date('2025-01-20T00:00:00Z')
+    );
+    expect(rows.map(r => r.toISOString())).toEqual([
+      '2025-01-01T00:00:00.000Z',
+      '2025-01-08T00:00:00.000Z',
+      '2025-01-15T00:00:00.000Z',
+    ]);
+  });
+});
+
+describe('calculateRowsEndTime', () => {
+  it('should return the range end for a single row', () => {
+    const endTime = new Date('2025-01-02T00:00:00Z');
+    expect(calculateRowsEndTime(endTime)).toBe(endTime);
+  });
+
+  it('should extend stacked rows to a whole final week', () => {
+    const rows = [new Date('2025-01-01T00:00:00Z'), new Date('2025-01-08T00:00:00Z')];
+    expect(calculateRowsEndTime(new Date('2025-01-10T00:00:00Z'), rows).toISOString()).toBe(
+      '2025-01-15T00:00:00.000Z'
+    );
+  });
+});
+
+describe('placeNowMarker', () => {
+  it('should mark the current position on blank axis cells', () => {
+    expect(placeNowMarker('09:00     ', 8)).toBe('09:00   ▼ ');
+  });
+
+  it('should not overwrite axis labels', () => {
+    expect(placeNowMarker('09:00     ', 2)).toBe('09:00     ');
+  });
+
+  it('should leave the axis untouched when now is outside the range', () => {
+    expect(placeNowMarker('09:00     ', undefined)).toBe('09:00     ');
+    expect(placeNowMarker('09:00     ', 10)).toBe('09:00     ');
+  });
+});
+
+describe('placeAnnotationMarkers', () => {
+  it('should mark annotated positions on blank axis cells only', () => {
+    expect(placeAnnotationMarkers('09:00   ▼ ', [1, 6, 8, 9])).toBe('09:00 ◆ ▼◆');
+  });
+});
+
+describe('shading', () => {
+  describe('parseHourRange', () => {
+    it('should parse valid ranges', () => {
+      expect(parseHourRange('19-8')).toEqual({ start: 19, end: 8 });
+      expect(parseHourRange('0-9')).toEqual({ start: 0, end: 9 });
+    });
+
+    it('should reject malformed ranges', () => {
+      expect(parseHourRange('19')).toBeNull();
+      expect(parseHourRange('25-8')).toBeNull();
+      expect(parseHourRange('8-8')).toBeNull();
+      expect(parseHourRange('evening')).toBeNull();
+    });
+  });
+
+  describe('isWithinHourRange', () => {
+    it('should handle ranges that wrap past midnight', () => {
+      const range = { start: 19, end: 8 };
+      expect(isWithinHourRange(22, range)).toBe(true);
+      expect(isWithinHourRange(3, range)).toBe(true);
+      expect(isWithinHourRange(8, range)).toBe(false);
+      expect(isWithinHourRange(12, range)).toBe(false);
+    });
+
+    it('should handle ranges within a single day', () => {
+      const range = { start: 0, end: 9 };
+      expect(isWithinHourRange(0, range)).toBe(true);
+      expect(isWithinHourRange(9, range)).toBe(false);
+    });
+  });
+
+  describe('calculateShadedCells', () => {
+    // Local-time dates so weekday and hour checks are timezone independent
+    const monday = new Date(2025, 0, 6);
+    const nextMonday = new Date(2025, 0, 13);
+
+    it('should shade weekend days in multi-day ranges', () => {
+      const shaded = calculateShadedCells(monday, nextMonday, 7, { weekends: true });
+      expect(shaded).toEqual([false, false, false, false, false, true, true]);
+    });
+
+    it('should not shade weekends in ranges of a day or less', () => {
+      const saturday = new Date(2025, 0, 11);
+      const shaded = calculateShadedCells(saturday, new Date(2025, 0, 12), 4, { weekends: true });
+      expect(shaded).toEqual([false, false, false, false]);
+    });
+
+    it('should shade off-hours', () => {
+      const shaded = calculateShadedCells(monday, new Date(2025, 0, 7), 4, {
+        offHours: { start: 18, end: 6 },
+      });
+      expect(shaded).toEqual([true, false, false, true]);
+    });
+  });
+
+  describe('splitShadedSegments', () => {
+    it('should group consecutive characters with equal shading', () => {
+      expect(splitShadedSegments('abcde', [false, false, true, true, false])).toEqual([
+        { text: 'ab', shaded: false },
+        { text: 'cd', shaded: true },
+        { text: 'e', shaded: false },
+      ]);
+    });
+  });
+});
+
+describe('createOverviewTimeline', () => {
+  const createMockTimeline = (projectName: string, timestamps: string[]): Timeline => ({
+    projectName,
+    events: createMockEvents(timestamps),
+    eventCount: timestamps.length,
+    activeDuration: timestamps.length * 5,
+    startTime: new Date(timestamps[0]),
+    endTime: new Date(timestamps[timestamps.length - 1]),
+  });
+
+  it('should aggregate events and totals across projects', () => {
+    const overview = createOverviewTimeline([
+      createMockTimeline('alpha', ['2025-01-01T09:00:00Z', '2025-01-01T10:00:00Z']),
+      createMockTimeline('beta', ['2025-01-01T08:00:00Z']),
+    ]);
+
+    expect(overview?.projectName).toBe('All projects');
+    expect(overview?.events).toHaveLength(3);
+    expect(overview?.eventCount).toBe(3);
+    expect(overview?.activeDuration).toBe(15);
+    expect(overview?.startTime.toISOString()).toBe('2025-01-01T08:00:00.000Z');
+    expect(overview?.endTime.toISOString()).toBe('2025-01-01T10:00:00.000Z');
+  });
+
+  it('should return null without timelines', () => {
+    expect(createOverviewTimeline([])).toBeNull();
+  });
+});
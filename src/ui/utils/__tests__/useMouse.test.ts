@@ -0,0 +1,20 @@
+import { parseMouseInput } from '../useMouse';
+
+describe('parseMouseInput', () => {
+  it('should report left clicks with their position', () => {
+    expect(parseMouseInput('[<0;12;7M')).toEqual([{ type: 'click', x: 12, y: 7 }]);
+  });
+
+  it('should report wheel turns', () => {
+    expect(parseMouseInput('\u001B[<64;1;1M\u001B[<65;1;1M')).toEqual([
+      { type: 'wheel', direction: 'up' },
+      { type: 'wheel', direction: 'down' },
+    ]);
+  });
+
+  it('should ignore releases, other buttons and keys', () => {
+    expect(parseMouseInput('[<0;12;7m')).toEqual([]);
+    expect(parseMouseInput('[<2;12;7M')).toEqual([]);
+    expect(parseMouseInput('j')).toEqual([]);
+  });
+});
@@ -0,0 +1,49 @@
+import { mkdtemp, readFile, rm } from 'fs/promises';
+import { tmpdir } from 'os';
+import { basename, join } from 'path';
+import { createTimeline } from '../../../core/parser';
+import { formatView, TableView, writeViewExport } from '../viewExport';
+
+describe('view export', () => {
+  const view: TableView = {
+    startTime: new Date(2025, 0, 1, 0, 0),
+    endTime: new Date(2025, 0, 2, 0, 0),
+    timelines: [
+      createTimeline('web|app', [
+        { timestamp: new Date(2025, 0, 1, 9, 0).toISOString() },
+        { timestamp: new Date(2025, 0, 1, 9, 3).toISOString() },
+      ]),
+    ],
+  };
+
+  it('should format the rows as a Markdown table', () => {
+    expect(formatView(view, 'markdown')).toBe(
+      [
+        '# ccstat 2025-01-01 00:00 - 2025-01-02 00:00',
+        '',
+        '| Project | Events | Duration | Last activity |',
+        '| --- | ---: | ---: | --- |',
+        '| web\\|app | 2 | 3m | 2025-01-01 09:03 |',
+        '',
+      ].join('\n')
+    );
+  });
+
+  it('should format the rows as JSON', () => {
+    const data = JSON.parse(formatView(view, 'json'));
+    expect(data.projects).toEqual([
+      expect.objectContaining({ name: 'web|app', events: 2, duration: 3 }),
+    ]);
+  });
+
+  it('should write a timestamped file', async () => {
+    const directory = await mkdtemp(join(tmpdir(), 'ccstat-view-'));
+    try {
+      const path = await writeViewExport(view, 'text', directory, new Date(2025, 0, 2, 8, 30));
+      expect(basename(path)).toBe('ccstat-20250102-083000.txt');
+      expect(await readFile(path, 'utf-8')).toContain('web|app');
+    } finally {
+      await rm(directory, { recursive: true, force: true });
+    }
+  });
+});
@@ -0,0 +1,78 @@
+import { format, isSameDay, startOfHour } from 'date-fns';
+import { Event, Timeline } from '../../models/models';
+import { countSessions } from './columns';
+import { formatHours } from '../../utils/durations';
+
+// Periods listed per project before the rest are only counted, so lines stay listenable
+const MAX_LISTED_PERIODS = 8;
+
+export interface ActivityPeriod {
+  start: Date;
+  end: Date;
+}
+
+// Runs of events no more than idleMinutes apart, in time order
+export function findActivityPeriods(events: Event[], idleMinutes: number): ActivityPeriod[] {
+  const times = events.map(event => new Date(event.timestamp).getTime()).sort((a, b) => a - b);
+  const periods: ActivityPeriod[] = [];
+
+  for (const time of times) {
+    const last = periods[periods.length - 1];
+    if (last && time - last.end.getTime() <= idleMinutes * 60 * 1000) {
+      last.end = new Date(time);
+    } else {
+      periods.push({ start: new Date(time), end: new Date(time) });
+    }
+  }
+  return periods;
+}
+
+// Start of the clock hour with the most events; the earliest one wins a tie
+export function findPeakHour(events: Event[]): Date | null {
+  const counts = new Map<number, number>();
+  for (const event of events) {
+    const hour = startOfHour(new Date(event.timestamp)).getTime();
+    counts.set(hour, (counts.get(hour) ?? 0) + 1);
+  }
+
+  let peak: number | null = null;
+  for (const [hour, count] of counts) {
+    const peakCount = peak === null ? 0 : counts.get(peak)!;
+    if (count > peakCount || (count === peakCount && hour < peak!)) peak = hour;
+  }
+  return peak === null ? null : new Date(peak);
+}
+
+// Dates are spelled out only when the range spans several days
+function formatPeriod(period: ActivityPeriod, withDate: boolean): string {
+  const start = format(period.start, withDate ? 'MM/dd HH:mm' : 'HH:mm');
+  if (period.end.getTime() - period.start.getTime() < 60 * 1000) return start;
+
+  const sameDay = isSameDay(period.start, period.end);
+  const end = format(period.end, withDate && !sameDay ? 'MM/dd HH:mm' : 'HH:mm');
+  return `${start}–${end}`;
+}
+
+// One sentence per project carrying what the timeline bar shows, e.g.
+// "webapp: 42 events, 1h 35m over 3 sessions; active 09:00–11:30, 14:00–15:10; peak 10:00"
+export function describeTimeline(
+  timeline: Timeline,
+  idleMinutes: number,
+  withDate: boolean
+): string {
+  const periods = findActivityPeriods(timeline.events, idleMinutes);
+  const listed = periods.slice(0, MAX_LISTED_PERIODS).map(p => formatPeriod(p, withDate));
+  if (periods.length > MAX_LISTED_PERIODS) {
+    listed.push(`and ${periods.length - MAX_LISTED_PERIODS} more periods`);
+  }
+
+  const parts = [
+    `${timeline.projectName}: ${timeline.eventCount} events, ` +
+      `${formatHours(timeline.activeDuration)} over ${countSessions([timeline])} sessions`,
+  ];
+  if (listed.length > 0) parts.push(`active ${listed.join(', ')}`);
+
+  const peak = findPeakHour(timeline.events);
+  if (peak) parts.push(`peak ${format(peak, withDate ? 'MM/dd HH:mm' : 'HH:mm')}`);
+  return parts.join('; ');
+}
@@ -0,0 +1,141 @@
+import chalk from 'chalk';
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+import { getLastPrompt, truncateText } from '../../utils/prompts';
+
+// Metric column shown after the timeline, right-aligned unless stated otherwise
+export interface TableColumn {
+  header: string;
+  width: number;
+  align?: 'left' | 'right';
+  value: (timeline: Timeline) => string;
+}
+
+export const EVENTS_COLUMN: TableColumn = {
+  header: 'Events',
+  width: 8,
+  value: timeline => `${timeline.eventCount}`,
+};
+
+// Distinct sessions; one resumed across projects counts once
+export function countSessions(timelines: Timeline[]): number {
+  const sessionIds = new Set<string>();
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      if (event.sessionId) sessionIds.add(event.sessionId);
+    }
+  }
+  return sessionIds.size;
+}
+
+export const SESSIONS_COLUMN: TableColumn = {
+  header: 'Sessions',
+  width: 10,
+  value: timeline => `${countSessions([timeline])}`,
+};
+
+export function countActiveDays(timeline: Timeline): number {
+  return new Set(timeline.events.map(e => format(new Date(e.timestamp), 'yyyy-MM-dd'))).size;
+}
+
+// Local calendar days with any activity, shown for ranges longer than a day
+export const DAYS_COLUMN: TableColumn = {
+  header: 'Days',
+  width: 6,
+  value: timeline => `${countActiveDays(timeline)}`,
+};
+
+export const DURATION_COLUMN: TableColumn = {
+  header: 'Duration',
+  width: 10,
+  value: timeline => `${timeline.activeDuration}m`,
+};
+
+export type ShareMetric = 'events' | 'duration';
+
+// Share follows the sort metric so proportions match what the table is ordered by
+export function getShareMetric(sort?: string): ShareMetric {
+  return sort === 'duration' ? 'duration' : 'events';
+}
+
+function getMetricValue(timeline: Timeline, metric: ShareMetric): number {
+  return metric === 'duration' ? timeline.activeDuration : timeline.eventCount;
+}
+
+export function formatShare(share: number): string {
+  if (share > 0 && share < 0.01) return '<1%';
+  return `${Math.round(share * 100)}%`;
+}
+
+export function createShareColumn(timelines: Timeline[], metric: ShareMetric): TableColumn {
+  const total = timelines.reduce((sum, t) => sum + getMetricValue(t, metric), 0);
+
+  return {
+    header: '%',
+    width: 6,
+    value: timeline => formatShare(total > 0 ? getMetricValue(timeline, metric) / total : 0),
+  };
+}
+
+export type TrendDirection = 'up' | 'down' | 'flat' | 'new';
+
+// Changes smaller than this fraction of the previous value count as flat
+const TREND_THRESHOLD = 0.1;
+
+export function compareToPrevious(current: number, previous: number): TrendDirection {
+  if (previous === 0) return current > 0 ? 'new' : 'flat';
+
+  const change = (current - previous) / previous;
+  if (change > TREND_THRESHOLD) return 'up';
+  if (change < -TREND_THRESHOLD) return 'down';
+  return 'flat';
+}
+
+const TREND_MARKERS: Record<TrendDirection, string> = {
+  up: chalk.green('▲'),
+  down: chalk.red('▼'),
+  flat: chalk.dim('='),
+  new: chalk.cyan('+'),
+};
+
+// Compare each project against the preceding window of the same length
+export function createTrendColumn(
+  previousTimelines: Timeline[],
+  metric: ShareMetric,
+  overviewName: string
+): TableColumn {
+  const previousValues = new Map<string, number>();
+  for (const timeline of previousTimelines) {
+    previousValues.set(timeline.projectName, getMetricValue(timeline, metric));
+  }
+  previousValues.set(
+    overviewName,
+    previousTimelines.reduce((sum, t) => sum + getMetricValue(t, metric), 0)
+  );
+
+  return {
+    header: '±',
+    width: 3,
+    value: timeline =>
+      TREND_MARKERS[
+        compareToPrevious(
+          getMetricValue(timeline, metric),
+          previousValues.get(timeline.projectName) ?? 0
+        )
+      ],
+  };
+}
+
+const LAST_PROMPT_WIDTH = 32;
+// Left-aligned text needs a gap from the numeric column before it
+export const LEFT_COLUMN_PADDING = 2;
+
+export const LAST_PROMPT_COLUMN: TableColumn = {
+  header: 'Last prompt',
+  width: LAST_PROMPT_WIDTH,
+  align: 'left',
+  value: timeline => {
+    const prompt = getLastPrompt(timeline);
+    return prompt ? truncateText(prompt, LAST_PROMPT_WIDTH - LEFT_COLUMN_PADDING) : '';
+  },
+};
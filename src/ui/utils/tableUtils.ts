@@ -1,5 +1,7 @@
-import { Timeline } from '../../models/models';
+import { Event, Timeline } from '../../models/models';
 import { format } from 'date-fns';
+import { RenderMode } from '../renderModes';
+import { brailleBars } from '../../utils/braille';
 
 interface TimeAxisFormat {
   formatStr: string;
@@ -92,6 +94,23 @@ function determineTimeAxisFormat(durationMs: number): TimeAxisFormat {
   }
 }
 
+export const OVERVIEW_ROW_NAME = 'All projects';
+
+// Aggregate every project's events into one synthetic timeline for the overview row, or null
+// when there is nothing to aggregate
+export function createOverviewTimeline(timelines: Timeline[]): Timeline | null {
+  if (timelines.length === 0) return null;
+
+  return {
+    projectName: OVERVIEW_ROW_NAME,
+    events: timelines.flatMap(t => t.events),
+    eventCount: timelines.reduce((sum, t) => sum + t.eventCount, 0),
+    activeDuration: timelines.reduce((sum, t) => sum + t.activeDuration, 0),
+    startTime: new Date(Math.min(...timelines.map(t => t.startTime.getTime()))),
+    endTime: new Date(Math.max(...timelines.map(t => t.endTime.getTime()))),
+  };
+}
+
 // Calculate optimal project column width
 export function calculateProjectWidth(timelines: Timeline[]): number {
   const minWidth = 20;
@@ -111,8 +130,18 @@ export function calculateProjectWidth(timelines: Timeline[]): number {
   return Math.max(minWidth, Math.min(maxWidth, calculatedWidth));
 }
 
+export interface TimeAxisLayout {
+  axis: string;
+  // Timeline cell positions of the labelled ticks, for aligning gridlines
+  ticks: number[];
+}
+
 // Create time axis with tick marks
 export function createTimeAxis(startTime: Date, endTime: Date, width: number): string {
+  return layoutTimeAxis(startTime, endTime, width).axis;
+}
+
+export function layoutTimeAxis(startTime: Date, endTime: Date, width: number): TimeAxisLayout {
   const duration = endTime.getTime() - startTime.getTime();
   const axisChars = new Array(width).fill(' ');
 
@@ -194,5 +223,266 @@ export function createTimeAxis(startTime: Date, endTime: Date, width: number): s
     }
   }
 
-  return axisChars.join('');
+  return {
+    axis: axisChars.join(''),
+    ticks: filteredLabels.map(({ position }) => position),
+  };
+}
+
+// Allocate an instant to one of `width` equal buckets spanning [startMs, endMs].
+// Buckets are half-open except the final one, which also covers endMs itself.
+// Returns -1 for instants outside the range.
+export function getBucketIndex(
+  timeMs: number,
+  startMs: number,
+  endMs: number,
+  width: number
+): number {
+  if (timeMs < startMs || timeMs > endMs || width <= 0) return -1;
+  if (timeMs === endMs || endMs === startMs) return width - 1;
+
+  // Integer floor division keeps bucket boundaries exact and evenly sized
+  return Math.floor(((timeMs - startMs) * width) / (endMs - startMs));
+}
+
+// Calculate density level per timeline cell (0 = no activity, 1-4 = relative density)
+export function calculateActivityLevels(
+  events: Event[],
+  startTime: Date,
+  endTime: Date,
+  width: number
+): number[] {
+  const startMs = startTime.getTime();
+  const endMs = endTime.getTime();
+  const activityCounts = new Array(width).fill(0);
+
+  // Count events per bucket, ignoring events outside the displayed range
+  for (const event of events) {
+    const bucket = getBucketIndex(new Date(event.timestamp).getTime(), startMs, endMs, width);
+    if (bucket >= 0) {
+      activityCounts[bucket]++;
+    }
+  }
+
+  // Find max activity for normalization
+  const maxActivity = Math.max(...activityCounts, 1);
+
+  return activityCounts.map(count =>
+    count === 0 ? 0 : Math.min(4, Math.floor((count / maxActivity) * 4) + 1)
+  );
+}
+
+export interface TimelineCell {
+  char: string;
+  level: number;
+  // Two columns wide and colored by itself, so drawn as is; the cell after it is left empty
+  wide?: boolean;
+}
+
+const BLOCK_CHAR = '■';
+
+// Indexed by (left half active ? 1 : 0) + (right half active ? 2 : 0)
+const HALF_BLOCK_CHARS = [BLOCK_CHAR, '▌', '▐', '█'];
+
+// Bottom row of braille dots, dimmed like the empty block, keeps the row visible
+const BRAILLE_EMPTY_CHAR = brailleBars(1, 1);
+
+// Density levels 0-4 as emoji, which keep their colors when pasted into chat or comments
+export const EMOJI_CHARS = ['⬜', '🟩', '🟨', '🟧', '🟥'];
+
+// Build the characters and density levels for a timeline row in the given render mode
+export function calculateTimelineCells(
+  events: Event[],
+  startTime: Date,
+  endTime: Date,
+  width: number,
+  mode: RenderMode = 'block'
+): TimelineCell[] {
+  if (mode === 'emoji') {
+    // Each emoji spans two columns, i.e. two cells, and carries the level of that whole span
+    const levels = calculateActivityLevels(events, startTime, endTime, Math.floor(width / 2));
+
+    return Array.from({ length: width }, (_, i) => {
+      const level = levels[Math.floor(i / 2)];
+      // An odd width leaves a last column no emoji fits in
+      if (level === undefined) return { char: ' ', level: 0 };
+      return i % 2 === 0 ? { char: EMOJI_CHARS[level], level, wide: true } : { char: '', level };
+    });
+  }
+
+  if (mode === 'braille') {
+    // Two sub-buckets per character, each drawn as a bar as high as its density level
+    const levels = calculateActivityLevels(events, startTime, endTime, width * 2);
+
+    return Array.from({ length: width }, (_, i) => {
+      const left = levels[i * 2];
+      const right = levels[i * 2 + 1];
+      return {
+        char: left === 0 && right === 0 ? BRAILLE_EMPTY_CHAR : brailleBars(left, right),
+        level: Math.max(left, right),
+      };
+    });
+  }
+
+  if (mode === 'half-block') {
+    // Two sub-buckets per character double the effective horizontal resolution
+    const levels = calculateActivityLevels(events, startTime, endTime, width * 2);
+
+    return Array.from({ length: width }, (_, i) => {
+      const left = levels[i * 2];
+      const right = levels[i * 2 + 1];
+      return {
+        char: HALF_BLOCK_CHARS[(left > 0 ? 1 : 0) + (right > 0 ? 2 : 0)],
+        level: Math.max(left, right),
+      };
+    });
+  }
+
+  return calculateActivityLevels(events, startTime, endTime, width).map(level => ({
+    char: BLOCK_CHAR,
+    level,
+  }));
+}
+
+export const WEEK_MS = 7 * 24 * 60 * 60 * 1000;
+
+// Start times of the week-long rows needed to cover a range, GitHub-calendar style
+export function calculateWeekRowStarts(startTime: Date, endTime: Date): Date[] {
+  const rowCount = Math.max(1, Math.ceil((endTime.getTime() - startTime.getTime()) / WEEK_MS));
+  return Array.from({ length: rowCount }, (_, i) => new Date(startTime.getTime() + i * WEEK_MS));
+}
+
+// Create an axis labelling each day boundary of a week-long row with its weekday
+export function createWeekdayAxis(startTime: Date, width: number): string {
+  return layoutWeekdayAxis(startTime, width).axis;
+}
+
+export function layoutWeekdayAxis(startTime: Date, width: number): TimeAxisLayout {
+  const axisChars = new Array(width).fill(' ');
+  const startMs = startTime.getTime();
+  const endMs = startMs + WEEK_MS;
+
+  const day = new Date(startTime);
+  day.setHours(0, 0, 0, 0);
+  if (day.getTime() < startMs) {
+    day.setDate(day.getDate() + 1);
+  }
+
+  const ticks: number[] = [];
+  let lastEndPos = -1;
+  while (day.getTime() < endMs) {
+    const label = format(day, 'EEE');
+    const position = getBucketIndex(day.getTime(), startMs, endMs, width);
+
+    if (position > lastEndPos && position + label.length <= width) {
+      for (let i = 0; i < label.length; i++) {
+        axisChars[position + i] = label[i];
+      }
+      lastEndPos = position + label.length;
+      ticks.push(position);
+    }
+    day.setDate(day.getDate() + 1);
+  }
+
+  return { axis: axisChars.join(''), ticks };
+}
+
+// End of the area covered by timeline rows; stacked week rows always span whole weeks
+export function calculateRowsEndTime(endTime: Date, weekRowStarts?: Date[]): Date {
+  if (!weekRowStarts || weekRowStarts.length === 0) return endTime;
+  return new Date(weekRowStarts[weekRowStarts.length - 1].getTime() + WEEK_MS);
+}
+
+export const NOW_MARKER_CHAR = '▼';
+
+// Mark the current time on the axis where it doesn't overwrite a label
+export function placeNowMarker(axis: string, nowPosition?: number): string {
+  if (nowPosition === undefined || nowPosition < 0 || nowPosition >= axis.length) return axis;
+  if (axis[nowPosition] !== ' ') return axis;
+  return axis.slice(0, nowPosition) + NOW_MARKER_CHAR + axis.slice(nowPosition + 1);
+}
+
+export const ANNOTATION_MARKER_CHAR = '◆';
+
+// Mark annotated times on the axis, again only where nothing else is drawn
+export function placeAnnotationMarkers(axis: string, positions: number[]): string {
+  const chars = axis.split('');
+  for (const position of positions) {
+    if (chars[position] === ' ') chars[position] = ANNOTATION_MARKER_CHAR;
+  }
+  return chars.join('');
+}
+
+export interface HourRange {
+  start: number;
+  end: number;
+}
+
+export interface ShadingOptions {
+  weekends?: boolean;
+  // Off-hours window in local hours; wraps past midnight when start > end (e.g. 19-8)
+  offHours?: HourRange;
+}
+
+export const DAY_MS = 24 * 60 * 60 * 1000;
+
+// Parse an hour range such as "19-8" or "0-9"
+export function parseHourRange(value: string): HourRange | null {
+  const match = value.match(/^(\d{1,2})-(\d{1,2})$/);
+  if (!match) return null;
+
+  const start = parseInt(match[1]);
+  const end = parseInt(match[2]);
+  if (start > 23 || end > 24 || start === end) return null;
+
+  return { start, end };
+}
+
+export function isWithinHourRange(hour: number, range: HourRange): boolean {
+  return range.start < range.end
+    ? hour >= range.start && hour < range.end
+    : hour >= range.start || hour < range.end;
+}
+
+// Determine which timeline cells fall on weekends or off-hours, judged at each cell's midpoint
+export function calculateShadedCells(
+  startTime: Date,
+  endTime: Date,
+  width: number,
+  options: ShadingOptions
+): boolean[] {
+  const startMs = startTime.getTime();
+  const cellMs = (endTime.getTime() - startMs) / width;
+  // Weekend shading only makes sense when cells are a fraction of a day across several days
+  const shadeWeekends = options.weekends && endTime.getTime() - startMs > DAY_MS;
+
+  return Array.from({ length: width }, (_, i) => {
+    const midpoint = new Date(startMs + (i + 0.5) * cellMs);
+    const day = midpoint.getDay();
+
+    if (shadeWeekends && (day === 0 || day === 6)) return true;
+    if (options.offHours && isWithinHourRange(midpoint.getHours(), options.offHours)) return true;
+    return false;
+  });
+}
+
+// Split text into runs of equal shading so each run can be styled once
+export function splitShadedSegments(
+  text: string,
+  shaded: boolean[]
+): Array<{ text: string; shaded: boolean }> {
+  const segments: Array<{ text: string; shaded: boolean }> = [];
+
+  for (let i = 0; i < text.length; i++) {
+    const isShaded = shaded[i] ?? false;
+    const last = segments[segments.length - 1];
+
+    if (last && last.shaded === isShaded) {
+      last.text += text[i];
+    } else {
+      segments.push({ text: text[i], shaded: isShaded });
+    }
+  }
+
+  return segments;
 }
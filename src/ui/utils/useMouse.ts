@@ -0,0 +1,50 @@
+import { useEffect } from 'react';
+import { useInput, useStdout } from 'ink';
+
+// SGR extended mouse reporting: button presses and the wheel, with 1-based coordinates
+const ENABLE_MOUSE = '\u001B[?1000h\u001B[?1006h';
+const DISABLE_MOUSE = '\u001B[?1000l\u001B[?1006l';
+// Ink strips the leading escape character before handing input over
+const MOUSE_SEQUENCE = /\u001B?\[<(\d+);(\d+);(\d+)([Mm])/g;
+
+const WHEEL_UP = 64;
+const WHEEL_DOWN = 65;
+
+export type MouseAction =
+  | { type: 'click'; x: number; y: number }
+  | { type: 'wheel'; direction: 'up' | 'down' };
+
+// Terminals may batch several reports into one read
+export function parseMouseInput(input: string): MouseAction[] {
+  const actions: MouseAction[] = [];
+  for (const [, button, x, y, state] of input.matchAll(MOUSE_SEQUENCE)) {
+    const code = parseInt(button);
+    if (code === WHEEL_UP || code === WHEEL_DOWN) {
+      actions.push({ type: 'wheel', direction: code === WHEEL_UP ? 'up' : 'down' });
+    } else if (code === 0 && state === 'M') {
+      actions.push({ type: 'click', x: parseInt(x), y: parseInt(y) });
+    }
+  }
+  return actions;
+}
+
+// Report left clicks and wheel turns while active; other buttons and releases are ignored
+export function useMouse(handler: (action: MouseAction) => void, isActive: boolean): void {
+  const { stdout } = useStdout();
+
+  useEffect(() => {
+    if (!isActive || !stdout) return;
+
+    stdout.write(ENABLE_MOUSE);
+    return () => {
+      stdout.write(DISABLE_MOUSE);
+    };
+  }, [stdout, isActive]);
+
+  useInput(
+    input => {
+      for (const action of parseMouseInput(input)) handler(action);
+    },
+    { isActive }
+  );
+}
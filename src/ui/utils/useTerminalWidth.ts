@@ -0,0 +1,20 @@
+import { useEffect, useState } from 'react';
+import { useStdout } from 'ink';
+
+// Terminal width that follows resizes, so views that stay open re-layout instead of wrapping
+export function useTerminalWidth(fallback = 80): number {
+  const { stdout } = useStdout();
+  const [columns, setColumns] = useState(stdout?.columns || fallback);
+
+  useEffect(() => {
+    if (!stdout) return;
+
+    const handleResize = () => setColumns(stdout.columns || fallback);
+    stdout.on('resize', handleResize);
+    return () => {
+      stdout.off('resize', handleResize);
+    };
+  }, [stdout, fallback]);
+
+  return columns;
+}
@@ -0,0 +1,96 @@
+import { writeFile } from 'fs/promises';
+import { join } from 'path';
+import { format } from 'date-fns';
+import { Timeline } from '../../models/models';
+
+export const VIEW_EXPORT_FORMAT_VALUES = ['text', 'json', 'markdown'] as const;
+export type ViewExportFormat = (typeof VIEW_EXPORT_FORMAT_VALUES)[number];
+
+const FILE_EXTENSIONS: Record<ViewExportFormat, string> = {
+  text: 'txt',
+  json: 'json',
+  markdown: 'md',
+};
+
+// The rows currently on screen, after filtering, grouping and sorting
+export interface TableView {
+  startTime: Date;
+  endTime: Date;
+  timelines: Timeline[];
+}
+
+const formatTime = (date: Date) => format(date, 'yyyy-MM-dd HH:mm');
+
+function formatText(view: TableView): string {
+  const nameWidth = Math.max(...view.timelines.map(t => t.projectName.length), 7);
+  const lines = [
+    `ccstat ${formatTime(view.startTime)} - ${formatTime(view.endTime)}`,
+    '',
+    `${'Project'.padEnd(nameWidth)}${'Events'.padStart(8)}${'Duration'.padStart(10)}`,
+  ];
+  for (const timeline of view.timelines) {
+    lines.push(
+      `${timeline.projectName.padEnd(nameWidth)}${`${timeline.eventCount}`.padStart(8)}` +
+        `${`${timeline.activeDuration}m`.padStart(10)}`
+    );
+  }
+  return lines.join('\n') + '\n';
+}
+
+function formatMarkdown(view: TableView): string {
+  const lines = [
+    `# ccstat ${formatTime(view.startTime)} - ${formatTime(view.endTime)}`,
+    '',
+    '| Project | Events | Duration | Last activity |',
+    '| --- | ---: | ---: | --- |',
+  ];
+  for (const timeline of view.timelines) {
+    const name = timeline.projectName.replace(/\|/g, '\\|');
+    lines.push(
+      `| ${name} | ${timeline.eventCount} | ${timeline.activeDuration}m | ` +
+        `${formatTime(timeline.endTime)} |`
+    );
+  }
+  return lines.join('\n') + '\n';
+}
+
+function formatJson(view: TableView): string {
+  const data = {
+    range: { start: view.startTime.toISOString(), end: view.endTime.toISOString() },
+    projects: view.timelines.map(timeline => ({
+      id: timeline.projectId,
+      name: timeline.projectName,
+      events: timeline.eventCount,
+      duration: timeline.activeDuration,
+      firstActivity: timeline.startTime.toISOString(),
+      lastActivity: timeline.endTime.toISOString(),
+    })),
+  };
+  return JSON.stringify(data, null, 2) + '\n';
+}
+
+export function formatView(view: TableView, viewFormat: ViewExportFormat): string {
+  switch (viewFormat) {
+    case 'json':
+      return formatJson(view);
+    case 'markdown':
+      return formatMarkdown(view);
+    default:
+      return formatText(view);
+  }
+}
+
+// Write the view to a timestamped file in the directory and return its path
+export async function writeViewExport(
+  view: TableView,
+  viewFormat: ViewExportFormat,
+  directory: string,
+  now = new Date()
+): Promise<string> {
+  const path = join(
+    directory,
+    `ccstat-${format(now, 'yyyyMMdd-HHmmss')}.${FILE_EXTENSIONS[viewFormat]}`
+  );
+  await writeFile(path, formatView(view, viewFormat));
+  return path;
+}
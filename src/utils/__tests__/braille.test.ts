@@ -0,0 +1,19 @@
+import { brailleBars, brailleSparkline } from '../braille';
+
+describe('braille', () => {
+  it('should raise bars from the bottom of each dot column', () => {
+    expect(brailleBars(0, 0)).toBe('⠀');
+    expect(brailleBars(1, 0)).toBe('⡀');
+    expect(brailleBars(0, 1)).toBe('⢀');
+    expect(brailleBars(4, 2)).toBe('⣧');
+    expect(brailleBars(4, 4)).toBe('⣿');
+    // Heights above four are capped
+    expect(brailleBars(9, 0)).toBe('⡇');
+  });
+
+  it('should pack two values into each sparkline character', () => {
+    expect(brailleSparkline([0, 8, 4, 2, 1])).toBe('⢸⣄⡀');
+    expect(brailleSparkline([2, 2], 8)).toBe('⣀');
+    expect(brailleSparkline([0, 0])).toBe('⠀');
+  });
+});
@@ -0,0 +1,52 @@
+import { createSingleflight, mapWithConcurrency } from '../concurrency';
+
+const tick = () => new Promise(resolve => setTimeout(resolve, 1));
+
+describe('mapWithConcurrency', () => {
+  it('should keep results in input order', async () => {
+    const results = await mapWithConcurrency([30, 10, 20], 2, async value => {
+      await new Promise(resolve => setTimeout(resolve, value));
+      return value * 2;
+    });
+    expect(results).toEqual([60, 20, 40]);
+  });
+
+  it('should not exceed the limit', async () => {
+    let running = 0;
+    let peak = 0;
+    await mapWithConcurrency(Array.from({ length: 10 }, (_, i) => i), 3, async () => {
+      running++;
+      peak = Math.max(peak, running);
+      await tick();
+      running--;
+    });
+    expect(peak).toBe(3);
+  });
+
+  it('should handle an empty list', async () => {
+    expect(await mapWithConcurrency([], 4, async value => value)).toEqual([]);
+  });
+});
+
+describe('createSingleflight', () => {
+  it('should share one call between concurrent callers of the same key', async () => {
+    const singleflight = createSingleflight<string, number>();
+    const fn = jest.fn(async () => {
+      await tick();
+      return 42;
+    });
+
+    const results = await Promise.all([singleflight('a', fn), singleflight('a', fn)]);
+    expect(results).toEqual([42, 42]);
+    expect(fn).toHaveBeenCalledTimes(1);
+  });
+
+  it('should call again once the previous call has settled', async () => {
+    const singleflight = createSingleflight<string, number>();
+    const fn = jest.fn(async () => 1);
+
+    await singleflight('a', fn);
+    await singleflight('a', fn);
+    expect(fn).toHaveBeenCalledTimes(2);
+  });
+});
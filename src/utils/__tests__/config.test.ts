@@ -0,0 +1,166 @@
+import { mkdtemp, rm, writeFile } from 'fs/promises';
+import { tmpdir } from 'os';
+import { delimiter, join } from 'path';
+import {
+  applyEnvOverrides,
+  getConfigPath,
+  loadConfig,
+  resolveProfile,
+  ENV_VARIABLES,
+  getEnvName,
+  resolveView,
+  saveConfig,
+} from '../config';
+import { CONFIG_KEYS } from '../configKeys';
+
+describe('config', () => {
+  let dir: string;
+
+  beforeEach(async () => {
+    dir = await mkdtemp(join(tmpdir(), 'ccstat-config-'));
+  });
+
+  afterEach(async () => {
+    await rm(dir, { recursive: true, force: true });
+  });
+
+  it('should follow XDG_CONFIG_HOME', () => {
+    const original = process.env.XDG_CONFIG_HOME;
+    process.env.XDG_CONFIG_HOME = '/tmp/xdg';
+    try {
+      expect(getConfigPath()).toBe('/tmp/xdg/ccstat/config.json');
+    } finally {
+      if (original === undefined) delete process.env.XDG_CONFIG_HOME;
+      else process.env.XDG_CONFIG_HOME = original;
+    }
+  });
+
+  it('should treat a missing file as empty', async () => {
+    expect(await loadConfig(join(dir, 'missing.json'))).toEqual({});
+  });
+
+  it('should round-trip settings and keep unknown keys', async () => {
+    const configPath = join(dir, 'nested', 'config.json');
+    await saveConfig({ pinned: ['web'], futureSetting: true }, configPath);
+
+    expect(await loadConfig(configPath)).toEqual({ pinned: ['web'], futureSetting: true });
+  });
+
+  it('should name the file in validation errors', async () => {
+    const configPath = join(dir, 'config.json');
+    await writeFile(configPath, '{"pinned": "web"}');
+
+    await expect(loadConfig(configPath)).rejects.toThrow(`${configPath}: pinned`);
+  });
+
+  describe('resolveProfile', () => {
+    const config = {
+      ignore: ['/tmp/**'],
+      color: 'ocean',
+      profiles: {
+        work: { logDirs: ['~/work-claude/projects'], color: 'sunset' },
+      },
+    };
+
+    it('should use the base settings without a profile', () => {
+      expect(resolveProfile(config)).toEqual({ ignore: ['/tmp/**'], color: 'ocean' });
+    });
+
+    it('should override base settings with the profile', () => {
+      expect(resolveProfile(config, 'work')).toEqual({
+        ignore: ['/tmp/**'],
+        color: 'sunset',
+        logDirs: ['~/work-claude/projects'],
+      });
+    });
+
+    it('should list available profiles for unknown names', () => {
+      expect(() => resolveProfile(config, 'personal')).toThrow(
+        "Unknown profile 'personal'. Available profiles: work"
+      );
+    });
+  });
+
+  describe('resolveView', () => {
+    const config = {
+      views: { 'weekly-client-report': ['--days', '7', '--tag', 'client:acme'] },
+    };
+
+    it('should return the saved arguments', () => {
+      expect(resolveView(config, 'weekly-client-report')).toEqual([
+        '--days',
+        '7',
+        '--tag',
+        'client:acme',
+      ]);
+    });
+
+    it('should list available views for unknown names', () => {
+      expect(() => resolveView(config, 'monthly')).toThrow(
+        "Unknown view 'monthly'. Available views: weekly-client-report"
+      );
+    });
+  });
+
+  describe('applyEnvOverrides', () => {
+    it('should override settings from CCSTAT_* variables', () => {
+      const settings = applyEnvOverrides(
+        { color: 'forest', ignore: ['/tmp/**'] },
+        {
+          CCSTAT_COLOR: 'ocean',
+          CCSTAT_PINNED: 'web, api',
+          CCSTAT_TAGS: '{"web":["client:acme"]}',
+          CCSTAT_LOG_DIRS: ['/a', '/b'].join(delimiter),
+          CCSTAT_IGNORE: '',
+        }
+      );
+
+      expect(settings).toEqual({
+        color: 'ocean',
+        ignore: ['/tmp/**'],
+        pinned: ['web', 'api'],
+        tags: { web: ['client:acme'] },
+        logDirs: ['/a', '/b'],
+      });
+    });
+
+    it('should read nested settings, numbers and booleans', () => {
+      const settings = applyEnvOverrides(
+        { duration: { algorithm: 'events' } },
+        {
+          CCSTAT_DURATION_IDLE_MINUTES: '10',
+          CCSTAT_USER_EVENTS_ONLY: 'true',
+          CCSTAT_WORKING_HOURS_START: '08:00',
+        }
+      );
+
+      expect(settings).toEqual({
+        duration: { algorithm: 'events', idleMinutes: 10 },
+        userEventsOnly: true,
+        workingHours: { start: '08:00' },
+      });
+      expect(() => applyEnvOverrides({}, { CCSTAT_STORE: 'yes' })).toThrow('CCSTAT_STORE:');
+    });
+
+    it('should have a variable for every setting', () => {
+      for (const { key } of CONFIG_KEYS) {
+        expect(ENV_VARIABLES).toContain(getEnvName(key.split('.')));
+      }
+    });
+
+    it('should read schedules as JSON so cron fields keep their commas', () => {
+      const settings = applyEnvOverrides({}, { CCSTAT_SCHEDULE: '["0 9,18 * * 1-5 summary"]' });
+
+      expect(settings).toEqual({ schedule: ['0 9,18 * * 1-5 summary'] });
+    });
+
+    it('should name the variable in errors', () => {
+      expect(() => applyEnvOverrides({}, { CCSTAT_TAGS: '{' })).toThrow(
+        'CCSTAT_TAGS: not valid JSON'
+      );
+      expect(() => applyEnvOverrides({}, { CCSTAT_TAGS: '{"web":"acme"}' })).toThrow(
+        'CCSTAT_TAGS:'
+      );
+    });
+  });
+});
@@ -0,0 +1,99 @@
+import { mkdtemp, rm } from 'fs/promises';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import {
+  getConfigValue,
+  listConfigValues,
+  parseConfigValue,
+  resolveConfigKey,
+  setConfigValue,
+  validateConfig,
+} from '../configKeys';
+
+const parse = (key: string, text: string) =>
+  parseConfigValue(resolveConfigKey(key).configKey, text);
+
+describe('configKeys', () => {
+  it('should resolve settings, also inside a profile', () => {
+    expect(resolveConfigKey('duration.idleMinutes').path).toEqual(['duration', 'idleMinutes']);
+    expect(resolveConfigKey('profiles.work.color')).toMatchObject({
+      configKey: { key: 'color' },
+      path: ['profiles', 'work', 'color'],
+    });
+    expect(() => resolveConfigKey('colour')).toThrow("Unknown setting 'colour'");
+    expect(() => resolveConfigKey('profiles.work.colour')).toThrow('Available settings: color');
+  });
+
+  it('should parse values by type', () => {
+    expect(parse('color', 'ocean')).toBe('ocean');
+    expect(parse('duration.idleMinutes', '7.5')).toBe(7.5);
+    expect(parse('store', 'true')).toBe(true);
+    expect(parse('pinned', 'webapp, api')).toEqual(['webapp', 'api']);
+    expect(parse('ignore', '["/tmp/**", "a,b"]')).toEqual(['/tmp/**', 'a,b']);
+  });
+
+  it('should reject values the setting does not allow', () => {
+    expect(() => parse('color', 'blue')).toThrow(
+      'color: must be one of: forest, ocean, honey, sunset, violet'
+    );
+    expect(() => parse('duration.idleMinutes', 'five')).toThrow('must be a number');
+    expect(() => parse('duration.idleMinutes', '0')).toThrow('must be positive');
+    expect(() => parse('retentionDays', '1.5')).toThrow('must be a positive whole number');
+    expect(() => parse('store', 'yes')).toThrow('must be true or false');
+    expect(() => parse('workingHours.start', '25:00')).toThrow('must be HH:MM');
+    expect(() => parse('pinned', '[1, 2]')).toThrow('must be a list of strings');
+    expect(() => parse('schedule', '0 18 * * 5 lunch')).toThrow("invalid entry '0 18 * * 5 lunch'");
+  });
+
+  it('should check that log directories exist', async () => {
+    const root = await mkdtemp(join(tmpdir(), 'ccstat-config-keys-'));
+    const { configKey } = resolveConfigKey('logDirs');
+
+    try {
+      expect(parseConfigValue(configKey, root)).toEqual([root]);
+      expect(() => parseConfigValue(configKey, `${root},${join(root, 'missing')}`)).toThrow(
+        `directory not found: ${join(root, 'missing')}`
+      );
+    } finally {
+      await rm(root, { recursive: true, force: true });
+    }
+  });
+
+  it('should set and unset nested values without changing the original', () => {
+    const config = { color: 'ocean', duration: { algorithm: 'events' as const } };
+
+    const updated = setConfigValue(config, ['duration', 'idleMinutes'], 10);
+    expect(updated).toEqual({ color: 'ocean', duration: { algorithm: 'events', idleMinutes: 10 } });
+    expect(config).toEqual({ color: 'ocean', duration: { algorithm: 'events' } });
+    expect(getConfigValue(updated, ['duration', 'idleMinutes'])).toBe(10);
+
+    // Objects left empty are removed along with their last value
+    const cleared = setConfigValue(
+      setConfigValue(updated, ['duration', 'idleMinutes'], undefined),
+      ['duration', 'algorithm'],
+      undefined
+    );
+    expect(cleared).toEqual({ color: 'ocean' });
+  });
+
+  it('should validate the values of known settings in every profile', () => {
+    expect(validateConfig({ color: 'ocean', profiles: { work: { color: 'sunset' } } })).toBeNull();
+    expect(validateConfig({ profiles: { work: { color: 'blue' } } })).toBe(
+      'profiles.work.color: must be one of: forest, ocean, honey, sunset, violet'
+    );
+    expect(validateConfig({ pinned: 'webapp' } as never)).toMatch(/^pinned: /);
+  });
+
+  it('should allow setting one bound of the working hours', () => {
+    const config = setConfigValue({}, ['workingHours', 'start'], '08:00');
+
+    expect(validateConfig(config)).toBeNull();
+    expect(validateConfig(setConfigValue(config, ['workingHours', 'start'], undefined))).toBeNull();
+  });
+
+  it('should list every value set as dotted keys', () => {
+    expect(
+      listConfigValues({ color: 'ocean', pinned: ['webapp'], duration: { idleMinutes: 10 } })
+    ).toEqual(['color = ocean', 'pinned = ["webapp"]', 'duration.idleMinutes = 10']);
+  });
+});
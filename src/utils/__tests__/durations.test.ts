@@ -0,0 +1,17 @@
+import { formatDuration, formatHours } from '../durations';
+
+describe('formatHours', () => {
+  it('should show hours and zero-padded minutes', () => {
+    expect(formatHours(65)).toBe('1h 05m');
+    expect(formatHours(7)).toBe('0h 07m');
+    expect(formatHours(600)).toBe('10h 00m');
+  });
+});
+
+describe('formatDuration', () => {
+  it('should leave out the units that are zero', () => {
+    expect(formatDuration(45)).toBe('45m');
+    expect(formatDuration(65)).toBe('1h 05m');
+    expect(formatDuration(26 * 60 + 30)).toBe('1d 2h');
+  });
+});
@@ -0,0 +1,28 @@
+import { Timeline } from '../../models/models';
+import { evaluateExitCode, EXIT_NO_ACTIVITY, EXIT_OVER_BUDGET } from '../exitCodes';
+
+const createTimeline = (eventCount: number, activeDuration: number): Timeline => ({
+  projectName: 'web',
+  events: [],
+  eventCount,
+  activeDuration,
+  startTime: new Date('2025-01-01T09:00:00Z'),
+  endTime: new Date('2025-01-01T10:00:00Z'),
+});
+
+describe('evaluateExitCode', () => {
+  it('should succeed when no condition is set', () => {
+    expect(evaluateExitCode([], {})).toBe(0);
+  });
+
+  it('should fail without activity when requested', () => {
+    expect(evaluateExitCode([], { failIfNoActivity: true })).toBe(EXIT_NO_ACTIVITY);
+    expect(evaluateExitCode([createTimeline(1, 0)], { failIfNoActivity: true })).toBe(0);
+  });
+
+  it('should fail only when the budget is exceeded', () => {
+    const timelines = [createTimeline(10, 30), createTimeline(5, 31)];
+    expect(evaluateExitCode(timelines, { budgetMinutes: 60 })).toBe(EXIT_OVER_BUDGET);
+    expect(evaluateExitCode(timelines, { budgetMinutes: 61 })).toBe(0);
+  });
+});
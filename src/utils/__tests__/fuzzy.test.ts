@@ -0,0 +1,14 @@
+import { fuzzyMatch } from '../fuzzy';
+
+describe('fuzzyMatch', () => {
+  it('should match characters in order, ignoring case', () => {
+    expect(fuzzyMatch('cst', 'ccstat')).toBe(true);
+    expect(fuzzyMatch('WebA', 'my-webapp')).toBe(true);
+    expect(fuzzyMatch('tsc', 'ccstat')).toBe(false);
+  });
+
+  it('should match everything with an empty query', () => {
+    expect(fuzzyMatch('', 'ccstat')).toBe(true);
+    expect(fuzzyMatch('  ', 'ccstat')).toBe(true);
+  });
+});
@@ -0,0 +1,60 @@
+import { homedir } from 'os';
+import { join } from 'path';
+import { createTimeline } from '../../core/parser';
+import { ALL_GROUP_NAME, groupTimelines } from '../grouping';
+
+describe('groupTimelines', () => {
+  const home = homedir();
+  const timelines = [
+    createTimeline('webapp', [
+      { timestamp: '2025-01-01T09:00:00Z', sessionId: 'aaaaaaaa-1', cwd: join(home, 'webapp') },
+      { timestamp: '2025-01-01T09:02:00Z', sessionId: 'bbbbbbbb-2', cwd: join(home, 'webapp') },
+      { timestamp: '2025-01-01T09:04:00Z', sessionId: 'bbbbbbbb-2' },
+    ]),
+    createTimeline('api', [{ timestamp: '2025-01-01T10:00:00Z', cwd: '/srv/api' }]),
+  ];
+  const summarize = (grouped: ReturnType<typeof groupTimelines>) =>
+    grouped.map(timeline => [timeline.projectName, timeline.eventCount]);
+
+  it('should keep project timelines as they are', () => {
+    expect(groupTimelines(timelines, 'project', {})).toBe(timelines);
+  });
+
+  it('should split projects per working directory', () => {
+    expect(summarize(groupTimelines(timelines, 'directory', {}))).toEqual([
+      ['~/webapp', 2],
+      ['webapp', 1],
+      ['/srv/api', 1],
+    ]);
+  });
+
+  it('should split projects per session', () => {
+    expect(summarize(groupTimelines(timelines, 'session', {}))).toEqual([
+      ['webapp:aaaaaaaa', 1],
+      ['webapp:bbbbbbbb', 2],
+      ['api', 1],
+    ]);
+  });
+
+  it('should name worktrees after their directory within the project', () => {
+    expect(summarize(groupTimelines(timelines, 'worktree', {}))).toEqual([
+      ['webapp', 3],
+      ['api', 1],
+    ]);
+  });
+
+  it('should split activity into interactive and agentic sessions', () => {
+    const subagent = createTimeline('api', [
+      { timestamp: '2025-01-01T11:00:00Z', sessionId: 'cccccccc-3', isSidechain: true },
+    ]);
+    expect(summarize(groupTimelines([...timelines, subagent], 'mode', {}))).toEqual([
+      ['interactive', 3],
+      ['api', 1],
+      ['agentic', 1],
+    ]);
+  });
+
+  it('should combine everything into one row without grouping', () => {
+    expect(summarize(groupTimelines(timelines, 'none', {}))).toEqual([[ALL_GROUP_NAME, 4]]);
+  });
+});
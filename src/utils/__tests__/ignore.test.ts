@@ -0,0 +1,28 @@
+import { homedir } from 'os';
+import { createIgnoreMatcher, globToRegExp } from '../ignore';
+
+describe('ignore patterns', () => {
+  it('should match a directory and everything below it', () => {
+    const matcher = createIgnoreMatcher(['/tmp']);
+    expect(matcher('/tmp')).toBe(true);
+    expect(matcher('/tmp/scratch/a')).toBe(true);
+    expect(matcher('/tmpfiles')).toBe(false);
+  });
+
+  it('should support ** across directories', () => {
+    expect(globToRegExp('/tmp/**').test('/tmp/a/b')).toBe(true);
+    expect(globToRegExp('**/.dotfiles').test('/home/user/.dotfiles')).toBe(true);
+    expect(globToRegExp('**/.dotfiles').test('/home/user/dotfiles')).toBe(false);
+  });
+
+  it('should keep * and ? within one directory', () => {
+    const regexp = globToRegExp('/home/*/experiments-?');
+    expect(regexp.test('/home/user/experiments-1')).toBe(true);
+    expect(regexp.test('/home/user/nested/experiments-1')).toBe(false);
+  });
+
+  it('should expand ~ and escape regular expression characters', () => {
+    expect(globToRegExp('~/sandbox').test(`${homedir()}/sandbox/x`)).toBe(true);
+    expect(globToRegExp('/work/a.b').test('/work/aXb')).toBe(false);
+  });
+});
@@ -0,0 +1,31 @@
+import { inflateSync } from 'zlib';
+import { crc32, encodePng } from '../png';
+
+describe('png', () => {
+  it('should compute CRC-32 check values', () => {
+    expect(crc32(Buffer.from('123456789'))).toBe(0xcbf43926);
+    expect(crc32(Buffer.from('IEND'))).toBe(0xae426082);
+  });
+
+  it('should encode RGBA pixels with a filter byte per row', () => {
+    const pixels = Buffer.from([255, 0, 0, 255, 0, 255, 0, 128]);
+    const png = encodePng(1, 2, pixels);
+
+    expect(png.subarray(0, 8)).toEqual(Buffer.from([137, 80, 78, 71, 13, 10, 26, 10]));
+    expect(png.subarray(12, 16).toString('ascii')).toBe('IHDR');
+    expect(png.readUInt32BE(16)).toBe(1);
+    expect(png.readUInt32BE(20)).toBe(2);
+    expect(png.subarray(24, 26)).toEqual(Buffer.from([8, 6]));
+
+    const idatLength = png.readUInt32BE(33);
+    expect(png.subarray(37, 41).toString('ascii')).toBe('IDAT');
+    expect(inflateSync(png.subarray(41, 41 + idatLength))).toEqual(
+      Buffer.from([0, 255, 0, 0, 255, 0, 0, 255, 0, 128])
+    );
+    expect(png.subarray(png.length - 8, png.length - 4).toString('ascii')).toBe('IEND');
+  });
+
+  it('should reject pixel data of the wrong size', () => {
+    expect(() => encodePng(2, 2, Buffer.alloc(4))).toThrow('Expected 16 bytes of pixels, got 4');
+  });
+});
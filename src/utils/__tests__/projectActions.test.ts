@@ -0,0 +1,72 @@
+import { Timeline } from '../../models/models';
+import {
+  findSessionLogFiles,
+  getDirectoryBreakdown,
+  getEditorCommand,
+  getLatestSessionId,
+  getProjectDirectory,
+  getResumeCommand,
+} from '../projectActions';
+
+const timeline: Timeline = {
+  projectName: 'web',
+  events: [
+    { timestamp: '2025-01-01T09:00:00.000Z', sessionId: 'old', cwd: '/home/user/web' },
+    { timestamp: '2025-01-01T10:00:00.000Z', sessionId: 'new', cwd: '/home/user/web/packages/ui' },
+    { timestamp: '2025-01-01T10:01:00.000Z' },
+  ],
+  eventCount: 3,
+  activeDuration: 2,
+  startTime: new Date('2025-01-01T09:00:00.000Z'),
+  endTime: new Date('2025-01-01T10:01:00.000Z'),
+};
+
+describe('project actions', () => {
+  it('should use the most recent working directory and session', () => {
+    expect(getProjectDirectory(timeline)).toBe('/home/user/web/packages/ui');
+    expect(getLatestSessionId(timeline)).toBe('new');
+  });
+
+  it('should return null without events', () => {
+    expect(getProjectDirectory({ ...timeline, events: [] })).toBeNull();
+    expect(getLatestSessionId({ ...timeline, events: [] })).toBeNull();
+  });
+
+  it('should count events per working directory, busiest first', () => {
+    const events = [
+      ...timeline.events,
+      { timestamp: '2025-01-01T10:02:00.000Z', cwd: '/home/user/web/packages/ui' },
+    ];
+    expect(getDirectoryBreakdown({ ...timeline, events })).toEqual([
+      { path: '/home/user/web/packages/ui', events: 2 },
+      { path: '/home/user/web', events: 1 },
+      { path: null, events: 1 },
+    ]);
+  });
+
+  it('should find session logs by file name', () => {
+    const files = [
+      '/home/user/.claude/projects/-home-user-web/new.jsonl',
+      '/home/user/.claude/projects/-home-user-web/old.jsonl',
+      '/home/user/.claude/projects/-home-user-api/renewed.jsonl',
+    ];
+    expect(findSessionLogFiles('new', files)).toEqual([files[0]]);
+  });
+
+  it('should build resume commands that cd into the project first', () => {
+    expect(getResumeCommand('abc')).toBe('claude --resume abc');
+    expect(getResumeCommand('abc', '/home/user/my web')).toBe(
+      "cd '/home/user/my web' && claude --resume abc"
+    );
+  });
+
+  it('should quote paths so the shell runs nothing in them', () => {
+    expect(getEditorCommand('/home/user/$(rm -rf ~)`id`$HOME', 'code -w', 'linux')).toBe(
+      "code -w '/home/user/$(rm -rf ~)`id`$HOME'"
+    );
+    expect(getEditorCommand("/home/user/it's", 'vi', 'darwin')).toBe("vi '/home/user/it'\\''s'");
+    expect(getEditorCommand('C:\\Users\\me\\web', 'notepad', 'win32')).toBe(
+      'notepad "C:\\Users\\me\\web"'
+    );
+  });
+});
@@ -0,0 +1,30 @@
+import { getBareProjectName, matchesProjectName } from '../projectNames';
+import { pinTimelines } from '../sort';
+import { getProjectTags } from '../tags';
+import { Timeline } from '../../models/models';
+
+describe('project names', () => {
+  it('should strip the owner or id a shared name was qualified with', () => {
+    expect(getBareProjectName('org-a/api')).toBe('api');
+    expect(getBareProjectName('api (3f2a1b)')).toBe('api');
+    expect(getBareProjectName('webapp')).toBe('webapp');
+  });
+
+  it('should match both the qualified and the bare name', () => {
+    expect(matchesProjectName(['org-a/api'], 'org-a/api')).toBe(true);
+    expect(matchesProjectName(['api'], 'org-a/api')).toBe(true);
+    expect(matchesProjectName(['org-b/api'], 'org-a/api')).toBe(false);
+    expect(matchesProjectName(['web'], 'webapp')).toBe(false);
+  });
+
+  it('should keep pins and tags written before names were qualified', () => {
+    const timeline = (projectName: string) => ({ projectName }) as Timeline;
+    const pinned = pinTimelines([timeline('web'), timeline('org-a/api')], ['api']);
+
+    expect(pinned.map(t => t.projectName)).toEqual(['org-a/api', 'web']);
+    expect(getProjectTags({ api: ['client:acme'] }, 'org-a/api')).toEqual(['client:acme']);
+    expect(getProjectTags({ 'org-b/api': ['oss'], api: ['client:acme'] }, 'org-b/api')).toEqual([
+      'oss',
+    ]);
+  });
+});
@@ -0,0 +1,71 @@
+import { Timeline } from '../../models/models';
+import { getLastPrompt, getPromptText, redactPrompt, truncateText } from '../prompts';
+
+const timestamp = '2025-01-01T10:00:00.000Z';
+
+describe('getPromptText', () => {
+  it('should read string and text-part content of user messages', () => {
+    expect(getPromptText({ timestamp, type: 'user', message: { content: 'fix the bug' } })).toBe(
+      'fix the bug'
+    );
+    expect(
+      getPromptText({
+        timestamp,
+        type: 'user',
+        message: { content: [{ type: 'text', text: 'add tests' }] },
+      })
+    ).toBe('add tests');
+  });
+
+  it('should ignore tool results and assistant messages', () => {
+    expect(
+      getPromptText({
+        timestamp,
+        type: 'user',
+        message: { content: [{ type: 'tool_result', content: 'ok' }] },
+      })
+    ).toBeNull();
+    expect(
+      getPromptText({ timestamp, type: 'assistant', message: { content: 'done' } })
+    ).toBeNull();
+  });
+});
+
+describe('redactPrompt', () => {
+  it('should mask secrets and email addresses', () => {
+    expect(redactPrompt('use key sk-abcdefghijklmnop1234 now')).toBe('use key [redacted] now');
+    expect(redactPrompt('set API_KEY=hunter2 please')).toBe('set [redacted] please');
+    expect(redactPrompt('mail me at dev@example.com')).toBe('mail me at [redacted]');
+  });
+
+  it('should collapse whitespace onto one line', () => {
+    expect(redactPrompt('  first line\n\nsecond\tline ')).toBe('first line second line');
+  });
+});
+
+describe('getLastPrompt', () => {
+  it('should return the most recent user prompt', () => {
+    const timeline: Timeline = {
+      projectName: 'web',
+      events: [
+        { timestamp, type: 'user', message: { content: 'first' } },
+        { timestamp, type: 'user', message: { content: 'second' } },
+        { timestamp, type: 'assistant', message: { content: 'reply' } },
+      ],
+      eventCount: 3,
+      activeDuration: 1,
+      startTime: new Date(timestamp),
+      endTime: new Date(timestamp),
+    };
+
+    expect(getLastPrompt(timeline)).toBe('second');
+    expect(getLastPrompt({ ...timeline, events: [] })).toBeNull();
+  });
+});
+
+describe('truncateText', () => {
+  it('should add an ellipsis only when needed', () => {
+    expect(truncateText('short', 10)).toBe('short');
+    expect(truncateText('a longer prompt', 8)).toBe('a longe…');
+  });
+});
@@ -0,0 +1,52 @@
+import { createTimeline } from '../../core/parser';
+import { Event } from '../../models/models';
+import { classifySession, filterTimelinesBySessionMode } from '../sessionModes';
+
+const event = (minute: number, extra: Partial<Event> = {}): Event => ({
+  timestamp: new Date(Date.UTC(2025, 0, 1, 10, minute)).toISOString(),
+  ...extra,
+});
+
+const prompt = (minute: number, sessionId: string) =>
+  event(minute, { sessionId, type: 'user', message: { content: 'Fix the build' } });
+
+// One prompt followed by a long run of assistant turns and tool results
+const agentRun = (sessionId: string): Event[] => [
+  prompt(0, sessionId),
+  ...Array.from({ length: 60 }, (_, i) => event(i % 60, { sessionId, type: 'assistant' })),
+];
+
+describe('classifySession', () => {
+  it('should treat a session steered by frequent prompts as interactive', () => {
+    const events = [prompt(0, 's'), event(1, { type: 'assistant' }), prompt(2, 's')];
+    expect(classifySession(events)).toBe('interactive');
+  });
+
+  it('should treat long runs after a single prompt as agentic', () => {
+    expect(classifySession(agentRun('s'))).toBe('agentic');
+  });
+
+  it('should treat sessions mostly logged by subagents as agentic', () => {
+    const subagent = { sessionId: 's', isSidechain: true };
+    const events = [prompt(0, 's'), event(1, subagent), event(2, subagent)];
+    expect(classifySession(events)).toBe('agentic');
+  });
+});
+
+describe('filterTimelinesBySessionMode', () => {
+  const timelines = [
+    createTimeline('webapp', [prompt(0, 'a'), prompt(5, 'a'), ...agentRun('b')]),
+    createTimeline('api', [prompt(10, 'c')]),
+  ];
+
+  it('should keep only events from sessions of the mode', () => {
+    const agentic = filterTimelinesBySessionMode(timelines, 'agentic');
+    expect(agentic.map(t => [t.projectName, t.eventCount])).toEqual([['webapp', 61]]);
+
+    const interactive = filterTimelinesBySessionMode(timelines, 'interactive');
+    expect(interactive.map(t => [t.projectName, t.eventCount])).toEqual([
+      ['webapp', 2],
+      ['api', 1],
+    ]);
+  });
+});
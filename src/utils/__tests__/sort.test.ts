@@ -1,4 +1,11 @@
-import { createSortOptions, sortTimelines, SortField, SortOrder } from '../sort';
+import {
+  createSortOptions,
+  getHotScore,
+  sortTimelines,
+  pinTimelines,
+  SortField,
+  SortOrder,
+} from '../sort';
 import { Timeline } from '../../models/models';
 
 // Mock session timeline data for testing
@@ -57,7 +64,7 @@ describe('sort utilities', () => {
     });
 
     it('should handle all valid sort fields', () => {
-      const validFields: SortField[] = ['project', 'timeline', 'events', 'duration'];
+      const validFields: SortField[] = ['project', 'timeline', 'events', 'duration', 'hot'];
 
       validFields.forEach(field => {
         const result = createSortOptions(field);
@@ -165,4 +172,42 @@ describe('sort utilities', () => {
       expect(mockTimelines).toEqual(originalTimelines);
     });
   });
+
+  describe('getHotScore', () => {
+    const now = new Date('2025-01-10T12:00:00Z').getTime();
+    const withEvents = (name: string, hoursAgo: number[]): Timeline => ({
+      ...createMockTimeline(name, new Date(now), hoursAgo.length, 0),
+      events: hoursAgo.map(h => ({ timestamp: new Date(now - h * 3600000).toISOString() })),
+    });
+
+    it('should halve the weight of an event every day', () => {
+      expect(getHotScore(withEvents('a', [0]), now)).toBeCloseTo(1);
+      expect(getHotScore(withEvents('a', [24, 48]), now)).toBeCloseTo(0.75);
+    });
+
+    it('should rank a few recent events above many old ones', () => {
+      const recent = withEvents('recent', [1, 2]);
+      const busyLastWeek = withEvents('busy', Array(20).fill(24 * 7));
+
+      expect(getHotScore(recent, now)).toBeGreaterThan(getHotScore(busyLastWeek, now));
+    });
+  });
+
+  describe('pinTimelines', () => {
+    it('should move pinned projects to the top in sorted order', () => {
+      const sorted = sortTimelines(mockTimelines, { field: 'project', order: 'asc' });
+      const result = pinTimelines(sorted, ['gamma-project', 'beta-project']);
+
+      expect(result.map(t => t.projectName)).toEqual([
+        'beta-project',
+        'gamma-project',
+        'alpha-project',
+      ]);
+    });
+
+    it('should ignore pins for projects without activity', () => {
+      const result = pinTimelines(mockTimelines, ['missing-project']);
+      expect(result.map(t => t.projectName)).toEqual(mockTimelines.map(t => t.projectName));
+    });
+  });
 });
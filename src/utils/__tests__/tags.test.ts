@@ -0,0 +1,41 @@
+import { Timeline } from '../../models/models';
+import { filterTimelinesByTags, groupTimelinesByTag, UNTAGGED_GROUP_NAME } from '../tags';
+
+const createTimeline = (projectName: string, timestamps: string[]): Timeline => ({
+  projectName,
+  events: timestamps.map(timestamp => ({ timestamp })),
+  eventCount: timestamps.length,
+  activeDuration: 0,
+  startTime: new Date(timestamps[0]),
+  endTime: new Date(timestamps[timestamps.length - 1]),
+});
+
+describe('project tags', () => {
+  const timelines = [
+    createTimeline('webapp', ['2025-01-01T09:00:00Z', '2025-01-01T09:02:00Z']),
+    createTimeline('api', ['2025-01-01T10:00:00Z']),
+    createTimeline('blog', ['2025-01-01T11:00:00Z']),
+  ];
+  const projectTags = {
+    webapp: ['client:acme'],
+    api: ['client:acme', 'backend'],
+  };
+
+  it('should keep projects with any requested tag', () => {
+    const filtered = filterTimelinesByTags(timelines, ['backend'], projectTags);
+    expect(filtered.map(t => t.projectName)).toEqual(['api']);
+    expect(filterTimelinesByTags(timelines, [], projectTags)).toBe(timelines);
+  });
+
+  it('should roll projects up per tag', () => {
+    const grouped = groupTimelinesByTag(timelines, projectTags);
+
+    expect(grouped.map(t => [t.projectName, t.eventCount])).toEqual([
+      ['client:acme', 3],
+      ['backend', 1],
+      [UNTAGGED_GROUP_NAME, 1],
+    ]);
+    expect(grouped[0].startTime).toEqual(new Date('2025-01-01T09:00:00Z'));
+    expect(grouped[0].endTime).toEqual(new Date('2025-01-01T10:00:00Z'));
+  });
+});
@@ -0,0 +1,27 @@
+import { detectGraphicsProtocol, formatItermImage, formatKittyImage } from '../terminalGraphics';
+
+describe('terminalGraphics', () => {
+  it('should detect terminals supporting inline images', () => {
+    expect(detectGraphicsProtocol({ TERM: 'xterm-kitty' })).toBe('kitty');
+    expect(detectGraphicsProtocol({ TERM: 'tmux-256color', KITTY_WINDOW_ID: '1' })).toBe('kitty');
+    expect(detectGraphicsProtocol({ TERM_PROGRAM: 'iTerm.app' })).toBe('iterm');
+    expect(detectGraphicsProtocol({ TERM_PROGRAM: 'WezTerm' })).toBe('iterm');
+    expect(detectGraphicsProtocol({ TERM_PROGRAM: 'Apple_Terminal' })).toBeNull();
+  });
+
+  it('should split kitty images into chunks of 4096 base64 bytes', () => {
+    // 3999 bytes are 5332 base64 characters
+    const output = formatKittyImage(Buffer.alloc(3999));
+    const chunks = output.split('\x1b\\').filter(chunk => chunk !== '');
+
+    expect(chunks).toHaveLength(2);
+    expect(chunks[0]).toMatch(/^\x1b_Gf=100,a=T,m=1;A{4096}$/);
+    expect(chunks[1]).toMatch(/^\x1b_Gm=0;A{1236}$/);
+  });
+
+  it('should send iTerm2 images in one sequence with their size', () => {
+    expect(formatItermImage(Buffer.from('png'))).toBe(
+      '\x1b]1337;File=inline=1;size=3;preserveAspectRatio=1:cG5n\x07'
+    );
+  });
+});
@@ -0,0 +1,57 @@
+import { calculateStartTime, formatTimeRange, parseSince, stepTimeRange } from '../timeRange';
+
+describe('calculateStartTime', () => {
+  const end = new Date(2025, 0, 10, 12, 0);
+
+  it('should go back by hours or days', () => {
+    expect(calculateStartTime(end, undefined, 6)).toEqual(new Date(2025, 0, 10, 6, 0));
+    expect(calculateStartTime(end, 7)).toEqual(new Date(2025, 0, 3, 12, 0));
+  });
+});
+
+describe('parseSince', () => {
+  const now = new Date(2025, 0, 10, 12, 0);
+
+  it('should read clock times as their latest occurrence', () => {
+    expect(parseSince('9am', now)).toEqual(new Date(2025, 0, 10, 9, 0));
+    expect(parseSince('12:30am', now)).toEqual(new Date(2025, 0, 10, 0, 30));
+    expect(parseSince('9:30 PM', now)).toEqual(new Date(2025, 0, 9, 21, 30));
+    expect(parseSince('14:00', now)).toEqual(new Date(2025, 0, 9, 14, 0));
+  });
+
+  it('should read durations and dates', () => {
+    expect(parseSince('2h', now)).toEqual(new Date(2025, 0, 10, 10, 0));
+    expect(parseSince('3d', now)).toEqual(new Date(2025, 0, 7, 12, 0));
+    expect(parseSince('2025-01-08', now)).toEqual(new Date(2025, 0, 8));
+  });
+
+  it('should reject anything else', () => {
+    for (const value of ['9', '13pm', '25:00', 'morning', '2w']) {
+      expect(parseSince(value, now)).toBeNull();
+    }
+  });
+});
+
+describe('stepTimeRange', () => {
+  it('should step to the neighboring presets', () => {
+    expect(stepTimeRange({ days: 7 }, 1)).toEqual({ days: 30 });
+    expect(stepTimeRange({ days: 7 }, -1)).toEqual({ days: 1 });
+    expect(stepTimeRange({ days: 90 }, 1)).toEqual({ allTime: true });
+  });
+
+  it('should step from ranges between presets', () => {
+    expect(stepTimeRange({ days: 14 }, 1)).toEqual({ days: 30 });
+    expect(stepTimeRange({ days: 14 }, -1)).toEqual({ days: 7 });
+  });
+
+  it('should stay put at either end', () => {
+    expect(stepTimeRange({ hours: 6 }, -1)).toEqual({ hours: 6 });
+    expect(stepTimeRange({ allTime: true }, 1)).toEqual({ allTime: true });
+  });
+
+  it('should describe ranges', () => {
+    expect(formatTimeRange({ hours: 6 })).toBe('6 hours');
+    expect(formatTimeRange({ days: 7 })).toBe('7 days');
+    expect(formatTimeRange({ allTime: true })).toBe('all time');
+  });
+});
@@ -0,0 +1,61 @@
+import {
+  checkTimestampSanity,
+  clampTimestamp,
+  isValidTimestampPolicy,
+  MIN_VALID_TIMESTAMP,
+} from '../timestampSanity';
+
+describe('timestamp sanity', () => {
+  const now = new Date('2025-06-01T12:00:00Z');
+
+  describe('checkTimestampSanity', () => {
+    it('should accept timestamps within the plausible range', () => {
+      expect(checkTimestampSanity(new Date('2025-05-31T08:00:00Z'), now)).toBe('valid');
+    });
+
+    it('should flag zero epochs as too old', () => {
+      expect(checkTimestampSanity(new Date(0), now)).toBe('too-old');
+    });
+
+    it('should flag timestamps far in the future', () => {
+      expect(checkTimestampSanity(new Date('2030-01-01T00:00:00Z'), now)).toBe('future');
+    });
+
+    it('should tolerate small clock skew', () => {
+      expect(checkTimestampSanity(new Date('2025-06-01T18:00:00Z'), now)).toBe('valid');
+    });
+  });
+
+  describe('clampTimestamp', () => {
+    it('should clamp old timestamps to the minimum', () => {
+      expect(clampTimestamp(new Date(0), now)).toEqual(MIN_VALID_TIMESTAMP);
+    });
+
+    it('should clamp future timestamps to now', () => {
+      expect(clampTimestamp(new Date('2030-01-01T00:00:00Z'), now)).toEqual(now);
+    });
+
+    it('should clamp to the given valid span', () => {
+      const valid = {
+        start: new Date('2025-05-01T00:00:00Z'),
+        end: new Date('2025-05-31T00:00:00Z'),
+      };
+      expect(clampTimestamp(new Date(0), now, valid)).toEqual(valid.start);
+      expect(clampTimestamp(new Date('2030-01-01T00:00:00Z'), now, valid)).toEqual(valid.end);
+    });
+
+    it('should leave valid timestamps untouched', () => {
+      const time = new Date('2025-05-31T08:00:00Z');
+      expect(clampTimestamp(time, now)).toBe(time);
+    });
+  });
+
+  describe('isValidTimestampPolicy', () => {
+    it('should accept known policies only', () => {
+      expect(isValidTimestampPolicy('warn')).toBe(true);
+      expect(isValidTimestampPolicy('clamp')).toBe(true);
+      expect(isValidTimestampPolicy('exclude')).toBe(true);
+      expect(isValidTimestampPolicy('ignore')).toBe(false);
+    });
+  });
+});
@@ -0,0 +1,50 @@
+import { createTokenCounter, formatTokens, getEventTokens, sumEventTokens } from '../tokens';
+
+describe('getEventTokens', () => {
+  const timestamp = '2025-01-01T10:00:00.000Z';
+
+  it('should sum normalized usage', () => {
+    expect(getEventTokens({ timestamp, usage: { inputTokens: 100, outputTokens: 20 } })).toBe(120);
+  });
+
+  it('should read usage from Claude Code assistant messages', () => {
+    const event = { timestamp, message: { usage: { input_tokens: 7, output_tokens: 3 } } };
+    expect(getEventTokens(event)).toBe(10);
+  });
+
+  it('should return zero without usage', () => {
+    expect(getEventTokens({ timestamp })).toBe(0);
+    expect(getEventTokens({ timestamp, message: 'hello' })).toBe(0);
+  });
+});
+
+describe('sumEventTokens', () => {
+  const timestamp = '2025-01-01T10:00:00.000Z';
+  const block = (id: string) => ({
+    timestamp,
+    message: { id, usage: { input_tokens: 7, output_tokens: 3 } },
+  });
+
+  it('should count the lines of one reply once', () => {
+    expect(sumEventTokens([block('msg_1'), block('msg_1'), block('msg_2')])).toBe(20);
+  });
+
+  it('should fall back to the request id', () => {
+    const event = { timestamp, requestId: 'req_1', usage: { inputTokens: 5 } };
+    expect(sumEventTokens([event, event])).toBe(5);
+  });
+
+  it('should count lines without an id each', () => {
+    const event = { timestamp, usage: { inputTokens: 5 } };
+    const countTokens = createTokenCounter();
+    expect(countTokens(event) + countTokens(event)).toBe(10);
+  });
+});
+
+describe('formatTokens', () => {
+  it('should show thousands and millions with one decimal', () => {
+    expect(formatTokens(950)).toBe('950');
+    expect(formatTokens(170_000)).toBe('170.0k');
+    expect(formatTokens(1_234_567)).toBe('1.2M');
+  });
+});
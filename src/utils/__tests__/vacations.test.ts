@@ -0,0 +1,20 @@
+import { countVacationDays, isVacationDay } from '../vacations';
+
+describe('vacations', () => {
+  const vacations = [
+    { start: '2025-08-04', end: '2025-08-08', label: 'summer' },
+    { start: '2025-12-25' },
+  ];
+
+  it('should match days within inclusive ranges and single days', () => {
+    expect(isVacationDay(new Date(2025, 7, 4, 9), vacations)).toBe(true);
+    expect(isVacationDay(new Date(2025, 7, 8, 23), vacations)).toBe(true);
+    expect(isVacationDay(new Date(2025, 7, 9), vacations)).toBe(false);
+    expect(isVacationDay(new Date(2025, 11, 25), vacations)).toBe(true);
+  });
+
+  it('should count days off in a range', () => {
+    expect(countVacationDays(new Date(2025, 7, 4), new Date(2025, 7, 11), vacations)).toBe(5);
+    expect(countVacationDays(new Date(2025, 7, 11), new Date(2025, 7, 18), vacations)).toBe(0);
+  });
+});
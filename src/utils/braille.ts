@@ -0,0 +1,29 @@
+// Unicode braille patterns draw a 2×4 grid of dots in one character: U+2800 plus one bit per dot
+const BRAILLE_BASE = 0x2800;
+
+// Dot bits of the left and right columns, bottom row first
+const LEFT_DOTS = [0x40, 0x04, 0x02, 0x01];
+const RIGHT_DOTS = [0x80, 0x20, 0x10, 0x08];
+
+export const BRAILLE_HEIGHT = LEFT_DOTS.length;
+
+// Two bars of 0-4 dots rising from the bottom of one character
+export function brailleBars(left: number, right: number): string {
+  let code = BRAILLE_BASE;
+  for (let i = 0; i < Math.min(left, BRAILLE_HEIGHT); i++) code |= LEFT_DOTS[i];
+  for (let i = 0; i < Math.min(right, BRAILLE_HEIGHT); i++) code |= RIGHT_DOTS[i];
+  return String.fromCharCode(code);
+}
+
+// Sparkline packing two values into each character, at four heights instead of eight
+export function brailleSparkline(values: number[], max = Math.max(...values, 0)): string {
+  const heights = values.map(value =>
+    value <= 0 || max <= 0 ? 0 : Math.min(BRAILLE_HEIGHT, Math.ceil((value / max) * BRAILLE_HEIGHT))
+  );
+
+  let line = '';
+  for (let i = 0; i < heights.length; i += 2) {
+    line += brailleBars(heights[i], heights[i + 1] ?? 0);
+  }
+  return line;
+}
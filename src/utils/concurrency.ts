@@ -0,0 +1,33 @@
+// Run fn over items with at most `limit` calls in flight, keeping results in order
+export async function mapWithConcurrency<T, R>(
+  items: T[],
+  limit: number,
+  fn: (item: T) => Promise<R>
+): Promise<R[]> {
+  const results: R[] = new Array(items.length);
+  let next = 0;
+
+  const worker = async () => {
+    while (next < items.length) {
+      const index = next++;
+      results[index] = await fn(items[index]);
+    }
+  };
+
+  await Promise.all(Array.from({ length: Math.min(limit, items.length) }, worker));
+  return results;
+}
+
+// Share one in-flight call per key, so concurrent callers don't repeat the same work
+export function createSingleflight<K, V>(): (key: K, fn: () => Promise<V>) => Promise<V> {
+  const inflight = new Map<K, Promise<V>>();
+
+  return (key, fn) => {
+    const existing = inflight.get(key);
+    if (existing) return existing;
+
+    const promise = fn().finally(() => inflight.delete(key));
+    inflight.set(key, promise);
+    return promise;
+  };
+}
@@ -0,0 +1,265 @@
+import { mkdir, readFile, writeFile } from 'fs/promises';
+import { delimiter, dirname, join } from 'path';
+import { homedir } from 'os';
+import { z } from 'zod';
+import { DURATION_ALGORITHM_VALUES } from '../core/duration';
+import { NESTED_REPOSITORY_VALUES } from '../core/git';
+import { LIMIT_PERIOD_VALUES } from '../core/projection';
+
+const DATE_PATTERN = /^\d{4}-\d{2}-\d{2}$/;
+const TIME_PATTERN = /^\d{1,2}:\d{2}$/;
+
+const SettingsSchema = z
+  .object({
+    // Projects always rendered at the top of the table
+    pinned: z.array(z.string()).optional(),
+    // Directories or glob patterns (e.g. "/tmp/**") excluded from every report
+    ignore: z.array(z.string()).optional(),
+    // Labels per project name, e.g. { "webapp": ["client:acme"] }
+    tags: z.record(z.array(z.string())).optional(),
+    // Claude projects directories to read instead of the defaults
+    logDirs: z.array(z.string()).optional(),
+    // Read reports from the store filled by `ccstat ingest` instead of the logs
+    store: z.boolean().optional(),
+    // Days of raw events `ccstat ingest --compact` keeps; older days become daily totals
+    retentionDays: z.number().int().positive().optional(),
+    // Run `git` for repository layouts ccstat can't read itself (submodules, GIT_DIR)
+    gitFallback: z.boolean().optional(),
+    // Group repositories nested in another one on their own ("innermost") or into the outer one
+    nestedRepositories: z.enum(NESTED_REPOSITORY_VALUES).optional(),
+    // Count only the user's own prompts, so long autonomous agent runs don't inflate activity
+    userEventsOnly: z.boolean().optional(),
+    // How active minutes are estimated, see DurationModel
+    duration: z
+      .object({
+        algorithm: z.enum(DURATION_ALGORITHM_VALUES).optional(),
+        idleMinutes: z.number().positive().optional(),
+        eventMinutes: z.record(z.number().nonnegative()).optional(),
+      })
+      .optional(),
+    // Days off, e.g. [{ "start": "2025-08-04", "end": "2025-08-15", "label": "summer" }], left
+    // out of averages, forecasts and unusual-activity baselines
+    vacations: z
+      .array(
+        z.object({
+          start: z.string().regex(DATE_PATTERN, 'must be YYYY-MM-DD'),
+          end: z.string().regex(DATE_PATTERN, 'must be YYYY-MM-DD').optional(),
+          label: z.string().optional(),
+        })
+      )
+      .optional(),
+    // Local working time for `ccstat working-hours`, 09:00-18:00 by default; days are 0 (Sunday)
+    // to 6, Monday-Friday by default
+    workingHours: z
+      .object({
+        start: z.string().regex(TIME_PATTERN, 'must be HH:MM').optional(),
+        end: z.string().regex(TIME_PATTERN, 'must be HH:MM').optional(),
+        days: z.array(z.number().int().min(0).max(6)).optional(),
+      })
+      .optional(),
+    // JSONL file of timeline markers written by `ccstat annotate` (default: in the data directory)
+    annotationsFile: z.string().optional(),
+    // Default color theme
+    color: z.string().optional(),
+    // Default output format
+    format: z.string().optional(),
+    // Local "HH:MM" time for the end-of-day summary in `ccstat serve`
+    summaryAt: z.string().optional(),
+    // Cron tasks of `ccstat serve`, e.g. ["0 18 * * 5 report"]; replaced by `--schedule`
+    schedule: z.array(z.string()).optional(),
+    // Plan quota used by `ccstat projection`
+    limit: z
+      .object({
+        period: z.enum(LIMIT_PERIOD_VALUES).optional(),
+        tokens: z.number().positive().optional(),
+        hours: z.number().positive().optional(),
+      })
+      .optional(),
+  })
+  .passthrough(); // Keep keys written by newer versions
+
+export const ConfigSchema = SettingsSchema.extend({
+  // Named blocks overriding the settings above, selected with --profile
+  profiles: z.record(SettingsSchema).optional(),
+  // Named argument lists run with `ccstat view <name>`, e.g. { "weekly": ["--days", "7"] }
+  views: z.record(z.array(z.string())).optional(),
+}).passthrough();
+
+export type Settings = z.infer<typeof SettingsSchema>;
+export type Config = z.infer<typeof ConfigSchema>;
+
+export function getConfigPath(): string {
+  if (process.env.CCSTAT_CONFIG) return process.env.CCSTAT_CONFIG;
+
+  const configHome = process.env.XDG_CONFIG_HOME || join(homedir(), '.config');
+  return join(configHome, 'ccstat', 'config.json');
+}
+
+// Where ccstat keeps data it collects itself, such as hook events
+export function getDataDir(): string {
+  if (process.env.CCSTAT_DATA_DIR) return process.env.CCSTAT_DATA_DIR;
+
+  const dataHome = process.env.XDG_DATA_HOME || join(homedir(), '.local', 'share');
+  return join(dataHome, 'ccstat');
+}
+
+// A missing config file is the same as an empty one
+export async function loadConfig(configPath = getConfigPath()): Promise<Config> {
+  let content: string;
+  try {
+    content = await readFile(configPath, 'utf-8');
+  } catch {
+    return {};
+  }
+
+  let data: unknown;
+  try {
+    data = JSON.parse(content);
+  } catch {
+    throw new Error(`${configPath}: not valid JSON`);
+  }
+
+  const result = ConfigSchema.safeParse(data);
+  if (!result.success) {
+    const issue = result.error.issues[0];
+    throw new Error(`${configPath}: ${issue?.path.join('.')} ${issue?.message}`);
+  }
+  return result.data;
+}
+
+export async function saveConfig(config: Config, configPath = getConfigPath()): Promise<void> {
+  await mkdir(dirname(configPath), { recursive: true });
+  await writeFile(configPath, JSON.stringify(config, null, 2) + '\n');
+}
+
+// Apply a named profile on top of the base settings
+export function resolveProfile(config: Config, profile?: string): Settings {
+  const { profiles, ...base } = config;
+  if (!profile) return base;
+
+  const overrides = profiles?.[profile];
+  if (!overrides) {
+    const available = Object.keys(profiles ?? {});
+    throw new Error(
+      `Unknown profile '${profile}'.` +
+        (available.length > 0 ? ` Available profiles: ${available.join(', ')}` : '')
+    );
+  }
+  return { ...base, ...overrides };
+}
+
+// Command-line arguments saved under a name in the config file
+export function resolveView(config: Config, name: string): string[] {
+  const args = config.views?.[name];
+  if (!args) {
+    const available = Object.keys(config.views ?? {});
+    throw new Error(
+      `Unknown view '${name}'.` +
+        (available.length > 0 ? ` Available views: ${available.join(', ')}` : '')
+    );
+  }
+  return args;
+}
+
+const splitList = (value: string) => value.split(',').map(item => item.trim()).filter(Boolean);
+const splitPaths = (value: string) => value.split(delimiter).filter(Boolean);
+
+// Booleans other than these are left as text for the schema to reject
+const parseBoolean = (value: string) =>
+  value === 'true' ? true : value === 'false' ? false : value;
+
+interface EnvSetting {
+  env: string;
+  // Path of the setting, e.g. ["duration", "idleMinutes"]
+  path: string[];
+  parse: (value: string) => unknown;
+}
+
+// CCSTAT_ and the path in upper snake case, e.g. CCSTAT_DURATION_IDLE_MINUTES
+export function getEnvName(path: string[]): string {
+  return `CCSTAT_${path.map(key => key.replace(/([A-Z])/g, '_$1').toUpperCase()).join('_')}`;
+}
+
+// Lists of text are comma-separated, log directories separated like PATH (":" or ";" on
+// Windows), and anything else that isn't text, a number or a boolean is JSON. Schedules
+// are JSON too, since cron fields such as "9,18" contain commas
+function getEnvParser(path: string[], type: z.ZodTypeAny): (value: string) => unknown {
+  if (path.join('.') === 'logDirs') return splitPaths;
+  if (path.join('.') === 'schedule') return value => JSON.parse(value);
+  if (type instanceof z.ZodArray && type.element instanceof z.ZodString) return splitList;
+  if (type instanceof z.ZodString || type instanceof z.ZodEnum) return value => value;
+  if (type instanceof z.ZodNumber) return Number;
+  if (type instanceof z.ZodBoolean) return parseBoolean;
+  return value => JSON.parse(value);
+}
+
+// One variable per setting, nested ones included, so none can be missed
+function collectEnvSettings(shape: z.ZodRawShape, path: string[] = []): EnvSetting[] {
+  return Object.entries(shape).flatMap(([key, schema]) => {
+    const type = schema instanceof z.ZodOptional ? schema.unwrap() : schema;
+    const settingPath = [...path, key];
+    if (type instanceof z.ZodObject) return collectEnvSettings(type.shape, settingPath);
+    const parse = getEnvParser(settingPath, type);
+    return [{ env: getEnvName(settingPath), path: settingPath, parse }];
+  });
+}
+
+// CCSTAT_* variables and the setting each one overrides
+const ENV_SETTINGS = collectEnvSettings(SettingsSchema.shape);
+
+// Copy of an object with a nested value replaced
+function withValue(target: Record<string, unknown>, path: string[], value: unknown) {
+  const [key, ...rest] = path;
+  if (rest.length === 0) return { ...target, [key]: value };
+
+  const child = target[key];
+  const object = child && typeof child === 'object' ? (child as Record<string, unknown>) : {};
+  return { ...target, [key]: withValue(object, rest, value) };
+}
+
+export const ENV_VARIABLES = [
+  'CCSTAT_CONFIG',
+  'CCSTAT_PROFILE',
+  'CCSTAT_DATA_DIR',
+  ...ENV_SETTINGS.map(s => s.env),
+];
+
+// Environment variables take precedence over the config file
+export function applyEnvOverrides(settings: Settings, env = process.env): Settings {
+  let overridden: Record<string, unknown> = settings;
+
+  for (const { env: name, path, parse } of ENV_SETTINGS) {
+    const value = env[name];
+    if (value === undefined || value === '') continue;
+
+    try {
+      overridden = withValue(overridden, path, parse(value));
+    } catch {
+      throw new Error(`${name}: not valid JSON`);
+    }
+  }
+
+  const result = SettingsSchema.safeParse(overridden);
+  if (!result.success) {
+    const issue = result.error.issues[0];
+    const setting = ENV_SETTINGS.find(
+      s => env[s.env] && s.path.every((key, i) => issue?.path[i] === key)
+    );
+    const name = setting?.env ?? 'environment';
+    throw new Error(`${name}: ${issue?.message}`);
+  }
+  return result.data;
+}
+
+// Profile chosen with --profile, applied to every command of the run
+let activeProfile: string | undefined;
+
+export function setActiveProfile(profile: string | undefined): void {
+  activeProfile = profile;
+}
+
+export async function loadSettings(
+  profile = activeProfile ?? process.env.CCSTAT_PROFILE
+): Promise<Settings> {
+  return applyEnvOverrides(resolveProfile(await loadConfig(), profile));
+}
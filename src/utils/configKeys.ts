@@ -0,0 +1,272 @@
+import { existsSync, statSync } from 'fs';
+import { DURATION_ALGORITHM_VALUES } from '../core/duration';
+import { NESTED_REPOSITORY_VALUES } from '../core/git';
+import { getProjectsDirs } from '../core/parser';
+import { LIMIT_PERIOD_VALUES } from '../core/projection';
+import { parseScheduleEntry } from '../core/scheduler';
+import { COLOR_THEME_VALUES } from '../ui/colorThemes';
+import { Config, ConfigSchema } from './config';
+import { OUTPUT_FORMAT_VALUES } from './outputFormats';
+
+export const CONFIG_OPERATION_VALUES = ['list', 'get', 'set', 'unset', 'edit'] as const;
+
+export type ConfigOperation = (typeof CONFIG_OPERATION_VALUES)[number];
+
+export function isValidConfigOperation(value: string): value is ConfigOperation {
+  return CONFIG_OPERATION_VALUES.includes(value as ConfigOperation);
+}
+
+export type ConfigValueType = 'string' | 'number' | 'boolean' | 'list';
+
+export interface ConfigKey {
+  // Dotted path in the config file, e.g. "duration.idleMinutes"
+  key: string;
+  type: ConfigValueType;
+  description: string;
+  // Allowed values of a string setting
+  values?: readonly string[];
+  // Why a parsed value is not allowed, or null when it is
+  check?: (value: unknown) => string | null;
+}
+
+const checkPositive = (value: unknown) => ((value as number) > 0 ? null : 'must be positive');
+
+const checkPositiveInteger = (value: unknown) =>
+  Number.isInteger(value) && (value as number) > 0 ? null : 'must be a positive whole number';
+
+const checkTime = (value: unknown) => {
+  const match = /^(\d{1,2}):(\d{2})$/.exec(value as string);
+  return match && parseInt(match[1]) <= 23 && parseInt(match[2]) <= 59 ? null : 'must be HH:MM';
+};
+
+const checkSchedule = (value: unknown) => {
+  const invalid = (value as string[]).find(entry => !parseScheduleEntry(entry));
+  return invalid ? `invalid entry '${invalid}', use "<five cron fields> <task>"` : null;
+};
+
+const checkDirectories = (value: unknown) => {
+  const missing = getProjectsDirs(value as string[]).find(
+    dir => !existsSync(dir) || !statSync(dir).isDirectory()
+  );
+  return missing ? `directory not found: ${missing}` : null;
+};
+
+// Settings `ccstat config` can get and set; the order is the order of the form
+export const CONFIG_KEYS: ConfigKey[] = [
+  { key: 'color', type: 'string', values: COLOR_THEME_VALUES, description: 'color theme' },
+  { key: 'format', type: 'string', values: OUTPUT_FORMAT_VALUES, description: 'output format' },
+  { key: 'pinned', type: 'list', description: 'projects always shown at the top' },
+  { key: 'ignore', type: 'list', description: 'directories or globs left out of reports' },
+  {
+    key: 'logDirs',
+    type: 'list',
+    description: 'Claude projects directories to read',
+    check: checkDirectories,
+  },
+  { key: 'userEventsOnly', type: 'boolean', description: 'count only your own prompts' },
+  {
+    key: 'duration.algorithm',
+    type: 'string',
+    values: DURATION_ALGORITHM_VALUES,
+    description: 'how active minutes are estimated',
+  },
+  {
+    key: 'duration.idleMinutes',
+    type: 'number',
+    description: 'gap after which a session counts as idle',
+    check: checkPositive,
+  },
+  {
+    key: 'nestedRepositories',
+    type: 'string',
+    values: NESTED_REPOSITORY_VALUES,
+    description: 'group nested repositories on their own or into the outer one',
+  },
+  { key: 'gitFallback', type: 'boolean', description: 'ask git for layouts ccstat cannot read' },
+  { key: 'store', type: 'boolean', description: 'read reports from the ingest store' },
+  {
+    key: 'retentionDays',
+    type: 'number',
+    description: 'days of raw events kept when compacting the store',
+    check: checkPositiveInteger,
+  },
+  {
+    key: 'workingHours.start',
+    type: 'string',
+    description: 'start of the working day',
+    check: checkTime,
+  },
+  {
+    key: 'workingHours.end',
+    type: 'string',
+    description: 'end of the working day',
+    check: checkTime,
+  },
+  {
+    key: 'summaryAt',
+    type: 'string',
+    description: 'time of the end-of-day summary in ccstat serve',
+    check: checkTime,
+  },
+  {
+    key: 'schedule',
+    type: 'list',
+    description: 'cron tasks of ccstat serve, e.g. "0 18 * * 5 report"',
+    check: checkSchedule,
+  },
+  {
+    key: 'limit.period',
+    type: 'string',
+    values: LIMIT_PERIOD_VALUES,
+    description: 'plan quota period',
+  },
+  {
+    key: 'limit.tokens',
+    type: 'number',
+    description: 'plan quota in tokens',
+    check: checkPositive,
+  },
+  { key: 'limit.hours', type: 'number', description: 'plan quota in hours', check: checkPositive },
+  { key: 'annotationsFile', type: 'string', description: 'file of timeline annotations' },
+];
+
+// Path of a setting in the config file; "profiles.<name>." in front sets it for a profile
+export function resolveConfigKey(key: string): { configKey: ConfigKey; path: string[] } {
+  const path = key.split('.');
+  const settingKey = path[0] === 'profiles' && path.length > 2 ? path.slice(2).join('.') : key;
+
+  const configKey = CONFIG_KEYS.find(k => k.key === settingKey);
+  if (!configKey || path.some(part => part === '')) {
+    throw new Error(
+      `Unknown setting '${key}'. Available settings: ${CONFIG_KEYS.map(k => k.key).join(', ')}`
+    );
+  }
+  return { configKey, path };
+}
+
+// Why a value is not allowed for a setting, or null when it is
+export function checkConfigValue(configKey: ConfigKey, value: unknown): string | null {
+  if (configKey.values && !configKey.values.includes(value as string)) {
+    return `must be one of: ${configKey.values.join(', ')}`;
+  }
+  return configKey.check?.(value) ?? null;
+}
+
+// Parse a value typed on the command line or in the form: lists are comma-separated or a JSON
+// array
+export function parseConfigValue(configKey: ConfigKey, text: string): unknown {
+  let value: unknown;
+  switch (configKey.type) {
+    case 'number':
+      value = Number(text.trim());
+      if (text.trim() === '' || !Number.isFinite(value)) {
+        throw new Error(`${configKey.key}: must be a number`);
+      }
+      break;
+    case 'boolean':
+      if (!['true', 'false'].includes(text.trim())) {
+        throw new Error(`${configKey.key}: must be true or false`);
+      }
+      value = text.trim() === 'true';
+      break;
+    case 'list':
+      if (text.trim().startsWith('[')) {
+        try {
+          value = JSON.parse(text);
+        } catch {
+          throw new Error(`${configKey.key}: not a valid JSON array`);
+        }
+        if (!Array.isArray(value) || value.some(item => typeof item !== 'string')) {
+          throw new Error(`${configKey.key}: must be a list of strings`);
+        }
+      } else {
+        value = text
+          .split(',')
+          .map(item => item.trim())
+          .filter(Boolean);
+      }
+      break;
+    default:
+      value = text;
+  }
+
+  const problem = checkConfigValue(configKey, value);
+  if (problem) throw new Error(`${configKey.key}: ${problem}`);
+  return value;
+}
+
+export function getConfigValue(config: Config, path: string[]): unknown {
+  let value: unknown = config;
+  for (const part of path) {
+    if (typeof value !== 'object' || value === null) return undefined;
+    value = (value as Record<string, unknown>)[part];
+  }
+  return value;
+}
+
+// A copy of the config with the value at the path replaced, or removed when undefined; objects
+// left empty by a removal are removed too
+export function setConfigValue(config: Config, path: string[], value: unknown): Config {
+  const [part, ...rest] = path;
+  const current = (config as Record<string, unknown>)[part];
+  const next =
+    rest.length === 0
+      ? value
+      : setConfigValue(
+          typeof current === 'object' && current !== null ? (current as Config) : {},
+          rest,
+          value
+        );
+
+  const copy: Record<string, unknown> = { ...config };
+  if (next === undefined || (rest.length > 0 && Object.keys(next as object).length === 0)) {
+    delete copy[part];
+  } else {
+    copy[part] = next;
+  }
+  return copy as Config;
+}
+
+// Why a config can't be used, or null when it can: the file format first, then the values of
+// known settings in the base settings and every profile
+export function validateConfig(config: Config): string | null {
+  const result = ConfigSchema.safeParse(config);
+  if (!result.success) {
+    const issue = result.error.issues[0];
+    return `${issue?.path.join('.')}: ${issue?.message}`;
+  }
+
+  const scopes: Array<[string, unknown]> = [
+    ['', config],
+    ...Object.entries(config.profiles ?? {}).map(
+      ([name, profile]): [string, unknown] => [`profiles.${name}.`, profile]
+    ),
+  ];
+  for (const [prefix, settings] of scopes) {
+    for (const configKey of CONFIG_KEYS) {
+      const value = getConfigValue(settings as Config, configKey.key.split('.'));
+      if (value === undefined) continue;
+
+      const problem = checkConfigValue(configKey, value);
+      if (problem) return `${prefix}${configKey.key}: ${problem}`;
+    }
+  }
+  return null;
+}
+
+export function formatConfigValue(value: unknown): string {
+  return typeof value === 'string' ? value : JSON.stringify(value);
+}
+
+// Every value set in the config file as "key = value", objects flattened to dotted keys
+export function listConfigValues(config: Config, prefix = ''): string[] {
+  const lines: string[] = [];
+  for (const [key, value] of Object.entries(config)) {
+    if (typeof value === 'object' && value !== null && !Array.isArray(value)) {
+      lines.push(...listConfigValues(value as Config, `${prefix}${key}.`));
+    } else {
+      lines.push(`${prefix}${key} = ${formatConfigValue(value)}`);
+    }
+  }
+  return lines;
+}
@@ -0,0 +1,11 @@
+// Minutes as hours and zero-padded minutes, e.g. 65 -> "1h 05m"
+export function formatHours(minutes: number): string {
+  return `${Math.floor(minutes / 60)}h ${`${minutes % 60}`.padStart(2, '0')}m`;
+}
+
+// Minutes in the largest fitting units, e.g. 45 -> "45m", 65 -> "1h 05m", 1560 -> "1d 2h"
+export function formatDuration(minutes: number): string {
+  if (minutes < 60) return `${minutes}m`;
+  if (minutes < 24 * 60) return formatHours(minutes);
+  return `${Math.floor(minutes / (24 * 60))}d ${Math.floor((minutes % (24 * 60)) / 60)}h`;
+}
@@ -0,0 +1,26 @@
+import { Timeline } from '../models/models';
+
+// Distinct codes so scripts can tell the conditions apart; 1 stays reserved for errors
+export const EXIT_NO_ACTIVITY = 2;
+export const EXIT_OVER_BUDGET = 3;
+export const EXIT_INVALID_LOGS = 4;
+
+export interface ExitConditions {
+  failIfNoActivity?: boolean;
+  // Active minutes allowed in the displayed range
+  budgetMinutes?: number;
+}
+
+export function evaluateExitCode(timelines: Timeline[], conditions: ExitConditions): number {
+  const totalEvents = timelines.reduce((sum, t) => sum + t.eventCount, 0);
+  if (conditions.failIfNoActivity && totalEvents === 0) {
+    return EXIT_NO_ACTIVITY;
+  }
+
+  const totalDuration = timelines.reduce((sum, t) => sum + t.activeDuration, 0);
+  if (conditions.budgetMinutes !== undefined && totalDuration > conditions.budgetMinutes) {
+    return EXIT_OVER_BUDGET;
+  }
+
+  return 0;
+}
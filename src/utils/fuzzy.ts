@@ -0,0 +1,13 @@
+// Characters of the query appear in order in the text, e.g. "cst" matches "ccstat"
+export function fuzzyMatch(query: string, text: string): boolean {
+  const needle = query.replace(/\s+/g, '').toLowerCase();
+  const haystack = text.toLowerCase();
+
+  let position = 0;
+  for (const char of needle) {
+    position = haystack.indexOf(char, position);
+    if (position === -1) return false;
+    position++;
+  }
+  return true;
+}
@@ -0,0 +1,90 @@
+import { homedir } from 'os';
+import { basename } from 'path';
+import { Event, Timeline } from '../models/models';
+import { createTimeline } from '../core/parser';
+import { findWorktreeRoot } from '../core/git';
+import { groupTimelinesByTag, ProjectTags } from './tags';
+import { classifySessions, SessionMode } from './sessionModes';
+
+// What each table row stands for; `project` is the repository a session ran in
+export const GROUP_BY_VALUES = [
+  'project',
+  'worktree',
+  'directory',
+  'session',
+  'mode',
+  'tag',
+  'none',
+] as const;
+
+export type GroupBy = (typeof GROUP_BY_VALUES)[number];
+
+export function isValidGroupBy(value: string): value is GroupBy {
+  return GROUP_BY_VALUES.includes(value as GroupBy);
+}
+
+// Row name for all activity when grouping is turned off
+export const ALL_GROUP_NAME = 'all';
+
+const worktreeRootCache = new Map<string, string | null>();
+
+function getWorktreeRoot(directory: string): string | null {
+  if (!worktreeRootCache.has(directory)) {
+    worktreeRootCache.set(directory, findWorktreeRoot(directory));
+  }
+  return worktreeRootCache.get(directory)!;
+}
+
+function shortenHome(path: string): string {
+  const home = homedir();
+  return path === home || path.startsWith(`${home}/`) ? `~${path.slice(home.length)}` : path;
+}
+
+// Row name of an event; events missing the grouped field stay with their project
+function getGroupName(
+  groupBy: GroupBy,
+  projectName: string,
+  event: Event,
+  sessionModes: Map<string, SessionMode>
+): string {
+  switch (groupBy) {
+    case 'worktree': {
+      if (!event.cwd) return projectName;
+      const name = basename(getWorktreeRoot(event.cwd) ?? event.cwd);
+      return name === projectName ? projectName : `${projectName}:${name}`;
+    }
+    case 'directory':
+      return event.cwd ? shortenHome(event.cwd) : projectName;
+    case 'session':
+      return event.sessionId ? `${projectName}:${event.sessionId.slice(0, 8)}` : projectName;
+    case 'mode':
+      return (event.sessionId && sessionModes.get(event.sessionId)) || projectName;
+    case 'none':
+      return ALL_GROUP_NAME;
+    default:
+      return projectName;
+  }
+}
+
+// Regroup project timelines into one timeline per row of the chosen grouping
+export function groupTimelines(
+  timelines: Timeline[],
+  groupBy: GroupBy,
+  projectTags: ProjectTags
+): Timeline[] {
+  if (groupBy === 'project') return timelines;
+  if (groupBy === 'tag') return groupTimelinesByTag(timelines, projectTags);
+
+  const sessionModes = groupBy === 'mode' ? classifySessions(timelines) : new Map();
+  const groups = new Map<string, Event[]>();
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      const name = getGroupName(groupBy, timeline.projectName, event, sessionModes);
+      const events = groups.get(name) ?? [];
+      events.push(event);
+      groups.set(name, events);
+    }
+  }
+
+  return Array.from(groups, ([name, events]) => createTimeline(name, events));
+}
@@ -0,0 +1,45 @@
+import { homedir } from 'os';
+
+// Convert a glob to a regular expression: ** spans directories, * and ? stay within one
+export function globToRegExp(pattern: string): RegExp {
+  const expanded = pattern.replace(/^~(?=\/|$)/, homedir()).replace(/\/+$/, '');
+  let source = '';
+
+  for (let i = 0; i < expanded.length; i++) {
+    const char = expanded[i];
+    if (char === '*' && expanded[i + 1] === '*') {
+      // "**/" also matches no directories at all
+      if (expanded[i + 2] === '/') {
+        source += '(?:.*/)?';
+        i += 2;
+      } else {
+        source += '.*';
+        i += 1;
+      }
+    } else if (char === '*') {
+      source += '[^/]*';
+    } else if (char === '?') {
+      source += '[^/]';
+    } else {
+      source += char.replace(/[.+^${}()|[\]\\]/g, '\\$&');
+    }
+  }
+
+  // Ignoring a directory also ignores everything below it
+  return new RegExp(`^${source}(?:/.*)?$`);
+}
+
+// Returns a cached predicate telling whether a directory matches any ignore pattern
+export function createIgnoreMatcher(patterns: string[]): (directory: string) => boolean {
+  const regexps = patterns.map(globToRegExp);
+  const cache = new Map<string, boolean>();
+
+  return directory => {
+    let ignored = cache.get(directory);
+    if (ignored === undefined) {
+      ignored = regexps.some(regexp => regexp.test(directory));
+      cache.set(directory, ignored);
+    }
+    return ignored;
+  };
+}
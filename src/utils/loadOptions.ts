@@ -0,0 +1,24 @@
+import { LoadOptions } from '../core/parser';
+import { getStorePath } from '../core/store';
+import { Settings } from './config';
+
+// Reports other than trend can't show compacted days, so say what is missing
+function warnCompactedRange(compactedBefore: string): void {
+  console.error(
+    `Warning: events before ${compactedBefore} are compacted into daily totals in the store ` +
+      'and left out of this report (ccstat trend still includes them)'
+  );
+}
+
+// Load options shared by every report, derived from the config file
+export function getLoadOptions(settings: Settings): LoadOptions {
+  return {
+    ignore: settings.ignore,
+    logDirs: settings.logDirs,
+    store: settings.store ? getStorePath() : undefined,
+    onCompactedRange: warnCompactedRange,
+    gitFallback: settings.gitFallback,
+    nestedRepositories: settings.nestedRepositories,
+    userEventsOnly: settings.userEventsOnly,
+  };
+}
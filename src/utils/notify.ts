@@ -0,0 +1,39 @@
+import { spawn } from 'child_process';
+
+function getNotifyCommand(title: string, body: string): [string, string[]] {
+  switch (process.platform) {
+    case 'darwin':
+      return [
+        'osascript',
+        ['-e', `display notification ${JSON.stringify(body)} with title ${JSON.stringify(title)}`],
+      ];
+    case 'win32': {
+      const quote = (text: string) => `'${text.replace(/'/g, "''")}'`;
+      return [
+        'powershell',
+        [
+          '-NoProfile',
+          '-Command',
+          'Add-Type -AssemblyName System.Windows.Forms; ' +
+            '$n = New-Object System.Windows.Forms.NotifyIcon; ' +
+            '$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; ' +
+            `$n.ShowBalloonTip(10000, ${quote(title)}, ${quote(body)}, 'Info')`,
+        ],
+      ];
+    }
+    default:
+      return ['notify-send', [title, body]];
+  }
+}
+
+// Show a desktop notification with the platform's native tool
+export function sendDesktopNotification(title: string, body: string): Promise<void> {
+  const [command, args] = getNotifyCommand(title, body);
+  return new Promise((resolve, reject) => {
+    const child = spawn(command, args, { stdio: 'ignore' });
+    child.on('error', reject);
+    child.on('close', code =>
+      code === 0 ? resolve() : reject(new Error(`${command} exited with code ${code}`))
+    );
+  });
+}
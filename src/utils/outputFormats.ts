@@ -0,0 +1,7 @@
+export const OUTPUT_FORMAT_VALUES = ['table', 'compact-json'] as const;
+
+export type OutputFormat = (typeof OUTPUT_FORMAT_VALUES)[number];
+
+export function isValidOutputFormat(value: string): value is OutputFormat {
+  return OUTPUT_FORMAT_VALUES.includes(value as OutputFormat);
+}
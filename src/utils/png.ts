@@ -0,0 +1,60 @@
+import { deflateSync } from 'zlib';
+
+const PNG_SIGNATURE = Buffer.from([0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a]);
+
+// Bit depth 8, color type 6 (truecolor with alpha)
+const BIT_DEPTH = 8;
+const COLOR_TYPE_RGBA = 6;
+
+const CRC_TABLE = Array.from({ length: 256 }, (_, n) => {
+  let c = n;
+  for (let k = 0; k < 8; k++) {
+    c = c & 1 ? 0xedb88320 ^ (c >>> 1) : c >>> 1;
+  }
+  return c >>> 0;
+});
+
+// CRC-32 as used by PNG chunks (and zip, gzip)
+export function crc32(data: Buffer): number {
+  let crc = 0xffffffff;
+  for (const byte of data) {
+    crc = CRC_TABLE[(crc ^ byte) & 0xff] ^ (crc >>> 8);
+  }
+  return (crc ^ 0xffffffff) >>> 0;
+}
+
+function createChunk(type: string, data: Buffer): Buffer {
+  const length = Buffer.alloc(4);
+  length.writeUInt32BE(data.length);
+  const body = Buffer.concat([Buffer.from(type, 'ascii'), data]);
+  const crc = Buffer.alloc(4);
+  crc.writeUInt32BE(crc32(body));
+  return Buffer.concat([length, body, crc]);
+}
+
+// Minimal PNG encoder for RGBA pixels, row by row, 4 bytes per pixel
+export function encodePng(width: number, height: number, pixels: Buffer): Buffer {
+  if (pixels.length !== width * height * 4) {
+    throw new Error(`Expected ${width * height * 4} bytes of pixels, got ${pixels.length}`);
+  }
+
+  const header = Buffer.alloc(13);
+  header.writeUInt32BE(width, 0);
+  header.writeUInt32BE(height, 4);
+  header.writeUInt8(BIT_DEPTH, 8);
+  header.writeUInt8(COLOR_TYPE_RGBA, 9);
+
+  // Each row is prefixed with filter type 0 (none)
+  const rowLength = width * 4;
+  const raw = Buffer.alloc((rowLength + 1) * height);
+  for (let y = 0; y < height; y++) {
+    pixels.copy(raw, y * (rowLength + 1) + 1, y * rowLength, (y + 1) * rowLength);
+  }
+
+  return Buffer.concat([
+    PNG_SIGNATURE,
+    createChunk('IHDR', header),
+    createChunk('IDAT', deflateSync(raw)),
+    createChunk('IEND', Buffer.alloc(0)),
+  ]);
+}
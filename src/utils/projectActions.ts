@@ -0,0 +1,131 @@
+import { spawn } from 'child_process';
+import { basename } from 'path';
+import { Timeline } from '../models/models';
+
+// Working directory of the most recent event, i.e. where the project was last used
+export function getProjectDirectory(timeline: Timeline): string | null {
+  for (let i = timeline.events.length - 1; i >= 0; i--) {
+    const cwd = timeline.events[i].cwd;
+    if (cwd) return cwd;
+  }
+  return null;
+}
+
+export interface DirectoryActivity {
+  // Working directory, or null for events that recorded none
+  path: string | null;
+  events: number;
+}
+
+// Events per working directory, e.g. per worktree or subdirectory of a repository, busiest first
+export function getDirectoryBreakdown(timeline: Timeline): DirectoryActivity[] {
+  const counts = new Map<string | null, number>();
+  for (const event of timeline.events) {
+    const path = event.cwd ?? null;
+    counts.set(path, (counts.get(path) ?? 0) + 1);
+  }
+
+  return Array.from(counts, ([path, events]) => ({ path, events })).sort(
+    (a, b) => b.events - a.events
+  );
+}
+
+export function getLatestSessionId(timeline: Timeline): string | null {
+  for (let i = timeline.events.length - 1; i >= 0; i--) {
+    const sessionId = timeline.events[i].sessionId;
+    if (sessionId) return sessionId;
+  }
+  return null;
+}
+
+// Single-quote for POSIX shells
+export function quoteShellArgument(value: string): string {
+  return `'${value.replace(/'/g, `'\\''`)}'`;
+}
+
+export function getEditor(env = process.env): string {
+  return env.VISUAL || env.EDITOR || 'vi';
+}
+
+// Shell command opening a path in the editor. Editors with arguments (e.g. "code -w") need the
+// shell, and paths taken from logs must not be expanded by it: cmd.exe doesn't expand $(…) or
+// backticks, and Windows paths can't contain double quotes
+export function getEditorCommand(
+  path: string,
+  editor = getEditor(),
+  platform = process.platform
+): string {
+  return `${editor} ${platform === 'win32' ? `"${path}"` : quoteShellArgument(path)}`;
+}
+
+// Claude Code looks sessions up relative to the working directory, so cd there first
+export function getResumeCommand(sessionId: string, directory?: string | null): string {
+  const resume = `claude --resume ${sessionId}`;
+  return directory ? `cd ${quoteShellArgument(directory)} && ${resume}` : resume;
+}
+
+// Claude Code names each session log <sessionId>.jsonl
+export function findSessionLogFiles(sessionId: string, logFiles: Iterable<string>): string[] {
+  return Array.from(logFiles).filter(file => basename(file) === `${sessionId}.jsonl`);
+}
+
+function getOpenCommand(): [string, string[]] {
+  switch (process.platform) {
+    case 'darwin':
+      return ['open', []];
+    case 'win32':
+      return ['cmd', ['/c', 'start', '']];
+    default:
+      return ['xdg-open', []];
+  }
+}
+
+// Open a path in the platform's file manager without blocking the UI
+export function openInFileManager(path: string): Promise<void> {
+  const [command, args] = getOpenCommand();
+  return new Promise((resolve, reject) => {
+    const child = spawn(command, [...args, path], { detached: true, stdio: 'ignore' });
+    child.on('error', reject);
+    child.on('spawn', () => {
+      child.unref();
+      resolve();
+    });
+  });
+}
+
+function getClipboardCommands(): Array<[string, string[]]> {
+  switch (process.platform) {
+    case 'darwin':
+      return [['pbcopy', []]];
+    case 'win32':
+      return [['clip', []]];
+    default:
+      return [
+        ['wl-copy', []],
+        ['xclip', ['-selection', 'clipboard']],
+        ['xsel', ['--clipboard', '--input']],
+      ];
+  }
+}
+
+function pipeTo(command: string, args: string[], text: string): Promise<void> {
+  return new Promise((resolve, reject) => {
+    const child = spawn(command, args, { stdio: ['pipe', 'ignore', 'ignore'] });
+    child.on('error', reject);
+    child.on('close', code => (code === 0 ? resolve() : reject(new Error(`${command} failed`))));
+    child.stdin.end(text);
+  });
+}
+
+// Try each available clipboard tool in turn
+export async function copyToClipboard(text: string): Promise<void> {
+  for (const [command, args] of getClipboardCommands()) {
+    try {
+      await pipeTo(command, args, text);
+      return;
+    } catch {
+      continue;
+    }
+  }
+  throw new Error('no clipboard tool found');
+}
@@ -0,0 +1,11 @@
+// Repository name of a project whose name was qualified because another repository shares
+// it, e.g. "api" for "org-a/api" or "api (3f2a1b)"
+export function getBareProjectName(projectName: string): string {
+  return projectName.replace(/^[^/]+\//, '').replace(/ \([0-9a-f]{6}\)$/, '');
+}
+
+// Whether any of the names from the config file or command line refers to the project. Pins,
+// tags and filters written before names were qualified use the bare name, so both match
+export function matchesProjectName(names: string[], projectName: string): boolean {
+  return names.includes(projectName) || names.includes(getBareProjectName(projectName));
+}
@@ -0,0 +1,53 @@
+import { Event, Timeline } from '../models/models';
+
+// Text the user typed, or null for tool results, assistant turns and meta records
+export function getPromptText(event: Event): string | null {
+  if (event.type !== 'user' || !event.message) return null;
+
+  const { content } = event.message;
+  if (typeof content === 'string') {
+    return content;
+  }
+  if (Array.isArray(content)) {
+    const texts = content
+      .filter(part => part?.type === 'text' && typeof part.text === 'string')
+      .map(part => part.text as string);
+    return texts.length > 0 ? texts.join(' ') : null;
+  }
+  return null;
+}
+
+const REDACTION_PATTERNS: RegExp[] = [
+  // API keys and tokens with well-known prefixes
+  /\b(?:sk|pk|rk)-[A-Za-z0-9_-]{16,}/g,
+  /\bgh[pousr]_[A-Za-z0-9]{20,}/g,
+  /\bxox[abpr]-[A-Za-z0-9-]{10,}/g,
+  /\bAKIA[0-9A-Z]{16}\b/g,
+  // Bearer tokens and key=value style secrets
+  /\bBearer\s+[A-Za-z0-9._-]{16,}/gi,
+  /\b(password|passwd|secret|token|api[_-]?key)\s*[:=]\s*\S+/gi,
+  // Email addresses
+  /[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}/g,
+];
+
+export function redactPrompt(text: string): string {
+  return REDACTION_PATTERNS.reduce(
+    (redacted, pattern) => redacted.replace(pattern, '[redacted]'),
+    text.replace(/\s+/g, ' ').trim()
+  );
+}
+
+// Most recent user prompt of the timeline, redacted and collapsed to one line
+export function getLastPrompt(timeline: Timeline): string | null {
+  for (let i = timeline.events.length - 1; i >= 0; i--) {
+    const text = getPromptText(timeline.events[i]);
+    if (text && text.trim().length > 0) {
+      return redactPrompt(text);
+    }
+  }
+  return null;
+}
+
+export function truncateText(text: string, width: number): string {
+  return text.length > width ? text.substring(0, width - 1) + '…' : text;
+}
@@ -0,0 +1,55 @@
+import { Event, Timeline } from '../models/models';
+import { createTimeline } from '../core/parser';
+import { getEventKind } from '../core/duration';
+
+// Hands-on sessions versus work delegated to an agent running on its own
+export const SESSION_MODE_VALUES = ['interactive', 'agentic'] as const;
+export type SessionMode = (typeof SESSION_MODE_VALUES)[number];
+
+export function isValidSessionMode(value: string): value is SessionMode {
+  return SESSION_MODE_VALUES.includes(value as SessionMode);
+}
+
+// Events per typed prompt above which nobody was steering the session
+const AGENTIC_EVENTS_PER_PROMPT = 50;
+
+// Sessions mostly run by subagents (sidechains), or with long runs between prompts, are agentic
+export function classifySession(events: Event[]): SessionMode {
+  const sidechainEvents = events.filter(event => event.isSidechain).length;
+  if (sidechainEvents * 2 > events.length) return 'agentic';
+
+  const prompts = events.filter(event => getEventKind(event) === 'user').length;
+  return events.length / Math.max(1, prompts) > AGENTIC_EVENTS_PER_PROMPT
+    ? 'agentic'
+    : 'interactive';
+}
+
+// Mode of every session across the timelines; a session spanning projects is classified once
+export function classifySessions(timelines: Timeline[]): Map<string, SessionMode> {
+  const sessionEvents = new Map<string, Event[]>();
+  for (const timeline of timelines) {
+    for (const event of timeline.events) {
+      if (!event.sessionId) continue;
+      const events = sessionEvents.get(event.sessionId) ?? [];
+      events.push(event);
+      sessionEvents.set(event.sessionId, events);
+    }
+  }
+
+  return new Map(
+    Array.from(sessionEvents, ([sessionId, events]) => [sessionId, classifySession(events)])
+  );
+}
+
+// Keep events from sessions of one mode; events without a session count as interactive
+export function filterTimelinesBySessionMode(timelines: Timeline[], mode: SessionMode): Timeline[] {
+  const modes = classifySessions(timelines);
+  const getMode = (event: Event) =>
+    (event.sessionId && modes.get(event.sessionId)) || 'interactive';
+
+  return timelines.flatMap(timeline => {
+    const events = timeline.events.filter(event => getMode(event) === mode);
+    if (events.length === 0) return [];
+    return [{ ...timeline, ...createTimeline(timeline.projectName, events) }];
+  });
+}
@@ -1,6 +1,7 @@
 import { Timeline } from '../models/models';
+import { matchesProjectName } from './projectNames';
 
-export type SortField = 'project' | 'timeline' | 'events' | 'duration';
+export type SortField = 'project' | 'timeline' | 'events' | 'duration' | 'hot';
 export type SortOrder = 'asc' | 'desc';
 
 export interface SortOptions {
@@ -22,13 +23,36 @@ export function createSortOptions(field?: string, reverse?: boolean): SortOption
 }
 
 function isValidSortField(field?: string): field is SortField {
-  return field === 'project' || field === 'timeline' || field === 'events' || field === 'duration';
+  return (
+    field === 'project' ||
+    field === 'timeline' ||
+    field === 'events' ||
+    field === 'duration' ||
+    field === 'hot'
+  );
+}
+
+// An event's weight halves every day, so a burst of work today outranks a busier week ago
+const HOT_HALF_LIFE_MS = 24 * 60 * 60 * 1000;
+
+export function getHotScore(timeline: Timeline, now = Date.now()): number {
+  return timeline.events.reduce((score, event) => {
+    const age = Math.max(0, now - new Date(event.timestamp).getTime());
+    return score + Math.pow(0.5, age / HOT_HALF_LIFE_MS);
+  }, 0);
 }
 
 export function sortTimelines(timelines: Timeline[], sortOptions: SortOptions): Timeline[] {
   const sorted = [...timelines];
   const { field, order } = sortOptions;
 
+  const now = Date.now();
+  const hotScores = new Map<Timeline, number>();
+  const hotScore = (timeline: Timeline) => {
+    if (!hotScores.has(timeline)) hotScores.set(timeline, getHotScore(timeline, now));
+    return hotScores.get(timeline)!;
+  };
+
   const compare = (a: Timeline, b: Timeline): number => {
     let result: number;
 
@@ -49,6 +73,10 @@ export function sortTimelines(timelines: Timeline[], sortOptions: SortOptions):
         result = a.activeDuration - b.activeDuration;
         break;
 
+      case 'hot':
+        result = hotScore(a) - hotScore(b);
+        break;
+
       default:
         result = 0;
         break;
@@ -59,3 +87,11 @@ export function sortTimelines(timelines: Timeline[], sortOptions: SortOptions):
 
   return sorted.sort(compare);
 }
+
+// Move pinned projects to the top, keeping the sort order within each group
+export function pinTimelines(timelines: Timeline[], pinned: string[]): Timeline[] {
+  if (pinned.length === 0) return timelines;
+
+  const isPinned = (timeline: Timeline) => matchesProjectName(pinned, timeline.projectName);
+  return [...timelines.filter(isPinned), ...timelines.filter(t => !isPinned(t))];
+}
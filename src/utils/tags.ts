@@ -0,0 +1,44 @@
+import { Timeline } from '../models/models';
+import { createTimeline } from '../core/parser';
+import { getBareProjectName } from './projectNames';
+
+// Tags per project name, e.g. { "webapp": ["client:acme"] }
+export type ProjectTags = Record<string, string[]>;
+
+// Row name collecting projects without tags when grouping by tag
+export const UNTAGGED_GROUP_NAME = 'untagged';
+
+// Tags written before the name was qualified, e.g. under "api" for "org-a/api", still apply
+export function getProjectTags(projectTags: ProjectTags, projectName: string): string[] {
+  return projectTags[projectName] ?? projectTags[getBareProjectName(projectName)] ?? [];
+}
+
+// Keep projects carrying any of the given tags
+export function filterTimelinesByTags(
+  timelines: Timeline[],
+  tags: string[],
+  projectTags: ProjectTags
+): Timeline[] {
+  if (tags.length === 0) return timelines;
+  return timelines.filter(timeline =>
+    getProjectTags(projectTags, timeline.projectName).some(tag => tags.includes(tag))
+  );
+}
+
+// Roll projects up into one timeline per tag; a project with several tags counts toward each
+export function groupTimelinesByTag(timelines: Timeline[], projectTags: ProjectTags): Timeline[] {
+  const tagEvents = new Map<string, Timeline['events']>();
+
+  for (const timeline of timelines) {
+    const tags = getProjectTags(projectTags, timeline.projectName);
+    for (const tag of tags.length > 0 ? tags : [UNTAGGED_GROUP_NAME]) {
+      const events = tagEvents.get(tag) ?? [];
+      events.push(...timeline.events);
+      tagEvents.set(tag, events);
+    }
+  }
+
+  return Array.from(tagEvents.entries())
+    .filter(([, events]) => events.length > 0)
+    .map(([tag, events]) => createTimeline(tag, events));
+}
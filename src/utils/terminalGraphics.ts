@@ -0,0 +1,46 @@
+export const GRAPHICS_PROTOCOL_VALUES = ['kitty', 'iterm'] as const;
+
+export type GraphicsProtocol = (typeof GRAPHICS_PROTOCOL_VALUES)[number];
+
+export function isValidGraphicsProtocol(value: string): value is GraphicsProtocol {
+  return GRAPHICS_PROTOCOL_VALUES.includes(value as GraphicsProtocol);
+}
+
+// Terminals known to support each protocol; WezTerm supports both and is sent iTerm2 images,
+// which need no chunking
+export function detectGraphicsProtocol(
+  env: NodeJS.ProcessEnv = process.env
+): GraphicsProtocol | null {
+  if (env.TERM_PROGRAM === 'iTerm.app' || env.TERM_PROGRAM === 'WezTerm') return 'iterm';
+  if (env.TERM === 'xterm-kitty' || env.KITTY_WINDOW_ID || env.TERM_PROGRAM === 'ghostty') {
+    return 'kitty';
+  }
+  return null;
+}
+
+// Kitty accepts at most 4096 bytes of base64 per escape sequence
+const KITTY_CHUNK_SIZE = 4096;
+
+// Kitty graphics protocol: transmit and display a PNG (f=100) at the cursor
+export function formatKittyImage(png: Buffer): string {
+  const data = png.toString('base64');
+  const chunks: string[] = [];
+  for (let i = 0; i < data.length; i += KITTY_CHUNK_SIZE) {
+    const more = i + KITTY_CHUNK_SIZE < data.length ? 1 : 0;
+    const control = i === 0 ? `f=100,a=T,m=${more}` : `m=${more}`;
+    chunks.push(`\x1b_G${control};${data.slice(i, i + KITTY_CHUNK_SIZE)}\x1b\\`);
+  }
+  return chunks.join('');
+}
+
+// iTerm2 inline images protocol (OSC 1337), also understood by WezTerm
+export function formatItermImage(png: Buffer): string {
+  return (
+    `\x1b]1337;File=inline=1;size=${png.length};preserveAspectRatio=1:` +
+    `${png.toString('base64')}\x07`
+  );
+}
+
+export function formatInlineImage(png: Buffer, protocol: GraphicsProtocol): string {
+  return protocol === 'kitty' ? formatKittyImage(png) : formatItermImage(png);
+}
@@ -0,0 +1,86 @@
+// Calculate the start of the analyzed range ending at endTime
+export function calculateStartTime(endTime: Date, days?: number, hours?: number): Date {
+  const startTime = new Date(endTime);
+
+  if (hours) {
+    startTime.setHours(endTime.getHours() - hours);
+  } else {
+    startTime.setDate(endTime.getDate() - (days || 1));
+  }
+
+  return startTime;
+}
+
+const RELATIVE_UNITS_MS: Record<string, number> = {
+  m: 60 * 1000,
+  h: 60 * 60 * 1000,
+  d: 24 * 60 * 60 * 1000,
+};
+
+// Parse a --since value: a clock time ("9am", "9:30pm", "14:00") meaning its latest
+// occurrence, a duration ago ("30m", "2h", "3d"), or a date ("2025-01-15")
+export function parseSince(value: string, now = new Date()): Date | null {
+  const input = value.trim().toLowerCase();
+
+  const relative = /^(\d+)([mhd])$/.exec(input);
+  if (relative) {
+    return new Date(now.getTime() - parseInt(relative[1]) * RELATIVE_UNITS_MS[relative[2]]);
+  }
+
+  const clock = /^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$/.exec(input);
+  if (clock && (clock[2] || clock[3])) {
+    let hour = parseInt(clock[1]);
+    const minute = clock[2] ? parseInt(clock[2]) : 0;
+    if (minute > 59 || (clock[3] ? hour < 1 || hour > 12 : hour > 23)) return null;
+    if (clock[3]) hour = (hour % 12) + (clock[3] === 'pm' ? 12 : 0);
+
+    const since = new Date(now);
+    since.setHours(hour, minute, 0, 0);
+    // "9am" before nine in the morning means yesterday's
+    if (since > now) since.setDate(since.getDate() - 1);
+    return since;
+  }
+
+  const date = /^(\d{4})-(\d{2})-(\d{2})$/.exec(input);
+  if (date) {
+    const since = new Date(parseInt(date[1]), parseInt(date[2]) - 1, parseInt(date[3]));
+    return isNaN(since.getTime()) ? null : since;
+  }
+  return null;
+}
+
+export interface TimeRange {
+  days?: number;
+  hours?: number;
+  allTime?: boolean;
+}
+
+// Ranges interactive mode steps through with [ and ], shortest first
+export const RANGE_PRESETS: TimeRange[] = [
+  { hours: 6 },
+  { days: 1 },
+  { days: 7 },
+  { days: 30 },
+  { days: 90 },
+  { allTime: true },
+];
+
+function getRangeHours(range: TimeRange): number {
+  if (range.allTime) return Infinity;
+  return range.hours ?? (range.days || 1) * 24;
+}
+
+// The next shorter or longer preset; ranges between presets, e.g. --days 14, step to a neighbor
+export function stepTimeRange(range: TimeRange, direction: 1 | -1): TimeRange {
+  const current = getRangeHours(range);
+  const candidates =
+    direction > 0
+      ? RANGE_PRESETS.filter(preset => getRangeHours(preset) > current)
+      : RANGE_PRESETS.filter(preset => getRangeHours(preset) < current).reverse();
+  return candidates[0] ?? range;
+}
+
+export function formatTimeRange(range: TimeRange): string {
+  if (range.allTime) return 'all time';
+  return range.hours ? `${range.hours} hours` : `${range.days || 1} days`;
+}
@@ -0,0 +1,42 @@
+export const TIMESTAMP_POLICY_VALUES = ['warn', 'clamp', 'exclude'] as const;
+
+export type TimestampPolicy = (typeof TIMESTAMP_POLICY_VALUES)[number];
+
+export type TimestampSanity = 'valid' | 'too-old' | 'future';
+
+// Claude Code logs cannot predate this, so anything earlier is a broken clock or a zero epoch
+export const MIN_VALID_TIMESTAMP = new Date('2024-01-01T00:00:00Z');
+
+// Tolerate small clock skew between machines before treating timestamps as future dates
+const FUTURE_TOLERANCE_MS = 24 * 60 * 60 * 1000;
+
+export function isValidTimestampPolicy(value: string): value is TimestampPolicy {
+  return TIMESTAMP_POLICY_VALUES.includes(value as TimestampPolicy);
+}
+
+export function checkTimestampSanity(time: Date, now: Date): TimestampSanity {
+  if (time.getTime() < MIN_VALID_TIMESTAMP.getTime()) {
+    return 'too-old';
+  }
+  if (time.getTime() > now.getTime() + FUTURE_TOLERANCE_MS) {
+    return 'future';
+  }
+  return 'valid';
+}
+
+// Clamp an out-of-range timestamp to the nearest end of the valid span, such as the span of
+// the plausible timestamps in the data, or to the nearest plausible instant
+export function clampTimestamp(
+  time: Date,
+  now: Date,
+  valid: { start: Date; end: Date } = { start: MIN_VALID_TIMESTAMP, end: now }
+): Date {
+  switch (checkTimestampSanity(time, now)) {
+    case 'too-old':
+      return new Date(valid.start);
+    case 'future':
+      return new Date(valid.end);
+    default:
+      return time;
+  }
+}
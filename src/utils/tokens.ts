@@ -0,0 +1,52 @@
+import { Event } from '../models/models';
+
+// Total input and output tokens recorded on an event. Claude Code logs keep
+// usage on the assistant message in snake_case; normalized events use `usage`.
+export function getEventTokens(event: Event): number {
+  if (event.usage) {
+    return (event.usage.inputTokens ?? 0) + (event.usage.outputTokens ?? 0);
+  }
+
+  const usage = event.message?.usage;
+  if (usage && typeof usage === 'object') {
+    const input = typeof usage.input_tokens === 'number' ? usage.input_tokens : 0;
+    const output = typeof usage.output_tokens === 'number' ? usage.output_tokens : 0;
+    return input + output;
+  }
+
+  return 0;
+}
+
+// The API response an event belongs to. Claude Code writes one line per content block of an
+// assistant reply, and every line repeats the usage of the whole reply.
+export function getMessageKey(event: Event): string | undefined {
+  const id = event.message && typeof event.message === 'object' ? event.message.id : undefined;
+  if (typeof id === 'string') return id;
+  return typeof event.requestId === 'string' ? event.requestId : undefined;
+}
+
+// getEventTokens counting each reply once: later lines of the same reply count zero. Share one
+// counter across everything that is summed together.
+export function createTokenCounter(): (event: Event) => number {
+  const counted = new Set<string>();
+  return event => {
+    const key = getMessageKey(event);
+    if (key !== undefined) {
+      if (counted.has(key)) return 0;
+      counted.add(key);
+    }
+    return getEventTokens(event);
+  };
+}
+
+export function sumEventTokens(events: Event[]): number {
+  const countTokens = createTokenCounter();
+  return events.reduce((sum, event) => sum + countTokens(event), 0);
+}
+
+// Token counts in thousands or millions, e.g. 1234 -> "1.2k"
+export function formatTokens(tokens: number): string {
+  if (tokens >= 1_000_000) return `${(tokens / 1_000_000).toFixed(1)}M`;
+  if (tokens >= 1_000) return `${(tokens / 1_000).toFixed(1)}k`;
+  return `${tokens}`;
+}
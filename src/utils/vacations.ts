@@ -0,0 +1,21 @@
+import { addDays, format } from 'date-fns';
+import { Settings } from './config';
+
+// Inclusive range of local dates ("YYYY-MM-DD"); a single day leaves out `end`
+export type Vacation = NonNullable<Settings['vacations']>[number];
+
+export function isVacationDay(day: Date, vacations: Vacation[]): boolean {
+  const date = format(day, 'yyyy-MM-dd');
+  return vacations.some(
+    vacation => date >= vacation.start && date <= (vacation.end ?? vacation.start)
+  );
+}
+
+// Local days in [start, end) taken off
+export function countVacationDays(start: Date, end: Date, vacations: Vacation[]): number {
+  let count = 0;
+  for (let day = start; day < end; day = addDays(day, 1)) {
+    if (isVacationDay(day, vacations)) count++;
+  }
+  return count;
+}